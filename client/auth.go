@@ -0,0 +1,210 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// TokenCreateParam is the param to create token, scope is one of System, Product and Support
+type TokenCreateParam struct {
+	Name        *string `json:"name"`
+	Scope       *string `json:"scope"`
+	ProductName *string `json:"product_name"`
+}
+
+// TokenCreateData carries the token value created
+type TokenCreateData struct {
+	Token string `json:"token"`
+}
+
+// Token is the token info
+type Token struct {
+	Name        string `json:"name"`
+	ProductName string `json:"product_name,omitempty"`
+	Token       string `json:"token,omitempty"`
+	Scope       string `json:"scope"`
+}
+
+// UserCreateParam is the param to create user, type is one of jwt and normal
+type UserCreateParam struct {
+	UserName *string `json:"user_name"`
+	Password *string `json:"password"`
+	IsAdmin  bool    `json:"is_admin"`
+	Type     string  `json:"type"`
+}
+
+// User is the user info, SessionKey only returned by Login
+type User struct {
+	UserName string `json:"user_name,omitempty"`
+	IsAdmin  bool   `json:"is_admin"`
+
+	SessionKey string   `json:"session_key,omitempty"`
+	Products   []string `json:"products,omitempty"`
+}
+
+type userNamePasswordParam struct {
+	UserName *string `json:"user_name"`
+	Password *string `json:"password"`
+}
+
+type userUpdateIsAdminParam struct {
+	IsAdmin bool `json:"is_admin"`
+}
+
+type userUpdatePasswordParam struct {
+	OldPassword string  `json:"old_password"`
+	Password    *string `json:"password"`
+}
+
+// Login create session key by user name and password,
+// SessionKey of result can be used by WithSessionKey
+func (c *Client) Login(ctx context.Context, userName, password string) (*User, error) {
+	data := &User{}
+	err := c.do(ctx, http.MethodPost, "/auth/session-keys", nil, &userNamePasswordParam{
+		UserName: &userName,
+		Password: &password,
+	}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Logout destroy session key
+func (c *Client) Logout(ctx context.Context, sessionKey string) error {
+	return c.do(ctx, http.MethodDelete, pathf("/auth/session-keys/%s", sessionKey), nil, nil, nil)
+}
+
+// CreateToken create token, the token value only returned once
+func (c *Client) CreateToken(ctx context.Context, param *TokenCreateParam) (*TokenCreateData, error) {
+	data := &TokenCreateData{}
+	if err := c.do(ctx, http.MethodPost, "/auth/tokens", nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetToken get token by name
+func (c *Client) GetToken(ctx context.Context, name string) (*Token, error) {
+	data := &Token{}
+	if err := c.do(ctx, http.MethodGet, pathf("/auth/tokens/%s", name), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListTokens list tokens
+func (c *Client) ListTokens(ctx context.Context) ([]*Token, error) {
+	var list []*Token
+	if err := c.do(ctx, http.MethodGet, "/auth/tokens", nil, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ListProductTokens list tokens of product
+func (c *Client) ListProductTokens(ctx context.Context, productName string) ([]*Token, error) {
+	var list []*Token
+	path := pathf("/auth/tokens/actions/search-by-product/%s", productName)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteToken delete token
+func (c *Client) DeleteToken(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, pathf("/auth/tokens/%s", name), nil, nil, nil)
+}
+
+// CreateUser create user
+func (c *Client) CreateUser(ctx context.Context, param *UserCreateParam) error {
+	return c.do(ctx, http.MethodPost, "/auth/users", nil, param, nil)
+}
+
+// GetUser get user by name
+func (c *Client) GetUser(ctx context.Context, name string) (*User, error) {
+	data := &User{}
+	if err := c.do(ctx, http.MethodGet, pathf("/auth/users/%s", name), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListUsers list users
+func (c *Client) ListUsers(ctx context.Context, param *PageParam) ([]*User, *Page, error) {
+	var list []*User
+	page, err := c.list(ctx, "/auth/users", param, nil, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, page, nil
+}
+
+// ListProductUsers list users bound to product, userType can be jwt, normal or empty for all
+func (c *Client) ListProductUsers(ctx context.Context, productName, userType string) ([]*User, error) {
+	query := url.Values{}
+	if userType != "" {
+		query.Set("type", userType)
+	}
+
+	var list []*User
+	path := pathf("/auth/users/actions/search-by-product/%s", productName)
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UpdateUserPassword change password of user, oldPassword is needed when changing password of self
+func (c *Client) UpdateUserPassword(ctx context.Context, name, oldPassword, password string) error {
+	return c.do(ctx, http.MethodPatch, pathf("/auth/users/%s/passwd", name), nil, &userUpdatePasswordParam{
+		OldPassword: oldPassword,
+		Password:    &password,
+	}, nil)
+}
+
+// UpdateUserIsAdmin grant or revoke admin privilege of user
+func (c *Client) UpdateUserIsAdmin(ctx context.Context, name string, isAdmin bool) error {
+	return c.do(ctx, http.MethodPatch, pathf("/auth/users/%s/is_admin", name), nil, &userUpdateIsAdminParam{
+		IsAdmin: isAdmin,
+	}, nil)
+}
+
+// DeleteUser delete user
+func (c *Client) DeleteUser(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, pathf("/auth/users/%s", name), nil, nil, nil)
+}
+
+// BindUserProduct grant user privilege of product
+func (c *Client) BindUserProduct(ctx context.Context, userName, productName string) error {
+	return c.do(ctx, http.MethodPost, pathf("/auth/users/%s/products/%s", userName, productName), nil, nil, nil)
+}
+
+// UnbindUserProduct revoke user privilege of product
+func (c *Client) UnbindUserProduct(ctx context.Context, userName, productName string) error {
+	return c.do(ctx, http.MethodDelete, pathf("/auth/users/%s/products/%s", userName, productName), nil, nil, nil)
+}
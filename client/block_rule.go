@@ -0,0 +1,59 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// BlockRule closes or allows connections matching Cond, Action is one of CLOSE and ALLOW
+type BlockRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Cond        string `json:"cond"`
+	Action      string `json:"action"`
+}
+
+// BlockRules is the ordered block rules of product
+type BlockRules struct {
+	Rules []*BlockRule `json:"rules"`
+}
+
+func blockRulePath(productName string) string {
+	return pathf("/products/%s/block-rules", productName)
+}
+
+// GetBlockRules get block rules of product
+func (c *Client) GetBlockRules(ctx context.Context, productName string) (*BlockRules, error) {
+	data := &BlockRules{}
+	if err := c.do(ctx, http.MethodGet, blockRulePath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpsertBlockRules replace all block rules of product
+func (c *Client) UpsertBlockRules(ctx context.Context, productName string,
+	param *BlockRules) (*BlockRules, error) {
+
+	data := &BlockRules{}
+	if err := c.do(ctx, http.MethodPut, blockRulePath(productName), nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
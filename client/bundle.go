@@ -0,0 +1,305 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bundle describes a product and its resources.
+// It's applied by the bundle api of server in one transaction: sections not set are kept untouched,
+// resources not exist will be created, resources differ from bundle will be updated,
+// and resources missing from a section set will be deleted.
+// Routes or TLSRule which is an empty object removes route rules or tls rule of product.
+type Bundle struct {
+	Product     *ProductCreateParam      `json:"product"`
+	Pools       []*PoolParam             `json:"pools"`
+	SubClusters []*SubClusterCreateParam `json:"sub_clusters"`
+	Clusters    []*ClusterParam          `json:"clusters"`
+	Domains     []string                 `json:"domains"`
+	Routes      *RouteRulesParam         `json:"routes"`
+	TLSRule     *TLSRule                 `json:"tls_rule"`
+}
+
+// ParseBundle parse bundle from JSON or YAML content
+func ParseBundle(content []byte) (*Bundle, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	bs, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{}
+	if err := json.Unmarshal(bs, bundle); err != nil {
+		return nil, err
+	}
+
+	if bundle.Product == nil || bundle.Product.Name == "" {
+		return nil, fmt.Errorf("product.name of bundle must be set")
+	}
+	for _, pool := range bundle.Pools {
+		if pool.Name == nil {
+			return nil, fmt.Errorf("name of pool must be set")
+		}
+	}
+	for _, sc := range bundle.SubClusters {
+		if sc.Name == nil {
+			return nil, fmt.Errorf("name of sub cluster must be set")
+		}
+	}
+	for _, cluster := range bundle.Clusters {
+		if cluster.Name == nil {
+			return nil, fmt.Errorf("name of cluster must be set")
+		}
+	}
+
+	return bundle, nil
+}
+
+// serverBundle convert bundle to the param of bundle api
+func (b *Bundle) serverBundle() *ProductBundle {
+	return &ProductBundle{
+		Product: &ProductUpdateParam{
+			Description:       &b.Product.Description,
+			MailList:          b.Product.MailList,
+			PhoneList:         b.Product.PhoneList,
+			ContactPersonList: b.Product.ContactPersonList,
+		},
+		Pools:       b.Pools,
+		SubClusters: b.SubClusters,
+		Clusters:    b.Clusters,
+		Routes:      b.Routes,
+		Domains:     b.Domains,
+		TLSRule:     b.TLSRule,
+	}
+}
+
+// actions of changes
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// resources of bundle, they are Kind of changes
+const (
+	ResourceProduct    = "product"
+	ResourcePool       = "pool"
+	ResourceSubCluster = "sub_cluster"
+	ResourceCluster    = "cluster"
+	ResourceDomain     = "domain"
+	ResourceRoute      = "route_rule"
+	ResourceTLSRule    = "tls_rule"
+)
+
+// ProductBundle is all resources of product, it's the param and result of bundle api
+type ProductBundle struct {
+	Product     *ProductUpdateParam      `json:"product"`
+	Pools       []*PoolParam             `json:"pools"`
+	SubClusters []*SubClusterCreateParam `json:"sub_clusters"`
+	Clusters    []*ClusterParam          `json:"clusters"`
+	Routes      *RouteRulesParam         `json:"routes"`
+	Domains     []string                 `json:"domains"`
+	TLSRule     *TLSRule                 `json:"tls_rule"`
+}
+
+// BundleChange is one change planned or applied by bundle api, Old and New are in JSON form of resource
+type BundleChange struct {
+	Resource string      `json:"resource"`
+	Key      string      `json:"key"`
+	Action   string      `json:"action"`
+	Old      interface{} `json:"old,omitempty"`
+	New      interface{} `json:"new,omitempty"`
+}
+
+// BundleApplyData is the result of bundle api
+type BundleApplyData struct {
+	DryRun  bool            `json:"dry_run"`
+	Changes []*BundleChange `json:"changes"`
+}
+
+// Change is one step to apply a bundle
+type Change struct {
+	Action string
+	Kind   string
+	Name   string
+
+	// Diff lists fields to be updated, like `basic.protocol: "http" => "https"`, empty when creating or deleting
+	Diff []string
+}
+
+func (ch *Change) String() string {
+	sign := "+"
+	switch ch.Action {
+	case ChangeUpdate:
+		sign = "~"
+	case ChangeDelete:
+		sign = "-"
+	}
+
+	s := fmt.Sprintf("%s %s %s", sign, ch.Kind, ch.Name)
+	for _, one := range ch.Diff {
+		s += "\n    " + one
+	}
+
+	return s
+}
+
+func bundlePath(productName string) string {
+	return pathf("/products/%s/bundle", productName)
+}
+
+// GetProductBundle export all resources of product
+func (c *Client) GetProductBundle(ctx context.Context, productName string) (*ProductBundle, error) {
+	data := &ProductBundle{}
+	if err := c.do(ctx, http.MethodGet, bundlePath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ApplyProductBundle reconcile resources of product with bundle in one transaction,
+// changes are only planned if dryRun
+func (c *Client) ApplyProductBundle(ctx context.Context, productName string, bundle *ProductBundle,
+	dryRun bool) (*BundleApplyData, error) {
+
+	query := url.Values{}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+
+	data := &BundleApplyData{}
+	if err := c.do(ctx, http.MethodPut, bundlePath(productName), query, bundle, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// PlanBundle return changes needed to apply the bundle, they are planned by server
+func (c *Client) PlanBundle(ctx context.Context, bundle *Bundle) ([]*Change, error) {
+	data, err := c.ApplyProductBundle(ctx, bundle.Product.Name, bundle.serverBundle(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	return newChanges(data), nil
+}
+
+// ApplyBundle apply bundle by server in one transaction, the product is created in it if not exist,
+// nothing is changed if err is not nil
+func (c *Client) ApplyBundle(ctx context.Context, bundle *Bundle) ([]*Change, error) {
+	productName := bundle.Product.Name
+
+	data, err := c.ApplyProductBundle(ctx, productName, bundle.serverBundle(), false)
+	if err != nil {
+		return nil, fmt.Errorf("apply bundle of product %s: %w", productName, err)
+	}
+
+	return newChanges(data), nil
+}
+
+func newChanges(data *BundleApplyData) []*Change {
+	changes := []*Change{}
+	for _, one := range data.Changes {
+		ch := &Change{
+			Action: one.Action,
+			Kind:   one.Resource,
+			Name:   one.Key,
+		}
+		if one.Action == ChangeUpdate {
+			ch.Diff = diffJSON(one.Old, one.New)
+		}
+		changes = append(changes, ch)
+	}
+
+	return changes
+}
+
+func toGeneric(v interface{}) interface{} {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var rst interface{}
+	if err := json.Unmarshal(bs, &rst); err != nil {
+		return nil
+	}
+
+	return rst
+}
+
+// diffJSON compare fields set in desired with current by their JSON form,
+// fields of desired which are null will be ignored
+func diffJSON(current, desired interface{}) []string {
+	return diffValue("", toGeneric(current), toGeneric(desired), nil)
+}
+
+func diffValue(prefix string, current, desired interface{}, rst []string) []string {
+	if desired == nil {
+		return rst
+	}
+
+	dm, ok1 := desired.(map[string]interface{})
+	cm, ok2 := current.(map[string]interface{})
+	if ok1 && ok2 {
+		keys := make([]string, 0, len(dm))
+		for k := range dm {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			rst = diffValue(prefix+"."+k, cm[k], dm[k], rst)
+		}
+		return rst
+	}
+
+	if isEmpty(current) && isEmpty(desired) || reflect.DeepEqual(current, desired) {
+		return rst
+	}
+
+	cs, _ := json.Marshal(current)
+	ds, _ := json.Marshal(desired)
+	return append(rst, fmt.Sprintf("%s: %s => %s", strings.TrimPrefix(prefix, "."), cs, ds))
+}
+
+func isEmpty(v interface{}) bool {
+	switch vv := v.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(vv) == 0
+	case map[string]interface{}:
+		return len(vv) == 0
+	}
+
+	return false
+}
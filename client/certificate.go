@@ -0,0 +1,88 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CertificateCreateParam is the param to upload certificate
+type CertificateCreateParam struct {
+	Name *string `json:"cert_name"`
+
+	Description *string `json:"description"`
+	IsDefault   *bool   `json:"is_default"`
+
+	CertFileName    *string `json:"cert_file_name"`
+	CertFileContent *string `json:"cert_file_content"`
+	KeyFileName     *string `json:"key_file_name"`
+	KeyFileContent  *string `json:"key_file_content"`
+	ExpiredDate     *string `json:"expired_date"`
+}
+
+// Certificate is the certificate info, content of files not returned
+type Certificate struct {
+	CertName    string `json:"cert_name"`
+	Description string `json:"description"`
+	IsDefault   bool   `json:"is_default"`
+
+	CertFileName string `json:"cert_file_name"`
+	KeyFileName  string `json:"key_file_name"`
+	ExpiredDate  string `json:"expired_date"`
+
+	Products []string `json:"products"`
+}
+
+// CreateCertificate upload a certificate
+func (c *Client) CreateCertificate(ctx context.Context, param *CertificateCreateParam) (*Certificate, error) {
+	data := &Certificate{}
+	if err := c.do(ctx, http.MethodPost, "/certificates", nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListCertificates list certificates
+func (c *Client) ListCertificates(ctx context.Context, param *PageParam) ([]*Certificate, *Page, error) {
+	var list []*Certificate
+	page, err := c.list(ctx, "/certificates", param, nil, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, page, nil
+}
+
+// SetDefaultCertificate make certificate as the default one
+func (c *Client) SetDefaultCertificate(ctx context.Context, name string) (*Certificate, error) {
+	data := &Certificate{}
+	if err := c.do(ctx, http.MethodPatch, pathf("/certificates/%s/default", name), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteCertificate delete certificate
+func (c *Client) DeleteCertificate(ctx context.Context, name string) (*Certificate, error) {
+	data := &Certificate{}
+	if err := c.do(ctx, http.MethodDelete, pathf("/certificates/%s", name), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client is a go client for the open api of api-server
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BasePath is the path prefix of open api
+const BasePath = "/open-api/v1"
+
+// types of Authorization header, same as server
+const (
+	authTypeSessionKey = "Session"
+	authTypeToken      = "Token"
+)
+
+// Client calls open api of api-server
+type Client struct {
+	baseURL       string
+	httpClient    *http.Client
+	authorization string
+}
+
+// Option used to customize Client
+type Option func(*Client)
+
+// WithToken authenticates requests with token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.authorization = authTypeToken + " " + token
+	}
+}
+
+// WithSessionKey authenticates requests with session key
+func WithSessionKey(sessionKey string) Option {
+	return func(c *Client) {
+		c.authorization = authTypeSessionKey + " " + sessionKey
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New create a client, baseURL is the address of api-server, like http://127.0.0.1:8183
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// RequestOption used to customize one request
+type RequestOption func(*http.Request)
+
+// IfMatch set header If-Match, request will fail with 412 if resource has been changed
+func IfMatch(revision string) RequestOption {
+	return func(req *http.Request) {
+		if revision != "" {
+			req.Header.Set("If-Match", strconv.Quote(revision))
+		}
+	}
+}
+
+// Error is returned when ErrNum of response is not 200
+type Error struct {
+	Code  int
+	Msg   string
+	ReqID string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api-server: code=%d msg=%s req_id=%s", e.Code, e.Msg, e.ReqID)
+}
+
+func isCode(err error, codes ...int) bool {
+	e, ok := err.(*Error)
+	if !ok {
+		return false
+	}
+
+	for _, code := range codes {
+		if e.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNotFound return true if resource not exist
+func IsNotFound(err error) bool {
+	return isCode(err, http.StatusNotFound)
+}
+
+// IsExisted return true if resource already existed
+func IsExisted(err error) bool {
+	return isCode(err, 555)
+}
+
+// IsPreconditionFailed return true if revision in If-Match mismatched
+func IsPreconditionFailed(err error) bool {
+	return isCode(err, http.StatusPreconditionFailed)
+}
+
+// IsUnauthorized return true if authenticate or authorize failed
+func IsUnauthorized(err error) bool {
+	return isCode(err, http.StatusUnauthorized, http.StatusPaymentRequired)
+}
+
+// IsParamError return true if param is invalid
+func IsParamError(err error) bool {
+	return isCode(err, http.StatusUnprocessableEntity)
+}
+
+type envelope struct {
+	ErrNum int             `json:"ErrNum"`
+	ErrMsg string          `json:"ErrMsg"`
+	Data   json.RawMessage `json:"Data"`
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{},
+	opts ...RequestOption) (*http.Response, []byte, error) {
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = bytes.NewReader(bs)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	rsp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rsp.Body.Close()
+
+	content, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rsp, content, nil
+}
+
+func decode(rsp *http.Response, content []byte, data interface{}) error {
+	env := &envelope{}
+	if err := json.Unmarshal(content, env); err != nil {
+		return &Error{
+			Code:  rsp.StatusCode,
+			Msg:   strings.TrimSpace(string(content)),
+			ReqID: rsp.Header.Get("Req-ID"),
+		}
+	}
+
+	if env.ErrNum != http.StatusOK {
+		// some apis return data explaining the error, like route cases fail
+		if data != nil && len(env.Data) > 0 {
+			json.Unmarshal(env.Data, data)
+		}
+		return &Error{
+			Code:  env.ErrNum,
+			Msg:   env.ErrMsg,
+			ReqID: rsp.Header.Get("Req-ID"),
+		}
+	}
+
+	if data == nil || len(env.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(env.Data, data)
+}
+
+// do call open api, Data of response will be decoded into data
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, data interface{},
+	opts ...RequestOption) error {
+
+	rsp, content, err := c.send(ctx, method, BasePath+path, query, body, opts...)
+	if err != nil {
+		return err
+	}
+
+	return decode(rsp, content, data)
+}
+
+func escape(ss ...string) []interface{} {
+	rst := make([]interface{}, len(ss))
+	for i, s := range ss {
+		rst[i] = url.PathEscape(s)
+	}
+
+	return rst
+}
+
+func pathf(format string, ss ...string) string {
+	return fmt.Sprintf(format, escape(ss...)...)
+}
+
+// PageParam selects one page of list api, zero fields are left to server
+type PageParam struct {
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+	Sort     string `form:"sort"`
+	Order    string `form:"order"`
+	Filter   string `form:"filter"`
+}
+
+func pageQuery(param *PageParam) url.Values {
+	query := url.Values{}
+	if param == nil {
+		return query
+	}
+
+	if param.Page > 0 {
+		query.Set("page", strconv.Itoa(param.Page))
+	}
+	if param.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(param.PageSize))
+	}
+	if param.Sort != "" {
+		query.Set("sort", param.Sort)
+	}
+	if param.Order != "" {
+		query.Set("order", param.Order)
+	}
+	if param.Filter != "" {
+		query.Set("filter", param.Filter)
+	}
+
+	return query
+}
+
+// Page is the result of paged list api
+type Page struct {
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+}
+
+func (c *Client) list(ctx context.Context, path string, param *PageParam, extra url.Values,
+	list interface{}) (*Page, error) {
+
+	query := pageQuery(param)
+	for k, vs := range extra {
+		query[k] = vs
+	}
+
+	if param == nil || param.PageSize == 0 {
+		if err := c.do(ctx, http.MethodGet, path, query, nil, list); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	page := &struct {
+		Page
+		List interface{} `json:"list"`
+	}{List: list}
+	if err := c.do(ctx, http.MethodGet, path, query, nil, page); err != nil {
+		return nil, err
+	}
+
+	return &page.Page, nil
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bfenetworks/api-server/endpoints/endpointtest"
+	"github.com/bfenetworks/api-server/lib"
+)
+
+const testBundle = `
+product:
+  name: demo
+  description: demo product
+  mail_list: [demo@example.org]
+pools:
+  - name: demo.demo_pool
+    instances:
+      - hostname: host1
+        ip: 10.0.0.1
+        weight: 10
+        ports: {Default: 8080}
+        tags: {idc: bj}
+sub_clusters:
+  - name: demo_sub_cluster
+    instance_pool: demo.demo_pool
+domains: [example.org]
+`
+
+// newTestClient start a server and return client logged in as admin
+func newTestClient(t *testing.T) *Client {
+	s := endpointtest.NewServer()
+	t.Cleanup(s.Close)
+
+	user, err := New(s.URL).Login(context.Background(), endpointtest.AdminUser, endpointtest.AdminPassword)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	return New(s.URL, WithSessionKey(user.SessionKey))
+}
+
+func TestProductNotFound(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	_, err := c.GetProduct(ctx, "demo")
+	if !IsNotFound(err) {
+		t.Fatalf("GetProduct: want not found, got %v", err)
+	}
+
+	_, _, err = c.ListDomains(ctx, "demo", nil)
+	if !IsNotFound(err) {
+		t.Fatalf("ListDomains: want not found, got %v", err)
+	}
+
+	if _, err = c.CreateProduct(ctx, &ProductCreateParam{Name: "demo"}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if _, err = c.GetProduct(ctx, "demo"); err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+}
+
+func TestPlanBundleNewProduct(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	bundle, err := ParseBundle([]byte(testBundle))
+	if err != nil {
+		t.Fatalf("ParseBundle: %v", err)
+	}
+
+	changes, err := c.PlanBundle(ctx, bundle)
+	if err != nil {
+		t.Fatalf("PlanBundle: %v", err)
+	}
+
+	want := []string{"product demo", "pool demo.demo_pool", "sub_cluster demo_sub_cluster", "domain example.org"}
+	if len(changes) != len(want) {
+		t.Fatalf("PlanBundle: want %d changes, got %v", len(want), changes)
+	}
+	for i, ch := range changes {
+		if got := ch.Kind + " " + ch.Name; ch.Action != ChangeCreate || got != want[i] {
+			t.Errorf("change %d: want create %s, got %s", i, want[i], ch)
+		}
+	}
+
+	if _, err = c.ApplyBundle(ctx, bundle); err != nil {
+		t.Fatalf("ApplyBundle: %v", err)
+	}
+
+	changes, err = c.PlanBundle(ctx, bundle)
+	if err != nil {
+		t.Fatalf("PlanBundle: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("PlanBundle after apply: want no change, got %v", changes)
+	}
+
+	bundle.Domains = []string{}
+	changes, err = c.PlanBundle(ctx, bundle)
+	if err != nil {
+		t.Fatalf("PlanBundle: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Action != ChangeDelete || changes[0].Name != "example.org" {
+		t.Fatalf("PlanBundle without domains: want domain deleted, got %v", changes)
+	}
+}
+
+func TestApplyBundleFailure(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	// sub cluster refers to a pool not exist, nothing in bundle can be applied
+	bundle, err := ParseBundle([]byte(testBundle))
+	if err != nil {
+		t.Fatalf("ParseBundle: %v", err)
+	}
+	bundle.SubClusters = append(bundle.SubClusters, &SubClusterCreateParam{
+		Name:         lib.PString("demo_bad_sub_cluster"),
+		InstancePool: lib.PString("demo.none_pool"),
+	})
+
+	applied, err := c.ApplyBundle(ctx, bundle)
+	if err == nil {
+		t.Fatalf("ApplyBundle: want error for sub cluster of unknown pool")
+	}
+	if len(applied) != 0 {
+		t.Fatalf("ApplyBundle: want nothing applied, got %v", applied)
+	}
+
+	// product is created in the same transaction, it's rolled back too
+	if _, err = c.GetProduct(ctx, "demo"); !IsNotFound(err) {
+		t.Fatalf("GetProduct: want not found, got %v", err)
+	}
+	if _, err = c.GetProductBundle(ctx, "demo"); !IsNotFound(err) {
+		t.Fatalf("GetProductBundle: want not found, got %v", err)
+	}
+	if _, _, err = c.ListDomains(ctx, "demo", nil); !IsNotFound(err) {
+		t.Fatalf("ListDomains: want not found, got %v", err)
+	}
+}
+
+// exportBlockRules export block rules by inner api, so a version of topic block_rule is saved
+func exportBlockRules(t *testing.T, c *Client, rules ...*BlockRule) string {
+	ctx := context.Background()
+	if _, err := c.UpsertBlockRules(ctx, "demo", &BlockRules{Rules: rules}); err != nil {
+		t.Fatalf("UpsertBlockRules: %v", err)
+	}
+
+	// versions are named by time in seconds, make sure the new one is different
+	start := time.Now().Truncate(time.Second)
+	time.Sleep(start.Add(time.Second).Sub(time.Now()))
+
+	if _, err := c.Export(ctx, "mod_block/block_rules", nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	versions, err := c.ListConfigVersions(ctx, "block_rule")
+	if err != nil || len(versions) == 0 {
+		t.Fatalf("ListConfigVersions: want versions, got %v %v", versions, err)
+	}
+
+	return versions[0].Version
+}
+
+func TestDiffConfig(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	if _, err := c.CreateProduct(ctx, &ProductCreateParam{Name: "demo"}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	rule := &BlockRule{Name: "close_all", Cond: "default_t()", Action: "CLOSE"}
+	oldVersion := exportBlockRules(t, c, rule)
+	newVersion := exportBlockRules(t, c, rule, &BlockRule{Name: "allow_all", Cond: "default_t()", Action: "ALLOW"})
+	if oldVersion == newVersion {
+		t.Fatalf("export: want a new version after block rules changed, got %s", newVersion)
+	}
+
+	// the latest version and the one before it are compared by default
+	diff, err := c.DiffConfig(ctx, "block_rule", "", "")
+	if err != nil {
+		t.Fatalf("DiffConfig: %v", err)
+	}
+	if diff.OldVersion != oldVersion || diff.NewVersion != newVersion {
+		t.Fatalf("DiffConfig: want %s => %s, got %s => %s", oldVersion, newVersion, diff.OldVersion, diff.NewVersion)
+	}
+	if len(diff.Entries) != 1 {
+		t.Fatalf("DiffConfig: want 1 entry, got %d", len(diff.Entries))
+	}
+	if one := diff.Entries[0]; one.Key != "demo" || one.Action != "modified" || one.Old == nil || one.New == nil {
+		t.Fatalf("DiffConfig: want block rules of demo modified, got %+v", one)
+	}
+
+	// reversed versions
+	diff, err = c.DiffConfig(ctx, "block_rule", newVersion, oldVersion)
+	if err != nil {
+		t.Fatalf("DiffConfig: %v", err)
+	}
+	if len(diff.Entries) != 1 || diff.Entries[0].Action != "modified" {
+		t.Fatalf("DiffConfig reversed: want block rules of demo modified, got %+v", diff.Entries)
+	}
+
+	diff, err = c.DiffConfig(ctx, "block_rule", oldVersion, oldVersion)
+	if err != nil {
+		t.Fatalf("DiffConfig: %v", err)
+	}
+	if len(diff.Entries) != 0 {
+		t.Fatalf("DiffConfig identical versions: want no entry, got %+v", diff.Entries)
+	}
+
+	if _, err = c.DiffConfig(ctx, "block_rule", "20000101000000", newVersion); !IsNotFound(err) {
+		t.Fatalf("DiffConfig missing version: want not found, got %v", err)
+	}
+	if _, err = c.DiffConfig(ctx, "block_rule", oldVersion, "20000101000000"); !IsNotFound(err) {
+		t.Fatalf("DiffConfig missing version: want not found, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// ClusterParam is the param to create or update cluster, nil field will not be changed when updating
+type ClusterParam struct {
+	Name           *string              `json:"name"`
+	Description    *string              `json:"description"`
+	Basic          *ClusterBasicParam   `json:"basic"`
+	StickySessions *StickySessionsParam `json:"sticky_sessions"`
+
+	SubClusters []string `json:"sub_clusters"`
+
+	Scheduler map[string]map[string]int `json:"scheduler"`
+
+	PassiveHealthCheck *PassiveHealthCheckParam `json:"passive_health_check"`
+}
+
+// ClusterBasicParam is the basic setting of cluster, protocol is one of http, https, h2c and fcgi,
+// balance mode is one of WRR and WLC
+type ClusterBasicParam struct {
+	Protocol                 *string `json:"protocol"`
+	BalanceMode              *string `json:"balance_mode"`
+	OutlierDetectionHttpCode *string `json:"outlier_detection_http_code"`
+
+	Connection *ConnectionParam `json:"connection"`
+	Retries    *RetriesParam    `json:"retries"`
+	Buffers    *BuffersParam    `json:"buffers"`
+	Timeouts   *TimeoutsParam   `json:"timeouts"`
+}
+
+// ConnectionParam is the connection setting of cluster
+type ConnectionParam struct {
+	MaxIdleConnPerRs    *int16 `json:"max_idle_conn_per_rs"`
+	MaxConnPerRs        *int32 `json:"max_conn_per_rs"`
+	CancelOnClientClose *bool  `json:"cancel_on_client_close"`
+	ClientIPCarry       *bool  `json:"client_ip_carry"`
+	ClientPortCarry     *bool  `json:"client_port_carry"`
+}
+
+// RetriesParam is the retry setting of cluster
+type RetriesParam struct {
+	MaxRetryInSubcluster    *int8 `json:"max_retry_in_subcluster"`
+	MaxRetryCrossSubcluster *int8 `json:"max_retry_cross_subcluster"`
+}
+
+// BuffersParam is the buffer setting of cluster
+type BuffersParam struct {
+	ReqWriteBufferSize *int32 `json:"req_write_buffer_size"`
+}
+
+// TimeoutsParam is the timeout setting of cluster, in milliseconds
+type TimeoutsParam struct {
+	TimeoutConnServ        *int32 `json:"timeout_conn_serv"`
+	TimeoutResponseHeader  *int32 `json:"timeout_response_header"`
+	TimeoutReadbodyClient  *int32 `json:"timeout_readbody_client"`
+	TimeoutReadClientAgain *int32 `json:"timeout_read_client_again"`
+	TimeoutWriteClient     *int32 `json:"timeout_write_client"`
+}
+
+// StickySessionsParam is the session sticky setting of cluster,
+// type is one of INSTANCE and SUB_CLUSTER, hash strategy is one of CLIENT_IP_ONLY, CLIENT_ID_ONLY and CLIENT_ID_PREFERED
+type StickySessionsParam struct {
+	SessionStickyType *string `json:"session_sticky_type"`
+	HashStrategy      *string `json:"hash_strategy"`
+	HashHeader        *string `json:"hash_header"`
+}
+
+// PassiveHealthCheckParam is the health check setting of cluster
+type PassiveHealthCheckParam struct {
+	Schema     *string `json:"schema"`
+	Interval   *int32  `json:"interval"`
+	Failnum    *int32  `json:"failnum"`
+	Statuscode *int32  `json:"statuscode"`
+	Host       *string `json:"host"`
+	Uri        *string `json:"uri"`
+}
+
+// Cluster is the cluster info, Revision can be used by IfMatch
+type Cluster struct {
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	Basic          *ClusterBasic   `json:"basic"`
+	StickySessions *StickySessions `json:"sticky_sessions"`
+	Ready          bool            `json:"ready"`
+
+	PassiveHealthCheck *PassiveHealthCheck `json:"passive_health_check"`
+
+	SubClusters []string `json:"sub_clusters"`
+
+	Scheduler map[string]map[string]int `json:"scheduler,omitempty"`
+
+	Revision string `json:"revision"`
+}
+
+// ClusterBasic is the basic setting of cluster
+type ClusterBasic struct {
+	Protocol                 string `json:"protocol"`
+	BalanceMode              string `json:"balance_mode"`
+	OutlierDetectionHttpCode string `json:"outlier_detection_http_code"`
+
+	Connection *Connection `json:"connection"`
+	Retries    *Retries    `json:"retries"`
+	Buffers    *Buffers    `json:"buffers"`
+	Timeouts   *Timeouts   `json:"timeouts"`
+}
+
+// Connection is the connection setting of cluster
+type Connection struct {
+	MaxIdleConnPerRs    int16 `json:"max_idle_conn_per_rs"`
+	MaxConnPerRs        int32 `json:"max_conn_per_rs"`
+	CancelOnClientClose bool  `json:"cancel_on_client_close"`
+	ClientIPCarry       bool  `json:"client_ip_carry"`
+	ClientPortCarry     bool  `json:"client_port_carry"`
+}
+
+// Retries is the retry setting of cluster
+type Retries struct {
+	MaxRetryInSubcluster    int8 `json:"max_retry_in_subcluster"`
+	MaxRetryCrossSubcluster int8 `json:"max_retry_cross_subcluster"`
+}
+
+// Buffers is the buffer setting of cluster
+type Buffers struct {
+	ReqWriteBufferSize int32 `json:"req_write_buffer_size"`
+}
+
+// Timeouts is the timeout setting of cluster, in milliseconds
+type Timeouts struct {
+	TimeoutConnServ        int32 `json:"timeout_conn_serv"`
+	TimeoutResponseHeader  int32 `json:"timeout_response_header"`
+	TimeoutReadbodyClient  int32 `json:"timeout_readbody_client"`
+	TimeoutReadClientAgain int32 `json:"timeout_read_client_again"`
+	TimeoutWriteClient     int32 `json:"timeout_write_client"`
+}
+
+// StickySessions is the session sticky setting of cluster
+type StickySessions struct {
+	SessionStickyType string `json:"session_sticky_type"`
+	HashStrategy      string `json:"hash_strategy"`
+	HashHeader        string `json:"hash_header"`
+}
+
+// PassiveHealthCheck is the health check setting of cluster
+type PassiveHealthCheck struct {
+	Schema     string `json:"schema"`
+	Interval   int32  `json:"interval"`
+	Failnum    int32  `json:"failnum"`
+	Statuscode int32  `json:"statuscode"`
+	Host       string `json:"host"`
+	Uri        string `json:"uri"`
+}
+
+// ClusterReady tells whether cluster is ready
+type ClusterReady struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+}
+
+type bindSubClusterParam struct {
+	SubClusters []string `json:"sub_clusters"`
+}
+
+func clusterPath(productName string) string {
+	return pathf("/products/%s/clusters", productName)
+}
+
+func (c *Client) oneCluster(ctx context.Context, method, path string, body interface{},
+	opts ...RequestOption) (*Cluster, error) {
+
+	data := &Cluster{}
+	if err := c.do(ctx, method, path, nil, body, data, opts...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CreateCluster create cluster of product
+func (c *Client) CreateCluster(ctx context.Context, productName string,
+	param *ClusterParam) (*Cluster, error) {
+
+	return c.oneCluster(ctx, http.MethodPost, clusterPath(productName), param)
+}
+
+// GetCluster get cluster of product, Revision of result can be used by IfMatch
+func (c *Client) GetCluster(ctx context.Context, productName, name string) (*Cluster, error) {
+	return c.oneCluster(ctx, http.MethodGet, clusterPath(productName)+pathf("/%s", name), nil)
+}
+
+// ListClusters list clusters of product
+func (c *Client) ListClusters(ctx context.Context, productName string) ([]*Cluster, error) {
+	var list []*Cluster
+	if err := c.do(ctx, http.MethodGet, clusterPath(productName), nil, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UpdateCluster update basic info of cluster
+func (c *Client) UpdateCluster(ctx context.Context, productName, name string, param *ClusterParam,
+	opts ...RequestOption) (*Cluster, error) {
+
+	return c.oneCluster(ctx, http.MethodPatch, clusterPath(productName)+pathf("/%s", name), param, opts...)
+}
+
+// BindSubClusters replace sub clusters of cluster
+func (c *Client) BindSubClusters(ctx context.Context, productName, name string, subClusters []string,
+	opts ...RequestOption) (*Cluster, error) {
+
+	return c.oneCluster(ctx, http.MethodPatch, clusterPath(productName)+pathf("/%s/sub-clusters", name),
+		&bindSubClusterParam{SubClusters: subClusters}, opts...)
+}
+
+// DeleteCluster delete cluster of product
+func (c *Client) DeleteCluster(ctx context.Context, productName, name string,
+	opts ...RequestOption) (*Cluster, error) {
+
+	return c.oneCluster(ctx, http.MethodDelete, clusterPath(productName)+pathf("/%s", name), nil, opts...)
+}
+
+// ClusterReady check whether cluster is ready
+func (c *Client) ClusterReady(ctx context.Context, productName, name string) (*ClusterReady, error) {
+	data := &ClusterReady{}
+	err := c.do(ctx, http.MethodGet, clusterPath(productName)+pathf("/%s/ready", name), nil, nil, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ConfigVersion is one version of config exported to BFE, Topic is like route_rule and gslb.{bfe_cluster}
+type ConfigVersion struct {
+	Topic     string    `json:"topic"`
+	Version   string    `json:"version"`
+	DataSign  string    `json:"data_sign"`
+	CreatedAt time.Time `json:"created_at"`
+	Pinned    bool      `json:"pinned"`
+}
+
+// ConfigDiffEntry is one item changed between two versions, Action is one of added, modified and removed
+type ConfigDiffEntry struct {
+	Resource string      `json:"resource"`
+	Key      string      `json:"key"`
+	Action   string      `json:"action"`
+	Old      interface{} `json:"old,omitempty"`
+	New      interface{} `json:"new,omitempty"`
+}
+
+// ConfigDiff is the changes from OldVersion to NewVersion of topic
+type ConfigDiff struct {
+	Topic      string             `json:"topic"`
+	OldVersion string             `json:"old_version"`
+	NewVersion string             `json:"new_version"`
+	Entries    []*ConfigDiffEntry `json:"entries"`
+}
+
+// ListConfigVersions list exported versions of topic, newest first
+func (c *Client) ListConfigVersions(ctx context.Context, topic string) ([]*ConfigVersion, error) {
+	data := []*ConfigVersion{}
+	if err := c.do(ctx, http.MethodGet, pathf("/config-versions/%s", topic), nil, nil, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DiffConfig compare two exported versions of topic,
+// the latest version is used if newVersion is empty, and the one before newVersion if oldVersion is empty
+func (c *Client) DiffConfig(ctx context.Context, topic, oldVersion, newVersion string) (*ConfigDiff, error) {
+	query := url.Values{}
+	if oldVersion != "" {
+		query.Set("old_version", oldVersion)
+	}
+	if newVersion != "" {
+		query.Set("new_version", newVersion)
+	}
+
+	data := &ConfigDiff{}
+	if err := c.do(ctx, http.MethodGet, pathf("/config-diffs/%s", topic), query, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
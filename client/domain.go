@@ -0,0 +1,127 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Domain is the domain info
+type Domain struct {
+	Name string `json:"name"`
+}
+
+// DomainUseStatus tells whether domain is used by others
+type DomainUseStatus struct {
+	DepType string `json:"dep_type"`
+	DepName string `json:"dep_name"`
+	BeUsed  bool   `json:"be_used"`
+}
+
+// DomainHTTPSConfig is the https redirect and hsts setting of domain
+type DomainHTTPSConfig struct {
+	DomainName            string `json:"domain_name"`
+	RedirectEnabled       bool   `json:"redirect_enabled"`
+	HstsEnabled           bool   `json:"hsts_enabled"`
+	HstsMaxAge            int64  `json:"hsts_max_age"`
+	HstsIncludeSubDomains bool   `json:"hsts_include_subdomains"`
+	HstsPreload           bool   `json:"hsts_preload"`
+}
+
+// DomainHTTPSConfigParam is the param to update https setting of domain, nil field will not be changed
+type DomainHTTPSConfigParam struct {
+	RedirectEnabled       *bool  `json:"redirect_enabled"`
+	HstsEnabled           *bool  `json:"hsts_enabled"`
+	HstsMaxAge            *int64 `json:"hsts_max_age"`
+	HstsIncludeSubDomains *bool  `json:"hsts_include_subdomains"`
+	HstsPreload           *bool  `json:"hsts_preload"`
+}
+
+type domainCreateParam struct {
+	Name *string `json:"name"`
+}
+
+func domainPath(productName string) string {
+	return pathf("/products/%s/domains", productName)
+}
+
+// CreateDomain create domain of product
+func (c *Client) CreateDomain(ctx context.Context, productName, name string) (*Domain, error) {
+	data := &Domain{}
+	err := c.do(ctx, http.MethodPost, domainPath(productName), nil, &domainCreateParam{Name: &name}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListDomains list domain names of product
+func (c *Client) ListDomains(ctx context.Context, productName string, param *PageParam) ([]string, *Page, error) {
+	var list []string
+	page, err := c.list(ctx, domainPath(productName), param, nil, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, page, nil
+}
+
+// DomainUseStatus check whether domain is used by route rules
+func (c *Client) DomainUseStatus(ctx context.Context, productName, name string) (*DomainUseStatus, error) {
+	data := &DomainUseStatus{}
+	err := c.do(ctx, http.MethodGet, domainPath(productName)+pathf("/%s/use-status", name), nil, nil, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DomainHTTPSConfig fetch HTTPS redirect and HSTS config of domain
+func (c *Client) DomainHTTPSConfig(ctx context.Context, productName, name string) (*DomainHTTPSConfig, error) {
+	data := &DomainHTTPSConfig{}
+	err := c.do(ctx, http.MethodGet, domainPath(productName)+pathf("/%s/https-config", name), nil, nil, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpdateDomainHTTPSConfig update HTTPS redirect and HSTS config of domain, nil fields are not changed
+func (c *Client) UpdateDomainHTTPSConfig(ctx context.Context, productName, name string,
+	param *DomainHTTPSConfigParam) (*DomainHTTPSConfig, error) {
+
+	data := &DomainHTTPSConfig{}
+	err := c.do(ctx, http.MethodPatch, domainPath(productName)+pathf("/%s/https-config", name), nil, param, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteDomain delete domain of product
+func (c *Client) DeleteDomain(ctx context.Context, productName, name string) (*Domain, error) {
+	data := &Domain{}
+	err := c.do(ctx, http.MethodDelete, domainPath(productName)+pathf("/%s", name), nil, nil, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
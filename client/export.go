@@ -0,0 +1,55 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// InnerBasePath is the path prefix of inner api
+const InnerBasePath = "/inner-api/v1"
+
+// Export fetch config exported by inner api, name is the path after /configs/, like gslb_data/cluster_table
+func (c *Client) Export(ctx context.Context, name string, query url.Values) (json.RawMessage, error) {
+	rsp, content, err := c.send(ctx, http.MethodGet, InnerBasePath+"/configs/"+name, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data json.RawMessage
+	if err := decode(rsp, content, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ExportExtraFile fetch content of extra file, like certificate and key
+func (c *Client) ExportExtraFile(ctx context.Context, name string) ([]byte, error) {
+	rsp, content, err := c.send(ctx, http.MethodGet, InnerBasePath+"/configs/extra_files/"+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp.StatusCode == http.StatusOK && !strings.HasPrefix(rsp.Header.Get("Content-Type"), "application/json") {
+		return content, nil
+	}
+
+	return nil, decode(rsp, content, nil)
+}
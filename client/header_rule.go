@@ -0,0 +1,114 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// HeaderAction is one action of header rule, like REQ_HEADER_SET and RSP_HEADER_DEL
+type HeaderAction struct {
+	Cmd    *string  `json:"cmd"`
+	Params []string `json:"params"`
+}
+
+// HeaderRuleCreateParam is the param to create header rule
+type HeaderRuleCreateParam struct {
+	Name        *string         `json:"name"`
+	Description *string         `json:"description"`
+	Cond        *string         `json:"cond"`
+	Actions     []*HeaderAction `json:"actions"`
+	Last        *bool           `json:"last"`
+}
+
+// HeaderRuleUpdateParam is the param to update header rule, nil field will not be changed
+type HeaderRuleUpdateParam struct {
+	Name        *string         `json:"name"`
+	Description *string         `json:"description"`
+	Cond        *string         `json:"cond"`
+	Actions     []*HeaderAction `json:"actions"`
+	Last        *bool           `json:"last"`
+}
+
+// HeaderRule is the header rule of product
+type HeaderRule struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Cond        string          `json:"cond"`
+	Actions     []*HeaderAction `json:"actions"`
+	Last        bool            `json:"last"`
+}
+
+func headerRulePath(productName string) string {
+	return pathf("/products/%s/header-rules", productName)
+}
+
+// CreateHeaderRule create header rule of product, it is appended to the end of rules
+func (c *Client) CreateHeaderRule(ctx context.Context, productName string,
+	param *HeaderRuleCreateParam) (*HeaderRule, error) {
+
+	data := &HeaderRule{}
+	if err := c.do(ctx, http.MethodPost, headerRulePath(productName), nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetHeaderRule get header rule of product
+func (c *Client) GetHeaderRule(ctx context.Context, productName, name string) (*HeaderRule, error) {
+	data := &HeaderRule{}
+	path := headerRulePath(productName) + pathf("/%s", name)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListHeaderRules list header rules of product in the order they are executed
+func (c *Client) ListHeaderRules(ctx context.Context, productName string) ([]*HeaderRule, error) {
+	var list []*HeaderRule
+	if err := c.do(ctx, http.MethodGet, headerRulePath(productName), nil, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UpdateHeaderRule update header rule of product
+func (c *Client) UpdateHeaderRule(ctx context.Context, productName, name string,
+	param *HeaderRuleUpdateParam) (*HeaderRule, error) {
+
+	data := &HeaderRule{}
+	path := headerRulePath(productName) + pathf("/%s", name)
+	if err := c.do(ctx, http.MethodPatch, path, nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteHeaderRule delete header rule of product
+func (c *Client) DeleteHeaderRule(ctx context.Context, productName, name string) (*HeaderRule, error) {
+	data := &HeaderRule{}
+	path := headerRulePath(productName) + pathf("/%s", name)
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// IPBlocklistEntry is one ip or cidr blocked, never expires if ExpireTime is nil
+type IPBlocklistEntry struct {
+	IP         string     `json:"ip"`
+	ExpireTime *time.Time `json:"expire_time"`
+}
+
+// IPBlocklist is the ip blocklist of product
+type IPBlocklist struct {
+	Entries []*IPBlocklistEntry `json:"entries"`
+}
+
+// IPBlocklistUpdateParam adds and removes entries of ip blocklist
+type IPBlocklistUpdateParam struct {
+	Add    []*IPBlocklistEntry `json:"add"`
+	Remove []string            `json:"remove"`
+}
+
+// IPBlocklistImportParam imports entries of ip blocklist,
+// Content has one entry each line, format is "ip_or_cidr [expire_time]"
+type IPBlocklistImportParam struct {
+	Content    string     `json:"content"`
+	ExpireTime *time.Time `json:"expire_time"`
+	Append     bool       `json:"append"`
+}
+
+func ipBlocklistPath(productName string) string {
+	return pathf("/products/%s/ip-blocklist", productName)
+}
+
+// GetIPBlocklist get ip blocklist of product
+func (c *Client) GetIPBlocklist(ctx context.Context, productName string) (*IPBlocklist, error) {
+	data := &IPBlocklist{}
+	if err := c.do(ctx, http.MethodGet, ipBlocklistPath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpdateIPBlocklist add entries to and remove ips from ip blocklist of product
+func (c *Client) UpdateIPBlocklist(ctx context.Context, productName string,
+	param *IPBlocklistUpdateParam) (*IPBlocklist, error) {
+
+	data := &IPBlocklist{}
+	if err := c.do(ctx, http.MethodPatch, ipBlocklistPath(productName), nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ImportIPBlocklist import ip blocklist of product in text
+func (c *Client) ImportIPBlocklist(ctx context.Context, productName string,
+	param *IPBlocklistImportParam) (*IPBlocklist, error) {
+
+	data := &IPBlocklist{}
+	if err := c.do(ctx, http.MethodPut, ipBlocklistPath(productName)+"/import", nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Instance is one backend instance of pool, Ports are keyed by port name
+type Instance struct {
+	Hostname string            `json:"hostname"`
+	IP       string            `json:"ip"`
+	Weight   int64             `json:"weight"`
+	Ports    map[string]int    `json:"ports"`
+	Tags     map[string]string `json:"tags"`
+	Disable  bool              `json:"disable"`
+}
+
+// PoolParam is the param to create or replace instance pool
+type PoolParam struct {
+	Name      *string     `json:"name"`
+	Instances []*Instance `json:"instances"`
+}
+
+// Pool is the instance pool, Revision can be used by IfMatch
+type Pool struct {
+	Name      string      `json:"name"`
+	Instances []*Instance `json:"instances"`
+	Revision  string      `json:"revision"`
+}
+
+// InstancePatch is the change of one instance, nil field will not be changed
+type InstancePatch struct {
+	Weight  *int64 `json:"weight,omitempty"`
+	Disable *bool  `json:"disable,omitempty"`
+}
+
+func productPoolPath(productName string) string {
+	return pathf("/products/%s/instance-pools", productName)
+}
+
+func (c *Client) onePool(ctx context.Context, method, path string, body interface{},
+	opts ...RequestOption) (*Pool, error) {
+
+	data := &Pool{}
+	if err := c.do(ctx, method, path, nil, body, data, opts...); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CreateProductPool create instance pool of product
+func (c *Client) CreateProductPool(ctx context.Context, productName string,
+	param *PoolParam) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodPost, productPoolPath(productName), param)
+}
+
+// GetProductPool get instance pool of product
+func (c *Client) GetProductPool(ctx context.Context, productName, poolName string) (*Pool, error) {
+	return c.onePool(ctx, http.MethodGet, productPoolPath(productName)+pathf("/%s", poolName), nil)
+}
+
+// ListProductPools list names of instance pools of product
+func (c *Client) ListProductPools(ctx context.Context, productName string, param *PageParam) ([]string, *Page, error) {
+	var list []string
+	page, err := c.list(ctx, productPoolPath(productName), param, nil, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, page, nil
+}
+
+// UpdateProductPool replace instances of pool
+func (c *Client) UpdateProductPool(ctx context.Context, productName, poolName string,
+	param *PoolParam) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodPatch, productPoolPath(productName)+pathf("/%s", poolName), param)
+}
+
+// DeleteProductPool delete instance pool of product
+func (c *Client) DeleteProductPool(ctx context.Context, productName, poolName string,
+	opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodDelete, productPoolPath(productName)+pathf("/%s", poolName), nil, opts...)
+}
+
+// AddProductPoolInstance add one instance to pool of product
+func (c *Client) AddProductPoolInstance(ctx context.Context, productName, poolName string,
+	instance *Instance, opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodPost, productPoolPath(productName)+pathf("/%s/instances", poolName),
+		instance, opts...)
+}
+
+// PatchProductPoolInstance change weight or disable flag of one instance in pool of product
+func (c *Client) PatchProductPoolInstance(ctx context.Context, productName, poolName, instance string,
+	patch *InstancePatch, opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodPatch, productPoolPath(productName)+pathf("/%s/instances/%s", poolName, instance),
+		patch, opts...)
+}
+
+// DeleteProductPoolInstance remove one instance from pool of product
+func (c *Client) DeleteProductPoolInstance(ctx context.Context, productName, poolName, instance string,
+	opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodDelete, productPoolPath(productName)+pathf("/%s/instances/%s", poolName, instance),
+		nil, opts...)
+}
+
+// CreateBFEPool create instance pool of bfe cluster
+func (c *Client) CreateBFEPool(ctx context.Context, param *PoolParam) (*Pool, error) {
+	return c.onePool(ctx, http.MethodPost, "/bfe-pools", param)
+}
+
+// GetBFEPool get instance pool of bfe cluster
+func (c *Client) GetBFEPool(ctx context.Context, poolName string) (*Pool, error) {
+	return c.onePool(ctx, http.MethodGet, pathf("/bfe-pools/%s", poolName), nil)
+}
+
+// ListBFEPools list names of instance pools of bfe cluster
+func (c *Client) ListBFEPools(ctx context.Context) ([]string, error) {
+	var list []string
+	if err := c.do(ctx, http.MethodGet, "/bfe-pools", nil, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UpdateBFEPool replace instances of pool
+func (c *Client) UpdateBFEPool(ctx context.Context, poolName string,
+	param *PoolParam) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodPatch, pathf("/bfe-pools/%s", poolName), param)
+}
+
+// DeleteBFEPool delete instance pool of bfe cluster
+func (c *Client) DeleteBFEPool(ctx context.Context, poolName string,
+	opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodDelete, pathf("/bfe-pools/%s", poolName), nil, opts...)
+}
+
+// AddBFEPoolInstance add one instance to pool of bfe cluster
+func (c *Client) AddBFEPoolInstance(ctx context.Context, poolName string,
+	instance *Instance, opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodPost, pathf("/bfe-pools/%s/instances", poolName), instance, opts...)
+}
+
+// PatchBFEPoolInstance change weight or disable flag of one instance in pool of bfe cluster
+func (c *Client) PatchBFEPoolInstance(ctx context.Context, poolName, instance string,
+	patch *InstancePatch, opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodPatch, pathf("/bfe-pools/%s/instances/%s", poolName, instance), patch, opts...)
+}
+
+// DeleteBFEPoolInstance remove one instance from pool of bfe cluster
+func (c *Client) DeleteBFEPoolInstance(ctx context.Context, poolName, instance string,
+	opts ...RequestOption) (*Pool, error) {
+
+	return c.onePool(ctx, http.MethodDelete, pathf("/bfe-pools/%s/instances/%s", poolName, instance), nil, opts...)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ProductCreateParam is the param to create product
+type ProductCreateParam struct {
+	Name              string   `json:"name"`
+	Description       string   `json:"description"`
+	MailList          []string `json:"mail_list"`
+	PhoneList         []string `json:"phone_list"`
+	ContactPersonList []string `json:"contact_person_list"`
+}
+
+// ProductUpdateParam is the param to update product, nil field will not be changed
+type ProductUpdateParam struct {
+	Description       *string  `json:"description"`
+	MailList          []string `json:"mail_list"`
+	PhoneList         []string `json:"phone_list"`
+	ContactPersonList []string `json:"contact_person_list"`
+}
+
+// ProductListParam filters products by domain or cluster
+type ProductListParam struct {
+	PageParam
+
+	Domain  *string
+	Cluster *string
+}
+
+// Product is the product info
+type Product struct {
+	Id                int64    `json:"id,omitempty"`
+	Name              string   `json:"name"`
+	Description       string   `json:"description"`
+	MailList          []string `json:"mail_list"`
+	PhoneList         []string `json:"phone_list"`
+	ContactPersonList []string `json:"contact_person_list"`
+}
+
+// CreateProduct create a product
+func (c *Client) CreateProduct(ctx context.Context, param *ProductCreateParam) (*Product, error) {
+	data := &Product{}
+	if err := c.do(ctx, http.MethodPost, "/products", nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetProduct get product by name
+func (c *Client) GetProduct(ctx context.Context, name string) (*Product, error) {
+	data := &Product{}
+	if err := c.do(ctx, http.MethodGet, pathf("/products/%s", name), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListProducts list products, page is nil if PageSize of param not set
+func (c *Client) ListProducts(ctx context.Context, param *ProductListParam) ([]*Product, *Page, error) {
+	var list []*Product
+	if param == nil {
+		param = &ProductListParam{}
+	}
+
+	extra := url.Values{}
+	if param.Domain != nil {
+		extra.Set("domain", *param.Domain)
+	}
+	if param.Cluster != nil {
+		extra.Set("cluster", *param.Cluster)
+	}
+
+	page, err := c.list(ctx, "/products", &param.PageParam, extra, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, page, nil
+}
+
+// UpdateProduct update product
+func (c *Client) UpdateProduct(ctx context.Context, name string,
+	param *ProductUpdateParam) (*Product, error) {
+
+	data := &Product{}
+	if err := c.do(ctx, http.MethodPatch, pathf("/products/%s", name), nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteProduct delete product
+func (c *Client) DeleteProduct(ctx context.Context, name string) (*Product, error) {
+	data := &Product{}
+	if err := c.do(ctx, http.MethodDelete, pathf("/products/%s", name), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
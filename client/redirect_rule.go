@@ -0,0 +1,61 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// RedirectRule redirects requests matching Cond, Cmd is one of URL_SET, URL_PREFIX_ADD and SCHEME_SET
+type RedirectRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Cond        string `json:"cond"`
+	Cmd         string `json:"cmd"`
+	Param       string `json:"param"`
+	Status      int    `json:"status"`
+}
+
+// RedirectRules is the ordered redirect rules of product
+type RedirectRules struct {
+	Rules []*RedirectRule `json:"rules"`
+}
+
+func redirectRulePath(productName string) string {
+	return pathf("/products/%s/redirect-rules", productName)
+}
+
+// GetRedirectRules get redirect rules of product
+func (c *Client) GetRedirectRules(ctx context.Context, productName string) (*RedirectRules, error) {
+	data := &RedirectRules{}
+	if err := c.do(ctx, http.MethodGet, redirectRulePath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpsertRedirectRules replace all redirect rules of product
+func (c *Client) UpsertRedirectRules(ctx context.Context, productName string,
+	param *RedirectRules) (*RedirectRules, error) {
+
+	data := &RedirectRules{}
+	if err := c.do(ctx, http.MethodPut, redirectRulePath(productName), nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// RewriteAction is one action of rewrite rule, like HOST_SET and PATH_PREFIX_ADD
+type RewriteAction struct {
+	Cmd    *string  `json:"cmd"`
+	Params []string `json:"params"`
+}
+
+// RewriteRule rewrites requests matching Cond
+type RewriteRule struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Cond        string           `json:"cond"`
+	Actions     []*RewriteAction `json:"actions"`
+	Last        bool             `json:"last"`
+}
+
+// RewriteRules is the ordered rewrite rules of product
+type RewriteRules struct {
+	Rules []*RewriteRule `json:"rules"`
+}
+
+func rewriteRulePath(productName string) string {
+	return pathf("/products/%s/rewrite-rules", productName)
+}
+
+// GetRewriteRules get rewrite rules of product
+func (c *Client) GetRewriteRules(ctx context.Context, productName string) (*RewriteRules, error) {
+	data := &RewriteRules{}
+	if err := c.do(ctx, http.MethodGet, rewriteRulePath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpsertRewriteRules replace all rewrite rules of product
+func (c *Client) UpsertRewriteRules(ctx context.Context, productName string,
+	param *RewriteRules) (*RewriteRules, error) {
+
+	data := &RewriteRules{}
+	if err := c.do(ctx, http.MethodPut, rewriteRulePath(productName), nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// BasicRouteRule routes requests by host and path
+type BasicRouteRule struct {
+	HostNames   []string `json:"host_names"`
+	Paths       []string `json:"paths"`
+	ClusterName string   `json:"cluster_name"`
+	Description string   `json:"description"`
+}
+
+// AdvanceRouteRule routes requests matching condition expression
+type AdvanceRouteRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"`
+	ClusterName string `json:"cluster_name"`
+}
+
+// RouteRulesParam is the param to replace route rules of product
+type RouteRulesParam struct {
+	BasicRouteRules   []*BasicRouteRule   `json:"basic_forward_rules"`
+	AdvanceRouteRules []*AdvanceRouteRule `json:"forward_rules"`
+}
+
+// RouteRules is the route rules of product, Revision can be used by IfMatch
+type RouteRules struct {
+	BasicRouteRules   []*BasicRouteRule   `json:"basic_forward_rules"`
+	AdvanceRouteRules []*AdvanceRouteRule `json:"forward_rules"`
+
+	// RouteCasesCode is 0 if no route case, 1 if all route cases pass, 2 if some fail
+	RouteCasesCode   int          `json:"forward_cases_code,omitempty"`
+	FailedRouteCases []*RouteCase `json:"failed_forward_cases,omitempty"`
+
+	Revision string `json:"revision"`
+}
+
+// RouteDryRunParam describes a request to be routed, the rules stored are used if Rules not set
+type RouteDryRunParam struct {
+	URL      string            `json:"url"`
+	Method   string            `json:"method"`
+	Header   map[string]string `json:"header"`
+	Cookies  map[string]string `json:"cookies"`
+	ClientIP string            `json:"client_ip"`
+
+	Rules *RouteRulesParam `json:"rules"`
+}
+
+// RouteDryRunData is the result of dry run, with the rule matched
+type RouteDryRunData struct {
+	Matched     bool   `json:"matched"`
+	ClusterName string `json:"cluster_name"`
+
+	BasicRouteRule   *BasicRouteRule   `json:"basic_forward_rule"`
+	AdvanceRouteRule *AdvanceRouteRule `json:"forward_rule"`
+}
+
+// RouteCaseCreateParam is the param to create route case
+type RouteCaseCreateParam struct {
+	Description   *string           `json:"description"`
+	URL           *string           `json:"url"`
+	Method        *string           `json:"method"`
+	Header        map[string]string `json:"header"`
+	ExpectCluster *string           `json:"expect_cluster"`
+}
+
+// RouteCaseUpdateParam is the param to update route case, nil field will not be changed
+type RouteCaseUpdateParam struct {
+	Description   *string           `json:"description"`
+	URL           *string           `json:"url"`
+	Method        *string           `json:"method"`
+	Header        map[string]string `json:"header"`
+	ExpectCluster *string           `json:"expect_cluster"`
+}
+
+// RouteCase is the route case, ActualCluster and Pass are set when cases are run
+type RouteCase struct {
+	ID            int64             `json:"id"`
+	Description   string            `json:"description"`
+	URL           string            `json:"url"`
+	Method        string            `json:"method"`
+	Header        map[string]string `json:"header"`
+	ExpectCluster string            `json:"expect_cluster"`
+
+	ActualCluster *string `json:"actual_cluster,omitempty"`
+	Pass          *bool   `json:"pass,omitempty"`
+}
+
+func routePath(productName string) string {
+	return pathf("/products/%s/routes", productName)
+}
+
+// GetRouteRules get route rules of product, Revision of result can be used by IfMatch
+func (c *Client) GetRouteRules(ctx context.Context, productName string) (*RouteRules, error) {
+	data := &RouteRules{}
+	if err := c.do(ctx, http.MethodGet, routePath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpsertRouteRules replace route rules of product,
+// if rules are rejected because some route cases fail, the result listing them is returned with error
+func (c *Client) UpsertRouteRules(ctx context.Context, productName string, param *RouteRulesParam,
+	opts ...RequestOption) (*RouteRules, error) {
+
+	data := &RouteRules{}
+	if err := c.do(ctx, http.MethodPatch, routePath(productName), nil, param, data, opts...); err != nil {
+		if len(data.FailedRouteCases) > 0 {
+			return data, err
+		}
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DryRunRoute evaluate which cluster a request will be forwarded to
+func (c *Client) DryRunRoute(ctx context.Context, productName string, param *RouteDryRunParam) (*RouteDryRunData, error) {
+	data := &RouteDryRunData{}
+	if err := c.do(ctx, http.MethodPost, routePath(productName)+"/dry-run", nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CreateRouteCase create route test case of product
+func (c *Client) CreateRouteCase(ctx context.Context, productName string,
+	param *RouteCaseCreateParam) (*RouteCase, error) {
+
+	data := &RouteCase{}
+	if err := c.do(ctx, http.MethodPost, routePath(productName)+"/cases", nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetRouteCase get route test case of product, the case will be evaluated
+func (c *Client) GetRouteCase(ctx context.Context, productName string, id int64) (*RouteCase, error) {
+	data := &RouteCase{}
+	path := routePath(productName) + "/cases/" + strconv.FormatInt(id, 10)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListRouteCases list route test cases of product
+func (c *Client) ListRouteCases(ctx context.Context, productName string) ([]*RouteCase, error) {
+	var list []*RouteCase
+	if err := c.do(ctx, http.MethodGet, routePath(productName)+"/cases", nil, nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UpdateRouteCase update route test case of product
+func (c *Client) UpdateRouteCase(ctx context.Context, productName string, id int64,
+	param *RouteCaseUpdateParam) (*RouteCase, error) {
+
+	data := &RouteCase{}
+	path := routePath(productName) + "/cases/" + strconv.FormatInt(id, 10)
+	if err := c.do(ctx, http.MethodPatch, path, nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteRouteCase delete route test case of product
+func (c *Client) DeleteRouteCase(ctx context.Context, productName string, id int64) (*RouteCase, error) {
+	data := &RouteCase{}
+	path := routePath(productName) + "/cases/" + strconv.FormatInt(id, 10)
+	if err := c.do(ctx, http.MethodDelete, path, nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// SubClusterCreateParam is the param to create sub cluster,
+// instances of pool matching InstanceSelector are used with port named PortName
+type SubClusterCreateParam struct {
+	Name         *string `json:"name"`
+	InstancePool *string `json:"instance_pool"`
+	Description  *string `json:"description"`
+
+	PortName         *string           `json:"port_name"`
+	InstanceSelector map[string]string `json:"instance_selector"`
+}
+
+// SubClusterUpdateParam is the param to update sub cluster, nil field will not be changed
+type SubClusterUpdateParam struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+
+	PortName         *string           `json:"port_name"`
+	InstanceSelector map[string]string `json:"instance_selector"`
+}
+
+// SubCluster is the sub cluster info
+type SubCluster struct {
+	Name         string `json:"name"`
+	InstancePool string `json:"instance_pool"`
+	Description  string `json:"description"`
+	Ready        bool   `json:"ready"`
+	ProductName  string `json:"product_name,omitempty"`
+
+	PortName         string            `json:"port_name"`
+	InstanceSelector map[string]string `json:"instance_selector"`
+}
+
+func subClusterPath(productName string) string {
+	return pathf("/products/%s/sub-clusters", productName)
+}
+
+func (c *Client) oneSubCluster(ctx context.Context, method, path string, body interface{}) (*SubCluster, error) {
+	data := &SubCluster{}
+	if err := c.do(ctx, method, path, nil, body, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CreateSubCluster create sub cluster of product
+func (c *Client) CreateSubCluster(ctx context.Context, productName string,
+	param *SubClusterCreateParam) (*SubCluster, error) {
+
+	return c.oneSubCluster(ctx, http.MethodPost, subClusterPath(productName), param)
+}
+
+// GetSubCluster get sub cluster of product
+func (c *Client) GetSubCluster(ctx context.Context, productName, name string) (*SubCluster, error) {
+	return c.oneSubCluster(ctx, http.MethodGet, subClusterPath(productName)+pathf("/%s", name), nil)
+}
+
+// ListSubClusters list sub clusters of product
+func (c *Client) ListSubClusters(ctx context.Context, productName string,
+	param *PageParam) ([]*SubCluster, *Page, error) {
+
+	var list []*SubCluster
+	page, err := c.list(ctx, subClusterPath(productName), param, nil, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return list, page, nil
+}
+
+// UpdateSubCluster update sub cluster of product
+func (c *Client) UpdateSubCluster(ctx context.Context, productName, name string,
+	param *SubClusterUpdateParam) (*SubCluster, error) {
+
+	return c.oneSubCluster(ctx, http.MethodPatch, subClusterPath(productName)+pathf("/%s", name), param)
+}
+
+// DeleteSubCluster delete sub cluster of product
+func (c *Client) DeleteSubCluster(ctx context.Context, productName, name string) (*SubCluster, error) {
+	return c.oneSubCluster(ctx, http.MethodDelete, subClusterPath(productName)+pathf("/%s", name), nil)
+}
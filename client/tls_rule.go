@@ -0,0 +1,66 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// TLSRule is the tls setting of product, Grade is one of A+, A, B and C
+type TLSRule struct {
+	CertName      string   `json:"cert_name"`
+	Domains       []string `json:"domains"`
+	NextProtos    []string `json:"next_protos"`
+	Grade         string   `json:"grade"`
+	Chacha20      bool     `json:"chacha20"`
+	DynamicRecord bool     `json:"dynamic_record"`
+}
+
+func tlsRulePath(productName string) string {
+	return pathf("/products/%s/tls-rule", productName)
+}
+
+// GetTLSRule get tls rule of product
+func (c *Client) GetTLSRule(ctx context.Context, productName string) (*TLSRule, error) {
+	data := &TLSRule{}
+	if err := c.do(ctx, http.MethodGet, tlsRulePath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpsertTLSRule bind certificate to product and its domains
+func (c *Client) UpsertTLSRule(ctx context.Context, productName string,
+	param *TLSRule) (*TLSRule, error) {
+
+	data := &TLSRule{}
+	if err := c.do(ctx, http.MethodPut, tlsRulePath(productName), nil, param, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteTLSRule unbind certificate from product
+func (c *Client) DeleteTLSRule(ctx context.Context, productName string) (*TLSRule, error) {
+	data := &TLSRule{}
+	if err := c.do(ctx, http.MethodDelete, tlsRulePath(productName), nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
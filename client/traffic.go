@@ -0,0 +1,60 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// AutoScheduler is the setting of auto traffic scheduling
+type AutoScheduler struct {
+	MaxRegionLoad    float64          `json:"max_region_load"`
+	MaxBlackholeLoad float64          `json:"max_blackhole_load"`
+	BlackholeEnabled bool             `json:"blackhole_enabled"`
+	Capacity         map[string]int64 `json:"capacity"`
+}
+
+// Scheduler is the traffic scheduler of cluster, Scheduler is map of source idc => sub cluster => weight
+type Scheduler struct {
+	Cluster       string                    `json:"cluster"`
+	Scheduler     map[string]map[string]int `json:"scheduler,omitempty"`
+	AutoScheduler *AutoScheduler            `json:"auto_scheduler,omitempty"`
+}
+
+// GetScheduler get traffic scheduler of cluster
+func (c *Client) GetScheduler(ctx context.Context, productName, clusterName string) (*Scheduler, error) {
+	data := &Scheduler{}
+	path := pathf("/products/%s/clusters/%s/scheduler", productName, clusterName)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// UpdateScheduler set traffic scheduler of cluster manually,
+// scheduler is map of source idc => sub cluster => weight
+func (c *Client) UpdateScheduler(ctx context.Context, productName, clusterName string,
+	scheduler map[string]map[string]int) (*Scheduler, error) {
+
+	data := &Scheduler{}
+	path := pathf("/products/%s/clusters/%s/scheduler", productName, clusterName)
+	if err := c.do(ctx, http.MethodPatch, path, nil, scheduler, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/bfenetworks/api-server/client"
+)
+
+func loadBundle(cmd string, args []string) (*client.Bundle, bool, error) {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fileName := fs.String("f", "", "JSON or YAML file of bundle, - means stdin")
+	dryRun := fs.Bool("dry-run", false, "only show changes, same as diff")
+	fs.Parse(args)
+
+	if *fileName == "" {
+		return nil, false, fmt.Errorf("file must be set by -f")
+	}
+
+	content, err := readFile(*fileName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	bundle, err := client.ParseBundle(content)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return bundle, *dryRun, nil
+}
+
+func printChanges(changes []*client.Change) {
+	if len(changes) == 0 {
+		fmt.Println("no changes")
+		return
+	}
+
+	for _, ch := range changes {
+		fmt.Println(ch)
+	}
+}
+
+func diff(ctx context.Context, conf *Config, args []string) error {
+	bundle, _, err := loadBundle("diff", args)
+	if err != nil {
+		return err
+	}
+
+	changes, err := newClient(conf).PlanBundle(ctx, bundle)
+	if err != nil {
+		return err
+	}
+
+	printChanges(changes)
+	return nil
+}
+
+func apply(ctx context.Context, conf *Config, args []string) error {
+	bundle, dryRun, err := loadBundle("apply", args)
+	if err != nil {
+		return err
+	}
+
+	c := newClient(conf)
+	changes, err := c.PlanBundle(ctx, bundle)
+	if err != nil {
+		return err
+	}
+
+	printChanges(changes)
+	if dryRun || len(changes) == 0 {
+		return nil
+	}
+
+	// bundle is applied in one transaction by server, nothing is changed when it fails
+	applied, err := c.ApplyBundle(ctx, bundle)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d changes applied\n", len(applied))
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bfenetworks/api-server/client"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+)
+
+// exportFile describes where config exported by inner api is saved, paths are same as conf dir of BFE
+type exportFile struct {
+	name  string // path of inner api after /configs/
+	field string // field of exported data saved to file, whole data is saved if empty
+	file  string
+
+	render func(data []byte) ([]byte, error) // build content of file from exported data, saved as json if nil
+}
+
+var exportFiles = []*exportFile{
+	{name: "tls_conf/server_data_conf", field: "HostTable", file: "server_data_conf/host_rule.data"},
+	{name: "tls_conf/server_data_conf", field: "RouteTable", file: "server_data_conf/route_rule.data"},
+	{name: "tls_conf/server_data_conf", field: "ClusterConf", file: "server_data_conf/cluster_conf.data"},
+	{name: "gslb_data/cluster_table", file: "cluster_conf/cluster_table.data"},
+	{name: "gslb_data/gslb", file: "cluster_conf/gslb.data"},
+	{name: "health_check/active_health_check_conf", file: "cluster_conf/active_health_check_conf.data"},
+	{name: "protocol/server_cert_conf", file: "tls_conf/server_cert_conf.data"},
+	{name: "protocol/tls_rule_conf", file: "tls_conf/tls_rule_conf.data"},
+	{name: "mod_redirect/redirect_conf", file: "mod_redirect/redirect.data"},
+	{name: "mod_header/header_rule_conf", file: "mod_header/header_rule.data"},
+	{name: "mod_rewrite/rewrite_conf", file: "mod_rewrite/rewrite.data"},
+	{name: "mod_block/block_rules", file: "mod_block/block_rules.data"},
+	{name: "mod_block/ip_blocklist", file: "mod_block/ip_blocklist.data", render: ipBlocklistFile},
+}
+
+func export(ctx context.Context, conf *Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	outDir := fs.String("o", "", "dir to save configs")
+	bfeCluster := fs.String("bfe-cluster", "", "bfe cluster name, needed by gslb config")
+	fs.Parse(args)
+
+	if *outDir == "" {
+		return fmt.Errorf("dir must be set by -o")
+	}
+
+	c := newClient(conf)
+	exported := map[string]map[string]json.RawMessage{}
+	for _, ef := range exportFiles {
+		query := url.Values{}
+		if ef.name == "gslb_data/gslb" {
+			if *bfeCluster == "" {
+				fmt.Fprintf(os.Stderr, "skip %s, -bfe-cluster not set\n", ef.file)
+				continue
+			}
+			query.Set("bfe_cluster", *bfeCluster)
+		}
+
+		fields, ok := exported[ef.name]
+		if !ok {
+			data, err := c.Export(ctx, ef.name, query)
+			if err != nil {
+				return fmt.Errorf("export %s: %w", ef.name, err)
+			}
+			if err := json.Unmarshal(data, &fields); err != nil {
+				return fmt.Errorf("export %s: %w", ef.name, err)
+			}
+			exported[ef.name] = fields
+		}
+
+		var data interface{} = fields
+		if ef.field != "" {
+			data = fields[ef.field]
+		}
+		var err error
+		if ef.render != nil {
+			err = writeRendered(filepath.Join(*outDir, ef.file), data, ef.render)
+		} else {
+			err = writeJSON(filepath.Join(*outDir, ef.file), data)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println("saved", ef.file)
+	}
+
+	return exportCertificates(ctx, c, *outDir, exported["protocol/server_cert_conf"])
+}
+
+// exportCertificates save files of certificates and keys referenced by server_cert_conf
+func exportCertificates(ctx context.Context, c *client.Client, outDir string,
+	certConf map[string]json.RawMessage) error {
+
+	if len(certConf["Config"]) == 0 {
+		return nil
+	}
+
+	config := struct {
+		CertConf map[string]struct {
+			ServerCertFile string
+			ServerKeyFile  string
+		}
+	}{}
+	if err := json.Unmarshal(certConf["Config"], &config); err != nil {
+		return err
+	}
+
+	for _, one := range config.CertConf {
+		for _, path := range []string{one.ServerCertFile, one.ServerKeyFile} {
+			// exported path is like tls_conf_{version}/{product}/{file}, extra file is named tls_conf/{product}/{file}
+			i := strings.Index(path, "/")
+			if i == -1 {
+				return fmt.Errorf("bad path of certificate: %s", path)
+			}
+
+			content, err := c.ExportExtraFile(ctx, "tls_conf"+path[i:])
+			if err != nil {
+				return fmt.Errorf("export %s: %w", path, err)
+			}
+
+			if err := writeFile(filepath.Join(outDir, path), content); err != nil {
+				return err
+			}
+			fmt.Println("saved", path)
+		}
+	}
+
+	return nil
+}
+
+// ipBlocklistFile build ip_blocklist.data, which is text file whose first line is meta info
+func ipBlocklistFile(data []byte) ([]byte, error) {
+	conf := &imodule_conf.IPBlocklistConf{}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return nil, err
+	}
+
+	return conf.FileContent(), nil
+}
+
+func writeRendered(fileName string, data interface{}, render func([]byte) ([]byte, error)) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	content, err := render(raw)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(fileName, content)
+}
+
+func writeJSON(fileName string, data interface{}) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(fileName, content)
+}
+
+func writeFile(fileName string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, content, 0644)
+}
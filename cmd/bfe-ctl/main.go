@@ -0,0 +1,212 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// bfe-ctl is a command line tool to administrate api-server
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bfenetworks/api-server/client"
+	"github.com/bfenetworks/api-server/version"
+)
+
+const usage = `Usage: bfe-ctl [flags] <command> [args]
+
+Commands:
+  login -u user -p password                  login and save session key to config file
+  logout                                     destroy session key saved in config file
+  get <kind> [name] [-product p]             show one resource or list resources
+  create <kind> -f file [-product p]         create resource from JSON/YAML file
+  update <kind> <name> -f file [-product p]  update resource from JSON/YAML file
+  delete <kind> <name> [-product p]          delete resource
+  diff -f bundle                             show changes apply would make
+  apply -f bundle [-dry-run]                 make resources of product same as bundle
+  export -o dir [-bfe-cluster c]             fetch configs exported by inner api to dir
+
+Kinds:
+  ` + "%s" + `
+
+Flags:
+`
+
+var (
+	help     *bool   = flag.Bool("h", false, "to show help")
+	showVer  *bool   = flag.Bool("v", false, "to show version")
+	server   *string = flag.String("s", "", "address of api-server, default from env BFE_CTL_SERVER or config file")
+	token    *string = flag.String("token", "", "token used to authenticate, default from env BFE_CTL_TOKEN or config file")
+	confFile *string = flag.String("c", defaultConfFile(), "config file saving server and session key")
+)
+
+// Config is saved after login
+type Config struct {
+	Server     string `json:"server"`
+	SessionKey string `json:"session_key,omitempty"`
+}
+
+func defaultConfFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".bfe-ctl.json"
+	}
+
+	return filepath.Join(home, ".bfe-ctl.json")
+}
+
+func loadConfig() *Config {
+	conf := &Config{}
+	if content, err := ioutil.ReadFile(*confFile); err == nil {
+		json.Unmarshal(content, conf)
+	}
+
+	if v := os.Getenv("BFE_CTL_SERVER"); v != "" {
+		conf.Server = v
+	}
+	if *server != "" {
+		conf.Server = *server
+	}
+	if conf.Server == "" {
+		conf.Server = "http://127.0.0.1:8183"
+	}
+
+	return conf
+}
+
+func saveConfig(conf *Config) error {
+	content, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(*confFile, content, 0600)
+}
+
+func newClient(conf *Config) *client.Client {
+	t := *token
+	if t == "" {
+		t = os.Getenv("BFE_CTL_TOKEN")
+	}
+
+	switch {
+	case t != "":
+		return client.New(conf.Server, client.WithToken(t))
+	case conf.SessionKey != "":
+		return client.New(conf.Server, client.WithSessionKey(conf.SessionKey))
+	}
+
+	return client.New(conf.Server)
+}
+
+type command func(ctx context.Context, conf *Config, args []string) error
+
+var commands = map[string]command{
+	"login":  login,
+	"logout": logout,
+	"get":    get,
+	"create": create,
+	"update": update,
+	"delete": remove,
+	"diff":   diff,
+	"apply":  apply,
+	"export": export,
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usage, strings.Join(kindNames(), ", "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *help {
+		flag.Usage()
+		return
+	}
+	if *showVer {
+		fmt.Printf("version %s\n", version.Version)
+		return
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", args[0])
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := cmd(context.Background(), loadConfig(), args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func printJSON(v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(content))
+	return nil
+}
+
+func login(ctx context.Context, conf *Config, args []string) error {
+	fs := flag.NewFlagSet("login", flag.ExitOnError)
+	userName := fs.String("u", "", "user name")
+	password := fs.String("p", "", "password")
+	fs.Parse(args)
+
+	if *userName == "" || *password == "" {
+		return fmt.Errorf("user name and password must be set")
+	}
+
+	user, err := client.New(conf.Server).Login(ctx, *userName, *password)
+	if err != nil {
+		return err
+	}
+
+	conf.SessionKey = user.SessionKey
+	if err := saveConfig(conf); err != nil {
+		return err
+	}
+
+	fmt.Printf("login succeed, session key saved to %s\n", *confFile)
+	return nil
+}
+
+func logout(ctx context.Context, conf *Config, args []string) error {
+	if conf.SessionKey == "" {
+		return nil
+	}
+
+	if err := newClient(conf).Logout(ctx, conf.SessionKey); err != nil && !client.IsNotFound(err) {
+		return err
+	}
+
+	conf.SessionKey = ""
+	return saveConfig(conf)
+}
@@ -0,0 +1,444 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bfenetworks/api-server/client"
+)
+
+// target is the resource a command operates on
+type target struct {
+	product string
+	name    string
+	content []byte
+	opts    []client.RequestOption
+}
+
+type operation func(ctx context.Context, c *client.Client, t *target) (interface{}, error)
+
+type kind struct {
+	name string
+	// resource belongs to product, -product must be set
+	inProduct bool
+
+	list   operation
+	get    operation
+	create operation
+	update operation
+	delete operation
+}
+
+var kinds = []*kind{
+	{
+		name: "products",
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			list, _, err := c.ListProducts(ctx, nil)
+			return list, err
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetProduct(ctx, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.ProductCreateParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.CreateProduct(ctx, param)
+		},
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.ProductUpdateParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.UpdateProduct(ctx, t.name, param)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DeleteProduct(ctx, t.name)
+		},
+	},
+	{
+		name:      "pools",
+		inProduct: true,
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			list, _, err := c.ListProductPools(ctx, t.product, nil)
+			return list, err
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetProductPool(ctx, t.product, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.PoolParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.CreateProductPool(ctx, t.product, param)
+		},
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.PoolParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.UpdateProductPool(ctx, t.product, t.name, param)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DeleteProductPool(ctx, t.product, t.name, t.opts...)
+		},
+	},
+	{
+		name: "bfe-pools",
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.ListBFEPools(ctx)
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetBFEPool(ctx, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.PoolParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.CreateBFEPool(ctx, param)
+		},
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.PoolParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.UpdateBFEPool(ctx, t.name, param)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DeleteBFEPool(ctx, t.name, t.opts...)
+		},
+	},
+	{
+		name:      "sub-clusters",
+		inProduct: true,
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			list, _, err := c.ListSubClusters(ctx, t.product, nil)
+			return list, err
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetSubCluster(ctx, t.product, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.SubClusterCreateParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.CreateSubCluster(ctx, t.product, param)
+		},
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.SubClusterUpdateParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.UpdateSubCluster(ctx, t.product, t.name, param)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DeleteSubCluster(ctx, t.product, t.name)
+		},
+	},
+	{
+		name:      "clusters",
+		inProduct: true,
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.ListClusters(ctx, t.product)
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetCluster(ctx, t.product, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.ClusterParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.CreateCluster(ctx, t.product, param)
+		},
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.ClusterParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.UpdateCluster(ctx, t.product, t.name, param, t.opts...)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DeleteCluster(ctx, t.product, t.name, t.opts...)
+		},
+	},
+	{
+		name:      "scheduler",
+		inProduct: true,
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetScheduler(ctx, t.product, t.name)
+		},
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := map[string]map[string]int{}
+			if err := decodeContent(t.content, &param); err != nil {
+				return nil, err
+			}
+			return c.UpdateScheduler(ctx, t.product, t.name, param)
+		},
+	},
+	{
+		name:      "routes",
+		inProduct: true,
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetRouteRules(ctx, t.product)
+		},
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.RouteRulesParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.UpsertRouteRules(ctx, t.product, param, t.opts...)
+		},
+	},
+	{
+		name:      "domains",
+		inProduct: true,
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			list, _, err := c.ListDomains(ctx, t.product, nil)
+			return list, err
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DomainUseStatus(ctx, t.product, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.CreateDomain(ctx, t.product, t.name)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DeleteDomain(ctx, t.product, t.name)
+		},
+	},
+	{
+		name: "certificates",
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			list, _, err := c.ListCertificates(ctx, nil)
+			return list, err
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.CertificateCreateParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.CreateCertificate(ctx, param)
+		},
+		// update makes the certificate default
+		update: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.SetDefaultCertificate(ctx, t.name)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.DeleteCertificate(ctx, t.name)
+		},
+	},
+	{
+		name: "tokens",
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.ListTokens(ctx)
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetToken(ctx, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.TokenCreateParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return c.CreateToken(ctx, param)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return nil, c.DeleteToken(ctx, t.name)
+		},
+	},
+	{
+		name: "users",
+		list: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			list, _, err := c.ListUsers(ctx, nil)
+			return list, err
+		},
+		get: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return c.GetUser(ctx, t.name)
+		},
+		create: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			param := &client.UserCreateParam{}
+			if err := decodeContent(t.content, param); err != nil {
+				return nil, err
+			}
+			return nil, c.CreateUser(ctx, param)
+		},
+		delete: func(ctx context.Context, c *client.Client, t *target) (interface{}, error) {
+			return nil, c.DeleteUser(ctx, t.name)
+		},
+	},
+}
+
+func kindNames() []string {
+	names := make([]string, len(kinds))
+	for i, k := range kinds {
+		names[i] = k.name
+	}
+
+	return names
+}
+
+func findKind(name string) (*kind, error) {
+	for _, k := range kinds {
+		// singular form is accepted too, like cluster
+		if k.name == name || k.name == name+"s" {
+			return k, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown kind: %s", name)
+}
+
+// decodeContent decode JSON or YAML content into v
+func decodeContent(content []byte, v interface{}) error {
+	if len(content) == 0 {
+		return fmt.Errorf("file must be set by -f")
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return err
+	}
+
+	bs, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bs, v)
+}
+
+func readFile(fileName string) ([]byte, error) {
+	if fileName == "" {
+		return nil, nil
+	}
+	if fileName == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+
+	return ioutil.ReadFile(fileName)
+}
+
+// parseTarget parse args like `clusters demo_cluster -product demo -f cluster.yaml`
+func parseTarget(cmd string, args []string, withName bool) (*kind, *target, error) {
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("kind must be set, one of %v", kindNames())
+	}
+
+	k, err := findKind(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	args = args[1:]
+
+	t := &target{}
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		t.name = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	productName := fs.String("product", "", "product name")
+	fileName := fs.String("f", "", "JSON or YAML file of param, - means stdin")
+	revision := fs.String("revision", "", "revision got before, request fails if resource changed since then")
+	fs.Parse(args)
+
+	if k.inProduct && *productName == "" {
+		return nil, nil, fmt.Errorf("-product must be set for %s", k.name)
+	}
+	if withName && t.name == "" {
+		return nil, nil, fmt.Errorf("name of %s must be set", k.name)
+	}
+
+	t.product = *productName
+	if *revision != "" {
+		t.opts = append(t.opts, client.IfMatch(*revision))
+	}
+	if t.content, err = readFile(*fileName); err != nil {
+		return nil, nil, err
+	}
+
+	return k, t, nil
+}
+
+func run(ctx context.Context, conf *Config, cmd string, op func(k *kind) operation, args []string,
+	withName bool) error {
+
+	k, t, err := parseTarget(cmd, args, withName)
+	if err != nil {
+		return err
+	}
+
+	f := op(k)
+	if f == nil {
+		return fmt.Errorf("%s is not supported by %s", cmd, k.name)
+	}
+
+	data, err := f(ctx, newClient(conf), t)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	return printJSON(data)
+}
+
+func get(ctx context.Context, conf *Config, args []string) error {
+	k, t, err := parseTarget("get", args, false)
+	if err != nil {
+		return err
+	}
+
+	f := k.get
+	if t.name == "" || k.get == nil {
+		f = k.list
+	}
+	if f == nil {
+		return fmt.Errorf("name of %s must be set", k.name)
+	}
+
+	data, err := f(ctx, newClient(conf), t)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(data)
+}
+
+func create(ctx context.Context, conf *Config, args []string) error {
+	return run(ctx, conf, "create", func(k *kind) operation { return k.create }, args, false)
+}
+
+func update(ctx context.Context, conf *Config, args []string) error {
+	return run(ctx, conf, "update", func(k *kind) operation { return k.update }, args, false)
+}
+
+func remove(ctx context.Context, conf *Config, args []string) error {
+	return run(ctx, conf, "delete", func(k *kind) operation { return k.delete }, args, true)
+}
@@ -44,6 +44,9 @@ CREATE TABLE `domains` (
   `type` int(11) NOT NULL,
   `using_advanced_redirect` tinyint(1) NOT NULL DEFAULT 0,
   `using_advanced_hsts` tinyint(1) NOT NULL DEFAULT 0,
+  `hsts_max_age` bigint(20) NOT NULL DEFAULT 0,
+  `hsts_include_subdomains` tinyint(1) NOT NULL DEFAULT 0,
+  `hsts_preload` tinyint(1) NOT NULL DEFAULT 0,
   `created_at` datetime NOT NULL,
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
@@ -86,6 +89,9 @@ CREATE TABLE `clusters` (
   `cancel_on_client_close` tinyint(1) NOT NULL DEFAULT '0',
   `failure_status` tinyint(1) NOT NULL DEFAULT '0',
   `max_conns_per_host` int(11) NOT NULL DEFAULT '0',
+  `protocol` varchar(16) NOT NULL DEFAULT 'http',
+  `balance_mode` varchar(16) NOT NULL DEFAULT 'WRR',
+  `outlier_detection_code` varchar(255) NOT NULL DEFAULT '',
   `created_at` datetime NOT NULL,
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
@@ -114,6 +120,8 @@ CREATE TABLE `sub_clusters` (
   `description` varchar(1024) CHARACTER SET utf8 COLLATE utf8_unicode_ci NOT NULL DEFAULT "no desc",
   `bns_name_id` bigint(20) NOT NULL,
   `enabled` tinyint(1) NOT NULL DEFAULT '1',
+  `port_name` varchar(255) NOT NULL DEFAULT '',
+  `instance_selector` varchar(1024) NOT NULL DEFAULT '',
   `created_at` datetime NOT NULL,
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
@@ -188,6 +196,114 @@ CREATE TABLE `route_cases` (
   INDEX `product_id` (`product_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- create active_health_checks
+DROP TABLE IF EXISTS `active_health_checks`;
+CREATE TABLE `active_health_checks` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `cluster_id` bigint(20) NOT NULL,
+  `check_schema` varchar(16) NOT NULL,
+  `check_interval` int(11) NOT NULL,
+  `check_timeout` int(11) NOT NULL,
+  `succ_num` int(11) NOT NULL,
+  `fail_num` int(11) NOT NULL,
+  `host` varchar(255) NOT NULL DEFAULT '',
+  `uri` varchar(1024) NOT NULL DEFAULT '',
+  `status_code` int(11) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `cluster_id_uni` (`cluster_id`),
+  INDEX `product_id` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create mod_header_rules
+DROP TABLE IF EXISTS `mod_header_rules`;
+CREATE TABLE `mod_header_rules` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `actions` text NOT NULL,
+  `is_last` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_name` (`product_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create mod_rewrite_rules
+DROP TABLE IF EXISTS `mod_rewrite_rules`;
+CREATE TABLE `mod_rewrite_rules` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `rule_index` int(11) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `actions` text NOT NULL,
+  `is_last` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_index` (`product_id`, `rule_index`),
+  UNIQUE KEY `product_name` (`product_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create mod_redirect_rules
+DROP TABLE IF EXISTS `mod_redirect_rules`;
+CREATE TABLE `mod_redirect_rules` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `rule_index` int(11) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `cmd` varchar(32) NOT NULL,
+  `param` varchar(4096) NOT NULL,
+  `status` int(11) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_index` (`product_id`, `rule_index`),
+  UNIQUE KEY `product_name` (`product_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create mod_block_rules
+DROP TABLE IF EXISTS `mod_block_rules`;
+CREATE TABLE `mod_block_rules` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `rule_index` int(11) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `action` varchar(32) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_index` (`product_id`, `rule_index`),
+  UNIQUE KEY `product_name` (`product_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create tls_rules
+DROP TABLE IF EXISTS `tls_rules`;
+CREATE TABLE `tls_rules` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `product_id` bigint(20) NOT NULL,
+  `cert_name` varchar(255) NOT NULL,
+  `hosts` text NOT NULL,
+  `next_protos` varchar(1024) NOT NULL DEFAULT '[]',
+  `grade` varchar(8) NOT NULL DEFAULT 'C',
+  `chacha20` tinyint(1) NOT NULL DEFAULT '0',
+  `dynamic_record` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `product_id` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- create certificates
 DROP TABLE IF EXISTS `certificates`;
 CREATE TABLE `certificates` (
@@ -234,6 +350,31 @@ CREATE TABLE `config_versions` (
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+-- create config_snapshots
+DROP TABLE IF EXISTS `config_snapshots`;
+CREATE TABLE `config_snapshots` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `version` varchar(255) NOT NULL,
+  `data` mediumblob NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name_version` (`name`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- create config_pins
+DROP TABLE IF EXISTS `config_pins`;
+CREATE TABLE `config_pins` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `version` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 
 -- create users
 DROP TABLE IF EXISTS `users`;
@@ -262,6 +403,26 @@ CREATE TABLE `user_products` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 
+-- create audits
+DROP TABLE IF EXISTS `audits`;
+CREATE TABLE `audits` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `visitor` varchar(255) NOT NULL DEFAULT '',
+  `product_id` bigint(20) NOT NULL DEFAULT '0',
+  `product_name` varchar(255) NOT NULL DEFAULT '',
+  `resource_type` varchar(255) NOT NULL,
+  `resource_name` varchar(255) NOT NULL DEFAULT '',
+  `action` varchar(32) NOT NULL,
+  `before_data` mediumtext NOT NULL,
+  `after_data` mediumtext NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  INDEX `product_id` (`product_id`),
+  INDEX `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+
 insert into users (id, name, password, scopes, created_at) values(1, 'admin', 'admin', 'System', now());
 insert into products (id, name, `description`,                              mail_list,       contact_person, created_at) values
                      (1, 'BFE', 'Build-in Product, User by System Manager', 'bfe@cncf.com', 'bfe',          now());
\ No newline at end of file
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package endpointtest runs the real router of api-server on in-memory storage,
+// for tests of clients calling open api.
+package endpointtest
+
+import (
+	"net/http/httptest"
+
+	"github.com/baidu/go-lib/log/log4go"
+	"github.com/codegangsta/negroni"
+	"github.com/gorilla/mux"
+
+	"github.com/bfenetworks/api-server/endpoints"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/stateful/container/memory"
+)
+
+// AdminUser and AdminPassword is the build-in user of a new server
+const (
+	AdminUser     = "admin"
+	AdminPassword = "admin"
+)
+
+// NewServer start a server with empty in-memory storage, caller should Close it.
+// Storagers and managers of container are replaced, so servers must not run concurrently.
+func NewServer() *httptest.Server {
+	stateful.DefaultConfig = &stateful.Config{
+		RunTime: stateful.RunTimeConfig{
+			SessionExpireInDay: 1,
+			PasswordPolicy: stateful.PasswordPolicyConfig{
+				MinLength: 1,
+			},
+			ExportCache: stateful.ExportCacheConfig{
+				Disable: true,
+			},
+		},
+		Vars: map[string]string{},
+	}
+	stateful.AccessLogger = log4go.NewDefaultLogger(log4go.CRITICAL)
+
+	memory.Init()
+
+	n := negroni.New()
+	router := mux.NewRouter()
+	endpoints.RegisterRouters(router)
+	n.UseHandler(router)
+
+	return httptest.NewServer(n)
+}
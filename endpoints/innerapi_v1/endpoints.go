@@ -19,6 +19,8 @@ import (
 
 	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/extra_file"
 	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/gslb_data"
+	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/health_check"
+	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/module_conf"
 	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/protocol"
 	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/server_data"
 	"github.com/bfenetworks/api-server/endpoints/middleware"
@@ -31,7 +33,14 @@ func endpoints() []*xreq.Endpoint {
 		gslb_data.ExportGSLBEndpoint,
 		gslb_data.ExportClusterTableEndpoint,
 		protocol.ServertCertExportEndpoint,
+		protocol.TLSRuleExportEndpoint,
 		extra_file.ExportExtraFileEndpoint,
+		health_check.ExportActiveHealthCheckEndpoint,
+		module_conf.ExportRedirectEndpoint,
+		module_conf.ExportHeaderEndpoint,
+		module_conf.ExportRewriteEndpoint,
+		module_conf.ExportBlockRuleEndpoint,
+		module_conf.ExportIPBlocklistEndpoint,
 	}
 }
 
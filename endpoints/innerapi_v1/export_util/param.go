@@ -16,12 +16,21 @@ package export_util
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/bfenetworks/api-server/lib/xreq"
 )
 
 type ExportParam struct {
 	Version string `form:"version"`
+
+	// Wait seconds to hold the request until config changed from Version, 0 means return immediately
+	Wait int `form:"wait" validate:"min=0,max=300"`
+}
+
+// WaitDuration return how long the request can be held
+func (param *ExportParam) WaitDuration() time.Duration {
+	return time.Duration(param.Wait) * time.Second
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
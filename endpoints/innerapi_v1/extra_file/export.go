@@ -20,6 +20,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/export_util"
 	"github.com/bfenetworks/api-server/lib/xerror"
 	"github.com/bfenetworks/api-server/lib/xreq"
 	"github.com/bfenetworks/api-server/model/iauth"
@@ -36,8 +37,9 @@ var ExportExtraFileEndpoint = &xreq.Endpoint{
 	Authorizer: iauth.FA(iauth.FeatureExtraFile, iauth.ActionExport),
 }
 
-func ExportExtraFileActionProcess(req *http.Request, fileName string) ([]byte, error) {
-	extraFile, err := container.ExtraFileManager.FetchExtraFile(req.Context(), fileName)
+// ExportExtraFileActionProcess version of extra file is hex of its md5
+func ExportExtraFileActionProcess(req *http.Request, fileName string, param *export_util.ExportParam) ([]byte, error) {
+	extraFile, err := container.ExtraFileManager.WaitExtraFile(req.Context(), fileName, param.Version, param.WaitDuration())
 	if err != nil {
 		return nil, err
 	}
@@ -54,5 +56,10 @@ var _ xreq.Handler = ExportExtraFileAction
 // ExportExtraFileAction action
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 func ExportExtraFileAction(req *http.Request) (interface{}, error) {
-	return ExportExtraFileActionProcess(req, strings.SplitN(req.URL.Path, "extra_files/", 2)[1])
+	param, err := export_util.NewExportFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExportExtraFileActionProcess(req, strings.SplitN(req.URL.Path, "extra_files/", 2)[1], param)
 }
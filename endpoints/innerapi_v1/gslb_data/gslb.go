@@ -17,6 +17,7 @@ package gslb_data
 import (
 	"net/http"
 
+	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/export_util"
 	"github.com/bfenetworks/api-server/lib/xreq"
 	"github.com/bfenetworks/api-server/model/iauth"
 	"github.com/bfenetworks/api-server/model/icluster_conf"
@@ -24,7 +25,8 @@ import (
 )
 
 type GSLBExportParam struct {
-	Version    string `form:"version"`
+	export_util.ExportParam
+
 	BFECluster string `form:"bfe_cluster" validate:"required,min=1"`
 }
 
@@ -49,7 +51,7 @@ var ExportGSLBEndpoint = &xreq.Endpoint{
 }
 
 func ExportGSLBActionProcess(req *http.Request, param *GSLBExportParam) (*icluster_conf.GSLBConf, error) {
-	return container.ClusterManager.ExportGSLB(req.Context(), param.Version, param.BFECluster)
+	return container.ClusterManager.ExportGSLB(req.Context(), param.Version, param.BFECluster, param.WaitDuration())
 }
 
 var _ xreq.Handler = ExportGSLBAction
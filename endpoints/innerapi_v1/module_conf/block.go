@@ -0,0 +1,78 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/export_util"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// ExportBlockRuleRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ExportBlockRuleEndpoint = &xreq.Endpoint{
+	Path:       "/configs/mod_block/block_rules",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ExportBlockRuleAction),
+	Authorizer: iauth.FA(iauth.FeatureBlock, iauth.ActionExport),
+}
+
+func ExportBlockRuleActionProcess(req *http.Request, param *export_util.ExportParam) (*imodule_conf.BlockRuleConfData, error) {
+	return container.BlockRuleManager.ExportBlockRule(req.Context(), param.Version, param.WaitDuration())
+}
+
+var _ xreq.Handler = ExportBlockRuleAction
+
+// ExportBlockRuleAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ExportBlockRuleAction(req *http.Request) (interface{}, error) {
+	param, err := export_util.NewExportFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExportBlockRuleActionProcess(req, param)
+}
+
+// ExportIPBlocklistRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ExportIPBlocklistEndpoint = &xreq.Endpoint{
+	Path:       "/configs/mod_block/ip_blocklist",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ExportIPBlocklistAction),
+	Authorizer: iauth.FA(iauth.FeatureBlock, iauth.ActionExport),
+}
+
+// ExportIPBlocklistActionProcess items are lines of ip_blocklist.data, expired entries are pruned
+func ExportIPBlocklistActionProcess(req *http.Request, param *export_util.ExportParam) (*imodule_conf.IPBlocklistConf, error) {
+	return container.IPBlocklistManager.ExportIPBlocklist(req.Context(), param.Version, param.WaitDuration())
+}
+
+var _ xreq.Handler = ExportIPBlocklistAction
+
+// ExportIPBlocklistAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ExportIPBlocklistAction(req *http.Request) (interface{}, error) {
+	param, err := export_util.NewExportFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExportIPBlocklistActionProcess(req, param)
+}
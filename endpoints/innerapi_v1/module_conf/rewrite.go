@@ -0,0 +1,51 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/endpoints/innerapi_v1/export_util"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// ExportRewriteRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ExportRewriteEndpoint = &xreq.Endpoint{
+	Path:       "/configs/mod_rewrite/rewrite_conf",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ExportRewriteAction),
+	Authorizer: iauth.FA(iauth.FeatureRewriteRule, iauth.ActionExport),
+}
+
+func ExportRewriteActionProcess(req *http.Request, param *export_util.ExportParam) (*imodule_conf.RewriteConf, error) {
+	return container.RewriteRuleManager.ExportRewrite(req.Context(), param.Version, param.WaitDuration())
+}
+
+var _ xreq.Handler = ExportRewriteAction
+
+// ExportRewriteAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ExportRewriteAction(req *http.Request) (interface{}, error) {
+	param, err := export_util.NewExportFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExportRewriteActionProcess(req, param)
+}
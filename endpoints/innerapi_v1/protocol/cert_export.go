@@ -22,6 +22,7 @@ import (
 	"github.com/bfenetworks/api-server/model/iauth"
 	"github.com/bfenetworks/api-server/model/iprotocol"
 	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/bfe/bfe_config/bfe_tls_conf/tls_rule_conf"
 )
 
 // ExportRoute route
@@ -33,8 +34,16 @@ var ServertCertExportEndpoint = &xreq.Endpoint{
 	Authorizer: iauth.FA(iauth.FeatureCert, iauth.ActionExport),
 }
 
+// exportActionProcess tls rules are exported by TLSRuleExportEndpoint, server_cert_conf.data keeps format of BFE
 func exportActionProcess(req *http.Request, param *export_util.ExportParam) (*iprotocol.ServerCertConf, error) {
-	return container.CertificateManager.ExportServerCert(req.Context(), param.Version)
+	conf, err := container.CertificateManager.ExportServerCert(req.Context(), param.Version, param.WaitDuration())
+	if err != nil || conf == nil {
+		return nil, err
+	}
+
+	return &iprotocol.ServerCertConf{
+		BfeServerCertConf: conf.BfeServerCertConf,
+	}, nil
 }
 
 var _ xreq.Handler = ServerCertExportAction
@@ -49,3 +58,42 @@ func ServerCertExportAction(req *http.Request) (interface{}, error) {
 
 	return exportActionProcess(req, param)
 }
+
+// TLSRuleExportRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var TLSRuleExportEndpoint = &xreq.Endpoint{
+	Path:       "/configs/protocol/tls_rule_conf",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(TLSRuleExportAction),
+	Authorizer: iauth.FA(iauth.FeatureCert, iauth.ActionExport),
+}
+
+// tlsRuleExportActionProcess tls_rule_conf.data is exported with server_cert_conf.data, they share the same version
+func tlsRuleExportActionProcess(req *http.Request, param *export_util.ExportParam) (*tls_rule_conf.BfeTlsRuleConf, error) {
+	conf, err := container.CertificateManager.ExportServerCert(req.Context(), param.Version, param.WaitDuration())
+	if err != nil || conf == nil {
+		return nil, err
+	}
+
+	if conf.TlsRuleConf == nil {
+		return &tls_rule_conf.BfeTlsRuleConf{
+			Version: conf.Version,
+			Config:  tls_rule_conf.TlsRuleMap{},
+		}, nil
+	}
+
+	return conf.TlsRuleConf, nil
+}
+
+var _ xreq.Handler = TLSRuleExportAction
+
+// TLSRuleExportAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func TLSRuleExportAction(req *http.Request) (interface{}, error) {
+	param, err := export_util.NewExportFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return tlsRuleExportActionProcess(req, param)
+}
@@ -53,8 +53,12 @@ func ProductProbeAction(req *http.Request) (*http.Request, error) {
 		return nil, err
 	}
 
+	// endpoints which create product can get its name, others fail when getting the product
+	if len(products) == 0 && param.ProductID == nil {
+		return req.WithContext(ibasic.NewMissingProductContext(req.Context(), *param.ProductName)), nil
+	}
 	if len(products) != 1 {
-		return nil, xerror.WrapParamErrorWithMsg("Product Not Exist")
+		return nil, xerror.WrapRecordNotExist("Product")
 	}
 
 	return req.WithContext(ibasic.NewProductContext(req.Context(), products[0])), nil
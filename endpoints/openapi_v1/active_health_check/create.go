@@ -0,0 +1,99 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package active_health_check
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// CreateParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type CreateParam struct {
+	ClusterName *string `uri:"cluster_name" validate:"required,min=1"`
+
+	Schema     *string `json:"schema" validate:"required,oneof=http tcp"`
+	Interval   *int32  `json:"interval" validate:"required,min=1"`
+	Timeout    *int32  `json:"timeout" validate:"required,min=1"`
+	SuccNum    *int32  `json:"succ_num" validate:"required,min=1"`
+	FailNum    *int32  `json:"fail_num" validate:"required,min=1"`
+	Host       *string `json:"host" validate:"omitempty,min=1"`
+	Uri        *string `json:"uri" validate:"omitempty,min=1,startswith=/"`
+	StatusCode *int32  `json:"status_code" validate:"omitempty,min=0,max=599"`
+}
+
+func (param *CreateParam) toModel() *icluster_conf.ActiveHealthCheckParam {
+	return &icluster_conf.ActiveHealthCheckParam{
+		Schema:     param.Schema,
+		Interval:   param.Interval,
+		Timeout:    param.Timeout,
+		SuccNum:    param.SuccNum,
+		FailNum:    param.FailNum,
+		Host:       param.Host,
+		Uri:        param.Uri,
+		StatusCode: param.StatusCode,
+	}
+}
+
+// CreateRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var CreateEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/clusters/{cluster_name}/active-health-check",
+	Method:     http.MethodPost,
+	Handler:    xreq.Convert(CreateAction),
+	Authorizer: iauth.FAP(iauth.FeatureActiveHealthCheck, iauth.ActionCreate),
+	Param:      &CreateParam{},
+	Data:       &ActiveHealthCheckData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newCreateParam(req *http.Request) (*CreateParam, error) {
+	param := &CreateParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+func createActionProcess(req *http.Request, param *CreateParam) (*ActiveHealthCheckData, error) {
+	product, cluster, err := mustGetCluster(req, param.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = container.ActiveHealthCheckManager.CreateActiveHealthCheck(req.Context(), product, cluster, param.toModel())
+	if err != nil {
+		return nil, err
+	}
+
+	return oneActionProcess(req, &OneParam{
+		ClusterName: param.ClusterName,
+	})
+}
+
+var _ xreq.Handler = CreateAction
+
+// CreateAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func CreateAction(req *http.Request) (interface{}, error) {
+	param, err := newCreateParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return createActionProcess(req, param)
+}
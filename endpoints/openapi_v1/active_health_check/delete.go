@@ -0,0 +1,60 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package active_health_check
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// DeleteRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var DeleteEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/clusters/{cluster_name}/active-health-check",
+	Method:     http.MethodDelete,
+	Handler:    xreq.Convert(DeleteAction),
+	Authorizer: iauth.FAP(iauth.FeatureActiveHealthCheck, iauth.ActionDelete),
+	Param:      &OneParam{},
+	Data:       &ActiveHealthCheckData{},
+}
+
+func deleteActionProcess(req *http.Request, param *OneParam) (*ActiveHealthCheckData, error) {
+	product, cluster, one, err := mustGetActiveHealthCheck(req, param.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = container.ActiveHealthCheckManager.DeleteActiveHealthCheck(req.Context(), product, cluster, one); err != nil {
+		return nil, err
+	}
+
+	return newActiveHealthCheckData(cluster, one), nil
+}
+
+var _ xreq.Handler = DeleteAction
+
+// DeleteAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func DeleteAction(req *http.Request) (interface{}, error) {
+	param, err := newOneParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteActionProcess(req, param)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package active_health_check
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// OneParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type OneParam struct {
+	ClusterName *string `uri:"cluster_name" validate:"required,min=1"`
+}
+
+// ActiveHealthCheckData Response Data
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type ActiveHealthCheckData struct {
+	ClusterName string `json:"cluster_name"`
+	Schema      string `json:"schema"`
+	Interval    int32  `json:"interval"`
+	Timeout     int32  `json:"timeout"`
+	SuccNum     int32  `json:"succ_num"`
+	FailNum     int32  `json:"fail_num"`
+	Host        string `json:"host"`
+	Uri         string `json:"uri"`
+	StatusCode  int32  `json:"status_code"`
+}
+
+func newActiveHealthCheckData(cluster *icluster_conf.Cluster, one *icluster_conf.ActiveHealthCheck) *ActiveHealthCheckData {
+	return &ActiveHealthCheckData{
+		ClusterName: cluster.Name,
+		Schema:      one.Schema,
+		Interval:    one.Interval,
+		Timeout:     one.Timeout,
+		SuccNum:     one.SuccNum,
+		FailNum:     one.FailNum,
+		Host:        one.Host,
+		Uri:         one.Uri,
+		StatusCode:  one.StatusCode,
+	}
+}
+
+// mustGetCluster return cluster of product in uri
+func mustGetCluster(req *http.Request, clusterName *string) (*ibasic.Product, *icluster_conf.Cluster, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cluster, err := container.ClusterManager.FetchCluster(req.Context(), &icluster_conf.ClusterFilter{
+		Name:    clusterName,
+		Product: product,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if cluster == nil {
+		return nil, nil, xerror.WrapRecordNotExist("Cluster")
+	}
+
+	return product, cluster, nil
+}
+
+// mustGetActiveHealthCheck return active health check of cluster in uri
+func mustGetActiveHealthCheck(req *http.Request, clusterName *string) (*ibasic.Product, *icluster_conf.Cluster,
+	*icluster_conf.ActiveHealthCheck, error) {
+
+	product, cluster, err := mustGetCluster(req, clusterName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	one, err := container.ActiveHealthCheckManager.FetchClusterActiveHealthCheck(req.Context(), cluster)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if one == nil {
+		return nil, nil, nil, xerror.WrapRecordNotExist("Active Health Check")
+	}
+
+	return product, cluster, one, nil
+}
+
+// OneRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var OneEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/clusters/{cluster_name}/active-health-check",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(OneAction),
+	Authorizer: iauth.FAP(iauth.FeatureActiveHealthCheck, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &ActiveHealthCheckData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newOneParam(req *http.Request) (*OneParam, error) {
+	param := &OneParam{}
+	err := xreq.BindURI(req, param)
+	return param, err
+}
+
+func oneActionProcess(req *http.Request, param *OneParam) (*ActiveHealthCheckData, error) {
+	_, cluster, one, err := mustGetActiveHealthCheck(req, param.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newActiveHealthCheckData(cluster, one), nil
+}
+
+var _ xreq.Handler = OneAction
+
+// OneAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func OneAction(req *http.Request) (interface{}, error) {
+	param, err := newOneParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return oneActionProcess(req, param)
+}
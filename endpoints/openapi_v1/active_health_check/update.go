@@ -0,0 +1,99 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package active_health_check
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpdateParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type UpdateParam struct {
+	ClusterName *string `uri:"cluster_name" validate:"required,min=1"`
+
+	Schema     *string `json:"schema" validate:"omitempty,oneof=http tcp"`
+	Interval   *int32  `json:"interval" validate:"omitempty,min=1"`
+	Timeout    *int32  `json:"timeout" validate:"omitempty,min=1"`
+	SuccNum    *int32  `json:"succ_num" validate:"omitempty,min=1"`
+	FailNum    *int32  `json:"fail_num" validate:"omitempty,min=1"`
+	Host       *string `json:"host"`
+	Uri        *string `json:"uri" validate:"omitempty,startswith=/"`
+	StatusCode *int32  `json:"status_code" validate:"omitempty,min=0,max=599"`
+}
+
+func (param *UpdateParam) toModel() *icluster_conf.ActiveHealthCheckParam {
+	return &icluster_conf.ActiveHealthCheckParam{
+		Schema:     param.Schema,
+		Interval:   param.Interval,
+		Timeout:    param.Timeout,
+		SuccNum:    param.SuccNum,
+		FailNum:    param.FailNum,
+		Host:       param.Host,
+		Uri:        param.Uri,
+		StatusCode: param.StatusCode,
+	}
+}
+
+// UpdateRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpdateEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/clusters/{cluster_name}/active-health-check",
+	Method:     http.MethodPatch,
+	Handler:    xreq.Convert(UpdateAction),
+	Authorizer: iauth.FAP(iauth.FeatureActiveHealthCheck, iauth.ActionUpdate),
+	Param:      &UpdateParam{},
+	Data:       &ActiveHealthCheckData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpdateParam(req *http.Request) (*UpdateParam, error) {
+	param := &UpdateParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+func updateActionProcess(req *http.Request, param *UpdateParam) (*ActiveHealthCheckData, error) {
+	product, cluster, one, err := mustGetActiveHealthCheck(req, param.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = container.ActiveHealthCheckManager.UpdateActiveHealthCheck(req.Context(), product, cluster, one, param.toModel())
+	if err != nil {
+		return nil, err
+	}
+
+	return oneActionProcess(req, &OneParam{
+		ClusterName: param.ClusterName,
+	})
+}
+
+var _ xreq.Handler = UpdateAction
+
+// UpdateAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpdateAction(req *http.Request) (interface{}, error) {
+	param, err := newUpdateParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateActionProcess(req, param)
+}
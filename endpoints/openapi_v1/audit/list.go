@@ -0,0 +1,154 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+const defaultPageSize = 20
+
+// ListParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type ListParam struct {
+	Page         int        `form:"page" validate:"omitempty,min=1"`
+	PageSize     int        `form:"page_size" validate:"omitempty,min=1,max=1000"`
+	Visitor      *string    `form:"visitor"`
+	ResourceType *string    `form:"resource_type"`
+	ResourceName *string    `form:"resource_name"`
+	Action       *string    `form:"action" validate:"omitempty,oneof=create update delete"`
+	StartTime    *time.Time `form:"start_time"`
+	EndTime      *time.Time `form:"end_time"`
+}
+
+func (param *ListParam) filter() *iaudit.AuditFilter {
+	filter := &iaudit.AuditFilter{
+		Visitor:      param.Visitor,
+		ResourceType: param.ResourceType,
+		ResourceName: param.ResourceName,
+		Action:       param.Action,
+		StartTime:    param.StartTime,
+		EndTime:      param.EndTime,
+		Page:         param.Page,
+		PageSize:     param.PageSize,
+	}
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize == 0 {
+		filter.PageSize = defaultPageSize
+	}
+
+	return filter
+}
+
+// AuditData Response Data
+type AuditData struct {
+	ID           int64     `json:"id"`
+	Visitor      string    `json:"visitor"`
+	ProductName  string    `json:"product_name"`
+	ResourceType string    `json:"resource_type"`
+	ResourceName string    `json:"resource_name"`
+	Action       string    `json:"action"`
+	Before       *string   `json:"before"`
+	After        *string   `json:"after"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ListData Response Data
+type ListData struct {
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Audits   []*AuditData `json:"audits"`
+}
+
+func pString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+func newListData(filter *iaudit.AuditFilter, list []*iaudit.Audit, total int64) *ListData {
+	audits := []*AuditData{}
+	for _, one := range list {
+		audits = append(audits, &AuditData{
+			ID:           one.ID,
+			Visitor:      one.Visitor,
+			ProductName:  one.ProductName,
+			ResourceType: one.ResourceType,
+			ResourceName: one.ResourceName,
+			Action:       one.Action,
+			Before:       pString(one.Before),
+			After:        pString(one.After),
+			CreatedAt:    one.CreatedAt,
+		})
+	}
+
+	return &ListData{
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Audits:   audits,
+	}
+}
+
+// ListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ListEndpoint = &xreq.Endpoint{
+	Path:       "/audits",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ListAction),
+	Authorizer: iauth.FA(iauth.FeatureAudit, iauth.ActionReadAll),
+	Param:      &ListParam{},
+	Data:       &ListData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newListParam(req *http.Request) (*ListParam, error) {
+	param := &ListParam{}
+	err := xreq.BindForm(req, param)
+	return param, err
+}
+
+func listActionProcess(req *http.Request, filter *iaudit.AuditFilter) (*ListData, error) {
+	list, total, err := container.AuditManager.AuditList(req.Context(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return newListData(filter, list, total), nil
+}
+
+var _ xreq.Handler = ListAction
+
+// ListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ListAction(req *http.Request) (interface{}, error) {
+	param, err := newListParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return listActionProcess(req, param.filter())
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+)
+
+// ProductListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ProductListEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/audits",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ProductListAction),
+	Authorizer: iauth.FAP(iauth.FeatureAudit, iauth.ActionRead),
+	Param:      &ListParam{},
+	Data:       &ListData{},
+}
+
+var _ xreq.Handler = ProductListAction
+
+// ProductListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ProductListAction(req *http.Request) (interface{}, error) {
+	param, err := newListParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := param.filter()
+	filter.ProductID = &product.ID
+
+	return listActionProcess(req, filter)
+}
@@ -36,6 +36,8 @@ var SessionKeyByPasswordEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(SessionKeyByPasswordAction),
 	Authorizer: nil,
+	Param:      &UserNamePasswordParam{},
+	Data:       &UserData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
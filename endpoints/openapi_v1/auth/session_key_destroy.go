@@ -34,6 +34,7 @@ var SessionKeyDestroyEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(SessionKeyDestroyAction),
 	Authorizer: nil,
+	Param:      &SessionKeyParam{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -31,6 +31,8 @@ var TokenCreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(TokenCreateAction),
 	Authorizer: iauth.FA(iauth.FeatureToken, iauth.ActionCreate),
+	Param:      &TokenCreateParam{},
+	Data:       &TokenCreateData{},
 }
 
 type TokenCreateParam struct {
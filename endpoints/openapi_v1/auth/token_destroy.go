@@ -36,6 +36,7 @@ var TokenDestroyEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(TokenDestroyAction),
 	Authorizer: iauth.FA(iauth.FeatureToken, iauth.ActionDelete),
+	Param:      &TokenNameParam{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -29,6 +29,7 @@ var TokenListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(TokenListAction),
 	Authorizer: iauth.FA(iauth.FeatureToken, iauth.ActionReadAll),
+	Data:       []*TokenData{},
 }
 
 func tokenListActionProcess(req *http.Request) ([]*TokenData, error) {
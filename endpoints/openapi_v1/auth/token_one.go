@@ -29,6 +29,8 @@ var TokenOneEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(TokenOneAction),
 	Authorizer: iauth.FA(iauth.FeatureToken, iauth.ActionReadAll),
+	Param:      &TokenNameParam{},
+	Data:       &TokenData{},
 }
 
 func tokenOneActionProcess(req *http.Request) (*TokenData, error) {
@@ -30,6 +30,7 @@ var ProductTokenListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ProductTokenListAction),
 	Authorizer: iauth.FA(iauth.FeatureToken, iauth.ActionReadAll),
+	Data:       []*TokenData{},
 }
 
 var _ xreq.Handler = ProductTokenListAction
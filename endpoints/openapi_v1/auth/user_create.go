@@ -39,6 +39,7 @@ var UserCreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(UserCreateAction),
 	Authorizer: iauth.FA(iauth.FeatureUser, iauth.ActionCreate),
+	Param:      &UserCreateParam{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -35,6 +35,7 @@ var UserDeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(UserDeleteAction),
 	Authorizer: iauth.FA(iauth.FeatureUser, iauth.ActionDelete),
+	Param:      &UserNameParam{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
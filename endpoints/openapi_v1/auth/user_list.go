@@ -29,12 +29,16 @@ var UserListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(UserListAction),
 	Authorizer: iauth.FA(iauth.FeatureUser, iauth.ActionReadAll),
+	Param:      &xreq.PageParam{},
+	Data:       []*UserData{},
 }
 
-func userListActionProcess(req *http.Request) ([]*UserData, error) {
-	list, err := container.AuthenticateManager.FetchUserList(req.Context(), nil)
+func userListActionProcess(req *http.Request, param *xreq.PageParam) ([]*UserData, int64, error) {
+	list, total, err := container.AuthenticateManager.FetchUserPage(req.Context(), &iauth.UserFilter{
+		Pagination: param.Pagination(),
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	users := []*UserData{}
@@ -42,7 +46,7 @@ func userListActionProcess(req *http.Request) ([]*UserData, error) {
 		users = append(users, newUserData(one))
 	}
 
-	return users, nil
+	return users, total, nil
 }
 
 var _ xreq.Handler = UserListAction
@@ -50,5 +54,15 @@ var _ xreq.Handler = UserListAction
 // UserListAction action
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 func UserListAction(req *http.Request) (interface{}, error) {
-	return userListActionProcess(req)
+	param, err := xreq.NewPageParamFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	users, total, err := userListActionProcess(req, param)
+	if err != nil {
+		return nil, err
+	}
+
+	return param.Result(users, total), nil
 }
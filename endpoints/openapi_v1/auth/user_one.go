@@ -30,6 +30,8 @@ var UserOneEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(UserOneAction),
 	Authorizer: iauth.FA(iauth.FeatureUser, iauth.ActionReadAll),
+	Param:      &UserNameParam{},
+	Data:       &UserData{},
 }
 
 func userOneActionProcess(req *http.Request, param *UserNameParam) (*UserData, error) {
@@ -31,6 +31,7 @@ var ProductUserBindEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(ProductUserBindAction),
 	Authorizer: iauth.FA(iauth.FeatureProductUser, iauth.ActionCreate),
+	Param:      &UserNameParam{},
 }
 
 func productUserBindActionProcess(req *http.Request, param *UserNameParam) error {
@@ -30,6 +30,8 @@ var ProductUserBindListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ProductUserListAction),
 	Authorizer: iauth.FA(iauth.FeatureProductUser, iauth.ActionReadAll),
+	Param:      &ProducctUserListParam{},
+	Data:       []*UserData{},
 }
 
 // ProducctUserListParam Request Param
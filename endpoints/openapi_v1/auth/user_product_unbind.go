@@ -31,6 +31,7 @@ var ProductUserUnbindEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(ProductUserUnbindAction),
 	Authorizer: iauth.FA(iauth.FeatureProductUser, iauth.ActionDelete),
+	Param:      &UserNameParam{},
 }
 
 func productUserUnbindActionProcess(req *http.Request, param *UserNameParam) error {
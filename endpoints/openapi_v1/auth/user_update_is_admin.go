@@ -30,6 +30,7 @@ var UserUpdateIsAdminEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UserUpdateIsAdminAction),
 	Authorizer: iauth.FA(iauth.FeatureUser, iauth.ActionUpdate),
+	Param:      &UserUpdateIsAdminParam{},
 }
 
 // UserUpdateIsAdminParam Request Param
@@ -30,6 +30,7 @@ var UserUpdatePasswordEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UserUpdatePasswordAction),
 	Authorizer: iauth.FA(iauth.FeatureUser, iauth.ActionUpdate),
+	Param:      &UserUpdatePasswordParam{},
 }
 
 // UserUpdatePasswordParam Request Param
@@ -37,7 +38,7 @@ var UserUpdatePasswordEndpoint = &xreq.Endpoint{
 type UserUpdatePasswordParam struct {
 	UserName    *string `uri:"user_name" validate:"required,min=1"`
 	OldPassword string  `json:"old_password" validate:"omitempty"`
-	Password    *string `json:"password" validate:"required,min=6"`
+	Password    *string `json:"password" validate:"required"`
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -38,6 +38,7 @@ var CreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(CreateAction),
 	Authorizer: iauth.FA(iauth.FeatureBFECluster, iauth.ActionCreate),
+	Param:      &BFEClusterCreateParam{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -36,6 +36,7 @@ var DeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(DeleteAction),
 	Authorizer: iauth.FA(iauth.FeatureBFECluster, iauth.ActionDelete),
+	Param:      &BFEClusterDeleteParam{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -36,6 +36,7 @@ var ListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ListAction),
 	Authorizer: iauth.FA(iauth.FeatureBFECluster, iauth.ActionReadAll),
+	Data:       []*BFEClusterDetail{},
 }
 
 func listActionProcess(req *http.Request) ([]*BFEClusterDetail, error) {
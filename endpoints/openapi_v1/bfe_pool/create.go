@@ -34,6 +34,8 @@ var CreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(CreateAction),
 	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionReadAll),
+	Param:      &product_pool.UpsertParam{},
+	Data:       &product_pool.OneData{},
 }
 
 var _ xreq.Handler = CreateAction
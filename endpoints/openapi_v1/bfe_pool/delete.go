@@ -30,6 +30,8 @@ var DeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(DeleteAction),
 	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionDelete),
+	Param:      &product_pool.OneParam{},
+	Data:       &product_pool.OneData{},
 }
 var _ xreq.Handler = DeleteAction
 
@@ -41,7 +43,7 @@ func DeleteAction(req *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	oldOne, err := container.PoolManager.DeleteBFEPool(req.Context(), param.InstancePoolName)
+	oldOne, err := container.PoolManager.DeleteBFEPool(req.Context(), param.InstancePoolName, xreq.IfMatch(req))
 	if err != nil {
 		return nil, err
 	}
@@ -22,4 +22,7 @@ var Endpoints = []*xreq.Endpoint{
 	DeleteEndpoint,
 	UpdateEndpoint,
 	CreateEndpoint,
+	AddInstanceEndpoint,
+	UpdateInstanceEndpoint,
+	DeleteInstanceEndpoint,
 }
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bfe_pool
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_pool"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+)
+
+// AddInstanceRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var AddInstanceEndpoint = &xreq.Endpoint{
+	Path:       "/bfe-pools/{instance_pool_name}/instances",
+	Method:     http.MethodPost,
+	Handler:    xreq.Convert(AddInstanceAction),
+	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionUpdate),
+	Param:      &product_pool.InstanceAddParam{},
+	Data:       &product_pool.OneData{},
+}
+
+// UpdateInstanceRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpdateInstanceEndpoint = &xreq.Endpoint{
+	Path:       "/bfe-pools/{instance_pool_name}/instances/{instance}",
+	Method:     http.MethodPatch,
+	Handler:    xreq.Convert(UpdateInstanceAction),
+	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionUpdate),
+	Param:      &product_pool.InstancePatchParam{},
+	Data:       &product_pool.OneData{},
+}
+
+// DeleteInstanceRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var DeleteInstanceEndpoint = &xreq.Endpoint{
+	Path:       "/bfe-pools/{instance_pool_name}/instances/{instance}",
+	Method:     http.MethodDelete,
+	Handler:    xreq.Convert(DeleteInstanceAction),
+	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionUpdate),
+	Param:      &product_pool.InstanceParam{},
+	Data:       &product_pool.OneData{},
+}
+
+var _ xreq.Handler = AddInstanceAction
+
+// AddInstanceAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func AddInstanceAction(req *http.Request) (interface{}, error) {
+	param, err := product_pool.NewInstanceAddParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return product_pool.AddInstanceProcess(req, ibasic.BuildinProduct, param)
+}
+
+var _ xreq.Handler = UpdateInstanceAction
+
+// UpdateInstanceAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpdateInstanceAction(req *http.Request) (interface{}, error) {
+	param, err := product_pool.NewInstancePatchParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return product_pool.UpdateInstanceProcess(req, ibasic.BuildinProduct, param)
+}
+
+var _ xreq.Handler = DeleteInstanceAction
+
+// DeleteInstanceAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func DeleteInstanceAction(req *http.Request) (interface{}, error) {
+	param, err := product_pool.NewInstanceParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return product_pool.DeleteInstanceProcess(req, ibasic.BuildinProduct, param)
+}
@@ -29,6 +29,7 @@ var ListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ListAction),
 	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionReadAll),
+	Data:       []string{},
 }
 
 var _ xreq.Handler = ListAction
@@ -31,6 +31,8 @@ var OneEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(OneAction),
 	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionReadAll),
+	Param:      &product_pool.OneParam{},
+	Data:       &product_pool.OneData{},
 }
 
 var _ xreq.Handler = OneAction
@@ -32,6 +32,8 @@ var UpdateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UpdateAction),
 	Authorizer: iauth.FA(iauth.FeatureBFEPool, iauth.ActionUpdate),
+	Param:      &product_pool.UpsertParam{},
+	Data:       &product_pool.OneData{},
 }
 
 var _ xreq.Handler = UpdateAction
@@ -54,9 +56,15 @@ func UpdateAction(req *http.Request) (interface{}, error) {
 
 	err = container.PoolManager.UpdateBFEPool(req.Context(), one, &icluster_conf.PoolParam{
 		Instances: product_pool.Instancesc2i(param.Instances),
-	})
+	}, xreq.IfMatch(req))
+	if err != nil {
+		return nil, err
+	}
 
-	one.Instances = product_pool.Instancesc2i(param.Instances)
+	one, err = container.PoolManager.FetchBFEPool(req.Context(), *param.Name)
+	if err != nil {
+		return nil, err
+	}
 
-	return product_pool.NewOneData(one), err
+	return product_pool.NewOneData(one), nil
 }
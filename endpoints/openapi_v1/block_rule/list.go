@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// BlockRule one block rule, the first matched rule takes effect
+type BlockRule struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=1024"`
+	Cond        string `json:"cond" validate:"required,min=1,max=4096"`
+	Action      string `json:"action" validate:"required,oneof=CLOSE ALLOW"`
+}
+
+// BlockRulesData Request Param / Response Data
+type BlockRulesData struct {
+	Rules []*BlockRule `json:"rules" validate:"dive"`
+}
+
+func newBlockRulesData(list []*imodule_conf.BlockRule) *BlockRulesData {
+	rules := []*BlockRule{}
+	for _, one := range list {
+		rules = append(rules, &BlockRule{
+			Name:        one.Name,
+			Description: one.Description,
+			Cond:        one.Cond,
+			Action:      one.Action,
+		})
+	}
+
+	return &BlockRulesData{
+		Rules: rules,
+	}
+}
+
+// ListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ListEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/block-rules",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ListAction),
+	Authorizer: iauth.FAP(iauth.FeatureBlock, iauth.ActionRead),
+	Data:       &BlockRulesData{},
+}
+
+func listActionProcess(req *http.Request) (*BlockRulesData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.BlockRuleManager.FetchProductBlockRules(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBlockRulesData(list), nil
+}
+
+var _ xreq.Handler = ListAction
+
+// ListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ListAction(req *http.Request) (interface{}, error) {
+	return listActionProcess(req)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package block_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpsertEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpsertEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/block-rules",
+	Method:     http.MethodPut,
+	Handler:    xreq.Convert(UpsertAction),
+	Authorizer: iauth.FAP(iauth.FeatureBlock, iauth.ActionUpdate),
+	Param:      &BlockRulesData{},
+	Data:       &BlockRulesData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpsertParam(req *http.Request) (*BlockRulesData, error) {
+	param := &BlockRulesData{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+func blockRulesToModel(param *BlockRulesData) []*imodule_conf.BlockRule {
+	rules := []*imodule_conf.BlockRule{}
+	for _, one := range param.Rules {
+		rules = append(rules, &imodule_conf.BlockRule{
+			Name:        one.Name,
+			Description: one.Description,
+			Cond:        one.Cond,
+			Action:      one.Action,
+		})
+	}
+
+	return rules
+}
+
+func upsertActionProcess(req *http.Request, param *BlockRulesData) (*BlockRulesData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.BlockRuleManager.UpsertProductBlockRules(req.Context(), product, blockRulesToModel(param))
+	if err != nil {
+		return nil, err
+	}
+
+	return newBlockRulesData(list), nil
+}
+
+var _ xreq.Handler = UpsertAction
+
+// UpsertAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpsertAction(req *http.Request) (interface{}, error) {
+	param, err := newUpsertParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return upsertActionProcess(req, param)
+}
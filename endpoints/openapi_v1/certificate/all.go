@@ -19,6 +19,7 @@ import (
 
 	"github.com/bfenetworks/api-server/lib/xreq"
 	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/iprotocol"
 	"github.com/bfenetworks/api-server/stateful/container"
 )
 
@@ -29,19 +30,23 @@ var AllEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(AllAction),
 	Authorizer: iauth.FA(iauth.FeatureCert, iauth.ActionReadAll),
+	Param:      &xreq.PageParam{},
+	Data:       []*OneData{},
 }
 
-func allActionProcess(req *http.Request) ([]*OneData, error) {
-	list, err := container.CertificateManager.FetchCertificates(req.Context(), nil)
+func allActionProcess(req *http.Request, param *xreq.PageParam) ([]*OneData, int64, error) {
+	list, total, err := container.CertificateManager.FetchCertificatePage(req.Context(), &iprotocol.CertificateFilter{
+		Pagination: param.Pagination(),
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	result := []*OneData{}
 	for _, one := range list {
 		result = append(result, newOneData(one))
 	}
-	return result, nil
+	return result, total, nil
 }
 
 var _ xreq.Handler = AllAction
@@ -49,5 +54,15 @@ var _ xreq.Handler = AllAction
 // AllAction action
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 func AllAction(req *http.Request) (interface{}, error) {
-	return allActionProcess(req)
+	param, err := xreq.NewPageParamFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	list, total, err := allActionProcess(req, param)
+	if err != nil {
+		return nil, err
+	}
+
+	return param.Result(list, total), nil
 }
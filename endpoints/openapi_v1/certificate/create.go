@@ -30,6 +30,8 @@ var CreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(CreateAction),
 	Authorizer: iauth.FA(iauth.FeatureCert, iauth.ActionCreate),
+	Param:      &CreateParam{},
+	Data:       &OneData{},
 }
 
 // CreateParam Request Param
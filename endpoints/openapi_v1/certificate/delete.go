@@ -40,6 +40,8 @@ type OneData struct {
 	CertFileName string `json:"cert_file_name"`
 	KeyFileName  string `json:"key_file_name"`
 	ExpiredDate  string `json:"expired_date"`
+
+	Products []string `json:"products"`
 }
 
 func newOneData(param *iprotocol.Certificate) *OneData {
@@ -47,6 +49,11 @@ func newOneData(param *iprotocol.Certificate) *OneData {
 		return nil
 	}
 
+	products := []string{}
+	for _, one := range param.Products {
+		products = append(products, one.Name)
+	}
+
 	return &OneData{
 		CertName:     param.CertName,
 		Description:  param.Description,
@@ -54,6 +61,7 @@ func newOneData(param *iprotocol.Certificate) *OneData {
 		CertFileName: param.CertFileName,
 		KeyFileName:  param.KeyFileName,
 		ExpiredDate:  param.ExpiredDate,
+		Products:     products,
 	}
 }
 
@@ -64,6 +72,8 @@ var DeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(DeleteAction),
 	Authorizer: iauth.FA(iauth.FeatureCert, iauth.ActionDelete),
+	Param:      &OneParam{},
+	Data:       &OneData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -37,6 +37,8 @@ var UpdateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UpdateAction),
 	Authorizer: iauth.FA(iauth.FeatureCert, iauth.ActionUpdate),
+	Param:      &UpdateParam{},
+	Data:       &OneData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
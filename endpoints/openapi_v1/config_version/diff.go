@@ -0,0 +1,150 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_version
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// DiffParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type DiffParam struct {
+	Topic      *string `uri:"topic" validate:"required,min=1"`
+	OldVersion string  `form:"old_version"`
+	NewVersion string  `form:"new_version"`
+}
+
+// DiffEntryData Response Data
+type DiffEntryData struct {
+	Resource string      `json:"resource"`
+	Key      string      `json:"key"`
+	Action   string      `json:"action"`
+	Old      interface{} `json:"old,omitempty"`
+	New      interface{} `json:"new,omitempty"`
+}
+
+// DiffData Response Data
+type DiffData struct {
+	Topic      string           `json:"topic"`
+	OldVersion string           `json:"old_version"`
+	NewVersion string           `json:"new_version"`
+	Entries    []*DiffEntryData `json:"entries"`
+}
+
+func newDiffData(diff *iversion_control.ConfigDiff) *DiffData {
+	entries := []*DiffEntryData{}
+	for _, one := range diff.Entries {
+		entries = append(entries, &DiffEntryData{
+			Resource: one.Resource,
+			Key:      one.Key,
+			Action:   string(one.Action),
+			Old:      one.Old,
+			New:      one.New,
+		})
+	}
+
+	return &DiffData{
+		Topic:      diff.Topic,
+		OldVersion: diff.OldVersion,
+		NewVersion: diff.NewVersion,
+		Entries:    entries,
+	}
+}
+
+// DiffEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var DiffEndpoint = &xreq.Endpoint{
+	Path:       "/config-diffs/{topic}",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(DiffAction),
+	Authorizer: iauth.FA(iauth.FeatureConfig, iauth.ActionRead),
+	Param:      &DiffParam{},
+	Data:       &DiffData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newDiffParam(req *http.Request) (*DiffParam, error) {
+	param := &DiffParam{}
+	if err := xreq.BindURI(req, param); err != nil {
+		return nil, err
+	}
+	if err := xreq.BindForm(req, param); err != nil {
+		return nil, err
+	}
+
+	return param, nil
+}
+
+func diffActionProcess(req *http.Request, param *DiffParam) (*DiffData, error) {
+	ctx := req.Context()
+	topic := *param.Topic
+
+	var diff *iversion_control.ConfigDiff
+	var err error
+	switch {
+	case topic == iroute_conf.ConfigTopicRouteRule:
+		diff, err = container.RouteRuleManager.DiffRouteRule(ctx, param.OldVersion, param.NewVersion)
+	case topic == icluster_conf.ConfigTopicClusterTable:
+		diff, err = container.ClusterManager.DiffClusterTable(ctx, param.OldVersion, param.NewVersion)
+	case strings.HasPrefix(topic, icluster_conf.ConfigTopicGSLB+"."):
+		bfeClusterName := strings.TrimPrefix(topic, icluster_conf.ConfigTopicGSLB+".")
+		diff, err = container.ClusterManager.DiffGSLB(ctx, bfeClusterName, param.OldVersion, param.NewVersion)
+	case topic == icluster_conf.ConfigTopicActiveHealthCheck:
+		diff, err = container.ActiveHealthCheckManager.DiffActiveHealthCheck(ctx, param.OldVersion, param.NewVersion)
+	case topic == imodule_conf.ConfigTopicRedirect:
+		diff, err = container.RedirectRuleManager.DiffRedirect(ctx, param.OldVersion, param.NewVersion)
+	case topic == imodule_conf.ConfigTopicHeader:
+		diff, err = container.HeaderRuleManager.DiffHeader(ctx, param.OldVersion, param.NewVersion)
+	case topic == imodule_conf.ConfigTopicRewrite:
+		diff, err = container.RewriteRuleManager.DiffRewrite(ctx, param.OldVersion, param.NewVersion)
+	case topic == imodule_conf.ConfigTopicBlockRule:
+		diff, err = container.BlockRuleManager.DiffBlockRule(ctx, param.OldVersion, param.NewVersion)
+	case topic == imodule_conf.ConfigTopicIPBlocklist:
+		diff, err = container.IPBlocklistManager.DiffIPBlocklist(ctx, param.OldVersion, param.NewVersion)
+	case topic == iprotocol.ConfigTopicServerCert:
+		diff, err = container.CertificateManager.DiffServerCert(ctx, param.OldVersion, param.NewVersion)
+	default:
+		return nil, xerror.WrapParamErrorWithMsg("Topic %s Not Support Diff", topic)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newDiffData(diff), nil
+}
+
+var _ xreq.Handler = DiffAction
+
+// DiffAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func DiffAction(req *http.Request) (interface{}, error) {
+	param, err := newDiffParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffActionProcess(req, param)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_version
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// ListParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type ListParam struct {
+	Topic *string `uri:"topic" validate:"required,min=1"`
+}
+
+// VersionData Response Data
+type VersionData struct {
+	Topic     string    `json:"topic"`
+	Version   string    `json:"version"`
+	DataSign  string    `json:"data_sign"`
+	CreatedAt time.Time `json:"created_at"`
+	Pinned    bool      `json:"pinned"`
+}
+
+func newVersionData(cv *iversion_control.ConfigVersion, pinnedVersion string) *VersionData {
+	return &VersionData{
+		Topic:     cv.Name,
+		Version:   cv.Version,
+		DataSign:  cv.DataSign,
+		CreatedAt: cv.CreatedAt,
+		Pinned:    cv.Version == pinnedVersion,
+	}
+}
+
+// ListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ListEndpoint = &xreq.Endpoint{
+	Path:       "/config-versions/{topic}",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ListAction),
+	Authorizer: iauth.FA(iauth.FeatureConfig, iauth.ActionReadAll),
+	Param:      &ListParam{},
+	Data:       []*VersionData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newListParam(req *http.Request) (*ListParam, error) {
+	param := &ListParam{}
+	err := xreq.BindURI(req, param)
+	return param, err
+}
+
+func pinnedVersion(req *http.Request, topic string) (string, error) {
+	pins, err := container.VersionControlManager.ConfigPinList(req.Context())
+	if err != nil {
+		return "", err
+	}
+
+	for _, one := range pins {
+		if one.Name == topic {
+			return one.Version, nil
+		}
+	}
+
+	return "", nil
+}
+
+func listActionProcess(req *http.Request, param *ListParam) ([]*VersionData, error) {
+	list, err := container.VersionControlManager.ConfigVersionList(req.Context(), *param.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, err := pinnedVersion(req, *param.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := []*VersionData{}
+	for _, one := range list {
+		rst = append(rst, newVersionData(one, pinned))
+	}
+
+	return rst, nil
+}
+
+var _ xreq.Handler = ListAction
+
+// ListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ListAction(req *http.Request) (interface{}, error) {
+	param, err := newListParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return listActionProcess(req, param)
+}
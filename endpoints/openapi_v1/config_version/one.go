@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_version
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// OneParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type OneParam struct {
+	Topic   *string `uri:"topic" validate:"required,min=1"`
+	Version *string `uri:"version" validate:"required,min=1"`
+}
+
+// SnapshotData Response Data
+type SnapshotData struct {
+	VersionData
+
+	Data json.RawMessage `json:"data"`
+}
+
+// OneEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var OneEndpoint = &xreq.Endpoint{
+	Path:       "/config-versions/{topic}/{version}",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(OneAction),
+	Authorizer: iauth.FA(iauth.FeatureConfig, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &SnapshotData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newOneParam(req *http.Request) (*OneParam, error) {
+	param := &OneParam{}
+	err := xreq.BindURI(req, param)
+	return param, err
+}
+
+func oneActionProcess(req *http.Request, param *OneParam) (*SnapshotData, error) {
+	cv, data, err := container.VersionControlManager.ConfigSnapshot(req.Context(), *param.Topic, *param.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, err := pinnedVersion(req, *param.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SnapshotData{
+		VersionData: *newVersionData(cv, pinned),
+		Data:        data,
+	}, nil
+}
+
+var _ xreq.Handler = OneAction
+
+// OneAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func OneAction(req *http.Request) (interface{}, error) {
+	param, err := newOneParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return oneActionProcess(req, param)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_version
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// PinParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type PinParam struct {
+	Topic   *string `json:"-" uri:"topic" validate:"required,min=1"`
+	Version *string `json:"version" validate:"required,min=1"`
+}
+
+// PinData Response Data
+type PinData struct {
+	Topic     string    `json:"topic"`
+	Version   string    `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newPinData(pin *iversion_control.ConfigPin) *PinData {
+	return &PinData{
+		Topic:     pin.Name,
+		Version:   pin.Version,
+		UpdatedAt: pin.UpdatedAt,
+	}
+}
+
+// PinListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var PinListEndpoint = &xreq.Endpoint{
+	Path:       "/config-pins",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(PinListAction),
+	Authorizer: iauth.FA(iauth.FeatureConfig, iauth.ActionReadAll),
+	Data:       []*PinData{},
+}
+
+var _ xreq.Handler = PinListAction
+
+// PinListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func PinListAction(req *http.Request) (interface{}, error) {
+	list, err := container.VersionControlManager.ConfigPinList(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rst := []*PinData{}
+	for _, one := range list {
+		rst = append(rst, newPinData(one))
+	}
+
+	return rst, nil
+}
+
+// PinEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var PinEndpoint = &xreq.Endpoint{
+	Path:       "/config-pins/{topic}",
+	Method:     http.MethodPut,
+	Handler:    xreq.Convert(PinAction),
+	Authorizer: iauth.FA(iauth.FeatureConfig, iauth.ActionUpdate),
+	Param:      &PinParam{},
+	Data:       &PinData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newPinParam(req *http.Request) (*PinParam, error) {
+	param := &PinParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+func pinActionProcess(req *http.Request, param *PinParam) (*PinData, error) {
+	if err := container.VersionControlManager.PinConfig(req.Context(), *param.Topic, *param.Version); err != nil {
+		return nil, err
+	}
+
+	return &PinData{
+		Topic:     *param.Topic,
+		Version:   *param.Version,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+var _ xreq.Handler = PinAction
+
+// PinAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func PinAction(req *http.Request) (interface{}, error) {
+	param, err := newPinParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pinActionProcess(req, param)
+}
+
+// UnpinParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type UnpinParam struct {
+	Topic *string `uri:"topic" validate:"required,min=1"`
+}
+
+// UnpinEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UnpinEndpoint = &xreq.Endpoint{
+	Path:       "/config-pins/{topic}",
+	Method:     http.MethodDelete,
+	Handler:    xreq.Convert(UnpinAction),
+	Authorizer: iauth.FA(iauth.FeatureConfig, iauth.ActionDelete),
+	Param:      &UnpinParam{},
+	Data:       "",
+}
+
+var _ xreq.Handler = UnpinAction
+
+// UnpinAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UnpinAction(req *http.Request) (interface{}, error) {
+	param := &UnpinParam{}
+	if err := xreq.BindURI(req, param); err != nil {
+		return nil, err
+	}
+
+	if err := container.VersionControlManager.UnpinConfig(req.Context(), *param.Topic); err != nil {
+		return nil, err
+	}
+
+	return param.Topic, nil
+}
@@ -37,6 +37,8 @@ var CreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(CreateAction),
 	Authorizer: iauth.FAP(iauth.FeatureDomain, iauth.ActionCreate),
+	Param:      &CreateParam{},
+	Data:       &OneData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
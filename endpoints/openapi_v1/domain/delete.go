@@ -38,6 +38,8 @@ var DeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(DeleteAction),
 	Authorizer: iauth.FAP(iauth.FeatureDomain, iauth.ActionDelete),
+	Param:      &DeleteParam{},
+	Data:       &OneData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -23,4 +23,6 @@ var Endpoints = []*xreq.Endpoint{
 	DeleteEndpoint,
 	ListEndpoint,
 	UseStatusEndpoint,
+	HTTPSConfigEndpoint,
+	UpdateHTTPSConfigEndpoint,
 }
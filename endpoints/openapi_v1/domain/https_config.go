@@ -0,0 +1,109 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// HTTPSConfigData Response Data
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type HTTPSConfigData struct {
+	DomainName            string `json:"domain_name"`
+	RedirectEnabled       bool   `json:"redirect_enabled"`
+	HstsEnabled           bool   `json:"hsts_enabled"`
+	HstsMaxAge            int64  `json:"hsts_max_age"`
+	HstsIncludeSubDomains bool   `json:"hsts_include_subdomains"`
+	HstsPreload           bool   `json:"hsts_preload"`
+}
+
+func newHTTPSConfigData(domain *iroute_conf.Domain) *HTTPSConfigData {
+	return &HTTPSConfigData{
+		DomainName:            domain.Name,
+		RedirectEnabled:       domain.UsingAdvancedRedirect != 0,
+		HstsEnabled:           domain.UsingAdvancedHsts != 0,
+		HstsMaxAge:            domain.HstsMaxAge,
+		HstsIncludeSubDomains: domain.HstsIncludeSubDomains,
+		HstsPreload:           domain.HstsPreload,
+	}
+}
+
+// mustGetDomain return domain of product in uri
+func mustGetDomain(req *http.Request, domainName string) (*ibasic.Product, *iroute_conf.Domain, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list, err := container.DomainManager.DomainList(req.Context(), &iroute_conf.DomainFilter{
+		Product: product,
+		Name:    &domainName,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil, xerror.WrapRecordNotExist("Domain")
+	}
+
+	return product, list[0], nil
+}
+
+// HTTPSConfigRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var HTTPSConfigEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/domains/{domain_name}/https-config",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(HTTPSConfigAction),
+	Authorizer: iauth.FAP(iauth.FeatureDomain, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &HTTPSConfigData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newOneParam4HTTPSConfig(req *http.Request) (*OneParam, error) {
+	param := &OneParam{}
+	err := xreq.BindURI(req, param)
+	return param, err
+}
+
+func httpsConfigActionProcess(req *http.Request, param *OneParam) (*HTTPSConfigData, error) {
+	_, domain, err := mustGetDomain(req, param.DomainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newHTTPSConfigData(domain), nil
+}
+
+var _ xreq.Handler = HTTPSConfigAction
+
+// HTTPSConfigAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func HTTPSConfigAction(req *http.Request) (interface{}, error) {
+	param, err := newOneParam4HTTPSConfig(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpsConfigActionProcess(req, param)
+}
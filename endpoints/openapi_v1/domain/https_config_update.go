@@ -0,0 +1,105 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpdateHTTPSConfigParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type UpdateHTTPSConfigParam struct {
+	DomainName string `uri:"domain_name" validate:"required,min=1"`
+
+	RedirectEnabled       *bool  `json:"redirect_enabled"`
+	HstsEnabled           *bool  `json:"hsts_enabled"`
+	HstsMaxAge            *int64 `json:"hsts_max_age" validate:"omitempty,min=0"`
+	HstsIncludeSubDomains *bool  `json:"hsts_include_subdomains"`
+	HstsPreload           *bool  `json:"hsts_preload"`
+}
+
+func (param *UpdateHTTPSConfigParam) toModel() *iroute_conf.DomainParam {
+	bool2int8 := func(b *bool) *int8 {
+		if b == nil {
+			return nil
+		}
+
+		var i int8
+		if *b {
+			i = 1
+		}
+		return &i
+	}
+
+	return &iroute_conf.DomainParam{
+		UsingAdvancedRedirect: bool2int8(param.RedirectEnabled),
+		UsingAdvancedHsts:     bool2int8(param.HstsEnabled),
+		HstsMaxAge:            param.HstsMaxAge,
+		HstsIncludeSubDomains: param.HstsIncludeSubDomains,
+		HstsPreload:           param.HstsPreload,
+	}
+}
+
+// UpdateHTTPSConfigRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpdateHTTPSConfigEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/domains/{domain_name}/https-config",
+	Method:     http.MethodPatch,
+	Handler:    xreq.Convert(UpdateHTTPSConfigAction),
+	Authorizer: iauth.FAP(iauth.FeatureDomain, iauth.ActionUpdate),
+	Param:      &UpdateHTTPSConfigParam{},
+	Data:       &HTTPSConfigData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpdateHTTPSConfigParam(req *http.Request) (*UpdateHTTPSConfigParam, error) {
+	param := &UpdateHTTPSConfigParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+func updateHTTPSConfigActionProcess(req *http.Request, param *UpdateHTTPSConfigParam) (*HTTPSConfigData, error) {
+	product, domain, err := mustGetDomain(req, param.DomainName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = container.DomainManager.UpdateDomainHTTPS(req.Context(), product, domain, param.toModel())
+	if err != nil {
+		return nil, err
+	}
+
+	return httpsConfigActionProcess(req, &OneParam{
+		DomainName: param.DomainName,
+	})
+}
+
+var _ xreq.Handler = UpdateHTTPSConfigAction
+
+// UpdateHTTPSConfigAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpdateHTTPSConfigAction(req *http.Request) (interface{}, error) {
+	param, err := newUpdateHTTPSConfigParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateHTTPSConfigActionProcess(req, param)
+}
@@ -43,19 +43,27 @@ var ListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ListAction),
 	Authorizer: iauth.FAP(iauth.FeatureDomain, iauth.ActionRead),
+	Param:      &xreq.PageParam{},
+	Data:       []string{},
 }
 var _ xreq.Handler = ListAction
 
 // ListAction action
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 func ListAction(req *http.Request) (interface{}, error) {
+	param, err := xreq.NewPageParamFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
 	product, err := ibasic.MustGetProduct(req.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	list, err := container.DomainManager.DomainList(req.Context(), &iroute_conf.DomainFilter{
-		Product: product,
+	list, total, err := container.DomainManager.DomainPage(req.Context(), &iroute_conf.DomainFilter{
+		Product:    product,
+		Pagination: param.Pagination(),
 	})
 	if err != nil {
 		return nil, err
@@ -65,5 +73,5 @@ func ListAction(req *http.Request) (interface{}, error) {
 	for i, one := range list {
 		rst[i] = one.Name
 	}
-	return rst, nil
+	return param.Result(rst, total), nil
 }
@@ -46,6 +46,8 @@ var UseStatusEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(UseStatusAction),
 	Authorizer: iauth.FAP(iauth.FeatureDomain, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &UseStatusRsp{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
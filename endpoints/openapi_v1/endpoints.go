@@ -18,26 +18,40 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/bfenetworks/api-server/endpoints/middleware"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/active_health_check"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/audit"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/auth"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/bfe_cluster"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/bfe_pool"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/block_rule"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/certificate"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/config_version"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/domain"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/header_rule"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/ip_blocklist"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_bundle"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_cluster"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_pool"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/redirect_rule"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/rewrite_rule"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/route"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/subcluster"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/tls_rule"
 	"github.com/bfenetworks/api-server/endpoints/openapi_v1/traffic"
 	"github.com/bfenetworks/api-server/lib/xreq"
 )
 
 func RegisterEndpoints(router *mux.Router) *mux.Router {
-	openAPIV1Router := router.PathPrefix("/open-api/v1").Subrouter()
+	openAPIV1Router := router.PathPrefix(openAPIBasePath).Subrouter()
 	openAPIV1Router.Use(middleware.McProductProbe, middleware.McUserProbe)
 	for _, one := range endpoints() {
 		one.Register(openAPIV1Router)
 	}
+	OpenAPIEndpoint.Register(openAPIV1Router)
+
+	// build document at startup
+	OpenAPIContent()
 	return openAPIV1Router
 }
 
@@ -54,6 +68,16 @@ func endpoints() []*xreq.Endpoint {
 		bfe_cluster.Endpoints,
 		route.Endpoints,
 		domain.Endpoints,
+		config_version.Endpoints,
+		audit.Endpoints,
+		active_health_check.Endpoints,
+		product_bundle.Endpoints,
+		header_rule.Endpoints,
+		rewrite_rule.Endpoints,
+		redirect_rule.Endpoints,
+		block_rule.Endpoints,
+		ip_blocklist.Endpoints,
+		tls_rule.Endpoints,
 	)
 }
 
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package header_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// CreateParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type CreateParam struct {
+	Name        *string   `json:"name" validate:"required,min=1,max=255"`
+	Description *string   `json:"description" validate:"omitempty,max=1024"`
+	Cond        *string   `json:"cond" validate:"required,min=1,max=4096"`
+	Actions     []*Action `json:"actions" validate:"required,min=1,dive"`
+	Last        *bool     `json:"last"`
+}
+
+// CreateEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var CreateEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/header-rules",
+	Method:     http.MethodPost,
+	Handler:    xreq.Convert(CreateAction),
+	Authorizer: iauth.FAP(iauth.FeatureHeaderRule, iauth.ActionCreate),
+	Param:      &CreateParam{},
+	Data:       &HeaderRuleData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newCreateParam(req *http.Request) (*CreateParam, error) {
+	param := &CreateParam{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+func createActionProcess(req *http.Request, param *CreateParam) (*HeaderRuleData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := container.HeaderRuleManager.CreateHeaderRule(req.Context(), product, &imodule_conf.HeaderRuleParam{
+		Name:        param.Name,
+		Description: param.Description,
+		Cond:        param.Cond,
+		Actions:     actionsToModel(param.Actions),
+		Last:        param.Last,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newHeaderRuleData(rule), nil
+}
+
+var _ xreq.Handler = CreateAction
+
+// CreateAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func CreateAction(req *http.Request) (interface{}, error) {
+	param, err := newCreateParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return createActionProcess(req, param)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package header_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// ListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ListEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/header-rules",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ListAction),
+	Authorizer: iauth.FAP(iauth.FeatureHeaderRule, iauth.ActionRead),
+	Data:       []*HeaderRuleData{},
+}
+
+// listActionProcess list header rules of product in the order they are executed
+func listActionProcess(req *http.Request) ([]*HeaderRuleData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := container.HeaderRuleManager.FetchHeaderRules(req.Context(), &imodule_conf.HeaderRuleFilter{
+		Product: product,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*HeaderRuleData{}
+	for _, one := range rules {
+		list = append(list, newHeaderRuleData(one))
+	}
+
+	return list, nil
+}
+
+var _ xreq.Handler = ListAction
+
+// ListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ListAction(req *http.Request) (interface{}, error) {
+	return listActionProcess(req)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package header_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// OneParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type OneParam struct {
+	RuleName *string `json:"-" uri:"rule_name" validate:"required,min=1"`
+}
+
+// Action Header Action
+type Action struct {
+	Cmd    *string  `json:"cmd" validate:"required,oneof=REQ_HEADER_SET REQ_HEADER_ADD REQ_HEADER_DEL REQ_HEADER_RENAME RSP_HEADER_SET RSP_HEADER_ADD RSP_HEADER_DEL RSP_HEADER_RENAME"`
+	Params []string `json:"params" validate:"required,min=1,max=2"`
+}
+
+// HeaderRuleData Response Data
+type HeaderRuleData struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Cond        string    `json:"cond"`
+	Actions     []*Action `json:"actions"`
+	Last        bool      `json:"last"`
+}
+
+func newHeaderRuleData(rule *imodule_conf.HeaderRule) *HeaderRuleData {
+	actions := []*Action{}
+	for _, one := range rule.Actions {
+		cmd := one.Cmd
+		actions = append(actions, &Action{
+			Cmd:    &cmd,
+			Params: one.Params,
+		})
+	}
+
+	return &HeaderRuleData{
+		Name:        rule.Name,
+		Description: rule.Description,
+		Cond:        rule.Cond,
+		Actions:     actions,
+		Last:        rule.Last,
+	}
+}
+
+func actionsToModel(actions []*Action) []*imodule_conf.HeaderAction {
+	if actions == nil {
+		return nil
+	}
+
+	rst := []*imodule_conf.HeaderAction{}
+	for _, one := range actions {
+		rst = append(rst, &imodule_conf.HeaderAction{
+			Cmd:    *one.Cmd,
+			Params: one.Params,
+		})
+	}
+
+	return rst
+}
+
+// OneEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var OneEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/header-rules/{rule_name}",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(OneAction),
+	Authorizer: iauth.FAP(iauth.FeatureHeaderRule, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &HeaderRuleData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newOneParam(req *http.Request) (*OneParam, error) {
+	param := &OneParam{}
+	err := xreq.BindURI(req, param)
+	return param, err
+}
+
+func mustGetHeaderRule(req *http.Request, ruleName string) (*imodule_conf.HeaderRule, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.HeaderRuleManager.FetchHeaderRules(req.Context(), &imodule_conf.HeaderRuleFilter{
+		Product: product,
+		Name:    &ruleName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, xerror.WrapRecordNotExist("Header Rule")
+	}
+
+	return list[0], nil
+}
+
+var _ xreq.Handler = OneAction
+
+// OneAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func OneAction(req *http.Request) (interface{}, error) {
+	param, err := newOneParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := mustGetHeaderRule(req, *param.RuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newHeaderRuleData(rule), nil
+}
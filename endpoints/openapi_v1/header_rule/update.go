@@ -0,0 +1,93 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package header_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpdateParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type UpdateParam struct {
+	RuleName *string `json:"-" uri:"rule_name" validate:"required,min=1"`
+
+	Name        *string   `json:"name" validate:"omitempty,min=1,max=255"`
+	Description *string   `json:"description" validate:"omitempty,max=1024"`
+	Cond        *string   `json:"cond" validate:"omitempty,min=1,max=4096"`
+	Actions     []*Action `json:"actions" validate:"omitempty,min=1,dive"`
+	Last        *bool     `json:"last"`
+}
+
+// UpdateEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpdateEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/header-rules/{rule_name}",
+	Method:     http.MethodPatch,
+	Handler:    xreq.Convert(UpdateAction),
+	Authorizer: iauth.FAP(iauth.FeatureHeaderRule, iauth.ActionUpdate),
+	Param:      &UpdateParam{},
+	Data:       &HeaderRuleData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpdateParam(req *http.Request) (*UpdateParam, error) {
+	param := &UpdateParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+func updateActionProcess(req *http.Request, param *UpdateParam) (*HeaderRuleData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	oldOne, err := mustGetHeaderRule(req, *param.RuleName)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := container.HeaderRuleManager.UpdateHeaderRule(req.Context(), product, oldOne, &imodule_conf.HeaderRuleParam{
+		Name:        param.Name,
+		Description: param.Description,
+		Cond:        param.Cond,
+		Actions:     actionsToModel(param.Actions),
+		Last:        param.Last,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newHeaderRuleData(rule), nil
+}
+
+var _ xreq.Handler = UpdateAction
+
+// UpdateAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpdateAction(req *http.Request) (interface{}, error) {
+	param, err := newUpdateParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateActionProcess(req, param)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ip_blocklist
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// ImportParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type ImportParam struct {
+	// Content one entry each line, format is "ip_or_cidr [expire_time]"
+	Content    string     `json:"content"`
+	ExpireTime *time.Time `json:"expire_time"`
+	Append     bool       `json:"append"`
+}
+
+// ImportEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ImportEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/ip-blocklist/import",
+	Method:     http.MethodPut,
+	Handler:    xreq.Convert(ImportAction),
+	Authorizer: iauth.FAP(iauth.FeatureBlock, iauth.ActionUpdate),
+	Param:      &ImportParam{},
+	Data:       &IPBlocklistData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newImportParam(req *http.Request) (*ImportParam, error) {
+	param := &ImportParam{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+func importActionProcess(req *http.Request, param *ImportParam) (*IPBlocklistData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := imodule_conf.ParseIPBlocklist([]byte(param.Content), param.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.IPBlocklistManager.ImportProductIPBlocklist(req.Context(), product, entries, param.Append)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIPBlocklistData(list), nil
+}
+
+var _ xreq.Handler = ImportAction
+
+// ImportAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ImportAction(req *http.Request) (interface{}, error) {
+	param, err := newImportParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return importActionProcess(req, param)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ip_blocklist
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// Entry single ip or cidr to be blocked
+type Entry struct {
+	IP         string     `json:"ip" validate:"required,min=2"`
+	ExpireTime *time.Time `json:"expire_time"`
+}
+
+// IPBlocklistData Response Data
+type IPBlocklistData struct {
+	Entries []*Entry `json:"entries"`
+}
+
+func newIPBlocklistData(list []*imodule_conf.IPBlocklistEntry) *IPBlocklistData {
+	entries := []*Entry{}
+	for _, one := range list {
+		entries = append(entries, &Entry{
+			IP:         one.IP,
+			ExpireTime: one.ExpireTime,
+		})
+	}
+
+	return &IPBlocklistData{
+		Entries: entries,
+	}
+}
+
+// OneEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var OneEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/ip-blocklist",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(OneAction),
+	Authorizer: iauth.FAP(iauth.FeatureBlock, iauth.ActionRead),
+	Data:       &IPBlocklistData{},
+}
+
+func oneActionProcess(req *http.Request) (*IPBlocklistData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.IPBlocklistManager.FetchProductIPBlocklist(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIPBlocklistData(list), nil
+}
+
+var _ xreq.Handler = OneAction
+
+// OneAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func OneAction(req *http.Request) (interface{}, error) {
+	return oneActionProcess(req)
+}
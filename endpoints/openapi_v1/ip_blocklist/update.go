@@ -0,0 +1,85 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ip_blocklist
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpdateParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type UpdateParam struct {
+	Add    []*Entry `json:"add" validate:"dive"`
+	Remove []string `json:"remove" validate:"dive,min=2"`
+}
+
+// UpdateEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpdateEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/ip-blocklist",
+	Method:     http.MethodPatch,
+	Handler:    xreq.Convert(UpdateAction),
+	Authorizer: iauth.FAP(iauth.FeatureBlock, iauth.ActionUpdate),
+	Param:      &UpdateParam{},
+	Data:       &IPBlocklistData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpdateParam(req *http.Request) (*UpdateParam, error) {
+	param := &UpdateParam{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+func updateActionProcess(req *http.Request, param *UpdateParam) (*IPBlocklistData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	add := []*imodule_conf.IPBlocklistEntry{}
+	for _, one := range param.Add {
+		add = append(add, &imodule_conf.IPBlocklistEntry{
+			IP:         one.IP,
+			ExpireTime: one.ExpireTime,
+		})
+	}
+
+	list, err := container.IPBlocklistManager.UpdateProductIPBlocklist(req.Context(), product, add, param.Remove)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIPBlocklistData(list), nil
+}
+
+var _ xreq.Handler = UpdateAction
+
+// UpdateAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpdateAction(req *http.Request) (interface{}, error) {
+	param, err := newUpdateParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateActionProcess(req, param)
+}
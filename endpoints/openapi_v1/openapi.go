@@ -0,0 +1,66 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_v1
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/version"
+)
+
+const openAPIBasePath = "/open-api/v1"
+
+var (
+	openAPIOnce    sync.Once
+	openAPIContent []byte
+)
+
+// OpenAPIContent return OpenAPI document of open api, it is built only once
+func OpenAPIContent() []byte {
+	openAPIOnce.Do(func() {
+		doc := xreq.NewOpenAPI("BFE API Server", version.Version, openAPIBasePath, endpoints())
+
+		var err error
+		openAPIContent, err = json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	return openAPIContent
+}
+
+// DumpOpenAPI write OpenAPI document to file
+func DumpOpenAPI(fileName string) error {
+	return ioutil.WriteFile(fileName, OpenAPIContent(), 0644)
+}
+
+// OpenAPIEndpoint serve OpenAPI document, no authorization required
+var OpenAPIEndpoint = &xreq.Endpoint{
+	Path:   "/openapi.json",
+	Method: http.MethodGet,
+	Handler: func(req *http.Request) *xreq.Result {
+		return &xreq.Result{
+			Render: func(w http.ResponseWriter, req *http.Request, res *xreq.Result) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Write(OpenAPIContent())
+			},
+		}
+	},
+}
@@ -33,6 +33,8 @@ var ProductCreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(ProductCreateAction),
 	Authorizer: iauth.FA(iauth.FeatureProduct, iauth.ActionCreate),
+	Param:      &ProductCreateParam{},
+	Data:       &ProductData{},
 }
 
 // ProductCreateParam Param
@@ -58,7 +60,7 @@ func ProductCreateAction(req *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	return newProductData(newOne), nil
+	return NewProductData(newOne), nil
 }
 
 func ProductCreateProcess(ctx context.Context, param *ProductCreateParam) (*ibasic.Product, error) {
@@ -33,6 +33,7 @@ var ProductDeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(ProductDeleteAction),
 	Authorizer: iauth.FAP(iauth.FeatureProduct, iauth.ActionDelete),
+	Data:       &ProductData{},
 }
 
 // ProductDeleteAction delete product
@@ -47,5 +48,5 @@ func ProductDeleteAction(req *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	return newProductData(p), nil
+	return NewProductData(p), nil
 }
@@ -27,11 +27,13 @@ import (
 )
 
 type ProductListParam struct {
+	xreq.PageParam
+
 	Domain  *string `form:"domain"`
 	Cluster *string `form:"cluster"`
 }
 
-func newProductData(pp *ibasic.Product) *ProductData {
+func NewProductData(pp *ibasic.Product) *ProductData {
 	return &ProductData{
 		Name:              pp.Name,
 		Description:       pp.Description,
@@ -50,6 +52,8 @@ var ProductListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ProductListAction),
 	Authorizer: iauth.FA(iauth.FeatureProduct, iauth.ActionRead),
+	Param:      &ProductListParam{},
+	Data:       []*ProductData{},
 }
 
 // ProductListAction get list
@@ -70,7 +74,7 @@ func ProductListAction(req *http.Request) (interface{}, error) {
 			return nil, err
 		}
 		if len(domains) == 0 {
-			return rst, nil
+			return param.Result(rst, 0), nil
 		}
 
 		productID = &(domains[0].ProductID)
@@ -82,7 +86,7 @@ func ProductListAction(req *http.Request) (interface{}, error) {
 			return nil, err
 		}
 		if clusterObj == nil {
-			return rst, nil
+			return param.Result(rst, 0), nil
 		}
 
 		productID = &(clusterObj.ProductID)
@@ -93,36 +97,34 @@ func ProductListAction(req *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	var grantedProducts []*ibasic.Product
+	filter := &ibasic.ProductFilter{
+		ID:         productID,
+		NeID:       lib.PInt64(1),
+		Pagination: param.Pagination(),
+	}
 	if !visitor.IsAdmin() {
-		grantedProducts, err = container.AuthorizeManager.FetchVisitorProductList(req.Context(), visitor)
+		grantedProducts, err := container.AuthorizeManager.FetchVisitorProductList(req.Context(), visitor)
 		if err != nil {
 			return nil, err
 		}
 		if len(grantedProducts) == 0 {
-			return []*ProductData{}, nil
+			return param.Result(rst, 0), nil
 		}
 
+		filter.NeID = nil
 		for _, pp := range grantedProducts {
-			if productID == nil || *productID == pp.ID {
-				rst = append(rst, newProductData(pp))
-			}
+			filter.IDs = append(filter.IDs, pp.ID)
 		}
-
-		return rst, nil
 	}
 
-	list, err := container.ProductManager.FetchProducts(req.Context(), &ibasic.ProductFilter{
-		ID:   productID,
-		NeID: lib.PInt64(1),
-	})
+	list, total, err := container.ProductManager.FetchProductPage(req.Context(), filter)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, pp := range list {
-		rst = append(rst, newProductData(pp))
+		rst = append(rst, NewProductData(pp))
 	}
 
-	return rst, nil
+	return param.Result(rst, total), nil
 }
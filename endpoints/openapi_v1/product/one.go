@@ -33,6 +33,7 @@ var ProductOneEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ProductOneAction),
 	Authorizer: iauth.FAP(iauth.FeatureProduct, iauth.ActionRead),
+	Data:       &ProductData{},
 }
 
 // ProductData one response
@@ -54,7 +55,7 @@ func ProductOneAction(req *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	return newProductData(_p), nil
+	return NewProductData(_p), nil
 }
 
 func getProduct(req *http.Request) (*ibasic.Product, error) {
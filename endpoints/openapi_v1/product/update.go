@@ -32,6 +32,8 @@ var ProductUpdateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(ProductUpdateAction),
 	Authorizer: iauth.FAP(iauth.FeatureProduct, iauth.ActionUpdate),
+	Param:      &ProductUpdateParam{},
+	Data:       &ProductData{},
 }
 
 // ProductUpdateParam
@@ -75,5 +77,5 @@ func ProductUpdateAction(req *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	return newProductData(list[0]), nil
+	return NewProductData(list[0]), nil
 }
@@ -0,0 +1,440 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package product_bundle
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_cluster"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_pool"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/route"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/subcluster"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/tls_rule"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/ibundle"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+var changeActions = map[iversion_control.DiffAction]string{
+	iversion_control.DiffActionAdded:    ChangeCreate,
+	iversion_control.DiffActionModified: ChangeUpdate,
+	iversion_control.DiffActionRemoved:  ChangeDelete,
+}
+
+// ApplyParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type ApplyParam struct {
+	DryRun bool `json:"-" form:"dry_run"`
+
+	Bundle
+}
+
+// ChangeData one planned change
+type ChangeData struct {
+	Resource string      `json:"resource"`
+	Key      string      `json:"key"`
+	Action   string      `json:"action"`
+	Old      interface{} `json:"old,omitempty"`
+	New      interface{} `json:"new,omitempty"`
+}
+
+// ApplyData Response Data
+type ApplyData struct {
+	DryRun  bool          `json:"dry_run"`
+	Changes []*ChangeData `json:"changes"`
+}
+
+// ApplyEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ApplyEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/bundle",
+	Method:     http.MethodPut,
+	Handler:    xreq.Convert(ApplyAction),
+	Authorizer: iauth.FAPC(iauth.FeatureProduct, iauth.ActionUpdate),
+	Param:      &ApplyParam{},
+	Data:       &ApplyData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newApplyParam(req *http.Request, productName string) (*ApplyParam, error) {
+	param := &ApplyParam{}
+	if err := xreq.BindJSON(req, param); err != nil {
+		return nil, err
+	}
+	if err := xreq.BindForm(req, param); err != nil {
+		return nil, err
+	}
+
+	for _, one := range param.Pools {
+		if one == nil {
+			return nil, xerror.WrapParamErrorWithMsg("Pools element cant be nil")
+		}
+		if !strings.HasPrefix(*one.Name, productName+".") || len(*one.Name) == len(productName)+1 {
+			return nil, xerror.WrapParamErrorWithMsg("Pool %s Want Prefix %s.", *one.Name, productName)
+		}
+	}
+	for _, one := range param.SubClusters {
+		if one == nil {
+			return nil, xerror.WrapParamErrorWithMsg("SubClusters element cant be nil")
+		}
+	}
+	for _, one := range param.Clusters {
+		if one == nil || one.Name == nil {
+			return nil, xerror.WrapParamErrorWithMsg("Clusters element want name")
+		}
+		if err := product_cluster.CheckCreateParam(one); err != nil {
+			return nil, err
+		}
+	}
+	if rule := param.Routes; rule != nil {
+		for _, one := range rule.AdvanceRouteRules {
+			if one == nil {
+				return nil, xerror.WrapParamErrorWithMsg("AdvanceRouteRules element cant be nil")
+			}
+		}
+		for _, one := range rule.BasicRouteRules {
+			if one == nil {
+				return nil, xerror.WrapParamErrorWithMsg("BasicRouteRules element cant be nil")
+			}
+		}
+		if rule.AdvanceRouteRules == nil {
+			rule.AdvanceRouteRules = []*route.AdvanceRouteRule{}
+		}
+		if rule.BasicRouteRules == nil {
+			rule.BasicRouteRules = []*route.BasicRouteRule{}
+		}
+	}
+	// empty tls rule means to remove it, validate others as the upsert api does
+	if rule := param.TLSRule; rule != nil && !emptyTLSRule(rule) {
+		if err := xreq.ValidateData(rule, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return param, nil
+}
+
+// emptyRoutes tell whether routes has no rule, which means to remove route rules of product
+func emptyRoutes(rule *route.ProductRouteRuleParam) bool {
+	return len(rule.BasicRouteRules) == 0 && len(rule.AdvanceRouteRules) == 0
+}
+
+// emptyTLSRule tell whether tls rule is an empty object, which means to remove tls rule of product
+func emptyTLSRule(rule *tls_rule.TLSRuleData) bool {
+	return reflect.DeepEqual(rule, &tls_rule.TLSRuleData{})
+}
+
+var _ xreq.Handler = ApplyAction
+
+// ApplyAction reconcile resources of product with the bundle in one transaction,
+// the product is created in the transaction if not exist
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ApplyAction(req *http.Request) (interface{}, error) {
+	productName, err := getProductName(req)
+	if err != nil {
+		return nil, err
+	}
+
+	param, err := newApplyParam(req, productName)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := container.BundleManager.ApplyProductBundle(req.Context(), productName,
+		newBundleParam(&param.Bundle), func(current *ibundle.Bundle) ([]*iversion_control.DiffEntry, error) {
+			bundle, err := newBundle(current)
+			if err != nil {
+				return nil, err
+			}
+
+			return planBundle(productName, bundle, &param.Bundle)
+		}, param.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &ApplyData{
+		DryRun:  param.DryRun,
+		Changes: []*ChangeData{},
+	}
+	for _, one := range changes {
+		data.Changes = append(data.Changes, &ChangeData{
+			Resource: one.Resource,
+			Key:      one.Key,
+			Action:   changeActions[one.Action],
+			Old:      one.Old,
+			New:      one.New,
+		})
+	}
+
+	return data, nil
+}
+
+// getProductName return name of product in uri, the product may not exist
+func getProductName(req *http.Request) (string, error) {
+	if name, ok := ibasic.GetMissingProductName(req.Context()); ok {
+		// validate it as creating product
+		if err := xreq.ValidateData(&product.ProductCreateParam{Name: name}, nil); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+
+	p, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return "", err
+	}
+
+	return p.Name, nil
+}
+
+// newBundleParam convert bundle to the param of model, sections not set keep nil
+func newBundleParam(b *Bundle) *ibundle.BundleParam {
+	param := &ibundle.BundleParam{
+		Domains: b.Domains,
+	}
+
+	if b.Product != nil {
+		param.Product = &ibasic.ProductParam{
+			Description:       b.Product.Description,
+			MailList:          b.Product.MailList,
+			PhoneList:         b.Product.PhoneList,
+			ContactPersonList: b.Product.ContactPersonList,
+		}
+	}
+
+	if b.Pools != nil {
+		param.Pools = []*icluster_conf.PoolParam{}
+		for _, one := range b.Pools {
+			param.Pools = append(param.Pools, &icluster_conf.PoolParam{
+				Name:      one.Name,
+				Instances: product_pool.Instancesc2i(one.Instances),
+			})
+		}
+	}
+
+	if b.SubClusters != nil {
+		param.SubClusters = []*icluster_conf.SubClusterParam{}
+		for _, one := range b.SubClusters {
+			param.SubClusters = append(param.SubClusters, &icluster_conf.SubClusterParam{
+				Name:             one.Name,
+				PoolName:         one.InstancePool,
+				Description:      one.Description,
+				PortName:         one.PortName,
+				InstanceSelector: one.InstanceSelector,
+			})
+		}
+	}
+
+	if b.Clusters != nil {
+		param.Clusters = []*icluster_conf.ClusterParam{}
+		for _, one := range b.Clusters {
+			param.Clusters = append(param.Clusters, product_cluster.ClusterParamControlModel(one))
+		}
+	}
+
+	if b.Routes != nil && !emptyRoutes(b.Routes) {
+		param.Routes = route.RouteRuleParam2RouteRule(b.Routes)
+	}
+
+	if b.TLSRule != nil && !emptyTLSRule(b.TLSRule) {
+		param.TLSRule = tls_rule.NewTLSRule(b.TLSRule)
+	}
+
+	return param
+}
+
+// planBundle return changes from current to desired in the order of resource dependency.
+// Resources are merged by mergeFields, an empty routes or tls rule means to remove it.
+// Product of current is nil if not exist, it's planned to be created.
+func planBundle(productName string, current, desired *Bundle) ([]*iversion_control.DiffEntry, error) {
+	var entries []*iversion_control.DiffEntry
+	diff := func(resource string, old, new map[string]interface{}) error {
+		oldMap, err := normalizeMap(old)
+		if err != nil {
+			return err
+		}
+		newMap, err := normalizeMap(new)
+		if err != nil {
+			return err
+		}
+
+		for k, v := range newMap {
+			newMap[k] = mergeFields(oldMap[k], v)
+		}
+		entries = append(entries, iversion_control.DiffMap(resource, oldMap, newMap)...)
+		return nil
+	}
+
+	var err error
+	if current.Product == nil {
+		newProduct := desired.Product
+		if newProduct == nil {
+			newProduct = &product.ProductUpdateParam{}
+		}
+		err = diff(ibundle.ResourceProduct, map[string]interface{}{},
+			map[string]interface{}{productName: newProduct})
+	} else if desired.Product != nil {
+		err = diff(ibundle.ResourceProduct, map[string]interface{}{productName: current.Product},
+			map[string]interface{}{productName: desired.Product})
+	}
+	if err == nil && desired.Pools != nil {
+		err = diff(ibundle.ResourcePool, poolMap(current.Pools), poolMap(desired.Pools))
+	}
+	if err == nil && desired.SubClusters != nil {
+		err = diff(ibundle.ResourceSubCluster, subClusterMap(current.SubClusters), subClusterMap(desired.SubClusters))
+	}
+	if err == nil && desired.Clusters != nil {
+		err = diff(ibundle.ResourceCluster, clusterMap(current.Clusters), clusterMap(desired.Clusters))
+	}
+	if err == nil && desired.Domains != nil {
+		err = diff(ibundle.ResourceDomain, domainMap(current.Domains), domainMap(desired.Domains))
+	}
+	if err == nil && desired.Routes != nil {
+		old, new := map[string]interface{}{}, map[string]interface{}{}
+		if !emptyRoutes(current.Routes) {
+			old[productName] = current.Routes
+		}
+		if !emptyRoutes(desired.Routes) {
+			new[productName] = desired.Routes
+		}
+		err = diff(ibundle.ResourceRoute, old, new)
+	}
+	if err == nil && desired.TLSRule != nil {
+		old, new := map[string]interface{}{}, map[string]interface{}{}
+		if current.TLSRule != nil {
+			old[productName] = current.TLSRule
+		}
+		if !emptyTLSRule(desired.TLSRule) {
+			new[productName] = desired.TLSRule
+		}
+		err = diff(ibundle.ResourceTLSRule, old, new)
+	}
+
+	return entries, err
+}
+
+func poolMap(list []*product_pool.UpsertParam) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, one := range list {
+		m[*one.Name] = one
+	}
+
+	return m
+}
+
+func subClusterMap(list []*subcluster.CreateParam) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, one := range list {
+		m[*one.Name] = one
+	}
+
+	return m
+}
+
+func clusterMap(list []*product_cluster.UpsertParam) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, one := range list {
+		m[*one.Name] = one
+	}
+
+	return m
+}
+
+func domainMap(list []string) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, one := range list {
+		m[one] = one
+	}
+
+	return m
+}
+
+// normalizeMap convert values to the form of json, so values can be compared directly
+func normalizeMap(m map[string]interface{}) (map[string]interface{}, error) {
+	rst := map[string]interface{}{}
+	for k, v := range m {
+		bs, err := json.Marshal(v)
+		if err != nil {
+			return nil, xerror.WrapModelError(err)
+		}
+
+		var one interface{}
+		if err = json.Unmarshal(bs, &one); err != nil {
+			return nil, xerror.WrapModelError(err)
+		}
+		rst[k] = dropNull(one)
+	}
+
+	return rst, nil
+}
+
+func dropNull(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, one := range vv {
+			if one == nil {
+				delete(vv, k)
+				continue
+			}
+			vv[k] = dropNull(one)
+		}
+	case []interface{}:
+		for i, one := range vv {
+			vv[i] = dropNull(one)
+		}
+	}
+
+	return v
+}
+
+// mergeFields overwrite fields of old with fields of new, it's how update api works.
+// The merge is shallow: only top level fields not set in new keep the old value,
+// a field set in new replaces the old one as a whole, lists and nested objects included.
+func mergeFields(old, new interface{}) interface{} {
+	oldFields, ok := old.(map[string]interface{})
+	if !ok {
+		return new
+	}
+	newFields, ok := new.(map[string]interface{})
+	if !ok {
+		return new
+	}
+
+	rst := map[string]interface{}{}
+	for k, v := range oldFields {
+		rst[k] = v
+	}
+	for k, v := range newFields {
+		rst[k] = v
+	}
+
+	return rst
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package product_bundle
+
+import (
+	"testing"
+
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/route"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/tls_rule"
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibundle"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+func newTestBundle() *Bundle {
+	return &Bundle{
+		Product: &product.ProductUpdateParam{
+			Description: lib.PString("demo product"),
+			MailList:    []string{"demo@example.org"},
+		},
+		Routes: &route.ProductRouteRuleParam{
+			BasicRouteRules: []*route.BasicRouteRule{{
+				HostNames:   []string{"example.org"},
+				ClusterName: "demo_cluster",
+			}},
+			AdvanceRouteRules: []*route.AdvanceRouteRule{},
+		},
+		TLSRule: &tls_rule.TLSRuleData{
+			CertName: "example_cert",
+			Domains:  []string{"example.org"},
+		},
+	}
+}
+
+func TestPlanBundle(t *testing.T) {
+	emptyRoutes := &route.ProductRouteRuleParam{
+		BasicRouteRules:   []*route.BasicRouteRule{},
+		AdvanceRouteRules: []*route.AdvanceRouteRule{},
+	}
+
+	cases := []struct {
+		name    string
+		current *Bundle
+		desired *Bundle
+		want    []string
+	}{
+		{
+			name:    "fields not set keep current value",
+			current: newTestBundle(),
+			desired: &Bundle{
+				Product: &product.ProductUpdateParam{
+					Description: lib.PString("demo product"),
+				},
+			},
+		},
+		{
+			name:    "fields set replace current value",
+			current: newTestBundle(),
+			desired: &Bundle{
+				Product: &product.ProductUpdateParam{
+					MailList: []string{},
+				},
+			},
+			want: []string{ibundle.ResourceProduct + " modified"},
+		},
+		{
+			name:    "empty objects remove routes and tls rule",
+			current: newTestBundle(),
+			desired: &Bundle{
+				Routes:  emptyRoutes,
+				TLSRule: &tls_rule.TLSRuleData{},
+			},
+			want: []string{ibundle.ResourceRoute + " removed", ibundle.ResourceTLSRule + " removed"},
+		},
+		{
+			name:    "empty objects without current ones",
+			current: &Bundle{Product: newTestBundle().Product, Routes: emptyRoutes},
+			desired: &Bundle{
+				Routes:  emptyRoutes,
+				TLSRule: &tls_rule.TLSRuleData{},
+			},
+		},
+		{
+			name:    "product not exist",
+			current: &Bundle{Routes: emptyRoutes},
+			desired: &Bundle{},
+			want:    []string{ibundle.ResourceProduct + " added"},
+		},
+		{
+			name:    "routes and tls rule created",
+			current: &Bundle{Product: newTestBundle().Product, Routes: emptyRoutes},
+			desired: newTestBundle(),
+			want:    []string{ibundle.ResourceRoute + " added", ibundle.ResourceTLSRule + " added"},
+		},
+	}
+
+	actions := map[iversion_control.DiffAction]string{
+		iversion_control.DiffActionAdded:    "added",
+		iversion_control.DiffActionModified: "modified",
+		iversion_control.DiffActionRemoved:  "removed",
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entries, err := planBundle("demo", c.current, c.desired)
+			if err != nil {
+				t.Fatalf("planBundle: %v", err)
+			}
+
+			var got []string
+			for _, one := range entries {
+				got = append(got, one.Resource+" "+actions[one.Action])
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("planBundle: want %v, got %v", c.want, got)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("planBundle: want %v, got %v", c.want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package product_bundle
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_cluster"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_pool"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/route"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/subcluster"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/tls_rule"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/ibundle"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// Bundle all resources of one product, a nil section means not to care about it,
+// an empty routes or tls_rule object means to remove it
+type Bundle struct {
+	Product     *product.ProductUpdateParam    `json:"product"`
+	Pools       []*product_pool.UpsertParam    `json:"pools" validate:"dive"`
+	SubClusters []*subcluster.CreateParam      `json:"sub_clusters" validate:"dive"`
+	Clusters    []*product_cluster.UpsertParam `json:"clusters" validate:"dive"`
+	Routes      *route.ProductRouteRuleParam   `json:"routes"`
+	Domains     []string                       `json:"domains" validate:"dive,min=2"`
+	TLSRule     *tls_rule.TLSRuleData          `json:"tls_rule" validate:"-"`
+}
+
+// OneEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var OneEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/bundle",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(OneAction),
+	Authorizer: iauth.FAP(iauth.FeatureProduct, iauth.ActionRead),
+	Data:       &Bundle{},
+}
+
+var _ xreq.Handler = OneAction
+
+// OneAction export all resources of product
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func OneAction(req *http.Request) (interface{}, error) {
+	p, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := container.BundleManager.FetchProductBundle(req.Context(), p)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBundle(bundle)
+}
+
+// newBundle convert bundle of model to api, product is nil if not exist
+func newBundle(b *ibundle.Bundle) (*Bundle, error) {
+	bundle := &Bundle{
+		Pools:       []*product_pool.UpsertParam{},
+		SubClusters: []*subcluster.CreateParam{},
+		Clusters:    []*product_cluster.UpsertParam{},
+		Routes: &route.ProductRouteRuleParam{
+			BasicRouteRules:   []*route.BasicRouteRule{},
+			AdvanceRouteRules: []*route.AdvanceRouteRule{},
+		},
+		Domains: []string{},
+		TLSRule: tls_rule.NewTLSRuleData(b.TLSRule),
+	}
+
+	if p := b.Product; p != nil {
+		bundle.Product = &product.ProductUpdateParam{
+			Description:       &p.Description,
+			MailList:          p.MailList,
+			PhoneList:         p.PhoneList,
+			ContactPersonList: p.ContactPersonList,
+		}
+	}
+
+	for _, one := range b.Pools {
+		bundle.Pools = append(bundle.Pools, &product_pool.UpsertParam{
+			Name:      &one.Name,
+			Instances: product_pool.NewOneData(one).Instances,
+		})
+	}
+
+	for _, one := range b.SubClusters {
+		bundle.SubClusters = append(bundle.SubClusters, newSubClusterParam(subcluster.NewOneData(one)))
+	}
+
+	for _, one := range b.Clusters {
+		param, err := newClusterParam(product_cluster.ClusterModel2Control(one))
+		if err != nil {
+			return nil, err
+		}
+		bundle.Clusters = append(bundle.Clusters, param)
+	}
+
+	if b.Routes != nil {
+		bundle.Routes = route.RouteRule2RouteRuleParam(b.Routes)
+	}
+
+	for _, one := range b.Domains {
+		bundle.Domains = append(bundle.Domains, one.Name)
+	}
+
+	return bundle, nil
+}
+
+func newSubClusterParam(data *subcluster.OneData) *subcluster.CreateParam {
+	param := &subcluster.CreateParam{
+		Name:             &data.Name,
+		InstancePool:     &data.InstancePool,
+		InstanceSelector: data.InstanceSelector,
+	}
+	if data.Description != "" {
+		param.Description = &data.Description
+	}
+	if data.PortName != "" {
+		param.PortName = &data.PortName
+	}
+
+	return param
+}
+
+// newClusterParam convert cluster to the param creating it, fields of both share the same json name
+func newClusterParam(data *product_cluster.ClusterData) (*product_cluster.UpsertParam, error) {
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return nil, xerror.WrapModelError(err)
+	}
+
+	param := &product_cluster.UpsertParam{}
+	if err = json.Unmarshal(bs, param); err != nil {
+		return nil, xerror.WrapModelError(err)
+	}
+
+	return param, nil
+}
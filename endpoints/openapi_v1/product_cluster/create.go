@@ -64,7 +64,10 @@ type UpsertParam struct {
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 type ConnectionParam struct {
 	MaxIdleConnPerRs    *int16 `json:"max_idle_conn_per_rs" validate:"required,min=0"`
+	MaxConnPerRs        *int32 `json:"max_conn_per_rs" validate:"omitempty,min=0"`
 	CancelOnClientClose *bool  `json:"cancel_on_client_close" validate:"required"`
+	ClientIPCarry       *bool  `json:"client_ip_carry"`
+	ClientPortCarry     *bool  `json:"client_port_carry"`
 }
 
 // BuffersParam Request Param
@@ -86,6 +89,10 @@ type TimeoutsParam struct {
 // BasicParam Request Param
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 type BasicParam struct {
+	Protocol                 *string `json:"protocol" validate:"omitempty,oneof=http https h2c fcgi"`
+	BalanceMode              *string `json:"balance_mode" validate:"omitempty,oneof=WRR WLC"`
+	OutlierDetectionHttpCode *string `json:"outlier_detection_http_code"`
+
 	Connection *ConnectionParam `json:"connection" validate:"required"`
 	Retries    *RetriesParam    `json:"retries" validate:"required"`
 	Buffers    *BuffersParam    `json:"buffers" validate:"required"`
@@ -114,6 +121,8 @@ var CreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(CreateAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductCluster, iauth.ActionCreate),
+	Param:      &UpsertParam{},
+	Data:       &ClusterData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -128,30 +137,44 @@ func newCreateParam4Create(req *http.Request) (*UpsertParam, error) {
 		return nil, err
 	}
 
+	return param, CheckCreateParam(param)
+}
+
+// CheckCreateParam check fields required when creating cluster, and fill default values
+func CheckCreateParam(param *UpsertParam) error {
 	if len(param.Scheduler) == 0 {
-		return nil, xerror.WrapParamErrorWithMsg("Scheduler Want Be Set")
+		return xerror.WrapParamErrorWithMsg("Scheduler Want Be Set")
 	}
 
 	if len(param.SubClusters) == 0 {
-		return nil, xerror.WrapParamErrorWithMsg("SubClusters Want Be Set")
+		return xerror.WrapParamErrorWithMsg("SubClusters Want Be Set")
 	}
 
 	if param.Basic == nil {
-		return nil, xerror.WrapParamErrorWithMsg("Basic Want Be Set")
+		return xerror.WrapParamErrorWithMsg("Basic Want Be Set")
+	}
+	if param.Basic.Protocol == nil {
+		param.Basic.Protocol = &icluster_conf.ClusterProtocolHTTP
+	}
+	if param.Basic.BalanceMode == nil {
+		param.Basic.BalanceMode = &icluster_conf.ClusterBalanceModeWRR
 	}
 
 	if param.PassiveHealthCheck == nil {
-		return nil, xerror.WrapParamErrorWithMsg("PassiveHealthCheck Want Be Set")
+		return xerror.WrapParamErrorWithMsg("PassiveHealthCheck Want Be Set")
 	}
 
 	if param.StickySessions == nil {
-		return nil, xerror.WrapParamErrorWithMsg("StickySessions Want Be Set")
+		return xerror.WrapParamErrorWithMsg("StickySessions Want Be Set")
+	}
+	if param.StickySessions.HashStrategy == nil {
+		param.StickySessions.HashStrategy = lib.PString(clusterHashStrategyClientIDOnly)
 	}
 	if *param.StickySessions.HashStrategy != clusterHashStrategyClientIPOnly && param.StickySessions.HashHeader == nil {
-		return nil, xerror.WrapParamErrorWithMsg("StickySessions.HashHeader Want Be Set")
+		return xerror.WrapParamErrorWithMsg("StickySessions.HashHeader Want Be Set")
 	}
 
-	return param, err
+	return nil
 }
 
 var (
@@ -160,7 +183,7 @@ var (
 	clusterHashStrategyClientIDPrefered = "CLIENT_ID_PREFERED"
 )
 
-func clusterParamControlModel(param *UpsertParam) *icluster_conf.ClusterParam {
+func ClusterParamControlModel(param *UpsertParam) *icluster_conf.ClusterParam {
 	rst := &icluster_conf.ClusterParam{
 		Name:        param.Name,
 		Description: param.Description,
@@ -169,11 +192,18 @@ func clusterParamControlModel(param *UpsertParam) *icluster_conf.ClusterParam {
 	}
 
 	if basic := param.Basic; basic != nil {
-		rst.Basic = &icluster_conf.ClusterBasicParam{}
+		rst.Basic = &icluster_conf.ClusterBasicParam{
+			Protocol:                 basic.Protocol,
+			BalanceMode:              basic.BalanceMode,
+			OutlierDetectionHttpCode: basic.OutlierDetectionHttpCode,
+		}
 		if conn := basic.Connection; conn != nil {
 			rst.Basic.Connection = &icluster_conf.ClusterBasicConnectionParam{
 				MaxIdleConnPerRs:    conn.MaxIdleConnPerRs,
+				MaxConnPerRs:        conn.MaxConnPerRs,
 				CancelOnClientClose: conn.CancelOnClientClose,
+				ClientIPCarry:       conn.ClientIPCarry,
+				ClientPortCarry:     conn.ClientPortCarry,
 			}
 		}
 
@@ -259,7 +289,7 @@ func CreateActionProcess(req *http.Request, _param *UpsertParam) (*ClusterData,
 		return nil, err
 	}
 
-	param := clusterParamControlModel(_param)
+	param := ClusterParamControlModel(_param)
 
 	err = container.ClusterManager.CreateCluster(req.Context(), product, param)
 	if err != nil {
@@ -273,7 +303,7 @@ func CreateActionProcess(req *http.Request, _param *UpsertParam) (*ClusterData,
 		return nil, err
 	}
 
-	return clusterModel2Control(cluster), nil
+	return ClusterModel2Control(cluster), nil
 }
 
 var _ xreq.Handler = CreateAction
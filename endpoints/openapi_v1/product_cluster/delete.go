@@ -32,6 +32,8 @@ var DeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(DeleteAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductCluster, iauth.ActionDelete),
+	Param:      &OneParam{},
+	Data:       &ClusterData{},
 }
 
 func deleteActionProcess(req *http.Request, param *OneParam) (*ClusterData, error) {
@@ -51,11 +53,11 @@ func deleteActionProcess(req *http.Request, param *OneParam) (*ClusterData, erro
 		return nil, xerror.WrapRecordNotExist("Cluster")
 	}
 
-	if err := container.ClusterManager.DeleteCluster(req.Context(), product, one); err != nil {
+	if err := container.ClusterManager.DeleteCluster(req.Context(), product, one, xreq.IfMatch(req)); err != nil {
 		return nil, err
 	}
 
-	return clusterModel2Control(one), nil
+	return ClusterModel2Control(one), nil
 }
 
 var _ xreq.Handler = DeleteAction
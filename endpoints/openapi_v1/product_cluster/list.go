@@ -31,6 +31,7 @@ var ListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ListAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductCluster, iauth.ActionRead),
+	Data:       []*ClusterData{},
 }
 
 func listActionProcess(req *http.Request) ([]*ClusterData, error) {
@@ -48,7 +49,7 @@ func listActionProcess(req *http.Request) ([]*ClusterData, error) {
 
 	rsp := []*ClusterData{}
 	for _, one := range list {
-		rsp = append(rsp, clusterModel2Control(one))
+		rsp = append(rsp, ClusterModel2Control(one))
 	}
 	return rsp, nil
 }
@@ -37,6 +37,10 @@ type StickySessions struct {
 // Basic Request Param
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 type Basic struct {
+	Protocol                 string `json:"protocol"`
+	BalanceMode              string `json:"balance_mode"`
+	OutlierDetectionHttpCode string `json:"outlier_detection_http_code"`
+
 	Connection *Connection `json:"connection" uri:"connection"`
 	Retries    *Retries    `json:"retries" uri:"retries"`
 	Buffers    *Buffers    `json:"buffers" uri:"buffers"`
@@ -47,7 +51,10 @@ type Basic struct {
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 type Connection struct {
 	MaxIdleConnPerRs    int16 `json:"max_idle_conn_per_rs" uri:"max_idle_conn_per_rs"`
+	MaxConnPerRs        int32 `json:"max_conn_per_rs"`
 	CancelOnClientClose bool  `json:"cancel_on_client_close" uri:"cancel_on_client_close"`
+	ClientIPCarry       bool  `json:"client_ip_carry"`
+	ClientPortCarry     bool  `json:"client_port_carry"`
 }
 
 // Retries Request Param
@@ -102,6 +109,19 @@ type ClusterData struct {
 	SubClusters []string `json:"sub_clusters"`
 
 	Scheduler map[string]map[string]int `json:"scheduler,omitempty"`
+
+	Revision string `json:"revision"`
+}
+
+var _ xreq.ETagger = &ClusterData{}
+
+// ETag return revision of cluster
+func (cd *ClusterData) ETag() string {
+	if cd == nil {
+		return ""
+	}
+
+	return cd.Revision
 }
 
 type AutoLbMatrix struct {
@@ -111,15 +131,21 @@ type AutoLbMatrix struct {
 	Capacity         map[string]int64 `json:"capacity"`
 }
 
-func clusterModel2Control(cluster *icluster_conf.Cluster) *ClusterData {
+func ClusterModel2Control(cluster *icluster_conf.Cluster) *ClusterData {
 	rsp := &ClusterData{
 		Name:        cluster.Name,
 		Description: cluster.Description,
 		Ready:       cluster.Ready,
 		Basic: &Basic{
+			Protocol:                 cluster.Basic.Protocol,
+			BalanceMode:              cluster.Basic.BalanceMode,
+			OutlierDetectionHttpCode: cluster.Basic.OutlierDetectionHttpCode,
 			Connection: &Connection{
 				MaxIdleConnPerRs:    cluster.Basic.Connection.MaxIdleConnPerRs,
+				MaxConnPerRs:        cluster.Basic.Connection.MaxConnPerRs,
 				CancelOnClientClose: cluster.Basic.Connection.CancelOnClientClose,
+				ClientIPCarry:       cluster.Basic.Connection.ClientIPCarry,
+				ClientPortCarry:     cluster.Basic.Connection.ClientPortCarry,
 			},
 			Retries: &Retries{
 				MaxRetryCrossSubcluster: cluster.Basic.Retries.MaxRetryCrossSubcluster,
@@ -154,6 +180,8 @@ func clusterModel2Control(cluster *icluster_conf.Cluster) *ClusterData {
 		Scheduler: cluster.Scheduler,
 
 		PassiveHealthCheck: PassiveHealthCheckM2C(cluster.PassiveHealthCheck),
+
+		Revision: cluster.Revision(),
 	}
 
 	return rsp
@@ -182,6 +210,8 @@ var OneEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(OneAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductCluster, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &ClusterData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -221,5 +251,5 @@ func oneActionProcess(req *http.Request, param *OneParam) (*ClusterData, error)
 		return nil, xerror.WrapRecordNotExist("Cluster")
 	}
 
-	return clusterModel2Control(one), nil
+	return ClusterModel2Control(one), nil
 }
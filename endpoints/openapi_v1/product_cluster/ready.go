@@ -39,6 +39,8 @@ var ReadyEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ReadyAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductCluster, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &ReadyRspParam{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
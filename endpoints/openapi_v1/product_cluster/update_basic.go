@@ -32,6 +32,8 @@ var UpdateBasicEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UpdateAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductCluster, iauth.ActionUpdate),
+	Param:      &UpsertParam{},
+	Data:       &ClusterData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -74,7 +76,8 @@ func updateActionProcess(req *http.Request, param *UpsertParam) (*ClusterData, e
 		return nil, xerror.WrapRecordNotExist("Cluster")
 	}
 
-	if err := container.ClusterManager.UpdateCluster(req.Context(), product, cluster, clusterParamControlModel(param)); err != nil {
+	if err := container.ClusterManager.UpdateCluster(req.Context(), product, cluster, ClusterParamControlModel(param),
+		xreq.IfMatch(req)); err != nil {
 		return nil, err
 	}
 
@@ -84,7 +87,7 @@ func updateActionProcess(req *http.Request, param *UpsertParam) (*ClusterData, e
 	if err != nil {
 		return nil, err
 	}
-	return clusterModel2Control(cluster), nil
+	return ClusterModel2Control(cluster), nil
 }
 
 var _ xreq.Handler = UpdateAction
@@ -40,6 +40,8 @@ var BindSubClusterEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(BindSubClusterAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductCluster, iauth.ActionUpdate),
+	Param:      &BindSubCluster{},
+	Data:       &ClusterData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -66,7 +68,8 @@ func bindSubClusterActionProcess(req *http.Request, param *BindSubCluster) (*Clu
 		return nil, xerror.WrapRecordNotExist("Cluster")
 	}
 
-	if err = container.ClusterManager.RebindSubCluster(req.Context(), product, cluster, param.SubClusters); err != nil {
+	if err = container.ClusterManager.RebindSubCluster(req.Context(), product, cluster, param.SubClusters,
+		xreq.IfMatch(req)); err != nil {
 		return nil, err
 	}
 
@@ -77,7 +80,7 @@ func bindSubClusterActionProcess(req *http.Request, param *BindSubCluster) (*Clu
 		return nil, err
 	}
 
-	return clusterModel2Control(cluster), nil
+	return ClusterModel2Control(cluster), nil
 }
 
 var _ xreq.Handler = BindSubClusterAction
@@ -40,6 +40,8 @@ var CreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(CreateAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionCreate),
+	Param:      &UpsertParam{},
+	Data:       &OneData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -97,6 +99,7 @@ func Instancesc2i(is []*Instance) []icluster_conf.Instance {
 			Ports:    instance.Ports,
 			Port:     port,
 			Tags:     instance.Tags,
+			Disable:  instance.Disable,
 		})
 	}
 
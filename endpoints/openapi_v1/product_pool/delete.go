@@ -30,6 +30,8 @@ var DeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(DeleteAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionDelete),
+	Param:      &OneParam{},
+	Data:       &OneData{},
 }
 
 var _ xreq.Handler = DeleteAction
@@ -47,7 +49,7 @@ func DeleteAction(req *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	oldOne, err := container.PoolManager.DeleteProductPool(req.Context(), product, param.InstancePoolName)
+	oldOne, err := container.PoolManager.DeleteProductPool(req.Context(), product, param.InstancePoolName, xreq.IfMatch(req))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,195 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package product_pool
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// InstanceParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type InstanceParam struct {
+	InstancePoolName string `uri:"instance_pool_name" validate:"required,min=2"`
+	Instance         string `uri:"instance" validate:"required,min=1"`
+}
+
+// InstancePatchParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type InstancePatchParam struct {
+	InstancePoolName string `uri:"instance_pool_name" validate:"required,min=2"`
+	Instance         string `uri:"instance" validate:"required,min=1"`
+
+	Weight  *int64 `json:"weight" validate:"omitempty,min=0,max=100"`
+	Disable *bool  `json:"disable"`
+}
+
+// InstanceAddParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type InstanceAddParam struct {
+	InstancePoolName string `uri:"instance_pool_name" validate:"required,min=2"`
+
+	Instance
+}
+
+// AddInstanceRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var AddInstanceEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/instance-pools/{instance_pool_name}/instances",
+	Method:     http.MethodPost,
+	Handler:    xreq.Convert(AddInstanceAction),
+	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionUpdate),
+	Param:      &InstanceAddParam{},
+	Data:       &OneData{},
+}
+
+// UpdateInstanceRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpdateInstanceEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/instance-pools/{instance_pool_name}/instances/{instance}",
+	Method:     http.MethodPatch,
+	Handler:    xreq.Convert(UpdateInstanceAction),
+	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionUpdate),
+	Param:      &InstancePatchParam{},
+	Data:       &OneData{},
+}
+
+// DeleteInstanceRoute route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var DeleteInstanceEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/instance-pools/{instance_pool_name}/instances/{instance}",
+	Method:     http.MethodDelete,
+	Handler:    xreq.Convert(DeleteInstanceAction),
+	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionUpdate),
+	Param:      &InstanceParam{},
+	Data:       &OneData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func NewInstanceAddParam(req *http.Request) (*InstanceAddParam, error) {
+	param := &InstanceAddParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func NewInstancePatchParam(req *http.Request) (*InstancePatchParam, error) {
+	param := &InstancePatchParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func NewInstanceParam(req *http.Request) (*InstanceParam, error) {
+	param := &InstanceParam{}
+	err := xreq.BindURI(req, param)
+	return param, err
+}
+
+// AddInstanceProcess append one instance to pool of product
+func AddInstanceProcess(req *http.Request, product *ibasic.Product, param *InstanceAddParam) (*OneData, error) {
+	instance := Instancesc2i([]*Instance{&param.Instance})[0]
+	pool, err := container.PoolManager.AddPoolInstance(req.Context(), product, param.InstancePoolName,
+		instance, xreq.IfMatch(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOneData(pool), nil
+}
+
+// UpdateInstanceProcess reweight, enable or disable instances of pool of product
+func UpdateInstanceProcess(req *http.Request, product *ibasic.Product, param *InstancePatchParam) (*OneData, error) {
+	pool, err := container.PoolManager.UpdatePoolInstance(req.Context(), product, param.InstancePoolName,
+		param.Instance, &icluster_conf.InstancePatchParam{
+			Weight:  param.Weight,
+			Disable: param.Disable,
+		}, xreq.IfMatch(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOneData(pool), nil
+}
+
+// DeleteInstanceProcess remove instances from pool of product
+func DeleteInstanceProcess(req *http.Request, product *ibasic.Product, param *InstanceParam) (*OneData, error) {
+	pool, err := container.PoolManager.RemovePoolInstance(req.Context(), product, param.InstancePoolName,
+		param.Instance, xreq.IfMatch(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOneData(pool), nil
+}
+
+var _ xreq.Handler = AddInstanceAction
+
+// AddInstanceAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func AddInstanceAction(req *http.Request) (interface{}, error) {
+	param, err := NewInstanceAddParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return AddInstanceProcess(req, product, param)
+}
+
+var _ xreq.Handler = UpdateInstanceAction
+
+// UpdateInstanceAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpdateInstanceAction(req *http.Request) (interface{}, error) {
+	param, err := NewInstancePatchParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateInstanceProcess(req, product, param)
+}
+
+var _ xreq.Handler = DeleteInstanceAction
+
+// DeleteInstanceAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func DeleteInstanceAction(req *http.Request) (interface{}, error) {
+	param, err := NewInstanceParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return DeleteInstanceProcess(req, product, param)
+}
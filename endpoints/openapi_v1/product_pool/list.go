@@ -30,6 +30,8 @@ var ListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ListAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionRead),
+	Param:      &xreq.PageParam{},
+	Data:       []string{},
 }
 
 var _ xreq.Handler = ListAction
@@ -37,12 +39,17 @@ var _ xreq.Handler = ListAction
 // ListAction action
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 func ListAction(req *http.Request) (interface{}, error) {
+	param, err := xreq.NewPageParamFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
 	product, err := ibasic.MustGetProduct(req.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	list, err := container.PoolManager.FetchProductPools(req.Context(), product)
+	list, total, err := container.PoolManager.FetchProductPoolPage(req.Context(), product, param.Pagination())
 	if err != nil {
 		return nil, err
 	}
@@ -51,5 +58,5 @@ func ListAction(req *http.Request) (interface{}, error) {
 	for i, one := range list {
 		rst[i] = one.Name
 	}
-	return rst, nil
+	return param.Result(rst, total), nil
 }
@@ -39,6 +39,7 @@ type Instance struct {
 	Weight   int64             `json:"weight" uri:"weight" validate:"min=0,max=100"`
 	Ports    map[string]int    `json:"ports" uri:"ports" validate:"required,min=1"`
 	Tags     map[string]string `json:"tags" uri:"tags" validate:"required,min=1"`
+	Disable  bool              `json:"disable"`
 }
 
 // OneData Request Param
@@ -46,6 +47,18 @@ type Instance struct {
 type OneData struct {
 	Name      string      `json:"name" uri:"name"`
 	Instances []*Instance `json:"instances" uri:"instances"`
+	Revision  string      `json:"revision"`
+}
+
+var _ xreq.ETagger = &OneData{}
+
+// ETag return revision of pool
+func (od *OneData) ETag() string {
+	if od == nil {
+		return ""
+	}
+
+	return od.Revision
 }
 
 func NewOneData(pool *icluster_conf.Pool) *OneData {
@@ -57,12 +70,14 @@ func NewOneData(pool *icluster_conf.Pool) *OneData {
 			Weight:   one.Weight,
 			Ports:    one.Ports,
 			Tags:     one.Tags,
+			Disable:  one.Disable,
 		})
 	}
 
 	return &OneData{
 		Name:      pool.Name,
 		Instances: is,
+		Revision:  pool.Revision(),
 	}
 }
 
@@ -73,6 +88,8 @@ var OneEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(OneAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &OneData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -32,6 +32,8 @@ var UpdateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UpdateAction),
 	Authorizer: iauth.FAP(iauth.FeatureProductPool, iauth.ActionUpdate),
+	Param:      &UpsertParam{},
+	Data:       &OneData{},
 }
 
 var _ xreq.Handler = UpdateAction
@@ -58,9 +60,15 @@ func UpdateAction(req *http.Request) (interface{}, error) {
 
 	err = container.PoolManager.UpdateProductPool(req.Context(), product, one, &icluster_conf.PoolParam{
 		Instances: Instancesc2i(param.Instances),
-	})
+	}, xreq.IfMatch(req))
+	if err != nil {
+		return nil, err
+	}
 
-	one.Instances = Instancesc2i(param.Instances)
+	one, err = container.PoolManager.FetchProductPool(req.Context(), product, *param.Name)
+	if err != nil {
+		return nil, err
+	}
 
-	return NewOneData(one), err
+	return NewOneData(one), nil
 }
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redirect_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// RedirectRule one redirect rule, the first matched rule takes effect
+type RedirectRule struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=1024"`
+	Cond        string `json:"cond" validate:"required,min=1,max=4096"`
+	Cmd         string `json:"cmd" validate:"required,oneof=URL_SET URL_PREFIX_ADD SCHEME_SET"`
+	Param       string `json:"param" validate:"required,min=1,max=4096"`
+	Status      int    `json:"status" validate:"required,oneof=301 302 303 307 308"`
+}
+
+// RedirectRulesData Request Param / Response Data
+type RedirectRulesData struct {
+	Rules []*RedirectRule `json:"rules" validate:"dive"`
+}
+
+func newRedirectRulesData(list []*imodule_conf.RedirectRule) *RedirectRulesData {
+	rules := []*RedirectRule{}
+	for _, one := range list {
+		rules = append(rules, &RedirectRule{
+			Name:        one.Name,
+			Description: one.Description,
+			Cond:        one.Cond,
+			Cmd:         one.Cmd,
+			Param:       one.Param,
+			Status:      one.Status,
+		})
+	}
+
+	return &RedirectRulesData{
+		Rules: rules,
+	}
+}
+
+// ListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ListEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/redirect-rules",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ListAction),
+	Authorizer: iauth.FAP(iauth.FeatureRedirectRule, iauth.ActionRead),
+	Data:       &RedirectRulesData{},
+}
+
+func listActionProcess(req *http.Request) (*RedirectRulesData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.RedirectRuleManager.FetchProductRedirectRules(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRedirectRulesData(list), nil
+}
+
+var _ xreq.Handler = ListAction
+
+// ListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ListAction(req *http.Request) (interface{}, error) {
+	return listActionProcess(req)
+}
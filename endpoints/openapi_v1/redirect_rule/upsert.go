@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redirect_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpsertEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpsertEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/redirect-rules",
+	Method:     http.MethodPut,
+	Handler:    xreq.Convert(UpsertAction),
+	Authorizer: iauth.FAP(iauth.FeatureRedirectRule, iauth.ActionUpdate),
+	Param:      &RedirectRulesData{},
+	Data:       &RedirectRulesData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpsertParam(req *http.Request) (*RedirectRulesData, error) {
+	param := &RedirectRulesData{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+func redirectRulesToModel(param *RedirectRulesData) []*imodule_conf.RedirectRule {
+	rules := []*imodule_conf.RedirectRule{}
+	for _, one := range param.Rules {
+		rules = append(rules, &imodule_conf.RedirectRule{
+			Name:        one.Name,
+			Description: one.Description,
+			Cond:        one.Cond,
+			Cmd:         one.Cmd,
+			Param:       one.Param,
+			Status:      one.Status,
+		})
+	}
+
+	return rules
+}
+
+func upsertActionProcess(req *http.Request, param *RedirectRulesData) (*RedirectRulesData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.RedirectRuleManager.UpsertProductRedirectRules(req.Context(), product, redirectRulesToModel(param))
+	if err != nil {
+		return nil, err
+	}
+
+	return newRedirectRulesData(list), nil
+}
+
+var _ xreq.Handler = UpsertAction
+
+// UpsertAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpsertAction(req *http.Request) (interface{}, error) {
+	param, err := newUpsertParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return upsertActionProcess(req, param)
+}
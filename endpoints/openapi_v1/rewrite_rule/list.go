@@ -0,0 +1,103 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rewrite_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// Action Rewrite Action
+type Action struct {
+	Cmd    *string  `json:"cmd" validate:"required,oneof=HOST_SET HOST_SET_FROM_PATH_PREFIX PATH_SET PATH_PREFIX_ADD PATH_PREFIX_TRIM QUERY_ADD QUERY_DEL QUERY_RENAME QUERY_DEL_ALL_EXCEPT"`
+	Params []string `json:"params"`
+}
+
+// RewriteRule one rewrite rule, rules are executed in order
+type RewriteRule struct {
+	Name        string    `json:"name" validate:"required,min=1,max=255"`
+	Description string    `json:"description" validate:"max=1024"`
+	Cond        string    `json:"cond" validate:"required,min=1,max=4096"`
+	Actions     []*Action `json:"actions" validate:"required,min=1,dive"`
+	Last        bool      `json:"last"`
+}
+
+// RewriteRulesData Request Param / Response Data
+type RewriteRulesData struct {
+	Rules []*RewriteRule `json:"rules" validate:"dive"`
+}
+
+func newRewriteRulesData(list []*imodule_conf.RewriteRule) *RewriteRulesData {
+	rules := []*RewriteRule{}
+	for _, rule := range list {
+		actions := []*Action{}
+		for _, one := range rule.Actions {
+			cmd := one.Cmd
+			actions = append(actions, &Action{
+				Cmd:    &cmd,
+				Params: one.Params,
+			})
+		}
+
+		rules = append(rules, &RewriteRule{
+			Name:        rule.Name,
+			Description: rule.Description,
+			Cond:        rule.Cond,
+			Actions:     actions,
+			Last:        rule.Last,
+		})
+	}
+
+	return &RewriteRulesData{
+		Rules: rules,
+	}
+}
+
+// ListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var ListEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/rewrite-rules",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(ListAction),
+	Authorizer: iauth.FAP(iauth.FeatureRewriteRule, iauth.ActionRead),
+	Data:       &RewriteRulesData{},
+}
+
+func listActionProcess(req *http.Request) (*RewriteRulesData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.RewriteRuleManager.FetchProductRewriteRules(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRewriteRulesData(list), nil
+}
+
+var _ xreq.Handler = ListAction
+
+// ListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func ListAction(req *http.Request) (interface{}, error) {
+	return listActionProcess(req)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rewrite_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpsertEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpsertEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/rewrite-rules",
+	Method:     http.MethodPut,
+	Handler:    xreq.Convert(UpsertAction),
+	Authorizer: iauth.FAP(iauth.FeatureRewriteRule, iauth.ActionUpdate),
+	Param:      &RewriteRulesData{},
+	Data:       &RewriteRulesData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpsertParam(req *http.Request) (*RewriteRulesData, error) {
+	param := &RewriteRulesData{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+func rewriteRulesToModel(param *RewriteRulesData) []*imodule_conf.RewriteRule {
+	rules := []*imodule_conf.RewriteRule{}
+	for _, rule := range param.Rules {
+		actions := []*imodule_conf.RewriteAction{}
+		for _, one := range rule.Actions {
+			actions = append(actions, &imodule_conf.RewriteAction{
+				Cmd:    *one.Cmd,
+				Params: one.Params,
+			})
+		}
+
+		rules = append(rules, &imodule_conf.RewriteRule{
+			Name:        rule.Name,
+			Description: rule.Description,
+			Cond:        rule.Cond,
+			Actions:     actions,
+			Last:        rule.Last,
+		})
+	}
+
+	return rules
+}
+
+func upsertActionProcess(req *http.Request, param *RewriteRulesData) (*RewriteRulesData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.RewriteRuleManager.UpsertProductRewriteRules(req.Context(), product, rewriteRulesToModel(param))
+	if err != nil {
+		return nil, err
+	}
+
+	return newRewriteRulesData(list), nil
+}
+
+var _ xreq.Handler = UpsertAction
+
+// UpsertAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpsertAction(req *http.Request) (interface{}, error) {
+	param, err := newUpsertParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return upsertActionProcess(req, param)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// CaseCreateParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type CaseCreateParam struct {
+	Description   *string           `json:"description"`
+	URL           *string           `json:"url" validate:"required,min=1"`
+	Method        *string           `json:"method" validate:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Header        map[string]string `json:"header"`
+	ExpectCluster *string           `json:"expect_cluster" validate:"required,min=1"`
+}
+
+// CaseCreateEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var CaseCreateEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/routes/cases",
+	Method:     http.MethodPost,
+	Handler:    xreq.Convert(CaseCreateAction),
+	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionCreate),
+	Param:      &CaseCreateParam{},
+	Data:       &RouteCaseData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newCaseCreateParam(req *http.Request) (*CaseCreateParam, error) {
+	param := &CaseCreateParam{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+func caseCreateActionProcess(req *http.Request, param *CaseCreateParam) (*RouteCaseData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	method := param.Method
+	if method == nil {
+		method = lib.PString(http.MethodGet)
+	}
+
+	rc, err := container.RouteRuleManager.CreateRouteCase(req.Context(), product, &iroute_conf.RouteCaseParam{
+		Description:   param.Description,
+		URL:           param.URL,
+		Method:        method,
+		Header:        param.Header,
+		ExpectCluster: param.ExpectCluster,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRouteCaseData(rc), nil
+}
+
+var _ xreq.Handler = CaseCreateAction
+
+// CaseCreateAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func CaseCreateAction(req *http.Request) (interface{}, error) {
+	param, err := newCaseCreateParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return caseCreateActionProcess(req, param)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// CaseDeleteEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var CaseDeleteEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/routes/cases/{case_id}",
+	Method:     http.MethodDelete,
+	Handler:    xreq.Convert(CaseDeleteAction),
+	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionDelete),
+	Param:      &CaseOneParam{},
+	Data:       &RouteCaseData{},
+}
+
+var _ xreq.Handler = CaseDeleteAction
+
+// CaseDeleteAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func CaseDeleteAction(req *http.Request) (interface{}, error) {
+	param, err := newCaseOneParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := oneRouteCase(req, *param.CaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := container.RouteRuleManager.DeleteRouteCase(req.Context(), product, rc); err != nil {
+		return nil, err
+	}
+
+	return newRouteCaseData(rc), nil
+}
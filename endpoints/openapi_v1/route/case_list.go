@@ -0,0 +1,62 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// CaseListEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var CaseListEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/routes/cases",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(CaseListAction),
+	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionRead),
+	Data:       []*RouteCaseData{},
+}
+
+// caseListActionProcess list route cases with the result of running them against current route rules
+func caseListActionProcess(req *http.Request) ([]*RouteCaseData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := container.RouteRuleManager.RunRouteCases(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*RouteCaseData{}
+	for _, one := range results {
+		list = append(list, newRouteCaseResultData(one))
+	}
+
+	return list, nil
+}
+
+var _ xreq.Handler = CaseListAction
+
+// CaseListAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func CaseListAction(req *http.Request) (interface{}, error) {
+	return caseListActionProcess(req)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// CaseOneParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type CaseOneParam struct {
+	CaseID *int64 `json:"-" uri:"case_id" validate:"required,min=1"`
+}
+
+// RouteCaseData Response Data
+type RouteCaseData struct {
+	ID            int64             `json:"id"`
+	Description   string            `json:"description"`
+	URL           string            `json:"url"`
+	Method        string            `json:"method"`
+	Header        map[string]string `json:"header"`
+	ExpectCluster string            `json:"expect_cluster"`
+
+	ActualCluster *string `json:"actual_cluster,omitempty"`
+	Pass          *bool   `json:"pass,omitempty"`
+}
+
+func newRouteCaseData(rc *iroute_conf.RouteRuleCase) *RouteCaseData {
+	header := rc.Header
+	if header == nil {
+		header = map[string]string{}
+	}
+
+	return &RouteCaseData{
+		ID:            rc.ID,
+		Description:   rc.Description,
+		URL:           rc.URL,
+		Method:        rc.Method,
+		Header:        header,
+		ExpectCluster: rc.ExpectCluster,
+	}
+}
+
+func newRouteCaseResultData(result *iroute_conf.RouteRuleRunCaseResult) *RouteCaseData {
+	data := newRouteCaseData(&result.RouteRuleCase)
+	data.ActualCluster = &result.ActualCluster
+	data.Pass = &result.Pass
+
+	return data
+}
+
+// CaseOneEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var CaseOneEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/routes/cases/{case_id}",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(CaseOneAction),
+	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionRead),
+	Param:      &CaseOneParam{},
+	Data:       &RouteCaseData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newCaseOneParam(req *http.Request) (*CaseOneParam, error) {
+	param := &CaseOneParam{}
+	err := xreq.BindURI(req, param)
+	return param, err
+}
+
+func oneRouteCase(req *http.Request, caseID int64) (*iroute_conf.RouteRuleCase, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := container.RouteRuleManager.RouteCaseList(req.Context(), &iroute_conf.RouteCaseFilter{
+		Product: product,
+		ID:      &caseID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, xerror.WrapRecordNotExist("Route Case")
+	}
+
+	return list[0], nil
+}
+
+var _ xreq.Handler = CaseOneAction
+
+// CaseOneAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func CaseOneAction(req *http.Request) (interface{}, error) {
+	param, err := newCaseOneParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := oneRouteCase(req, *param.CaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRouteCaseData(rc), nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// CaseUpdateParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type CaseUpdateParam struct {
+	CaseID *int64 `json:"-" uri:"case_id" validate:"required,min=1"`
+
+	Description   *string           `json:"description"`
+	URL           *string           `json:"url" validate:"omitempty,min=1"`
+	Method        *string           `json:"method" validate:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Header        map[string]string `json:"header"`
+	ExpectCluster *string           `json:"expect_cluster" validate:"omitempty,min=1"`
+}
+
+// CaseUpdateEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var CaseUpdateEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/routes/cases/{case_id}",
+	Method:     http.MethodPatch,
+	Handler:    xreq.Convert(CaseUpdateAction),
+	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionUpdate),
+	Param:      &CaseUpdateParam{},
+	Data:       &RouteCaseData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newCaseUpdateParam(req *http.Request) (*CaseUpdateParam, error) {
+	param := &CaseUpdateParam{}
+	err := xreq.Bind(req, param)
+	return param, err
+}
+
+func caseUpdateActionProcess(req *http.Request, param *CaseUpdateParam) (*RouteCaseData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	oldOne, err := oneRouteCase(req, *param.CaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := container.RouteRuleManager.UpdateRouteCase(req.Context(), product, oldOne, &iroute_conf.RouteCaseParam{
+		Description:   param.Description,
+		URL:           param.URL,
+		Method:        param.Method,
+		Header:        param.Header,
+		ExpectCluster: param.ExpectCluster,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRouteCaseData(rc), nil
+}
+
+var _ xreq.Handler = CaseUpdateAction
+
+// CaseUpdateAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func CaseUpdateAction(req *http.Request) (interface{}, error) {
+	param, err := newCaseUpdateParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return caseUpdateActionProcess(req, param)
+}
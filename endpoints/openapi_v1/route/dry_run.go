@@ -0,0 +1,145 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// DryRunParam Request Param
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+type DryRunParam struct {
+	URL      string            `json:"url" validate:"required,min=1"`
+	Method   string            `json:"method" validate:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Header   map[string]string `json:"header"`
+	Cookies  map[string]string `json:"cookies"`
+	ClientIP string            `json:"client_ip" validate:"omitempty,ip"`
+
+	// Rules to be evaluated, the rules stored will be used if not set
+	Rules *ProductRouteRuleParam `json:"rules"`
+}
+
+// DryRunData Response Data
+type DryRunData struct {
+	Matched     bool   `json:"matched"`
+	ClusterName string `json:"cluster_name"`
+
+	BasicRouteRule   *BasicRouteRule   `json:"basic_forward_rule"`
+	AdvanceRouteRule *AdvanceRouteRule `json:"forward_rule"`
+}
+
+// DryRunEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var DryRunEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/routes/dry-run",
+	Method:     http.MethodPost,
+	Handler:    xreq.Convert(DryRunAction),
+	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionRead),
+	Param:      &DryRunParam{},
+	Data:       &DryRunData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newDryRunParam(req *http.Request) (*DryRunParam, error) {
+	param := &DryRunParam{}
+	if err := xreq.BindJSON(req, param); err != nil {
+		return nil, err
+	}
+
+	if param.Rules != nil {
+		for _, one := range param.Rules.AdvanceRouteRules {
+			if one == nil {
+				return nil, xerror.WrapParamErrorWithMsg("AdvanceRouteRules element cant be nil")
+			}
+		}
+		for _, one := range param.Rules.BasicRouteRules {
+			if one == nil {
+				return nil, xerror.WrapParamErrorWithMsg("BasicRouteRules element cant be nil")
+			}
+		}
+	}
+
+	return param, nil
+}
+
+func dryRunActionProcess(req *http.Request, param *DryRunParam) (*DryRunData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	var rule *iroute_conf.ProductRouteRule
+	if param.Rules != nil {
+		rule = RouteRuleParam2RouteRule(param.Rules)
+	}
+
+	mr, err := container.RouteRuleManager.RouteDryRun(req.Context(), product, &iroute_conf.RouteDryRunParam{
+		URL:      param.URL,
+		Method:   param.Method,
+		Header:   param.Header,
+		Cookies:  param.Cookies,
+		ClientIP: param.ClientIP,
+		Rule:     rule,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &DryRunData{}
+	if mr == nil {
+		return data, nil
+	}
+
+	data.Matched = true
+	data.ClusterName = mr.ClusterName
+	if one := mr.BasicRouteRule; one != nil {
+		data.BasicRouteRule = &BasicRouteRule{
+			HostNames:   one.HostNames,
+			Paths:       one.Paths,
+			Description: one.Description,
+			ClusterName: one.ClusterName,
+		}
+	}
+	if one := mr.AdvanceRouteRule; one != nil {
+		data.AdvanceRouteRule = &AdvanceRouteRule{
+			Name:        one.Name,
+			Description: one.Description,
+			Expression:  one.Expression,
+			ClusterName: one.ClusterName,
+		}
+	}
+
+	return data, nil
+}
+
+var _ xreq.Handler = DryRunAction
+
+// DryRunAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func DryRunAction(req *http.Request) (interface{}, error) {
+	param, err := newDryRunParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return dryRunActionProcess(req, param)
+}
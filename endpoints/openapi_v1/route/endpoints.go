@@ -22,4 +22,11 @@ var Endpoints = []*xreq.Endpoint{
 	ListEndpoint,
 	UpsertEndpoint,
 	ExpressionVerifyEndpoint,
+	DryRunEndpoint,
+
+	CaseListEndpoint,
+	CaseOneEndpoint,
+	CaseCreateEndpoint,
+	CaseUpdateEndpoint,
+	CaseDeleteEndpoint,
 }
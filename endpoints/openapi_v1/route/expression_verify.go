@@ -28,6 +28,8 @@ var ExpressionVerifyEndpoint = &xreq.Endpoint{
 	Handler: xreq.Convert(ExpressionVerifyAction),
 
 	Authorizer: nil,
+	Param:      &ExpressionVerifyParam{},
+	Data:       &VerifyResult{},
 }
 
 type ExpressionVerifyParam struct {
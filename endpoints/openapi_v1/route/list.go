@@ -32,9 +32,10 @@ var ListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ListAction),
 	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionRead),
+	Data:       &ProductRouteRuleData{},
 }
 
-func routeRule2routeRuleParam(p *iroute_conf.ProductRouteRule) *ProductRouteRuleParam {
+func RouteRule2RouteRuleParam(p *iroute_conf.ProductRouteRule) *ProductRouteRuleParam {
 	afrs := []*AdvanceRouteRule{}
 	for _, one := range p.AdvanceRouteRules {
 		afrs = append(afrs, &AdvanceRouteRule{
@@ -76,11 +77,22 @@ func listActionProcess(req *http.Request) (*ProductRouteRuleData, error) {
 		return nil, err
 	}
 
-	if rule == nil {
-		return nullRule, nil
+	caseResults, err := container.RouteRuleManager.RunRouteCases(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &ProductRouteRuleData{
+		BasicRouteRules:   nullRule.BasicRouteRules,
+		AdvanceRouteRules: nullRule.AdvanceRouteRules,
+	}
+	if rule != nil {
+		data = newProductRouteRuleData(RouteRule2RouteRuleParam(rule))
 	}
+	data.setRouteCases(caseResults)
+	data.Revision = rule.Revision()
 
-	return newProductRouteRuleData(routeRule2routeRuleParam(rule)), nil
+	return data, nil
 }
 
 var nullRule = &ProductRouteRuleData{
@@ -70,6 +70,47 @@ type ProductRouteRuleData struct {
 	AdvanceRouteRules []*AdvanceRouteRule `json:"forward_rules"`
 
 	RouteCasesCode int `json:"forward_cases_code,omitempty"`
+	// FailedRouteCases lists route cases fail when RouteCasesCode is RouteCasesCodeFail
+	FailedRouteCases []*RouteCaseData `json:"failed_forward_cases,omitempty"`
+
+	Revision string `json:"revision"`
+}
+
+var _ xreq.ETagger = &ProductRouteRuleData{}
+
+// ETag return revision of route rules
+func (prrd *ProductRouteRuleData) ETag() string {
+	if prrd == nil {
+		return ""
+	}
+
+	return prrd.Revision
+}
+
+const (
+	// RouteCasesCodeNone no route case configured
+	RouteCasesCodeNone = 0
+	// RouteCasesCodePass all route cases pass
+	RouteCasesCodePass = 1
+	// RouteCasesCodeFail some route cases fail
+	RouteCasesCodeFail = 2
+)
+
+// setRouteCases set RouteCasesCode of data by results of route cases, with the cases fail
+func (prrd *ProductRouteRuleData) setRouteCases(results []*iroute_conf.RouteRuleRunCaseResult) {
+	prrd.RouteCasesCode = RouteCasesCodeNone
+	prrd.FailedRouteCases = nil
+	if len(results) == 0 {
+		return
+	}
+
+	prrd.RouteCasesCode = RouteCasesCodePass
+	for _, one := range results {
+		if !one.Pass {
+			prrd.RouteCasesCode = RouteCasesCodeFail
+			prrd.FailedRouteCases = append(prrd.FailedRouteCases, newRouteCaseResultData(one))
+		}
+	}
 }
 
 func newProductRouteRuleData(pfr *ProductRouteRuleParam) *ProductRouteRuleData {
@@ -88,7 +129,7 @@ func newProductRouteRule(req *http.Request) (*ProductRouteRuleParam, error) {
 	return pfr, nil
 }
 
-func routeRuleParam2routeRule(p *ProductRouteRuleParam) *iroute_conf.ProductRouteRule {
+func RouteRuleParam2RouteRule(p *ProductRouteRuleParam) *iroute_conf.ProductRouteRule {
 	afrs := []*iroute_conf.AdvanceRouteRule{}
 	for _, one := range p.AdvanceRouteRules {
 		afrs = append(afrs, &iroute_conf.AdvanceRouteRule{
@@ -126,6 +167,8 @@ var UpsertEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UpsertAction),
 	Authorizer: iauth.FAP(iauth.FeatureRoute, iauth.ActionUpdate),
+	Param:      &ProductRouteRuleParam{},
+	Data:       &ProductRouteRuleData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -156,14 +199,22 @@ func UpsertActionProcess(req *http.Request, rule *ProductRouteRuleParam) (*Produ
 		return nil, err
 	}
 
-	ipfr := routeRuleParam2routeRule(rule)
+	ipfr := RouteRuleParam2RouteRule(rule)
 
-	err = container.RouteRuleManager.UpsertProductRule(req.Context(), product, ipfr)
+	caseResults, err := container.RouteRuleManager.UpsertProductRule(req.Context(), product, ipfr, xreq.IfMatch(req))
+	data := newProductRouteRuleData(rule)
+	data.setRouteCases(caseResults)
 	if err != nil {
+		// rule is rejected if any route case fails, return the cases fail with the error
+		if data.RouteCasesCode == RouteCasesCodeFail {
+			return data, err
+		}
 		return nil, err
 	}
 
-	return newProductRouteRuleData(rule), nil
+	data.Revision = ipfr.Revision()
+
+	return data, nil
 }
 
 var _ xreq.Handler = UpsertAction
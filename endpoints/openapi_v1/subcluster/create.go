@@ -30,6 +30,9 @@ type CreateParam struct {
 	Name         *string `json:"name" uri:"name" validate:"required,min=2"`
 	InstancePool *string `json:"instance_pool" uri:"instance_pool" validate:"required,min=2"`
 	Description  *string `json:"description" uri:"description" validate:"omitempty,min=2"`
+
+	PortName         *string           `json:"port_name" validate:"omitempty,min=1"`
+	InstanceSelector map[string]string `json:"instance_selector"`
 }
 
 // CreateRoute route
@@ -39,6 +42,8 @@ var CreateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPost,
 	Handler:    xreq.Convert(CreateAction),
 	Authorizer: iauth.FAP(iauth.FeatureSubCluster, iauth.ActionCreate),
+	Param:      &CreateParam{},
+	Data:       &OneData{},
 }
 
 var _ xreq.Handler = CreateAction
@@ -74,6 +79,9 @@ func CreateProcess(req *http.Request, param *CreateParam) (*OneData, error) {
 			Product:     product,
 			PoolName:    param.InstancePool,
 			Description: param.Description,
+
+			PortName:         param.PortName,
+			InstanceSelector: param.InstanceSelector,
 		})
 	if err != nil {
 		return nil, err
@@ -87,5 +95,5 @@ func CreateProcess(req *http.Request, param *CreateParam) (*OneData, error) {
 		return nil, err
 	}
 
-	return newOneData(newCluster), nil
+	return NewOneData(newCluster), nil
 }
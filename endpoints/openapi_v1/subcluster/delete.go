@@ -32,6 +32,8 @@ var DeleteEndpoint = &xreq.Endpoint{
 	Method:     http.MethodDelete,
 	Handler:    xreq.Convert(DeleteAction),
 	Authorizer: iauth.FAP(iauth.FeatureSubCluster, iauth.ActionDelete),
+	Param:      &OneParam{},
+	Data:       &OneData{},
 }
 
 var _ xreq.Handler = DeleteAction
@@ -72,5 +74,5 @@ func deleteActionProcess(req *http.Request, param *OneParam) (*OneData, error) {
 		return nil, err
 	}
 
-	return newOneData(subCluster), nil
+	return NewOneData(subCluster), nil
 }
@@ -31,6 +31,8 @@ var ListEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(ListAction),
 	Authorizer: iauth.FAP(iauth.FeatureSubCluster, iauth.ActionRead),
+	Param:      &xreq.PageParam{},
+	Data:       []*OneData{},
 }
 
 var _ xreq.Handler = ListAction
@@ -38,33 +40,44 @@ var _ xreq.Handler = ListAction
 // ListAction action
 // AUTO GEN BY ctrl, MODIFY AS U NEED
 func ListAction(req *http.Request) (interface{}, error) {
-	return listActionProcess(req)
+	param, err := xreq.NewPageParamFromReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	list, total, err := listActionProcess(req, param)
+	if err != nil {
+		return nil, err
+	}
+
+	return param.Result(list, total), nil
 }
 
-func listActionProcess(req *http.Request) ([]*OneData, error) {
+func listActionProcess(req *http.Request, param *xreq.PageParam) ([]*OneData, int64, error) {
 	// get product info
 	product, err := ibasic.MustGetProduct(req.Context())
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	subClusterList, err := container.SubClusterManager.SubClusterList(req.Context(),
+	subClusterList, total, err := container.SubClusterManager.SubClusterPage(req.Context(),
 		&icluster_conf.SubClusterFilter{
-			Product: product,
+			Product:    product,
+			Pagination: param.Pagination(),
 		})
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if len(subClusterList) == 0 {
-		return nil, nil
+		return nil, total, nil
 	}
 
 	list := make([]*OneData, len(subClusterList))
 	for i, one := range subClusterList {
-		list[i] = newOneData(one)
+		list[i] = NewOneData(one)
 
 	}
-	return list, nil
+	return list, total, nil
 }
@@ -33,6 +33,9 @@ type OneData struct {
 	Description  string `json:"description" uri:"description"`
 	Ready        bool   `json:"ready" uri:"ready"`
 	ProductName  string `json:"product_name,omitempty"`
+
+	PortName         string            `json:"port_name"`
+	InstanceSelector map[string]string `json:"instance_selector"`
 }
 
 // OneParam Request Param
@@ -48,6 +51,8 @@ var OneEndpoint = &xreq.Endpoint{
 	Method:     http.MethodGet,
 	Handler:    xreq.Convert(OneAction),
 	Authorizer: iauth.FAP(iauth.FeatureSubCluster, iauth.ActionRead),
+	Param:      &OneParam{},
+	Data:       &OneData{},
 }
 
 var _ xreq.Handler = OneAction
@@ -89,14 +94,14 @@ func oneActionProcess(req *http.Request, param *OneParam) (*OneData, error) {
 		return nil, xerror.WrapRecordNotExist()
 	}
 
-	return newOneData(subCluster), nil
+	return NewOneData(subCluster), nil
 }
 
 func one(req *http.Request, subCluster *icluster_conf.SubClusterFilter) (*icluster_conf.SubCluster, error) {
 	return container.SubClusterManager.FetchSubCluster(req.Context(), subCluster)
 }
 
-func newOneData(sc *icluster_conf.SubCluster) *OneData {
+func NewOneData(sc *icluster_conf.SubCluster) *OneData {
 	if sc == nil {
 		return nil
 	}
@@ -105,6 +110,9 @@ func newOneData(sc *icluster_conf.SubCluster) *OneData {
 		Description: sc.Description,
 		Ready:       sc.Ready,
 		ProductName: sc.ProductName,
+
+		PortName:         sc.PortName,
+		InstanceSelector: sc.InstanceSelector,
 	}
 
 	if sc.InstancePool != nil {
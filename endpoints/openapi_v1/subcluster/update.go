@@ -31,6 +31,9 @@ type UpdateParam struct {
 	Name           *string `json:"name" uri:"name" validate:"min=2"`
 	Description    *string `json:"description" uri:"description" validate:"omitempty,min=2"`
 	SubClusterName *string `uri:"sub_cluster_name" validate:"required,min=2"`
+
+	PortName         *string           `json:"port_name"`
+	InstanceSelector map[string]string `json:"instance_selector"`
 }
 
 // UpdateRoute route
@@ -40,6 +43,8 @@ var UpdateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(UpdateAction),
 	Authorizer: iauth.FAP(iauth.FeatureSubCluster, iauth.ActionUpdate),
+	Param:      &UpdateParam{},
+	Data:       &OneData{},
 }
 
 var _ xreq.Handler = UpdateAction
@@ -83,6 +88,9 @@ func updateActionProcess(req *http.Request, param *UpdateParam) (*OneData, error
 
 	err = container.SubClusterManager.UpdateSubCluster(req.Context(), oldOne, &icluster_conf.SubClusterParam{
 		Description: param.Description,
+
+		PortName:         param.PortName,
+		InstanceSelector: param.InstanceSelector,
 	})
 	if err != nil {
 		return nil, err
@@ -95,5 +103,5 @@ func updateActionProcess(req *http.Request, param *UpdateParam) (*OneData, error
 		return nil, err
 	}
 
-	return newOneData(newSubCluster), nil
+	return NewOneData(newSubCluster), nil
 }
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tls_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// DeleteEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var DeleteEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/tls-rule",
+	Method:     http.MethodDelete,
+	Handler:    xreq.Convert(DeleteAction),
+	Authorizer: iauth.FAP(iauth.FeatureCert, iauth.ActionDelete),
+	Data:       &TLSRuleData{},
+}
+
+func deleteActionProcess(req *http.Request) (*TLSRuleData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := container.CertificateManager.DeleteProductTLSRule(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTLSRuleData(rule), nil
+}
+
+var _ xreq.Handler = DeleteAction
+
+// DeleteAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func DeleteAction(req *http.Request) (interface{}, error) {
+	return deleteActionProcess(req)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tls_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// TLSRuleData certificate bound to product, and tls options of product
+type TLSRuleData struct {
+	CertName      string   `json:"cert_name" validate:"required,min=1,max=255"`
+	Domains       []string `json:"domains" validate:"required,min=1,dive,min=1,max=255"`
+	NextProtos    []string `json:"next_protos" validate:"dive,min=1"`
+	Grade         string   `json:"grade"`
+	Chacha20      bool     `json:"chacha20"`
+	DynamicRecord bool     `json:"dynamic_record"`
+}
+
+// NewTLSRuleData convert tls rule to the data of api, return nil if rule is nil
+func NewTLSRuleData(rule *iprotocol.TLSRule) *TLSRuleData {
+	if rule == nil {
+		return nil
+	}
+
+	nextProtos := rule.NextProtos
+	if nextProtos == nil {
+		nextProtos = []string{}
+	}
+
+	return &TLSRuleData{
+		CertName:      rule.CertName,
+		Domains:       rule.Hosts,
+		NextProtos:    nextProtos,
+		Grade:         rule.Grade,
+		Chacha20:      rule.Chacha20,
+		DynamicRecord: rule.DynamicRecord,
+	}
+}
+
+// OneEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var OneEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/tls-rule",
+	Method:     http.MethodGet,
+	Handler:    xreq.Convert(OneAction),
+	Authorizer: iauth.FAP(iauth.FeatureCert, iauth.ActionRead),
+	Data:       &TLSRuleData{},
+}
+
+func oneActionProcess(req *http.Request) (*TLSRuleData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := container.CertificateManager.FetchProductTLSRule(req.Context(), product)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, xerror.WrapRecordNotExist("TLS Rule")
+	}
+
+	return NewTLSRuleData(rule), nil
+}
+
+var _ xreq.Handler = OneAction
+
+// OneAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func OneAction(req *http.Request) (interface{}, error) {
+	return oneActionProcess(req)
+}
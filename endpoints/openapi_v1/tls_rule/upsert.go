@@ -0,0 +1,82 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tls_rule
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib/xreq"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/stateful/container"
+)
+
+// UpsertEndpoint route
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+var UpsertEndpoint = &xreq.Endpoint{
+	Path:       "/products/{product_name}/tls-rule",
+	Method:     http.MethodPut,
+	Handler:    xreq.Convert(UpsertAction),
+	Authorizer: iauth.FAP(iauth.FeatureCert, iauth.ActionUpdate),
+	Param:      &TLSRuleData{},
+	Data:       &TLSRuleData{},
+}
+
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func newUpsertParam(req *http.Request) (*TLSRuleData, error) {
+	param := &TLSRuleData{}
+	err := xreq.BindJSON(req, param)
+	return param, err
+}
+
+// NewTLSRule convert data of api to tls rule
+func NewTLSRule(param *TLSRuleData) *iprotocol.TLSRule {
+	return &iprotocol.TLSRule{
+		CertName:      param.CertName,
+		Hosts:         param.Domains,
+		NextProtos:    param.NextProtos,
+		Grade:         param.Grade,
+		Chacha20:      param.Chacha20,
+		DynamicRecord: param.DynamicRecord,
+	}
+}
+
+func upsertActionProcess(req *http.Request, param *TLSRuleData) (*TLSRuleData, error) {
+	product, err := ibasic.MustGetProduct(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := container.CertificateManager.UpsertProductTLSRule(req.Context(), product, NewTLSRule(param))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTLSRuleData(rule), nil
+}
+
+var _ xreq.Handler = UpsertAction
+
+// UpsertAction action
+// AUTO GEN BY ctrl, MODIFY AS U NEED
+func UpsertAction(req *http.Request) (interface{}, error) {
+	param, err := newUpsertParam(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return upsertActionProcess(req, param)
+}
@@ -32,6 +32,8 @@ var ManualUpdateEndpoint = &xreq.Endpoint{
 	Method:     http.MethodPatch,
 	Handler:    xreq.Convert(ManualUpdateAction),
 	Authorizer: iauth.FAP(iauth.FeatureTraffic, iauth.ActionUpdate),
+	Param:      map[string]map[string]int{},
+	Data:       &OneData{},
 }
 
 // AUTO GEN BY ctrl, MODIFY AS U NEED
@@ -65,7 +67,7 @@ func manualUpdateActionProcess(req *http.Request, updateParam map[string]map[str
 
 	err = container.ClusterManager.UpdateCluster(req.Context(), product, cluster, &icluster_conf.ClusterParam{
 		Scheduler: updateParam,
-	})
+	}, "")
 	if err != nil {
 		return nil, err
 	}
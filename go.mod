@@ -13,11 +13,14 @@ require (
 	github.com/go-playground/validator/v10 v10.9.0
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/gorilla/mux v1.8.0
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.11.0
 	github.com/rs/cors v1.8.0
+	golang.org/x/crypto v0.0.0-20210711020723-a769d52b0f97
 	gopkg.in/gcfg.v1 v1.2.3
 	gopkg.in/tylerb/graceful.v1 v1.2.15
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 // replace github.com/bfenetworks/bfe => /home/liuqing/Workspace/src/github.com/bfenetworks/bfe
@@ -39,6 +39,16 @@ func NewLogContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, logIDKey, RandomLogID())
 }
 
+// DetachLogContext return a context which is never canceled and carries log id of ctx,
+// used by works shared with other requests
+func DetachLogContext(ctx context.Context) context.Context {
+	if id := ctx.Value(logIDKey); id != nil {
+		return context.WithValue(context.Background(), logIDKey, id)
+	}
+
+	return context.Background()
+}
+
 func GainLogID(ctx context.Context) string {
 	id := ctx.Value(logIDKey)
 	if id == nil {
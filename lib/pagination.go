@@ -0,0 +1,56 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lib
+
+const (
+	SortByName      = "name"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// Pagination describe page, sort and name prefix filter of list query
+type Pagination struct {
+	Page     int    // start from 1
+	PageSize int    // 0 means return all records
+	Sort     string // SortByXXX, default is SortByName
+	Order    string // OrderAsc or OrderDesc, default is OrderAsc
+	Filter   string // name prefix
+}
+
+// Offset return index of first record in page
+func (p *Pagination) Offset() int {
+	if p.Page < 1 {
+		return 0
+	}
+
+	return (p.Page - 1) * p.PageSize
+}
+
+// SortField return field to sort by
+func (p *Pagination) SortField() string {
+	switch p.Sort {
+	case SortByCreatedAt, SortByUpdatedAt:
+		return p.Sort
+	}
+
+	return SortByName
+}
+
+func (p *Pagination) Desc() bool {
+	return p.Order == OrderDesc
+}
@@ -20,15 +20,28 @@ import (
 	"strings"
 )
 
+// SQLConn is implemented by both *sql.DB and *sql.Tx
+type SQLConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type DBContexter interface {
 	context.Context
-	Conn() *sql.DB
+	Conn() SQLConn
+	BlockWrite() bool
 }
 
 type DBContext struct {
 	conn *sql.DB
 	tx   *sql.Tx
 
+	// txnDepth is depth of nested RDBTxnExecute, only the outermost one commit or rollback transaction
+	txnDepth int
+	// blockWrite lock selected rows until transaction be finished
+	blockWrite bool
+
 	context.Context
 }
 
@@ -39,10 +52,29 @@ func NewDBContext(ctx context.Context, conn *sql.DB) *DBContext {
 	}
 }
 
-func (ctx *DBContext) Conn() *sql.DB {
+// Conn return transaction if it be opened, otherwise return db
+func (ctx *DBContext) Conn() SQLConn {
+	if ctx.tx != nil {
+		return ctx.tx
+	}
+
 	return ctx.conn
 }
 
+// BlockWrite return true if rows selected should be locked
+func (ctx *DBContext) BlockWrite() bool {
+	return ctx.blockWrite && ctx.tx != nil
+}
+
+// WithBlockWrite return a copy of ctx whose selected rows will be locked,
+// it must be used in the transaction of ctx
+func (ctx *DBContext) WithBlockWrite() *DBContext {
+	tmp := *ctx
+	tmp.blockWrite = true
+
+	return &tmp
+}
+
 func (ctx *DBContext) BeginTrans() error {
 	if ctx.tx != nil {
 		return nil
@@ -93,6 +125,11 @@ func WantOpenTxn(ops ...*Op) bool {
 type DBContextFactory func(ctx context.Context, ops ...*Op) (*DBContext, error)
 
 func RDBTxnExecute(dc *DBContext, handler func(context.Context) error) error {
+	// nested, transaction will be finished by the outermost one
+	if dc.txnDepth > 0 {
+		return handler(dc)
+	}
+
 	var err error
 	if dc.tx == nil {
 		dc.tx, err = dc.conn.Begin()
@@ -101,8 +138,14 @@ func RDBTxnExecute(dc *DBContext, handler func(context.Context) error) error {
 		}
 	}
 
+	dc.txnDepth++
 	err = handler(dc)
-	return commitOrRollback(dc.tx, err)
+	dc.txnDepth--
+
+	err = commitOrRollback(dc.tx, err)
+	dc.tx = nil
+
+	return err
 }
 
 func commitOrRollback(tx *sql.Tx, err error) error {
@@ -76,6 +76,9 @@ func Resolve(err error) *ResolveResult {
 	case etExistedData:
 		rr.Type = "Record Existed"
 		rr.ErrNo = 555
+	case etPrecondition:
+		rr.Type = "Precondition Failed"
+		rr.ErrNo = 412
 	case etDependentUnReady:
 		rr.Type = "Dependent Not Ready"
 		rr.Msg = err.Error()
@@ -30,6 +30,7 @@ const (
 	etNullData         = "Model.NullData"
 	etDependentUnReady = "Model.DependentUnReady"
 	etExistedData      = "Model.ExistedData"
+	etPrecondition     = "Model.PreconditionFailed"
 	etDao              = "DAO"
 
 	etAuthenticateFail = "Authentication.Fail"
@@ -134,3 +135,8 @@ func WrapDirtyDataError(err error) error {
 func WrapDirtyDataErrorWithMsg(msg string, args ...interface{}) error {
 	return errors.Wrap(fmt.Errorf(msg, args...), etDirtyData)
 }
+
+// WrapPreconditionFailedErrorWithMsg Just Service layout invoke, used when resource be changed by others
+func WrapPreconditionFailedErrorWithMsg(msg string, args ...interface{}) error {
+	return errors.Wrap(fmt.Errorf(msg, args...), etPrecondition)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xreq
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ETagger is implemented by response data which has revision,
+// revision will be returned in ETag header
+type ETagger interface {
+	ETag() string
+}
+
+// IfMatch return revision in If-Match header, empty means any revision be accepted
+func IfMatch(req *http.Request) string {
+	v := strings.TrimSpace(req.Header.Get("If-Match"))
+	if v == "*" {
+		return ""
+	}
+
+	v = strings.TrimPrefix(v, "W/")
+	return strings.Trim(v, `"`)
+}
+
+func setETag(w http.ResponseWriter, res *Result) {
+	if et, ok := res.Data.(ETagger); ok && et.ETag() != "" {
+		w.Header().Set("ETag", `"`+et.ETag()+`"`)
+	}
+}
@@ -0,0 +1,527 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xreq
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPI is document of OpenAPI 3, see https://spec.openapis.org/oas/v3.0.3
+type OpenAPI struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       *OpenAPIInfo                     `json:"info"`
+	Servers    []*OpenAPIServer                 `json:"servers,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components *OpenAPIComponents               `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+type OpenAPIComponents struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+const (
+	openAPIVersion     = "3.0.3"
+	openAPISecurityKey = "Authorization"
+	openAPISchemaRef   = "#/components/schemas/"
+)
+
+var (
+	pathParamRegexp = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// NewOpenAPI build OpenAPI document by reflecting over endpoints,
+// Param and Data of endpoint are used to describe request and response
+func NewOpenAPI(title, version, basePath string, endpoints []*Endpoint) *OpenAPI {
+	doc := &OpenAPI{
+		OpenAPI: openAPIVersion,
+		Info: &OpenAPIInfo{
+			Title:   title,
+			Version: version,
+		},
+		Servers: []*OpenAPIServer{{URL: basePath}},
+		Paths:   map[string]map[string]*Operation{},
+		Components: &OpenAPIComponents{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				openAPISecurityKey: {
+					Type:        "apiKey",
+					Name:        "Authorization",
+					In:          "header",
+					Description: "Token {token} or Session {session key}",
+				},
+			},
+		},
+	}
+
+	for _, ep := range endpoints {
+		path := pathParamRegexp.ReplaceAllString(ep.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*Operation{}
+		}
+		doc.Paths[path][strings.ToLower(ep.Method)] = doc.newOperation(ep, path)
+	}
+
+	return doc
+}
+
+func (doc *OpenAPI) newOperation(ep *Endpoint, path string) *Operation {
+	op := &Operation{
+		OperationID: operationID(ep.Method, path),
+		Tags:        operationTags(path),
+		Responses: map[string]*Response{
+			"200": {
+				Description: "success",
+				Content: map[string]*MediaType{
+					"application/json": {Schema: doc.resultSchema(ep.Data)},
+				},
+			},
+			"default": {
+				Description: "fail, see ErrNum and ErrMsg",
+				Content: map[string]*MediaType{
+					"application/json": {Schema: doc.resultSchema(nil)},
+				},
+			},
+		},
+	}
+	if ep.Authorizer != nil {
+		op.Security = []map[string][]string{{openAPISecurityKey: {}}}
+	}
+
+	pathParams := map[string]bool{}
+	for _, one := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		pathParams[one[1]] = true
+	}
+
+	body := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	if ep.Param != nil {
+		doc.walkParam(reflect.TypeOf(ep.Param), ep.Method, pathParams, op, body)
+	}
+
+	for _, name := range sortedKeys(pathParams) {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if (len(body.Properties) > 0 || body.Type != "object" || body.AdditionalProperties != nil) &&
+		methodWithBody(ep.Method) {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: body},
+			},
+		}
+	}
+
+	return op
+}
+
+// walkParam split fields of param into path parameters, query parameters and properties of request body
+func (doc *OpenAPI) walkParam(t reflect.Type, method string, pathParams map[string]bool, op *Operation, body *Schema) {
+	t = indirect(t)
+	if t.Kind() != reflect.Struct {
+		*body = *doc.schema(t)
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		uriName := tagName(field, "uri")
+		formName := tagName(field, "form")
+		jsonName := tagName(field, "json")
+
+		switch {
+		case uriName != "" && pathParams[uriName]:
+			delete(pathParams, uriName)
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     uriName,
+				In:       "path",
+				Required: true,
+				Schema:   doc.fieldSchema(field),
+			})
+			continue
+
+		case formName != "" && formName != "-":
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     formName,
+				In:       "query",
+				Required: hasRule(field, "required"),
+				Schema:   doc.fieldSchema(field),
+			})
+			continue
+		}
+
+		if field.Anonymous && jsonName == "" {
+			doc.walkParam(field.Type, method, pathParams, op, body)
+			continue
+		}
+
+		if jsonName == "-" || (jsonName == "" && (uriName != "" || formName != "")) {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+
+		body.Properties[jsonName] = doc.fieldSchema(field)
+		if hasRule(field, "required") {
+			body.Required = append(body.Required, jsonName)
+		}
+	}
+}
+
+func (doc *OpenAPI) resultSchema(data interface{}) *Schema {
+	rst := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"ErrNum": {Type: "integer"},
+			"ErrMsg": {Type: "string"},
+		},
+		Required: []string{"ErrNum", "ErrMsg"},
+	}
+	if data != nil {
+		rst.Properties["Data"] = doc.schema(reflect.TypeOf(data))
+	}
+
+	return rst
+}
+
+// fieldSchema return schema of struct field, rules in validate tag are applied
+func (doc *OpenAPI) fieldSchema(field reflect.StructField) *Schema {
+	s := doc.schema(field.Type)
+	if s.Ref != "" {
+		return s
+	}
+
+	tmp := *s
+	s = &tmp
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if rule == "dive" {
+			break
+		}
+
+		k, v := head(rule, "=")
+		switch k {
+		case "min", "gte":
+			applyLimit(s, v, true)
+		case "max", "lte":
+			applyLimit(s, v, false)
+		case "len":
+			applyLimit(s, v, true)
+			applyLimit(s, v, false)
+		case "oneof":
+			for _, one := range strings.Fields(v) {
+				s.Enum = append(s.Enum, enumValue(s.Type, one))
+			}
+		case "ip":
+			s.Format = "ipv4"
+		case "url":
+			s.Format = "uri"
+		case "email":
+			s.Format = "email"
+		}
+	}
+
+	return s
+}
+
+func (doc *OpenAPI) schema(t reflect.Type) *Schema {
+	nullable := false
+	if t.Kind() == reflect.Ptr {
+		nullable = true
+		t = indirect(t)
+	}
+
+	var s *Schema
+	switch {
+	case t == timeType:
+		s = &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Bool:
+		s = &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int32, t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint32:
+		s = &Schema{Type: "integer", Format: "int32"}
+	case t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64:
+		s = &Schema{Type: "integer", Format: "int64"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s = &Schema{Type: "number"}
+	case t.Kind() == reflect.String:
+		s = &Schema{Type: "string"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		s = &Schema{Type: "string", Format: "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		s = &Schema{Type: "array", Items: doc.schema(t.Elem())}
+	case t.Kind() == reflect.Map:
+		s = &Schema{Type: "object", AdditionalProperties: doc.schema(t.Elem())}
+	case t.Kind() == reflect.Struct:
+		return doc.structRef(t)
+	default:
+		s = &Schema{}
+	}
+
+	s.Nullable = nullable && s.Type != ""
+	return s
+}
+
+// structRef register schema of struct in components and return reference of it
+func (doc *OpenAPI) structRef(t reflect.Type) *Schema {
+	name := schemaName(t)
+	ref := &Schema{Ref: openAPISchemaRef + name}
+	if _, ok := doc.Components.Schemas[name]; ok {
+		return ref
+	}
+
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	// placeholder, avoid infinite recursion of recursive type
+	doc.Components.Schemas[name] = s
+	doc.structProperties(t, s)
+
+	return ref
+}
+
+func (doc *OpenAPI) structProperties(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name := tagName(field, "json")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			doc.structProperties(indirect(field.Type), s)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		s.Properties[name] = doc.fieldSchema(field)
+		if hasRule(field, "required") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+		pkg = pkg[idx+1:]
+	}
+	if pkg == "" {
+		return t.Name()
+	}
+
+	return pkg + "." + t.Name()
+}
+
+func operationID(method, path string) string {
+	words := []string{strings.ToLower(method)}
+	for _, one := range strings.Split(path, "/") {
+		one = strings.NewReplacer("{", "", "}", "", "-", "_", ".", "_").Replace(one)
+		if one != "" {
+			words = append(words, one)
+		}
+	}
+
+	return strings.Join(words, "_")
+}
+
+// operationTags return resource of path as tag, such as clusters of /products/{product_name}/clusters/{cluster_name}
+func operationTags(path string) []string {
+	ss := strings.Split(strings.Trim(path, "/"), "/")
+	if len(ss) > 2 && ss[0] == "products" && strings.HasPrefix(ss[1], "{") {
+		ss = ss[2:]
+	}
+	if ss[0] == "" || strings.HasPrefix(ss[0], "{") {
+		return nil
+	}
+
+	return []string{ss[0]}
+}
+
+func methodWithBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func tagName(field reflect.StructField, tag string) string {
+	name, _ := head(field.Tag.Get(tag), ",")
+	return name
+}
+
+func hasRule(field reflect.StructField, rule string) bool {
+	for _, one := range strings.Split(field.Tag.Get("validate"), ",") {
+		if one == "dive" {
+			return false
+		}
+		if one == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyLimit(s *Schema, v string, isMin bool) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return
+	}
+	i := int(f)
+
+	switch s.Type {
+	case "string":
+		if isMin {
+			s.MinLength = &i
+		} else {
+			s.MaxLength = &i
+		}
+	case "array":
+		if isMin {
+			s.MinItems = &i
+		} else {
+			s.MaxItems = &i
+		}
+	case "integer", "number":
+		if isMin {
+			s.Minimum = &f
+		} else {
+			s.Maximum = &f
+		}
+	}
+}
+
+func enumValue(typ, v string) interface{} {
+	switch typ {
+	case "integer":
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+
+	return v
+}
+
+func head(str, sep string) (string, string) {
+	idx := strings.Index(str, sep)
+	if idx < 0 {
+		return str, ""
+	}
+
+	return str[:idx], str[idx+len(sep):]
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xreq
+
+import (
+	"net/http"
+
+	"github.com/bfenetworks/api-server/lib"
+)
+
+// PageParam query params of list endpoints
+type PageParam struct {
+	Page     int    `form:"page" validate:"min=0"`
+	PageSize int    `form:"page_size" validate:"min=0,max=1000"`
+	Sort     string `form:"sort" validate:"omitempty,oneof=name created_at updated_at"`
+	Order    string `form:"order" validate:"omitempty,oneof=asc desc"`
+	Filter   string `form:"filter"`
+}
+
+// PageData response of list endpoints when page_size be specified
+type PageData struct {
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	List     interface{} `json:"list"`
+}
+
+func NewPageParamFromReq(req *http.Request) (*PageParam, error) {
+	param := &PageParam{}
+	if err := BindForm(req, param); err != nil {
+		return nil, err
+	}
+
+	return param, nil
+}
+
+func (param *PageParam) Pagination() *lib.Pagination {
+	return &lib.Pagination{
+		Page:     param.Page,
+		PageSize: param.PageSize,
+		Sort:     param.Sort,
+		Order:    param.Order,
+		Filter:   param.Filter,
+	}
+}
+
+// Result wrap list with total as PageData if page_size specified
+// otherwise list be returned directly to keep compatible with old clients
+func (param *PageParam) Result(list interface{}, total int64) interface{} {
+	if param.PageSize == 0 {
+		return list
+	}
+
+	page := param.Page
+	if page < 1 {
+		page = 1
+	}
+
+	return &PageData{
+		Total:    total,
+		Page:     page,
+		PageSize: param.PageSize,
+		List:     list,
+	}
+}
@@ -67,6 +67,10 @@ type Endpoint struct {
 	RegisterHandler func(*mux.Router) *mux.Route
 
 	Authorizer *iauth.Authorization
+
+	// Param and Data are samples of request param and response data, used to generate OpenAPI document
+	Param interface{}
+	Data  interface{}
 }
 
 func (ep *Endpoint) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -197,6 +201,9 @@ func Render(w http.ResponseWriter, req *http.Request, res *Result) {
 	requestInfo.StatusCode = res.Code
 
 	w.Header().Add("Req-ID", requestInfo.LogID)
+	if isSucc {
+		setETag(w, res)
+	}
 
 	var rspContent []byte
 	w.Header().Add("Content-Type", "application/json")
@@ -26,6 +26,7 @@ import (
 	"gopkg.in/tylerb/graceful.v1"
 
 	"github.com/bfenetworks/api-server/endpoints"
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1"
 	"github.com/bfenetworks/api-server/stateful"
 	"github.com/bfenetworks/api-server/stateful/container/rdb"
 	"github.com/bfenetworks/api-server/version"
@@ -38,6 +39,8 @@ var (
 	serverConf *string = flag.String("sc", "api_server.toml", "server conf file")
 
 	logDir *string = flag.String("l", "./log", "dir path of log")
+
+	openAPIFile *string = flag.String("openapi", "", "dump OpenAPI document to file and exit")
 )
 
 func main() {
@@ -51,6 +54,12 @@ func main() {
 		fmt.Printf("version %s\n", version.Version)
 		return
 	}
+	if *openAPIFile != "" {
+		if err := openapi_v1.DumpOpenAPI(*openAPIFile); err != nil {
+			stateful.Exit("DumpOpenAPI", err, -1)
+		}
+		return
+	}
 
 	if err := stateful.LoadConfig(filepath.Join(*confDir, *serverConf)); err != nil {
 		stateful.Exit("LoadConfig", err, -1)
@@ -84,6 +93,10 @@ func main() {
 		stateful.Exit("config.InitDB", err, -1)
 	}
 
+	if err := rdb.Migrate(); err != nil {
+		stateful.Exit("rdb.Migrate", err, -1)
+	}
+
 	rdb.Init()
 
 	serverStartUp()
@@ -0,0 +1,183 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iaudit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/itxn"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+const (
+	ResourceProduct     = "product"
+	ResourceBFECluster  = "bfe_cluster"
+	ResourcePool        = "pool"
+	ResourceSubCluster  = "sub_cluster"
+	ResourceCluster     = "cluster"
+	ResourceRouteRule   = "route_rule"
+	ResourceRouteCase   = "route_case"
+	ResourceDomain      = "domain"
+	ResourceCertificate = "certificate"
+	ResourceUser        = "user"
+	ResourceToken       = "token"
+	ResourceUserProduct = "user_product"
+	ResourceConfigPin   = "config_pin"
+
+	ResourceActiveHealthCheck = "active_health_check"
+	ResourceHeaderRule        = "header_rule"
+	ResourceRewriteRule       = "rewrite_rule"
+	ResourceRedirectRule      = "redirect_rule"
+	ResourceBlockRule         = "block_rule"
+	ResourceIPBlocklist       = "ip_blocklist"
+	ResourceTLSRule           = "tls_rule"
+)
+
+const anonymousVisitor = "anonymous"
+
+type key string
+
+var keyVisitor key = "audit_visitor"
+
+// NewVisitorContext set name of visitor who makes the changes
+func NewVisitorContext(ctx context.Context, visitorName string) context.Context {
+	return context.WithValue(ctx, keyVisitor, visitorName)
+}
+
+func visitorName(ctx context.Context) string {
+	if name, ok := ctx.Value(keyVisitor).(string); ok && name != "" {
+		return name
+	}
+
+	return anonymousVisitor
+}
+
+type Audit struct {
+	ID           int64
+	Visitor      string
+	ProductID    int64
+	ProductName  string
+	ResourceType string
+	ResourceName string
+	Action       string
+
+	// Before/After JSON of resource
+	Before string
+	After  string
+
+	CreatedAt time.Time
+}
+
+type AuditFilter struct {
+	ProductID    *int64
+	Visitor      *string
+	ResourceType *string
+	ResourceName *string
+	Action       *string
+
+	StartTime *time.Time
+	EndTime   *time.Time
+
+	// Page start from 1
+	Page     int
+	PageSize int
+}
+
+type AuditParam struct {
+	ProductID    int64
+	ProductName  string
+	ResourceType string
+	ResourceName string
+	Action       string
+
+	Before interface{}
+	After  interface{}
+}
+
+type AuditStorager interface {
+	CreateAudit(ctx context.Context, audit *Audit) error
+	FetchAudits(ctx context.Context, filter *AuditFilter) (list []*Audit, total int64, err error)
+}
+
+type AuditManager struct {
+	txn      itxn.TxnStorager
+	storager AuditStorager
+}
+
+func NewAuditManager(txn itxn.TxnStorager, storager AuditStorager) *AuditManager {
+	return &AuditManager{
+		txn:      txn,
+		storager: storager,
+	}
+}
+
+func marshal(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return "", xerror.WrapModelErrorWithMsg("Marshal Audit Data Fail: %v", err)
+	}
+
+	return string(bs), nil
+}
+
+// Record save audit of a change,
+// it should be called in the AtomExecute of caller, so the audit is saved with the change
+func (m *AuditManager) Record(ctx context.Context, param *AuditParam) error {
+	if m == nil {
+		return nil
+	}
+
+	before, err := marshal(param.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := marshal(param.After)
+	if err != nil {
+		return err
+	}
+
+	return m.storager.CreateAudit(ctx, &Audit{
+		Visitor:      visitorName(ctx),
+		ProductID:    param.ProductID,
+		ProductName:  param.ProductName,
+		ResourceType: param.ResourceType,
+		ResourceName: param.ResourceName,
+		Action:       param.Action,
+		Before:       before,
+		After:        after,
+	})
+}
+
+func (m *AuditManager) AuditList(ctx context.Context, filter *AuditFilter) (list []*Audit, total int64, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, total, err = m.storager.FetchAudits(ctx, filter)
+		return err
+	})
+
+	return
+}
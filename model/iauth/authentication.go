@@ -22,6 +22,7 @@ import (
 
 	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/itxn"
 	"github.com/bfenetworks/api-server/stateful"
@@ -41,6 +42,9 @@ const (
 	AuthTypeSessionKey = "Session"
 	AuthTypeToken      = "Token"
 	AuthTypeSkip       = "Skip"
+
+	// PasswordSkip can be used as password of any user if RunTime.SkipPasswordValidate be opened
+	PasswordSkip = "SKIP"
 )
 
 type Loginer interface {
@@ -123,6 +127,15 @@ func (u *User) IsAdmin() bool {
 	return u.Admin
 }
 
+// auditData return user data without password and session key
+func (u *User) auditData() interface{} {
+	return map[string]interface{}{
+		"Name":  u.Name,
+		"Type":  u.Type,
+		"Admin": u.Admin,
+	}
+}
+
 type Token struct {
 	ID    int64
 	Name  string
@@ -148,7 +161,21 @@ func (t *Token) IsAdmin() bool {
 	return t.Scope == ScopeSystem
 }
 
+// auditData return token data without token value
+func (t *Token) auditData() interface{} {
+	data := map[string]interface{}{
+		"Name":  t.Name,
+		"Scope": t.Scope,
+	}
+	if t.Product != nil {
+		data["Product"] = t.Product.Name
+	}
+
+	return data
+}
+
 func NewVisitorContext(ctx context.Context, visitor *Visitor) context.Context {
+	ctx = iaudit.NewVisitorContext(ctx, visitor.GetName())
 	return context.WithValue(ctx, keyUser, visitor)
 }
 
@@ -189,6 +216,8 @@ type UserFilter struct {
 	SessionKey *string
 	Type       *int8
 	Types      []int8
+
+	Pagination *lib.Pagination
 }
 
 type AuthenticateParam struct {
@@ -199,6 +228,7 @@ type AuthenticateParam struct {
 
 type AuthenticateStorager interface {
 	FetchUserList(ctx context.Context, param *UserFilter) ([]*User, error)
+	CountUsers(ctx context.Context, param *UserFilter) (int64, error)
 	FetchUser(ctx context.Context, param *UserFilter) (*User, error)
 	UpdateUser(ctx context.Context, user *User, param *UserParam) error
 	CreateUser(ctx context.Context, param *UserParam) error
@@ -213,14 +243,16 @@ type AuthenticateManager struct {
 	txn               itxn.TxnStorager
 	storager          AuthenticateStorager // the storager for authentication
 	authorizeStorager AuthorizeStorager    // the storager for authorization
+	auditManager      *iaudit.AuditManager
 }
 
 func NewAuthenticateManager(txn itxn.TxnStorager, storager AuthenticateStorager,
-	authorizeStorage AuthorizeStorager) *AuthenticateManager {
+	authorizeStorage AuthorizeStorager, auditManager *iaudit.AuditManager) *AuthenticateManager {
 	return &AuthenticateManager{
 		txn:               txn,
 		storager:          storager,
 		authorizeStorager: authorizeStorage,
+		auditManager:      auditManager,
 	}
 }
 
@@ -249,10 +281,29 @@ func authTypePassword(ctx context.Context, param *AuthenticateParam, manager *Au
 			return xerror.WrapAuthenticateFailErrorWithMsg("User %s Not Exist", userName)
 		}
 
-		if param.Extend != "SKIP" && user.Password != param.Extend {
+		skip := stateful.DefaultConfig.RunTime.SkipPasswordValidate && param.Extend == PasswordSkip
+		ok, needRehash, err := verifyPassword(user, param.Extend)
+		if err != nil {
+			return err
+		}
+		if !skip && !ok {
 			return xerror.WrapAuthenticateFailErrorWithMsg("Password Wrong")
 		}
 
+		// migrate plaintext password
+		if needRehash {
+			hashed, err := hashPassword(param.Extend)
+			if err != nil {
+				return err
+			}
+			if err = manager.storager.UpdateUser(ctx, user, &UserParam{
+				Password: hashed,
+			}); err != nil {
+				return err
+			}
+			user.Password = *hashed
+		}
+
 		// update session key
 		for {
 			sessionKey, err := sessionKeyFactory(15)
@@ -405,7 +456,16 @@ func (m *AuthenticateManager) DeleteToken(ctx context.Context, token *Token) (er
 			return err
 		}
 
-		return m.authorizeStorager.UnbindTokenAllProduct(ctx, token)
+		if err = m.authorizeStorager.UnbindTokenAllProduct(ctx, token); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceToken,
+			ResourceName: token.Name,
+			Action:       iaudit.ActionDelete,
+			Before:       token.auditData(),
+		})
 	})
 
 	return err
@@ -463,9 +523,23 @@ func (m *AuthenticateManager) CreateToken(ctx context.Context, param *TokenParam
 			token = tokens[0]
 
 			if product != nil {
-				err = m.authorizeStorager.BindTokenProduct(ctx, token, product)
+				if err = m.authorizeStorager.BindTokenProduct(ctx, token, product); err != nil {
+					return err
+				}
+				token.Product = product
 			}
-			return err
+
+			auditParam := &iaudit.AuditParam{
+				ResourceType: iaudit.ResourceToken,
+				ResourceName: token.Name,
+				Action:       iaudit.ActionCreate,
+				After:        token.auditData(),
+			}
+			if product != nil {
+				auditParam.ProductID = product.ID
+				auditParam.ProductName = product.Name
+			}
+			return m.auditManager.Record(ctx, auditParam)
 		}
 
 		return err
@@ -493,7 +567,25 @@ func (m *AuthenticateManager) CreateUser(ctx context.Context, param *UserParam)
 			return xerror.WrapModelErrorWithMsg("User Existed")
 		}
 
-		return m.storager.CreateUser(ctx, param)
+		if param.Password != nil {
+			if param.Password, err = hashPassword(*param.Password); err != nil {
+				return err
+			}
+		}
+
+		if err = m.storager.CreateUser(ctx, param); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceUser,
+			ResourceName: *param.Name,
+			Action:       iaudit.ActionCreate,
+			After: map[string]interface{}{
+				"Name":   *param.Name,
+				"Scopes": param.Scopes,
+			},
+		})
 	})
 }
 
@@ -514,16 +606,17 @@ func (m *AuthenticateManager) DeleteUser(ctx context.Context, userName string) (
 			return err
 		}
 
-		return m.authorizeStorager.UnbindUserAllProduct(ctx, user)
-	})
-}
-
-func passwordCheck(password string) error {
-	if len(password) < 6 {
-		return xerror.WrapParamErrorWithMsg("Password Lenght Must Bigger Than 6")
-	}
+		if err = m.authorizeStorager.UnbindUserAllProduct(ctx, user); err != nil {
+			return err
+		}
 
-	return nil
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceUser,
+			ResourceName: user.Name,
+			Action:       iaudit.ActionDelete,
+			Before:       user.auditData(),
+		})
+	})
 }
 
 type PasswordChangeData struct {
@@ -537,17 +630,26 @@ func (m *AuthenticateManager) UpdateUserPassword(ctx context.Context, pcd *Passw
 		return err
 	}
 
+	hashed, err := hashPassword(pcd.Password)
+	if err != nil {
+		return err
+	}
+
 	return m.updateUser(ctx, &UserFilter{
 		Name: &pcd.UserName,
 	}, func(user *User) error {
 		if pcd.OldPassword != "" {
-			if user.Password != pcd.OldPassword {
+			ok, _, err := verifyPassword(user, pcd.OldPassword)
+			if err != nil {
+				return err
+			}
+			if !ok {
 				return xerror.WrapModelErrorWithMsg("Invalid Password")
 			}
 		}
 		return nil
 	}, &UserParam{
-		Password:           &pcd.Password,
+		Password:           hashed,
 		SessionKey:         lib.PString(""),
 		SessionKeyCreateAt: lib.PTime(time.Time{}.AddDate(0, 1, 1)),
 	})
@@ -572,7 +674,28 @@ func (m *AuthenticateManager) updateUser(ctx context.Context, filter *UserFilter
 			}
 		}
 
-		return m.storager.UpdateUser(ctx, user, newData)
+		if err = m.storager.UpdateUser(ctx, user, newData); err != nil {
+			return err
+		}
+
+		after, err := m.storager.FetchUser(ctx, &UserFilter{
+			IDs: []int64{user.ID},
+		})
+		if err != nil {
+			return err
+		}
+		if after == nil {
+			return xerror.WrapModelErrorWithMsg("User Not Exist")
+		}
+
+		// password and session key are never recorded
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceUser,
+			ResourceName: user.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       user.auditData(),
+			After:        after.auditData(),
+		})
 	})
 }
 
@@ -599,3 +722,18 @@ func (m *AuthenticateManager) FetchUserList(ctx context.Context, param *UserFilt
 
 	return
 }
+
+// FetchUserPage return users in page of param.Pagination, and number of all users match param
+func (m *AuthenticateManager) FetchUserPage(ctx context.Context, param *UserFilter) (users []*User, total int64, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		total, err = m.storager.CountUsers(ctx, param)
+		if err != nil {
+			return err
+		}
+
+		users, err = m.storager.FetchUserList(ctx, param)
+		return err
+	})
+
+	return
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iauth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/api-server/stateful/container/memory"
+)
+
+// initMemory init container with a new memory db, it has user admin whose password admin is stored in plaintext
+func initMemory(skipPasswordValidate bool) {
+	stateful.DefaultConfig = &stateful.Config{
+		RunTime: stateful.RunTimeConfig{
+			SessionExpireInDay:   1,
+			SkipPasswordValidate: skipPasswordValidate,
+			PasswordPolicy: stateful.PasswordPolicyConfig{
+				MinLength: 1,
+			},
+		},
+	}
+	memory.Init()
+}
+
+func login(userName, password string) (*iauth.Visitor, error) {
+	return container.AuthenticateManager.Authenticate(context.Background(), &iauth.AuthenticateParam{
+		Type:     iauth.AuthTypePassword,
+		Identify: userName,
+		Extend:   password,
+	})
+}
+
+func storedPassword(t *testing.T, userName string) string {
+	user, err := container.AuthenticateStoragerSingleton.FetchUser(context.Background(), &iauth.UserFilter{
+		Name: &userName,
+	})
+	if err != nil || user == nil {
+		t.Fatalf("FetchUser: %v %v", user, err)
+	}
+
+	return user.Password
+}
+
+func TestLoginRehashPlaintextPassword(t *testing.T) {
+	initMemory(false)
+
+	if _, err := login("admin", "wrong"); err == nil {
+		t.Fatalf("login: want wrong password rejected")
+	}
+	if stored := storedPassword(t, "admin"); stored != "admin" {
+		t.Fatalf("login: want plaintext kept after failed login, got %s", stored)
+	}
+
+	if _, err := login("admin", "admin"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	stored := storedPassword(t, "admin")
+	if stored == "admin" || !iauth.DefaultPasswordHasher.IsHashed(stored) {
+		t.Fatalf("login: want plaintext password rehashed, got %s", stored)
+	}
+
+	if _, err := login("admin", "admin"); err != nil {
+		t.Fatalf("login with hashed password: %v", err)
+	}
+}
+
+func TestLoginPasswordSkip(t *testing.T) {
+	initMemory(false)
+	if _, err := login("admin", iauth.PasswordSkip); err == nil {
+		t.Fatalf("login: want %s rejected if SkipPasswordValidate closed", iauth.PasswordSkip)
+	}
+
+	initMemory(true)
+	if _, err := login("admin", iauth.PasswordSkip); err != nil {
+		t.Fatalf("login: want %s accepted if SkipPasswordValidate opened, got %v", iauth.PasswordSkip, err)
+	}
+	if stored := storedPassword(t, "admin"); stored != "admin" {
+		t.Fatalf("login: want password not changed by %s, got %s", iauth.PasswordSkip, stored)
+	}
+	if _, err := login("admin", "wrong"); err == nil {
+		t.Fatalf("login: want wrong password rejected if SkipPasswordValidate opened")
+	}
+}
+
+func TestCreateUserPasswordTooLong(t *testing.T) {
+	initMemory(false)
+
+	name := "demo"
+	password := strings.Repeat("a", iauth.PasswordMaxLength+1)
+	if err := container.AuthenticateManager.CreateUser(context.Background(), &iauth.UserParam{
+		Name:     &name,
+		Password: &password,
+	}); err == nil {
+		t.Fatalf("CreateUser: want password longer than %d bytes rejected", iauth.PasswordMaxLength)
+	}
+}
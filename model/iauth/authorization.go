@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/itxn"
 )
@@ -47,14 +48,16 @@ type AuthorizeStorager interface {
 }
 
 type AuthorizeManager struct {
-	storager AuthorizeStorager
-	txn      itxn.TxnStorager
+	storager     AuthorizeStorager
+	txn          itxn.TxnStorager
+	auditManager *iaudit.AuditManager
 }
 
-func NewAuthorizeManager(txn itxn.TxnStorager, storager AuthorizeStorager) *AuthorizeManager {
+func NewAuthorizeManager(txn itxn.TxnStorager, storager AuthorizeStorager, auditManager *iaudit.AuditManager) *AuthorizeManager {
 	return &AuthorizeManager{
-		txn:      txn,
-		storager: storager,
+		txn:          txn,
+		storager:     storager,
+		auditManager: auditManager,
 	}
 }
 
@@ -65,7 +68,21 @@ func (m *AuthorizeManager) UpdateUserIsAdmin(ctx context.Context, user *User, is
 			true:  {ScopeSystem},
 		}
 
-		return m.storager.UpdateUserScopes(ctx, user, mapping[isAdmin])
+		if err := m.storager.UpdateUserScopes(ctx, user, mapping[isAdmin]); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceUser,
+			ResourceName: user.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       user.auditData(),
+			After: map[string]interface{}{
+				"Name":  user.Name,
+				"Type":  user.Type,
+				"Admin": isAdmin,
+			},
+		})
 	})
 
 	return
@@ -81,6 +98,10 @@ func (m *AuthorizeManager) Authorizate(ctx context.Context, authrizer *Authoriza
 		return nil
 	}
 
+	if _, ok := ibasic.GetMissingProductName(ctx); ok && authrizer.CreateMissingProduct {
+		return m.Authorizate(ctx, FA(FeatureProduct, ActionCreate))
+	}
+
 	featureGranted := false
 	for _, scope := range visitor.GetScopes() {
 		permissions, ok := scope2permission[scope]
@@ -166,9 +187,18 @@ func (m *AuthorizeManager) FetchProductUsers(ctx context.Context, product *ibasi
 
 func (m *AuthorizeManager) BindUserProduct(ctx context.Context, user *User, product *ibasic.Product) (err error) {
 	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		err = m.storager.BindUserProduct(ctx, user, product)
+		if err = m.storager.BindUserProduct(ctx, user, product); err != nil {
+			return err
+		}
 
-		return err
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceUserProduct,
+			ResourceName: user.Name,
+			Action:       iaudit.ActionCreate,
+			After:        user.auditData(),
+		})
 	})
 
 	return
@@ -176,9 +206,18 @@ func (m *AuthorizeManager) BindUserProduct(ctx context.Context, user *User, prod
 
 func (m *AuthorizeManager) UnBindUserProduct(ctx context.Context, user *User, product *ibasic.Product) (err error) {
 	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		err = m.storager.UnbindUserProduct(ctx, user, product)
+		if err = m.storager.UnbindUserProduct(ctx, user, product); err != nil {
+			return err
+		}
 
-		return err
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceUserProduct,
+			ResourceName: user.Name,
+			Action:       iaudit.ActionDelete,
+			Before:       user.auditData(),
+		})
 	})
 
 	return
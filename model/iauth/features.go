@@ -27,6 +27,10 @@ type FeatureAuthorition struct {
 type Authorization struct {
 	FeatureAuthorizer *FeatureAuthorition
 	ValidateProduct   bool
+
+	// CreateMissingProduct means the product will be created if not exist,
+	// visitor must be allowed to create product instead
+	CreateMissingProduct bool
 }
 
 func NewFeatureAuthorization(f Feature, a Action) *Authorization {
@@ -45,9 +49,19 @@ func NewFeatureAuthorizerWithFactoryWithProduct(f Feature, a Action) *Authorizat
 	return tmp
 }
 
+// NewFeatureAuthorizerWithProductOrCreate validate product like FAP if product exists,
+// otherwise require the permission to create product
+func NewFeatureAuthorizerWithProductOrCreate(f Feature, a Action) *Authorization {
+	tmp := NewFeatureAuthorizerWithFactoryWithProduct(f, a)
+	tmp.CreateMissingProduct = true
+
+	return tmp
+}
+
 var (
-	FA  = NewFeatureAuthorization
-	FAP = NewFeatureAuthorizerWithFactoryWithProduct
+	FA   = NewFeatureAuthorization
+	FAP  = NewFeatureAuthorizerWithFactoryWithProduct
+	FAPC = NewFeatureAuthorizerWithProductOrCreate
 )
 
 const (
@@ -81,6 +95,8 @@ const (
 	FeatureDomain     Feature = "Domain"
 	FeatureProduct    Feature = "Product"
 	FeatureExtraFile  Feature = "ExtraFile"
+	FeatureConfig     Feature = "Config"
+	FeatureAudit      Feature = "Audit"
 
 	// product resource
 	FeatureProductPool       Feature = "ProductPool"
@@ -90,6 +106,10 @@ const (
 	FeatureTraffic           Feature = "Traffic"
 	FeatureCert              Feature = "Cert"
 	FeatureActiveHealthCheck Feature = "ActiveHealthCheck"
+	FeatureHeaderRule        Feature = "HeaderRule"
+	FeatureRewriteRule       Feature = "RewriteRule"
+	FeatureRedirectRule      Feature = "RedirectRule"
+	FeatureBlock             Feature = "Block"
 
 	// auth
 	FeatureProductUser Feature = "AuthProductUser"
@@ -126,6 +146,8 @@ var scope2permission = map[string]map[Feature]Action{
 		FeatureArea:       actionAll,
 		FeatureDomain:     actionAll,
 		FeatureProduct:    actionAll,
+		FeatureConfig:     actionAll,
+		FeatureAudit:      actionAll,
 
 		FeatureProductPool:       actionAll,
 		FeatureRoute:             actionAll,
@@ -134,6 +156,10 @@ var scope2permission = map[string]map[Feature]Action{
 		FeatureTraffic:           actionAll,
 		FeatureCert:              actionAll,
 		FeatureActiveHealthCheck: actionAll,
+		FeatureHeaderRule:        actionAll,
+		FeatureRewriteRule:       actionAll,
+		FeatureRedirectRule:      actionAll,
+		FeatureBlock:             actionAll,
 
 		FeatureProductUser: actionAll,
 		FeatureUser:        actionAll,
@@ -151,6 +177,7 @@ var scope2permission = map[string]map[Feature]Action{
 		FeatureArea:       actionProductNormal.Grant(ActionReadAll),
 		FeatureDomain:     actionProductNormal.Grant(ActionReadAll),
 		FeatureProduct:    actionProductNormal.Grant(ActionReadAll),
+		FeatureAudit:      ActionRead,
 
 		FeatureProductPool:       actionProductNormal,
 		FeatureRoute:             actionProductNormal,
@@ -159,6 +186,10 @@ var scope2permission = map[string]map[Feature]Action{
 		FeatureTraffic:           actionProductNormal,
 		FeatureCert:              actionProductNormal,
 		FeatureActiveHealthCheck: actionProductNormal,
+		FeatureHeaderRule:        actionProductNormal,
+		FeatureRewriteRule:       actionProductNormal,
+		FeatureRedirectRule:      actionProductNormal,
+		FeatureBlock:             actionProductNormal,
 
 		FeatureProductUser: actionProductNormal,
 
@@ -171,5 +202,10 @@ var scope2permission = map[string]map[Feature]Action{
 		FeatureCert:              ActionExport,
 		FeatureActiveHealthCheck: ActionExport,
 		FeatureExtraFile:         ActionExport,
+		FeatureDomain:            ActionExport,
+		FeatureHeaderRule:        ActionExport,
+		FeatureRewriteRule:       ActionExport,
+		FeatureRedirectRule:      ActionExport,
+		FeatureBlock:             ActionExport,
 	},
 }
@@ -0,0 +1,153 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iauth
+
+import (
+	"crypto/subtle"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+// PasswordHasher hash password before it be stored, and verify password when login
+type PasswordHasher interface {
+	// Hash return hashed password, salt should be included in result
+	Hash(password string) (string, error)
+
+	// IsHashed return whether hashed is generated by this hasher
+	IsHashed(hashed string) bool
+
+	// Verify return whether password match hashed
+	Verify(hashed, password string) (bool, error)
+}
+
+// DefaultPasswordHasher be used to hash password of user, replace it if U want other hash algorithm
+var DefaultPasswordHasher PasswordHasher = NewBcryptPasswordHasher(bcrypt.DefaultCost)
+
+type BcryptPasswordHasher struct {
+	cost int
+}
+
+var _ PasswordHasher = &BcryptPasswordHasher{}
+
+func NewBcryptPasswordHasher(cost int) *BcryptPasswordHasher {
+	return &BcryptPasswordHasher{
+		cost: cost,
+	}
+}
+
+func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
+	bs, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", xerror.WrapModelErrorWithMsg("Hash Password Fail: %v", err)
+	}
+
+	return string(bs), nil
+}
+
+func (h *BcryptPasswordHasher) IsHashed(hashed string) bool {
+	_, err := bcrypt.Cost([]byte(hashed))
+	return err == nil
+}
+
+func (h *BcryptPasswordHasher) Verify(hashed, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password))
+	if err == nil {
+		return true, nil
+	}
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+
+	return false, xerror.WrapDirtyDataErrorWithMsg("Verify Password Fail: %v", err)
+}
+
+// verifyPassword check password of user
+// needRehash will be true if password of user stored in plaintext, it should be hashed after login
+func verifyPassword(user *User, password string) (ok bool, needRehash bool, err error) {
+	if user.Password == "" {
+		return false, false, nil
+	}
+
+	if DefaultPasswordHasher.IsHashed(user.Password) {
+		ok, err = DefaultPasswordHasher.Verify(user.Password, password)
+		return ok, false, err
+	}
+
+	// plaintext password stored before password be hashed
+	ok = subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) == 1
+	return ok, ok, nil
+}
+
+func hashPassword(password string) (*string, error) {
+	hashed, err := DefaultPasswordHasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hashed, nil
+}
+
+// PasswordMaxLength is max bytes of password, bcrypt only uses the first 72 bytes of password
+const PasswordMaxLength = 72
+
+// passwordCheck check password with stateful.DefaultConfig.RunTime.PasswordPolicy
+func passwordCheck(password string) error {
+	policy := stateful.DefaultConfig.RunTime.PasswordPolicy
+
+	if len(password) < policy.MinLength {
+		return xerror.WrapParamErrorWithMsg("Password Length Must Not Less Than %d", policy.MinLength)
+	}
+	if len(password) > PasswordMaxLength {
+		return xerror.WrapParamErrorWithMsg("Password Length Must Not More Than %d Bytes", PasswordMaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			hasSpecial = true
+		}
+	}
+
+	var wants []string
+	if policy.RequireUpper && !hasUpper {
+		wants = append(wants, "Upper Case Letter")
+	}
+	if policy.RequireLower && !hasLower {
+		wants = append(wants, "Lower Case Letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		wants = append(wants, "Digit")
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		wants = append(wants, "Special Character")
+	}
+	if len(wants) > 0 {
+		return xerror.WrapParamErrorWithMsg("Password Must Contain %s", strings.Join(wants, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iauth
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+func TestBcryptPasswordHasher(t *testing.T) {
+	h := NewBcryptPasswordHasher(bcrypt.MinCost)
+
+	hashed, err := h.Hash("Passw0rd!")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if hashed == "Passw0rd!" || !h.IsHashed(hashed) {
+		t.Fatalf("Hash: want bcrypt hash, got %s", hashed)
+	}
+	if h.IsHashed("Passw0rd!") {
+		t.Fatalf("IsHashed: want plaintext not hashed")
+	}
+
+	other, err := h.Hash("Passw0rd!")
+	if err != nil || other == hashed {
+		t.Fatalf("Hash: want salted hash differ each time, got %s %v", other, err)
+	}
+
+	if ok, err := h.Verify(hashed, "Passw0rd!"); !ok || err != nil {
+		t.Fatalf("Verify: want match, got %v %v", ok, err)
+	}
+	if ok, err := h.Verify(hashed, "passw0rd!"); ok || err != nil {
+		t.Fatalf("Verify: want mismatch, got %v %v", ok, err)
+	}
+}
+
+func TestVerifyPassword(t *testing.T) {
+	hashed, err := NewBcryptPasswordHasher(bcrypt.MinCost).Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	cases := []struct {
+		name       string
+		stored     string
+		password   string
+		ok         bool
+		needRehash bool
+	}{
+		{"hashed match", hashed, "secret", true, false},
+		{"hashed mismatch", hashed, "other", false, false},
+		{"plaintext match", "secret", "secret", true, true},
+		{"plaintext mismatch", "secret", "other", false, false},
+		{"no password", "", "", false, false},
+	}
+	for _, c := range cases {
+		ok, needRehash, err := verifyPassword(&User{Password: c.stored}, c.password)
+		if err != nil || ok != c.ok || needRehash != c.needRehash {
+			t.Errorf("%s: want %v %v, got %v %v %v", c.name, c.ok, c.needRehash, ok, needRehash, err)
+		}
+	}
+}
+
+func TestPasswordCheck(t *testing.T) {
+	stateful.DefaultConfig = &stateful.Config{
+		RunTime: stateful.RunTimeConfig{
+			PasswordPolicy: stateful.PasswordPolicyConfig{
+				MinLength:    6,
+				RequireDigit: true,
+			},
+		},
+	}
+
+	cases := []struct {
+		password string
+		ok       bool
+	}{
+		{"abc1", false},
+		{"abcdef", false},
+		{"abcde1", true},
+		{strings.Repeat("a", PasswordMaxLength-1) + "1", true},
+		{strings.Repeat("a", PasswordMaxLength) + "1", false},
+	}
+	for _, c := range cases {
+		err := passwordCheck(c.password)
+		if (err == nil) != c.ok {
+			t.Errorf("passwordCheck(%q): want ok %v, got %v", c.password, c.ok, err)
+		}
+		if err != nil && xerror.Resolve(err).ErrNo != 422 {
+			t.Errorf("passwordCheck(%q): want param error, got %v", c.password, err)
+		}
+	}
+}
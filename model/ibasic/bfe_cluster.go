@@ -18,9 +18,18 @@ import (
 	"context"
 
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
 )
 
+// configTopicGSLB same as icluster_conf.ConfigTopicGSLB, gslb conf is exported for each BFE cluster
+const configTopicGSLB = "gslb"
+
+// ConfigTopicServerCert is topic of certificates and tls rules of products,
+// it is defined here for packages iprotocol depends on, same as iprotocol.ConfigTopicServerCert
+const ConfigTopicServerCert = "certificate"
+
 type BFECluster struct {
 	ID                 int64
 	Name               string
@@ -50,12 +59,19 @@ type BFEClusterStorager interface {
 type BFEClusterManager struct {
 	storager BFEClusterStorager
 	txn      itxn.TxnStorager
+
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
 }
 
-func NewBFEClusterManager(txn itxn.TxnStorager, storager BFEClusterStorager) *BFEClusterManager {
+func NewBFEClusterManager(txn itxn.TxnStorager, storager BFEClusterStorager,
+	versionControlManager *iversion_control.VersionControlManager, auditManager *iaudit.AuditManager) *BFEClusterManager {
 	return &BFEClusterManager{
 		txn:      txn,
 		storager: storager,
+
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
 	}
 }
 
@@ -69,7 +85,7 @@ func (pm *BFEClusterManager) FetchBFEClusters(ctx context.Context, param *BFEClu
 }
 
 func (pm *BFEClusterManager) CreateBFECluster(ctx context.Context, param *BFEClusterParam) (err error) {
-	return pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
 		list, err := pm.storager.FetchBFEClusters(ctx, &BFEClusterFilter{
 			Name: param.Name,
 		})
@@ -80,12 +96,26 @@ func (pm *BFEClusterManager) CreateBFECluster(ctx context.Context, param *BFEClu
 			return xerror.WrapRecordExisted("BFE Cluster")
 		}
 
-		return pm.storager.CreateBFECluster(ctx, param)
+		if err = pm.storager.CreateBFECluster(ctx, param); err != nil {
+			return err
+		}
+
+		return pm.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceBFECluster,
+			ResourceName: *param.Name,
+			Action:       iaudit.ActionCreate,
+			After:        param,
+		})
 	})
+	if err == nil {
+		pm.versionControlManager.NotifyChange(ctx, configTopicGSLB)
+	}
+
+	return
 }
 
 func (pm *BFEClusterManager) DeleteBFECluster(ctx context.Context, param *BFEClusterParam) (err error) {
-	return pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
 		_param := &BFEClusterFilter{
 			Name: param.Name,
 		}
@@ -97,8 +127,22 @@ func (pm *BFEClusterManager) DeleteBFECluster(ctx context.Context, param *BFEClu
 			return xerror.WrapRecordNotExist("BFE Cluster")
 		}
 
-		return pm.storager.DeleteBFECluster(ctx, list[0])
+		if err = pm.storager.DeleteBFECluster(ctx, list[0]); err != nil {
+			return err
+		}
+
+		return pm.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceBFECluster,
+			ResourceName: list[0].Name,
+			Action:       iaudit.ActionDelete,
+			Before:       list[0],
+		})
 	})
+	if err == nil {
+		pm.versionControlManager.NotifyChange(ctx, configTopicGSLB)
+	}
+
+	return
 }
 
 func BFEClusterID2NameMap(list []*BFECluster) map[int64]string {
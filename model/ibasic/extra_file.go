@@ -16,11 +16,18 @@ package ibasic
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/bfenetworks/api-server/model/iversion_control"
 )
 
+// ConfigTopicExtraFile topic be notified when extra files changed
+const ConfigTopicExtraFile = "extra_file"
+
 type ExtraFile struct {
 	ID          int64
 	Name        string
@@ -55,14 +62,24 @@ func ExtraFilePath(moduleDir string, product *Product, fileName string) string {
 
 type ExtraFileManager struct {
 	storager ExtraFileStorager
+
+	versionControlManager *iversion_control.VersionControlManager
 }
 
-func NewExtraFileManager(storager ExtraFileStorager) *ExtraFileManager {
+func NewExtraFileManager(storager ExtraFileStorager,
+	versionControlManager *iversion_control.VersionControlManager) *ExtraFileManager {
 	return &ExtraFileManager{
 		storager: storager,
+
+		versionControlManager: versionControlManager,
 	}
 }
 
+// Version return hex of md5 as version of extra file
+func (ef *ExtraFile) Version() string {
+	return hex.EncodeToString(ef.Md5)
+}
+
 func (em *ExtraFileManager) FetchExtraFile(ctx context.Context, fileName string) (*ExtraFile, error) {
 	list, err := em.storager.FetchExtraFiles(ctx, &ExtraFileFilter{
 		Name: &fileName,
@@ -77,3 +94,20 @@ func (em *ExtraFileManager) FetchExtraFile(ctx context.Context, fileName string)
 
 	return nil, nil
 }
+
+// WaitExtraFile fetch extra file, if file not exist or version of file equals lastVersion,
+// request will be held until extra files changed or wait timeout
+func (em *ExtraFileManager) WaitExtraFile(ctx context.Context, fileName, lastVersion string,
+	wait time.Duration) (extraFile *ExtraFile, err error) {
+
+	err = em.versionControlManager.WaitChange(ctx, ConfigTopicExtraFile, wait, func(ctx context.Context) (bool, error) {
+		extraFile, err = em.FetchExtraFile(ctx, fileName)
+		if err != nil {
+			return false, err
+		}
+
+		return extraFile != nil && extraFile.Version() != lastVersion, nil
+	})
+
+	return
+}
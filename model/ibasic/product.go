@@ -18,8 +18,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
 )
 
 const (
@@ -28,15 +31,33 @@ const (
 
 type key string
 
-var keyProduct key = "product"
+var (
+	keyProduct        key = "product"
+	keyMissingProduct key = "missing_product"
+)
 
 func NewProductContext(ctx context.Context, product *Product) context.Context {
 	return context.WithValue(ctx, keyProduct, product)
 }
 
+// NewMissingProductContext record the name of product which is requested but not exist,
+// MustGetProduct return not exist error for it
+func NewMissingProductContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, keyMissingProduct, name)
+}
+
+// GetMissingProductName return the name of product which is requested but not exist
+func GetMissingProductName(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(keyMissingProduct).(string)
+	return name, ok
+}
+
 func MustGetProduct(ctx context.Context) (*Product, error) {
 	obj := ctx.Value(keyProduct)
 	if obj == nil {
+		if _, ok := GetMissingProductName(ctx); ok {
+			return nil, xerror.WrapRecordNotExist("Product")
+		}
 		return nil, xerror.WrapParamErrorWithMsg("Fail To Get Product")
 	}
 
@@ -72,6 +93,8 @@ type ProductFilter struct {
 	IDs  []int64
 
 	Name *string
+
+	Pagination *lib.Pagination
 }
 
 type ProductParam struct {
@@ -85,21 +108,45 @@ type ProductParam struct {
 
 type ProductStorager interface {
 	FetchProducts(context.Context, *ProductFilter) ([]*Product, error)
+	CountProducts(context.Context, *ProductFilter) (int64, error)
 	DeleteProduct(context.Context, *Product) error
 	CreateProduct(context.Context, *ProductParam) error
 	UpdateProduct(context.Context, *Product, *ProductParam) error
 }
 
 type ProductManager struct {
-	storager ProductStorager
-	txn      itxn.TxnStorager
+	storager              ProductStorager
+	txn                   itxn.TxnStorager
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
+
+	// changedTopics are topics whose export data contains product name or resources of product,
+	// they are provided by the packages owning the topics
+	changedTopics []string
 }
 
-func NewProductManager(txn itxn.TxnStorager, storager ProductStorager) *ProductManager {
+func NewProductManager(txn itxn.TxnStorager, storager ProductStorager,
+	versionControlManager *iversion_control.VersionControlManager, auditManager *iaudit.AuditManager,
+	changedTopics ...string) *ProductManager {
 	return &ProductManager{
-		txn:      txn,
-		storager: storager,
+		txn:                   txn,
+		storager:              storager,
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+		changedTopics:         changedTopics,
+	}
+}
+
+func (pm *ProductManager) fetchProduct(ctx context.Context, filter *ProductFilter) (*Product, error) {
+	list, err := pm.storager.FetchProducts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, xerror.WrapRecordNotExist("Product")
 	}
+
+	return list[0], nil
 }
 
 func (pm *ProductManager) FetchProducts(ctx context.Context, param *ProductFilter) (list []*Product, err error) {
@@ -111,13 +158,42 @@ func (pm *ProductManager) FetchProducts(ctx context.Context, param *ProductFilte
 	return
 }
 
+// FetchProductPage return products in page of param.Pagination, and number of all products match param
+func (pm *ProductManager) FetchProductPage(ctx context.Context, param *ProductFilter) (list []*Product, total int64, err error) {
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		total, err = pm.storager.CountProducts(ctx, param)
+		if err != nil {
+			return err
+		}
+
+		list, err = pm.storager.FetchProducts(ctx, param)
+		return err
+	})
+
+	return
+}
+
 func (pm *ProductManager) DeleteProduct(ctx context.Context, p *Product) (err error) {
 	if p.ID == 1 {
 		return xerror.WrapModelErrorWithMsg("Cant Delete Build-in Product")
 	}
 	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		return pm.storager.DeleteProduct(ctx, p)
+		if err := pm.storager.DeleteProduct(ctx, p); err != nil {
+			return err
+		}
+
+		return pm.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    p.ID,
+			ProductName:  p.Name,
+			ResourceType: iaudit.ResourceProduct,
+			ResourceName: p.Name,
+			Action:       iaudit.ActionDelete,
+			Before:       p,
+		})
 	})
+	if err == nil {
+		pm.versionControlManager.NotifyChange(ctx, pm.changedTopics...)
+	}
 
 	return
 }
@@ -134,8 +210,29 @@ func (pm *ProductManager) CreateProduct(ctx context.Context, p *ProductParam) (e
 			return xerror.WrapRecordExisted("Product")
 		}
 
-		return pm.storager.CreateProduct(ctx, p)
+		if err = pm.storager.CreateProduct(ctx, p); err != nil {
+			return err
+		}
+
+		product, err := pm.fetchProduct(ctx, &ProductFilter{
+			Name: p.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		return pm.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceProduct,
+			ResourceName: product.Name,
+			Action:       iaudit.ActionCreate,
+			After:        product,
+		})
 	})
+	if err == nil {
+		pm.versionControlManager.NotifyChange(ctx, pm.changedTopics...)
+	}
 
 	return
 }
@@ -146,8 +243,30 @@ func (pm *ProductManager) UpdateProduct(ctx context.Context, p *Product, newVal
 	}
 
 	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		return pm.storager.UpdateProduct(ctx, p, newVal)
+		if err := pm.storager.UpdateProduct(ctx, p, newVal); err != nil {
+			return err
+		}
+
+		product, err := pm.fetchProduct(ctx, &ProductFilter{
+			ID: &p.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return pm.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    p.ID,
+			ProductName:  p.Name,
+			ResourceType: iaudit.ResourceProduct,
+			ResourceName: p.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       p,
+			After:        product,
+		})
 	})
+	if err == nil {
+		pm.versionControlManager.NotifyChange(ctx, pm.changedTopics...)
+	}
 
 	return
 }
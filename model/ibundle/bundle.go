@@ -0,0 +1,497 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ibundle
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// resources of bundle, they are Resource of changes planned
+const (
+	ResourceProduct    = "product"
+	ResourcePool       = "pool"
+	ResourceSubCluster = "sub_cluster"
+	ResourceCluster    = "cluster"
+	ResourceDomain     = "domain"
+	ResourceRoute      = "route_rule"
+	ResourceTLSRule    = "tls_rule"
+)
+
+// Bundle all resources of one product
+type Bundle struct {
+	Product     *ibasic.Product // nil if product not exist
+	Pools       []*icluster_conf.Pool
+	SubClusters []*icluster_conf.SubCluster
+	Clusters    []*icluster_conf.Cluster
+	Domains     []*iroute_conf.Domain
+	Routes      *iroute_conf.ProductRouteRule // nil if product has no route rule
+	TLSRule     *iprotocol.TLSRule            // nil if product has no tls rule
+}
+
+// BundleParam resources of product wanted, a nil section means not to care about it.
+// Product, pools, sub clusters and clusters are applied by updating fields set,
+// routes and tls rule are replaced as a whole.
+type BundleParam struct {
+	Product     *ibasic.ProductParam
+	Pools       []*icluster_conf.PoolParam
+	SubClusters []*icluster_conf.SubClusterParam
+	Clusters    []*icluster_conf.ClusterParam
+	Domains     []string
+	Routes      *iroute_conf.ProductRouteRule
+	TLSRule     *iprotocol.TLSRule
+}
+
+// Planner return changes from current resources to the wanted ones,
+// Key of change is name of resource, or name of product for product, route_rule and tls_rule
+type Planner func(current *Bundle) ([]*iversion_control.DiffEntry, error)
+
+type BundleManager struct {
+	txn                   itxn.TxnStorager
+	versionControlManager *iversion_control.VersionControlManager
+
+	productManager     *ibasic.ProductManager
+	poolManager        *icluster_conf.PoolManager
+	subClusterManager  *icluster_conf.SubClusterManager
+	clusterManager     *icluster_conf.ClusterManager
+	domainManager      *iroute_conf.DomainManager
+	routeRuleManager   *iroute_conf.RouteRuleManager
+	certificateManager *iprotocol.CertificateManager
+}
+
+func NewBundleManager(txn itxn.TxnStorager, versionControlManager *iversion_control.VersionControlManager,
+	productManager *ibasic.ProductManager, poolManager *icluster_conf.PoolManager,
+	subClusterManager *icluster_conf.SubClusterManager, clusterManager *icluster_conf.ClusterManager,
+	domainManager *iroute_conf.DomainManager, routeRuleManager *iroute_conf.RouteRuleManager,
+	certificateManager *iprotocol.CertificateManager) *BundleManager {
+
+	return &BundleManager{
+		txn:                   txn,
+		versionControlManager: versionControlManager,
+		productManager:        productManager,
+		poolManager:           poolManager,
+		subClusterManager:     subClusterManager,
+		clusterManager:        clusterManager,
+		domainManager:         domainManager,
+		routeRuleManager:      routeRuleManager,
+		certificateManager:    certificateManager,
+	}
+}
+
+func (m *BundleManager) FetchProductBundle(ctx context.Context, product *ibasic.Product) (bundle *Bundle, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		bundle, err = m.fetchProductBundle(ctx, product)
+		return err
+	})
+
+	return
+}
+
+func (m *BundleManager) fetchProductBundle(ctx context.Context, product *ibasic.Product) (*Bundle, error) {
+	bundle := &Bundle{
+		Product: product,
+	}
+
+	var err error
+	if bundle.Pools, err = m.poolManager.FetchProductPools(ctx, product); err != nil {
+		return nil, err
+	}
+
+	if bundle.SubClusters, err = m.subClusterManager.SubClusterList(ctx, &icluster_conf.SubClusterFilter{
+		Product: product,
+	}); err != nil {
+		return nil, err
+	}
+
+	if bundle.Clusters, err = m.clusterManager.FetchClusterList(ctx, &icluster_conf.ClusterFilter{
+		Product: product,
+	}); err != nil {
+		return nil, err
+	}
+
+	if bundle.Domains, err = m.domainManager.DomainList(ctx, &iroute_conf.DomainFilter{
+		Product: product,
+	}); err != nil {
+		return nil, err
+	}
+
+	if bundle.Routes, err = m.routeRuleManager.FetchProductRule(ctx, product); err != nil {
+		return nil, err
+	}
+
+	if bundle.TLSRule, err = m.certificateManager.FetchProductTLSRule(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// ApplyProductBundle plan changes from resources of product to param, and apply them if not dryRun.
+// Fetching, planning and applying run in one transaction, nothing is changed if any step fails.
+// Config topics changed are notified once after the transaction committed.
+// ApplyProductBundle plan changes with the current bundle of product and apply them in one transaction,
+// the product is created first if not exist, and its current bundle has nothing
+func (m *BundleManager) ApplyProductBundle(ctx context.Context, productName string, param *BundleParam,
+	plan Planner, dryRun bool) (changes []*iversion_control.DiffEntry, err error) {
+
+	// managers called below notify changes before the outer transaction finished, defer them
+	deferCtx := iversion_control.DeferNotify(ctx)
+	err = m.txn.AtomExecute(deferCtx, func(ctx context.Context) error {
+		product, err := m.fetchProduct(ctx, productName)
+		if err != nil {
+			return err
+		}
+
+		current := &Bundle{}
+		if product != nil {
+			if current, err = m.fetchProductBundle(ctx, product); err != nil {
+				return err
+			}
+		}
+
+		if changes, err = plan(current); err != nil || dryRun {
+			return err
+		}
+
+		if product == nil {
+			if product, err = m.createProduct(ctx, productName, param.Product); err != nil {
+				return err
+			}
+		}
+
+		return m.applyChanges(ctx, product, param, changes)
+	})
+	if err == nil {
+		m.versionControlManager.NotifyDeferred(deferCtx)
+	}
+
+	return
+}
+
+// fetchProduct return nil if product not exist
+func (m *BundleManager) fetchProduct(ctx context.Context, name string) (*ibasic.Product, error) {
+	list, err := m.productManager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: &name,
+	})
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return list[0], nil
+}
+
+func (m *BundleManager) createProduct(ctx context.Context, name string,
+	param *ibasic.ProductParam) (*ibasic.Product, error) {
+
+	create := &ibasic.ProductParam{
+		Name:        &name,
+		Description: lib.PString(""),
+	}
+	if param != nil {
+		if param.Description != nil {
+			create.Description = param.Description
+		}
+		create.MailList = param.MailList
+		create.PhoneList = param.PhoneList
+		create.ContactPersonList = param.ContactPersonList
+	}
+	if err := m.productManager.CreateProduct(ctx, create); err != nil {
+		return nil, err
+	}
+
+	product, err := m.fetchProduct(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, xerror.WrapRecordNotExist("Product")
+	}
+
+	return product, nil
+}
+
+func changedKeys(changes []*iversion_control.DiffEntry, resource string, action iversion_control.DiffAction) []string {
+	var keys []string
+	for _, one := range changes {
+		if one.Resource == resource && one.Action == action {
+			keys = append(keys, one.Key)
+		}
+	}
+
+	return keys
+}
+
+// applyChanges create and update resources in order of dependency, then delete resources in reverse order
+func (m *BundleManager) applyChanges(ctx context.Context, product *ibasic.Product, param *BundleParam,
+	changes []*iversion_control.DiffEntry) error {
+
+	added := func(resource string) []string {
+		return changedKeys(changes, resource, iversion_control.DiffActionAdded)
+	}
+	modified := func(resource string) []string {
+		return changedKeys(changes, resource, iversion_control.DiffActionModified)
+	}
+	removed := func(resource string) []string {
+		return changedKeys(changes, resource, iversion_control.DiffActionRemoved)
+	}
+
+	if len(modified(ResourceProduct)) > 0 {
+		if err := m.productManager.UpdateProduct(ctx, product, param.Product); err != nil {
+			return err
+		}
+	}
+
+	pools := map[string]*icluster_conf.PoolParam{}
+	for _, one := range param.Pools {
+		pools[*one.Name] = one
+	}
+	for _, name := range added(ResourcePool) {
+		if _, err := m.poolManager.CreateProductPool(ctx, product, &icluster_conf.PoolParam{
+			Name:      lib.PString(name),
+			Instances: pools[name].Instances,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, name := range modified(ResourcePool) {
+		pool, err := m.poolManager.FetchProductPool(ctx, product, name)
+		if err != nil {
+			return err
+		}
+		if err = m.poolManager.UpdateProductPool(ctx, product, pool, &icluster_conf.PoolParam{
+			Instances: pools[name].Instances,
+		}, ""); err != nil {
+			return err
+		}
+	}
+
+	subClusters := map[string]*icluster_conf.SubClusterParam{}
+	for _, one := range param.SubClusters {
+		subClusters[*one.Name] = one
+	}
+	for _, name := range added(ResourceSubCluster) {
+		one := subClusters[name]
+		if err := m.subClusterManager.CreateSubCluster(ctx, product, &icluster_conf.SubClusterParam{
+			Name:             one.Name,
+			Product:          product,
+			PoolName:         one.PoolName,
+			Description:      one.Description,
+			PortName:         one.PortName,
+			InstanceSelector: one.InstanceSelector,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, name := range modified(ResourceSubCluster) {
+		one := subClusters[name]
+		old, err := m.fetchSubCluster(ctx, product, name)
+		if err != nil {
+			return err
+		}
+		if one.PoolName != nil && old.InstancePool != nil && old.InstancePool.Name != *one.PoolName {
+			return xerror.WrapParamErrorWithMsg("InstancePool Of SubCluster %s Cant Be Changed", name)
+		}
+		if err = m.subClusterManager.UpdateSubCluster(ctx, old, &icluster_conf.SubClusterParam{
+			Description:      one.Description,
+			PortName:         one.PortName,
+			InstanceSelector: one.InstanceSelector,
+		}); err != nil {
+			return err
+		}
+	}
+
+	clusters := map[string]*icluster_conf.ClusterParam{}
+	for _, one := range param.Clusters {
+		clusters[*one.Name] = one
+	}
+	for _, name := range added(ResourceCluster) {
+		if err := m.clusterManager.CreateCluster(ctx, product, clusters[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range modified(ResourceCluster) {
+		if err := m.updateCluster(ctx, product, clusters[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range added(ResourceDomain) {
+		if err := m.domainManager.CreateDomain(ctx, product, &iroute_conf.DomainParam{
+			ProductID: &product.ID,
+			Name:      lib.PString(name),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(modified(ResourceRoute)) > 0 || len(added(ResourceRoute)) > 0 {
+		if _, err := m.routeRuleManager.UpsertProductRule(ctx, product, param.Routes, ""); err != nil {
+			return err
+		}
+	}
+	// route rules refer clusters, remove them before clusters deleted
+	if len(removed(ResourceRoute)) > 0 {
+		if _, err := m.routeRuleManager.UpsertProductRule(ctx, product, &iroute_conf.ProductRouteRule{
+			BasicRouteRules:   []*iroute_conf.BasicRouteRule{},
+			AdvanceRouteRules: []*iroute_conf.AdvanceRouteRule{},
+		}, ""); err != nil {
+			return err
+		}
+	}
+
+	// hosts of tls rule must be domains of product, bind them after domains created
+	if len(modified(ResourceTLSRule)) > 0 || len(added(ResourceTLSRule)) > 0 {
+		if _, err := m.certificateManager.UpsertProductTLSRule(ctx, product, param.TLSRule); err != nil {
+			return err
+		}
+	}
+	// and unbind them before domains deleted
+	if len(removed(ResourceTLSRule)) > 0 {
+		if _, err := m.certificateManager.DeleteProductTLSRule(ctx, product); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range removed(ResourceDomain) {
+		list, err := m.domainManager.DomainList(ctx, &iroute_conf.DomainFilter{
+			Product: product,
+			Name:    lib.PString(name),
+		})
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return xerror.WrapRecordNotExist("Domain")
+		}
+		if err = m.domainManager.DeleteDomain(ctx, product, list[0]); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range removed(ResourceCluster) {
+		cluster, err := m.fetchCluster(ctx, product, name)
+		if err != nil {
+			return err
+		}
+		if err = m.clusterManager.DeleteCluster(ctx, product, cluster, ""); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range removed(ResourceSubCluster) {
+		one, err := m.fetchSubCluster(ctx, product, name)
+		if err != nil {
+			return err
+		}
+		if err = m.subClusterManager.DeleteSubCluster(ctx, one); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range removed(ResourcePool) {
+		if _, err := m.poolManager.DeleteProductPool(ctx, product, name, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateCluster update basic setting, sub clusters and scheduler of cluster one by one
+func (m *BundleManager) updateCluster(ctx context.Context, product *ibasic.Product, param *icluster_conf.ClusterParam) error {
+	cluster, err := m.fetchCluster(ctx, product, *param.Name)
+	if err != nil {
+		return err
+	}
+
+	basic := *param
+	basic.SubClusters = nil
+	basic.Scheduler = nil
+	if err = m.clusterManager.UpdateCluster(ctx, product, cluster, &basic, ""); err != nil {
+		return err
+	}
+
+	oldNames := cluster.SubClusterNames()
+	if len(lib.StringSliceSubtract(oldNames, param.SubClusters)) > 0 ||
+		len(lib.StringSliceSubtract(param.SubClusters, oldNames)) > 0 {
+		// sub clusters can only be unbound without traffic, send all traffic to black hole before rebinding,
+		// nobody can see it before the transaction commit
+		scheduler := map[string]map[string]int{}
+		for bfeCluster := range cluster.Scheduler {
+			scheduler[bfeCluster] = map[string]int{
+				icluster_conf.BlackHole: 100,
+			}
+			for _, name := range oldNames {
+				scheduler[bfeCluster][name] = 0
+			}
+		}
+		if err = m.clusterManager.UpdateCluster(ctx, product, cluster, &icluster_conf.ClusterParam{
+			Scheduler: scheduler,
+		}, ""); err != nil {
+			return err
+		}
+
+		if cluster, err = m.fetchCluster(ctx, product, *param.Name); err != nil {
+			return err
+		}
+		if err = m.clusterManager.RebindSubCluster(ctx, product, cluster, param.SubClusters, ""); err != nil {
+			return err
+		}
+	}
+
+	return m.clusterManager.UpdateCluster(ctx, product, cluster, &icluster_conf.ClusterParam{
+		Scheduler: param.Scheduler,
+	}, "")
+}
+
+func (m *BundleManager) fetchCluster(ctx context.Context, product *ibasic.Product, name string) (*icluster_conf.Cluster, error) {
+	cluster, err := m.clusterManager.FetchCluster(ctx, &icluster_conf.ClusterFilter{
+		Name:    &name,
+		Product: product,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, xerror.WrapRecordNotExist("Cluster")
+	}
+
+	return cluster, nil
+}
+
+func (m *BundleManager) fetchSubCluster(ctx context.Context, product *ibasic.Product,
+	name string) (*icluster_conf.SubCluster, error) {
+
+	one, err := m.subClusterManager.FetchSubCluster(ctx, &icluster_conf.SubClusterFilter{
+		Name:    &name,
+		Product: product,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if one == nil {
+		return nil, xerror.WrapRecordNotExist("SubCluster")
+	}
+
+	return one, nil
+}
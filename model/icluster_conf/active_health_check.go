@@ -0,0 +1,350 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icluster_conf
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/bfe/bfe_config/bfe_cluster_conf/cluster_conf"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+const ConfigTopicActiveHealthCheck = "active_health_check"
+
+// ActiveHealthCheck probe backends of cluster periodically
+type ActiveHealthCheck struct {
+	ID        int64
+	ProductID int64
+	ClusterID int64
+
+	Schema   string // ClusterHealthCheckHTTP or ClusterHealthCheckTCP
+	Interval int32  // in ms
+	Timeout  int32  // in ms
+	SuccNum  int32  // healthy threshold, consecutive successes
+	FailNum  int32  // unhealthy threshold, consecutive failures
+
+	// used when schema is http
+	Host       string
+	Uri        string
+	StatusCode int32 // expected status code, 0 means any status code
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ActiveHealthCheckParam struct {
+	Schema   *string
+	Interval *int32
+	Timeout  *int32
+	SuccNum  *int32
+	FailNum  *int32
+
+	Host       *string
+	Uri        *string
+	StatusCode *int32
+}
+
+type ActiveHealthCheckFilter struct {
+	ClusterID  *int64
+	ClusterIDs []int64
+}
+
+type ActiveHealthCheckStorager interface {
+	FetchActiveHealthChecks(ctx context.Context, filter *ActiveHealthCheckFilter) ([]*ActiveHealthCheck, error)
+	CreateActiveHealthCheck(ctx context.Context, product *ibasic.Product, cluster *Cluster, param *ActiveHealthCheckParam) error
+	UpdateActiveHealthCheck(ctx context.Context, old *ActiveHealthCheck, param *ActiveHealthCheckParam) error
+	DeleteActiveHealthCheck(ctx context.Context, one *ActiveHealthCheck) error
+}
+
+func (ahc *ActiveHealthCheck) toBackendCheck() *cluster_conf.BackendCheck {
+	int322intp := func(i int32) *int {
+		tmp := int(i)
+		return &tmp
+	}
+
+	return &cluster_conf.BackendCheck{
+		Schem:         lib.PString(ahc.Schema),
+		Uri:           lib.PString(ahc.Uri),
+		Host:          lib.PString(ahc.Host),
+		StatusCode:    int322intp(ahc.StatusCode),
+		FailNum:       int322intp(ahc.FailNum),
+		SuccNum:       int322intp(ahc.SuccNum),
+		CheckTimeout:  int322intp(ahc.Timeout),
+		CheckInterval: int322intp(ahc.Interval),
+	}
+}
+
+// merge return a copy of ahc updated by param
+func (ahc *ActiveHealthCheck) merge(param *ActiveHealthCheckParam) *ActiveHealthCheck {
+	tmp := *ahc
+	if param.Schema != nil {
+		tmp.Schema = *param.Schema
+	}
+	if param.Interval != nil {
+		tmp.Interval = *param.Interval
+	}
+	if param.Timeout != nil {
+		tmp.Timeout = *param.Timeout
+	}
+	if param.SuccNum != nil {
+		tmp.SuccNum = *param.SuccNum
+	}
+	if param.FailNum != nil {
+		tmp.FailNum = *param.FailNum
+	}
+	if param.Host != nil {
+		tmp.Host = *param.Host
+	}
+	if param.Uri != nil {
+		tmp.Uri = *param.Uri
+	}
+	if param.StatusCode != nil {
+		tmp.StatusCode = *param.StatusCode
+	}
+
+	return &tmp
+}
+
+func (ahc *ActiveHealthCheck) check() error {
+	if !ClusterHealthCheckSchemaMap[ahc.Schema] {
+		return xerror.WrapParamErrorWithMsg("Schema %s Illegal, Want http or tcp", ahc.Schema)
+	}
+	if ahc.Timeout >= ahc.Interval {
+		return xerror.WrapParamErrorWithMsg("Timeout Must Less Than Interval")
+	}
+
+	if err := cluster_conf.BackendCheckCheck(ahc.toBackendCheck()); err != nil {
+		return xerror.WrapParamErrorWithMsg("Active Health Check Illegal: %v", err)
+	}
+
+	return nil
+}
+
+type ActiveHealthCheckManager struct {
+	txn itxn.TxnStorager
+
+	storager        ActiveHealthCheckStorager
+	clusterStorager ClusterStorager
+
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
+}
+
+func NewActiveHealthCheckManager(txn itxn.TxnStorager, storager ActiveHealthCheckStorager,
+	clusterStorager ClusterStorager, versionControlManager *iversion_control.VersionControlManager,
+	auditManager *iaudit.AuditManager) *ActiveHealthCheckManager {
+
+	return &ActiveHealthCheckManager{
+		txn:                   txn,
+		storager:              storager,
+		clusterStorager:       clusterStorager,
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+func (m *ActiveHealthCheckManager) fetchClusterActiveHealthCheck(ctx context.Context, cluster *Cluster) (*ActiveHealthCheck, error) {
+	list, err := m.storager.FetchActiveHealthChecks(ctx, &ActiveHealthCheckFilter{
+		ClusterID: &cluster.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return list[0], nil
+}
+
+// FetchClusterActiveHealthCheck return nil if cluster has no active health check
+func (m *ActiveHealthCheckManager) FetchClusterActiveHealthCheck(ctx context.Context,
+	cluster *Cluster) (one *ActiveHealthCheck, err error) {
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		one, err = m.fetchClusterActiveHealthCheck(ctx, cluster)
+		return err
+	})
+
+	return
+}
+
+func (m *ActiveHealthCheckManager) recordAudit(ctx context.Context, product *ibasic.Product, cluster *Cluster,
+	action string, before *ActiveHealthCheck) error {
+
+	param := &iaudit.AuditParam{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		ResourceType: iaudit.ResourceActiveHealthCheck,
+		ResourceName: cluster.Name,
+		Action:       action,
+	}
+	if before != nil {
+		param.Before = before
+	}
+
+	if action != iaudit.ActionDelete {
+		after, err := m.fetchClusterActiveHealthCheck(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		param.After = after
+	}
+
+	return m.auditManager.Record(ctx, param)
+}
+
+func (m *ActiveHealthCheckManager) CreateActiveHealthCheck(ctx context.Context, product *ibasic.Product,
+	cluster *Cluster, param *ActiveHealthCheckParam) (err error) {
+
+	if err = (&ActiveHealthCheck{}).merge(param).check(); err != nil {
+		return err
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		old, err := m.fetchClusterActiveHealthCheck(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		if old != nil {
+			return xerror.WrapRecordExisted("Active Health Check")
+		}
+
+		if err = m.storager.CreateActiveHealthCheck(ctx, product, cluster, param); err != nil {
+			return err
+		}
+
+		return m.recordAudit(ctx, product, cluster, iaudit.ActionCreate, nil)
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicActiveHealthCheck)
+	}
+
+	return
+}
+
+func (m *ActiveHealthCheckManager) UpdateActiveHealthCheck(ctx context.Context, product *ibasic.Product,
+	cluster *Cluster, old *ActiveHealthCheck, param *ActiveHealthCheckParam) (err error) {
+
+	if err = old.merge(param).check(); err != nil {
+		return err
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err = m.storager.UpdateActiveHealthCheck(ctx, old, param); err != nil {
+			return err
+		}
+
+		return m.recordAudit(ctx, product, cluster, iaudit.ActionUpdate, old)
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicActiveHealthCheck)
+	}
+
+	return
+}
+
+func (m *ActiveHealthCheckManager) DeleteActiveHealthCheck(ctx context.Context, product *ibasic.Product,
+	cluster *Cluster, old *ActiveHealthCheck) (err error) {
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err = m.storager.DeleteActiveHealthCheck(ctx, old); err != nil {
+			return err
+		}
+
+		return m.recordAudit(ctx, product, cluster, iaudit.ActionDelete, old)
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicActiveHealthCheck)
+	}
+
+	return
+}
+
+// ActiveHealthCheckConf is active health check config of all clusters
+type ActiveHealthCheckConf struct {
+	Version string
+	Config  map[string]*cluster_conf.BackendCheck // cluster name => check config
+}
+
+func (ahcc *ActiveHealthCheckConf) UpdateVersion(version string) error {
+	ahcc.Version = version
+
+	return nil
+}
+
+func (m *ActiveHealthCheckManager) activeHealthCheckConfGenerator(ctx context.Context) (*iversion_control.ExportData, error) {
+	checks, err := m.storager.FetchActiveHealthChecks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := m.clusterStorager.FetchClusterList(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	clusterMap := ClusterList2MapByID(clusters)
+
+	conf := &ActiveHealthCheckConf{
+		Version: iversion_control.ZeroVersion,
+		Config:  map[string]*cluster_conf.BackendCheck{},
+	}
+	for _, one := range checks {
+		cluster, ok := clusterMap[one.ClusterID]
+		if !ok {
+			continue
+		}
+
+		conf.Config[cluster.Name] = one.toBackendCheck()
+	}
+
+	return &iversion_control.ExportData{
+		Topic:              ConfigTopicActiveHealthCheck,
+		DataWithoutVersion: conf,
+	}, nil
+}
+
+// ExportActiveHealthCheck return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (m *ActiveHealthCheckManager) ExportActiveHealthCheck(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *ActiveHealthCheckConf, err error) {
+
+	err = m.versionControlManager.WaitChange(ctx, ConfigTopicActiveHealthCheck, wait, func(ctx context.Context) (bool, error) {
+		ed, err := m.versionControlManager.ExportConfig(ctx, ConfigTopicActiveHealthCheck, m.activeHealthCheckConfGenerator,
+			func() iversion_control.VersionValuable { return &ActiveHealthCheckConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*ActiveHealthCheckConf)
+		return conf.Version != lastVersion, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Version == lastVersion {
+		return nil, nil
+	}
+
+	return conf, nil
+}
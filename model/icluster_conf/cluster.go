@@ -16,12 +16,18 @@ package icluster_conf
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
 
 	"github.com/bfenetworks/bfe/bfe_config/bfe_cluster_conf/cluster_conf"
 	"github.com/bfenetworks/bfe/bfe_config/bfe_route_conf/route_rule_conf"
 
 	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/itxn"
 	"github.com/bfenetworks/api-server/model/iversion_control"
@@ -45,11 +51,35 @@ var (
 
 	ClusterDefaultReqFlushInterval int32 = 0
 	ClusterDefaultResFlushInterval int32 = -1 // -1: write response directly without using timing refresh
+
+	ClusterProtocolHTTP  = "http"
+	ClusterProtocolHTTPS = "https"
+	ClusterProtocolH2C   = "h2c"
+	ClusterProtocolFCGI  = "fcgi"
+	ClusterProtocolMap   = map[string]bool{
+		ClusterProtocolHTTP:  true,
+		ClusterProtocolHTTPS: true,
+		ClusterProtocolH2C:   true,
+		ClusterProtocolFCGI:  true,
+	}
+
+	ClusterBalanceModeWRR = cluster_conf.BalanceModeWrr
+	ClusterBalanceModeWLC = cluster_conf.BalanceModeWlc
+	ClusterBalanceModeMap = map[string]bool{
+		ClusterBalanceModeWRR: true,
+		ClusterBalanceModeWLC: true,
+	}
 )
 
+// outlierDetectionHttpCodeRegexp match http code like 500 or 5xx
+var outlierDetectionHttpCodeRegexp = regexp.MustCompile(`^[1-5]([0-9]{2}|xx)$`)
+
 type ClusterBasicConnectionParam struct {
 	MaxIdleConnPerRs    *int16
+	MaxConnPerRs        *int32
 	CancelOnClientClose *bool
+	ClientIPCarry       *bool
+	ClientPortCarry     *bool
 }
 
 type ClusterBasicBuffersParam struct {
@@ -72,12 +102,41 @@ type ClusterBasicTimeoutsParam struct {
 }
 
 type ClusterBasicParam struct {
+	Protocol                 *string
+	BalanceMode              *string
+	OutlierDetectionHttpCode *string
+
 	Connection *ClusterBasicConnectionParam
 	Retries    *ClusterBasicRetriesParam
 	Buffers    *ClusterBasicBuffersParam
 	Timeouts   *ClusterBasicTimeoutsParam
 }
 
+// check protocol, balance mode and outlier detection http code if be set
+func (cbp *ClusterBasicParam) check() error {
+	if cbp == nil {
+		return nil
+	}
+
+	if cbp.Protocol != nil && !ClusterProtocolMap[*cbp.Protocol] {
+		return xerror.WrapParamErrorWithMsg("Protocol %s Illegal, Want http, https, h2c or fcgi", *cbp.Protocol)
+	}
+
+	if cbp.BalanceMode != nil && !ClusterBalanceModeMap[*cbp.BalanceMode] {
+		return xerror.WrapParamErrorWithMsg("BalanceMode %s Illegal, Want WRR or WLC", *cbp.BalanceMode)
+	}
+
+	if cbp.OutlierDetectionHttpCode != nil && *cbp.OutlierDetectionHttpCode != "" {
+		for _, code := range strings.Split(*cbp.OutlierDetectionHttpCode, "|") {
+			if !outlierDetectionHttpCodeRegexp.MatchString(code) {
+				return xerror.WrapParamErrorWithMsg("OutlierDetectionHttpCode %s Illegal, Want Format Like 500|5xx", *cbp.OutlierDetectionHttpCode)
+			}
+		}
+	}
+
+	return nil
+}
+
 type ClusterStickySessionsParam struct {
 	SessionSticky *bool
 	HashStrategy  *int32
@@ -125,7 +184,10 @@ type ClusterParam struct {
 
 type ClusterBasicConnection struct {
 	MaxIdleConnPerRs    int16
+	MaxConnPerRs        int32 // 0 means unrestricted
 	CancelOnClientClose bool
+	ClientIPCarry       bool
+	ClientPortCarry     bool
 }
 
 type ClusterBasicBuffers struct {
@@ -148,6 +210,10 @@ type ClusterBasicTimeouts struct {
 }
 
 type ClusterBasic struct {
+	Protocol                 string
+	BalanceMode              string
+	OutlierDetectionHttpCode string // http codes split by |, such as 500|5xx
+
 	Connection *ClusterBasicConnection
 	Retries    *ClusterBasicRetries
 	Buffers    *ClusterBasicBuffers
@@ -212,6 +278,32 @@ func (cluster *Cluster) SubClusterNames() []string {
 	return names
 }
 
+// Revision return hash of cluster config, it changes when cluster be updated
+func (cluster *Cluster) Revision() string {
+	bs, _ := json.Marshal(struct {
+		Name               string
+		Description        string
+		Ready              bool
+		Basic              *ClusterBasic
+		StickySessions     *ClusterStickySessions
+		SubClusters        []string
+		Scheduler          map[string]map[string]int
+		PassiveHealthCheck *ClusterPassiveHealthCheck
+	}{
+		Name:               cluster.Name,
+		Description:        cluster.Description,
+		Ready:              cluster.Ready,
+		Basic:              cluster.Basic,
+		StickySessions:     cluster.StickySessions,
+		SubClusters:        cluster.SubClusterNames(),
+		Scheduler:          cluster.Scheduler,
+		PassiveHealthCheck: cluster.PassiveHealthCheck,
+	})
+	sum := md5.Sum(bs)
+
+	return hex.EncodeToString(sum[:])
+}
+
 func ClusterList2MapByName(list []*Cluster) map[string]*Cluster {
 	m := map[string]*Cluster{}
 	for _, one := range list {
@@ -237,7 +329,8 @@ func ClusterList2MapByID(list []*Cluster) map[int64]*Cluster {
 func NewClusterManager(txn itxn.TxnStorager, storager ClusterStorager,
 	subClusterStorager SubClusterStorager, bfeClusterStorager ibasic.BFEClusterStorager,
 	versionControlManager *iversion_control.VersionControlManager,
-	deleteCheckers map[string]func(context.Context, *ibasic.Product, *Cluster) error) *ClusterManager {
+	deleteCheckers map[string]func(context.Context, *ibasic.Product, *Cluster) error,
+	auditManager *iaudit.AuditManager) *ClusterManager {
 
 	return &ClusterManager{
 		txn:                   txn,
@@ -245,6 +338,7 @@ func NewClusterManager(txn itxn.TxnStorager, storager ClusterStorager,
 		subClusterStorager:    subClusterStorager,
 		bfeClusterStorager:    bfeClusterStorager,
 		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
 
 		deleteCheckers: deleteCheckers,
 	}
@@ -253,6 +347,8 @@ func NewClusterManager(txn itxn.TxnStorager, storager ClusterStorager,
 type ClusterStorager interface {
 	FetchCluster(ctx context.Context, param *ClusterFilter) (*Cluster, error)
 	FetchClusterList(ctx context.Context, param *ClusterFilter) ([]*Cluster, error)
+	// LockCluster fetch cluster and lock it until transaction be finished
+	LockCluster(ctx context.Context, param *ClusterFilter) (*Cluster, error)
 	ClusterUpdate(ctx context.Context, product *ibasic.Product, old *Cluster, param *ClusterParam) error
 	ClusterCreate(ctx context.Context, product *ibasic.Product, param *ClusterParam, subClusters []*SubCluster) (int64, error)
 	ClusterDelete(ctx context.Context, product *ibasic.Product, cluster *Cluster) error
@@ -267,10 +363,61 @@ type ClusterManager struct {
 	bfeClusterStorager ibasic.BFEClusterStorager
 
 	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
 
 	deleteCheckers map[string]func(context.Context, *ibasic.Product, *Cluster) error
 }
 
+// recordAudit save audit of cluster, after data is fetched from storager if cluster not be deleted
+func (cm *ClusterManager) recordAudit(ctx context.Context, product *ibasic.Product, action string,
+	name string, before *Cluster) error {
+
+	param := &iaudit.AuditParam{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		ResourceType: iaudit.ResourceCluster,
+		ResourceName: name,
+		Action:       action,
+	}
+	if before != nil {
+		param.Before = before
+	}
+
+	if action != iaudit.ActionDelete {
+		after, err := cm.storager.FetchCluster(ctx, &ClusterFilter{
+			Name:    &name,
+			Product: product,
+		})
+		if err != nil {
+			return err
+		}
+		param.After = after
+	}
+
+	return cm.auditManager.Record(ctx, param)
+}
+
+// lockCluster lock cluster in transaction, if revision is not empty, it must be equal to revision of cluster in storage
+func (cm *ClusterManager) lockCluster(ctx context.Context, product *ibasic.Product, name, revision string) (*Cluster, error) {
+	cluster, err := cm.storager.LockCluster(ctx, &ClusterFilter{
+		Name:    &name,
+		Product: product,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, xerror.WrapRecordNotExist("Cluster")
+	}
+
+	if revision != "" && revision != cluster.Revision() {
+		return nil, xerror.WrapPreconditionFailedErrorWithMsg("Cluster %s Has Been Changed, Revision Is %s",
+			name, cluster.Revision())
+	}
+
+	return cluster, nil
+}
+
 func (rm *ClusterManager) FetchClusterList(ctx context.Context, param *ClusterFilter) (list []*Cluster, err error) {
 	err = rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
 		list, err = rm.storager.FetchClusterList(ctx, param)
@@ -321,6 +468,9 @@ func (cm *ClusterManager) CreateCluster(ctx context.Context, product *ibasic.Pro
 		if err := cm.checkManualLB(ctx, nil, param); err != nil {
 			return err
 		}
+		if err := param.Basic.check(); err != nil {
+			return err
+		}
 
 		if param.Scheduler == nil {
 			if param.Scheduler, err = cm.constructDefaultScheduler(ctx, bindingSubClusters); err != nil {
@@ -333,10 +483,17 @@ func (cm *ClusterManager) CreateCluster(ctx context.Context, product *ibasic.Pro
 			return err
 		}
 
-		return cm.storager.BindSubCluster(ctx, &Cluster{
+		if err = cm.storager.BindSubCluster(ctx, &Cluster{
 			ID: clusterID,
-		}, bindingSubClusters, nil)
+		}, bindingSubClusters, nil); err != nil {
+			return err
+		}
+
+		return cm.recordAudit(ctx, product, iaudit.ActionCreate, *param.Name, nil)
 	})
+	if err == nil {
+		cm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
@@ -447,16 +604,32 @@ func (cm *ClusterManager) checkBindingSubClusters(ctx context.Context, cluster *
 	return nil
 }
 
+// UpdateCluster update cluster, if revision is not empty, it must be equal to revision of cluster in storage
 func (cm *ClusterManager) UpdateCluster(ctx context.Context, product *ibasic.Product, oldData *Cluster,
-	param *ClusterParam) (err error) {
+	param *ClusterParam, revision string) (err error) {
 
 	err = cm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		oldData, err := cm.lockCluster(ctx, product, oldData.Name, revision)
+		if err != nil {
+			return err
+		}
+
 		if err = cm.checkManualLB(ctx, oldData, param); err != nil {
 			return err
 		}
+		if err = param.Basic.check(); err != nil {
+			return err
+		}
+
+		if err = cm.storager.ClusterUpdate(ctx, product, oldData, param); err != nil {
+			return err
+		}
 
-		return cm.storager.ClusterUpdate(ctx, product, oldData, param)
+		return cm.recordAudit(ctx, product, iaudit.ActionUpdate, oldData.Name, oldData)
 	})
+	if err == nil {
+		cm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
@@ -486,8 +659,10 @@ func (cm *ClusterManager) checkLbMatrix(cluster *Cluster, unbindSubClusters, app
 	return newManualLbMatrix, nil
 }
 
+// RebindSubCluster bind sub clusters to cluster, if revision is not empty,
+// it must be equal to revision of cluster in storage
 func (cm *ClusterManager) RebindSubCluster(ctx context.Context, product *ibasic.Product, cluster *Cluster,
-	bindingSubClusterNames []string) error {
+	bindingSubClusterNames []string, revision string) (err error) {
 
 	unbindSubClusterNames := lib.StringSliceSubtract(cluster.SubClusterNames(), bindingSubClusterNames)
 	appendSubClusterNames := lib.StringSliceSubtract(bindingSubClusterNames, cluster.SubClusterNames())
@@ -500,7 +675,11 @@ func (cm *ClusterManager) RebindSubCluster(ctx context.Context, product *ibasic.
 		return err
 	}
 
-	return cm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+	err = cm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if _, err := cm.lockCluster(ctx, product, cluster.Name, revision); err != nil {
+			return err
+		}
+
 		bindingSubClusters, err := cm.subClusterStorager.FetchSubClusterList(ctx, &SubClusterFilter{
 			Names:   bindingSubClusterNames,
 			Product: product,
@@ -535,12 +714,29 @@ func (cm *ClusterManager) RebindSubCluster(ctx context.Context, product *ibasic.
 		}
 
 		// U should check param by yourself
-		return cm.storager.BindSubCluster(ctx, cluster, appendSubClusters, unbindSubClusters)
+		if err = cm.storager.BindSubCluster(ctx, cluster, appendSubClusters, unbindSubClusters); err != nil {
+			return err
+		}
+
+		return cm.recordAudit(ctx, product, iaudit.ActionUpdate, cluster.Name, cluster)
 	})
+	if err == nil {
+		cm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
+
+	return
 }
 
-func (cm *ClusterManager) DeleteCluster(ctx context.Context, product *ibasic.Product, cluster *Cluster) (err error) {
+// DeleteCluster delete cluster, if revision is not empty, it must be equal to revision of cluster in storage
+func (cm *ClusterManager) DeleteCluster(ctx context.Context, product *ibasic.Product, cluster *Cluster,
+	revision string) (err error) {
+
 	err = cm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		cluster, err := cm.lockCluster(ctx, product, cluster.Name, revision)
+		if err != nil {
+			return err
+		}
+
 		for _, checker := range cm.deleteCheckers {
 			err = checker(ctx, product, cluster)
 			if err != nil {
@@ -556,8 +752,11 @@ func (cm *ClusterManager) DeleteCluster(ctx context.Context, product *ibasic.Pro
 			return err
 		}
 
-		return nil
+		return cm.recordAudit(ctx, product, iaudit.ActionDelete, cluster.Name, cluster)
 	})
+	if err == nil {
+		cm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
@@ -582,8 +781,33 @@ func AppendAdvancedRuleCluster(list []*Cluster) []*Cluster {
 	})
 }
 
-func NewBfeClusterConf(version string, clusters []*Cluster) *cluster_conf.BfeClusterConf {
-	clusterConfMap := cluster_conf.ClusterToConf{}
+// ClusterBasicConf extends cluster_conf.ClusterBasicConf with client ip and port carry settings,
+// fields of the embedded struct keep the same json names in cluster_conf.data
+type ClusterBasicConf struct {
+	cluster_conf.ClusterBasicConf
+
+	ClientIPCarry   *bool // carry client ip to backend
+	ClientPortCarry *bool // carry client port to backend
+}
+
+// ClusterConf same as cluster_conf.ClusterConf, except ClusterBasic
+type ClusterConf struct {
+	BackendConf  *cluster_conf.BackendBasic
+	CheckConf    *cluster_conf.BackendCheck
+	GslbBasic    *cluster_conf.GslbBasicConf
+	ClusterBasic *ClusterBasicConf
+}
+
+type ClusterToConf map[string]ClusterConf
+
+// BfeClusterConf content of cluster_conf.data
+type BfeClusterConf struct {
+	Version *string
+	Config  *ClusterToConf
+}
+
+func NewBfeClusterConf(version string, clusters []*Cluster) *BfeClusterConf {
+	clusterConfMap := ClusterToConf{}
 
 	int322intp := func(i int32) *int {
 		tmp := int(i)
@@ -603,12 +827,23 @@ func NewBfeClusterConf(version string, clusters []*Cluster) *cluster_conf.BfeClu
 			continue
 		}
 
-		clusterConfMap[cluster.Name] = cluster_conf.ClusterConf{
+		protocol := cluster.Basic.Protocol
+		if protocol == "" {
+			protocol = ClusterProtocolHTTP
+		}
+		balanceMode := cluster.Basic.BalanceMode
+		if balanceMode == "" {
+			balanceMode = ClusterBalanceModeWRR
+		}
+
+		clusterConfMap[cluster.Name] = ClusterConf{
 			BackendConf: &cluster_conf.BackendBasic{
-				Protocol:              lib.PString("http"),
-				TimeoutConnSrv:        int322intp(cluster.Basic.Timeouts.TimeoutConnServ),
-				TimeoutResponseHeader: int322intp(cluster.Basic.Timeouts.TimeoutResponseHeader),
-				MaxIdleConnsPerHost:   int162intp(cluster.Basic.Connection.MaxIdleConnPerRs),
+				Protocol:                 lib.PString(protocol),
+				TimeoutConnSrv:           int322intp(cluster.Basic.Timeouts.TimeoutConnServ),
+				TimeoutResponseHeader:    int322intp(cluster.Basic.Timeouts.TimeoutResponseHeader),
+				MaxIdleConnsPerHost:      int162intp(cluster.Basic.Connection.MaxIdleConnPerRs),
+				MaxConnsPerHost:          int322intp(cluster.Basic.Connection.MaxConnPerRs),
+				OutlierDetectionHttpCode: lib.PString(cluster.Basic.OutlierDetectionHttpCode),
 			},
 			CheckConf: cluster.PassiveHealthCheck.toBackendCheck(),
 			GslbBasic: &cluster_conf.GslbBasicConf{
@@ -619,21 +854,25 @@ func NewBfeClusterConf(version string, clusters []*Cluster) *cluster_conf.BfeClu
 					HashHeader:    &cluster.StickySessions.HashHeader,
 					SessionSticky: &cluster.StickySessions.SessionSticky,
 				},
-				BalanceMode: lib.PString("WRR"),
+				BalanceMode: lib.PString(balanceMode),
 			},
-			ClusterBasic: &cluster_conf.ClusterBasicConf{
-				TimeoutReadClient:      int322intp(cluster.Basic.Timeouts.TimeoutReadbodyClient),
-				TimeoutWriteClient:     int322intp(cluster.Basic.Timeouts.TimeoutWriteClient),
-				TimeoutReadClientAgain: int322intp(cluster.Basic.Timeouts.TimeoutReadClientAgain),
-				ReqWriteBufferSize:     int322intp(cluster.Basic.Buffers.ReqWriteBufferSize),
-				ReqFlushInterval:       int322intp(cluster.Basic.Buffers.ReqFlushInterval),
-				ResFlushInterval:       int322intp(cluster.Basic.Buffers.ResFlushInterval),
-				CancelOnClientClose:    &cluster.Basic.Connection.CancelOnClientClose,
+			ClusterBasic: &ClusterBasicConf{
+				ClusterBasicConf: cluster_conf.ClusterBasicConf{
+					TimeoutReadClient:      int322intp(cluster.Basic.Timeouts.TimeoutReadbodyClient),
+					TimeoutWriteClient:     int322intp(cluster.Basic.Timeouts.TimeoutWriteClient),
+					TimeoutReadClientAgain: int322intp(cluster.Basic.Timeouts.TimeoutReadClientAgain),
+					ReqWriteBufferSize:     int322intp(cluster.Basic.Buffers.ReqWriteBufferSize),
+					ReqFlushInterval:       int322intp(cluster.Basic.Buffers.ReqFlushInterval),
+					ResFlushInterval:       int322intp(cluster.Basic.Buffers.ResFlushInterval),
+					CancelOnClientClose:    &cluster.Basic.Connection.CancelOnClientClose,
+				},
+				ClientIPCarry:   &cluster.Basic.Connection.ClientIPCarry,
+				ClientPortCarry: &cluster.Basic.Connection.ClientPortCarry,
 			},
 		}
 
 	}
-	return &cluster_conf.BfeClusterConf{
+	return &BfeClusterConf{
 		Version: &version,
 		Config:  &clusterConfMap,
 	}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icluster_conf
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+var (
+	_ iversion_control.Diffable = &ClusterTableConf{}
+	_ iversion_control.Diffable = &GSLBConf{}
+	_ iversion_control.Diffable = &ActiveHealthCheckConf{}
+)
+
+// backendMap return cluster/sub_cluster/addr:port => backend
+func (ctc *ClusterTableConf) backendMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if ctc.Config == nil {
+		return m
+	}
+
+	for clusterName, cluster := range *ctc.Config {
+		for subClusterName, backends := range cluster {
+			for _, backend := range backends {
+				if backend == nil || backend.Addr == nil || backend.Port == nil {
+					continue
+				}
+				m[clusterName+"/"+subClusterName+"/"+backend.AddrInfo()] = backend
+			}
+		}
+	}
+
+	return m
+}
+
+// Diff backends of sub clusters
+func (ctc *ClusterTableConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*ClusterTableConf)
+
+	return iversion_control.DiffMap("backend", o.backendMap(), ctc.backendMap())
+}
+
+// weightMap return cluster/sub_cluster => weight
+func (gc *GSLBConf) weightMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if gc.Clusters == nil {
+		return m
+	}
+
+	for clusterName, weights := range *gc.Clusters {
+		for subClusterName, weight := range weights {
+			m[clusterName+"/"+subClusterName] = weight
+		}
+	}
+
+	return m
+}
+
+// Diff gslb weights of sub clusters
+func (gc *GSLBConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*GSLBConf)
+
+	return iversion_control.DiffMap("gslb_weight", o.weightMap(), gc.weightMap())
+}
+
+// Diff active health check of clusters
+func (ahcc *ActiveHealthCheckConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*ActiveHealthCheckConf)
+
+	toMap := func(conf *ActiveHealthCheckConf) map[string]interface{} {
+		m := map[string]interface{}{}
+		for clusterName, check := range conf.Config {
+			m[clusterName] = check
+		}
+		return m
+	}
+
+	return iversion_control.DiffMap("active_health_check", toMap(o), toMap(ahcc))
+}
+
+func (rm *ClusterManager) DiffClusterTable(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return rm.versionControlManager.DiffConfig(ctx, ConfigTopicClusterTable, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &ClusterTableConf{} })
+}
+
+func (rm *ClusterManager) DiffGSLB(ctx context.Context, bfeClusterName, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return rm.versionControlManager.DiffConfig(ctx, ConfigTopicGSLB+"."+bfeClusterName, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &GSLBConf{} })
+}
+
+func (m *ActiveHealthCheckManager) DiffActiveHealthCheck(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return m.versionControlManager.DiffConfig(ctx, ConfigTopicActiveHealthCheck, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &ActiveHealthCheckConf{} })
+}
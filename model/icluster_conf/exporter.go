@@ -16,10 +16,12 @@ package icluster_conf
 
 import (
 	"context"
+	"time"
 
 	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
 	"github.com/bfenetworks/api-server/model/iversion_control"
+	"github.com/bfenetworks/api-server/stateful"
 	"github.com/bfenetworks/bfe/bfe_config/bfe_cluster_conf/cluster_table_conf"
 	"github.com/bfenetworks/bfe/bfe_config/bfe_cluster_conf/gslb_conf"
 )
@@ -27,8 +29,19 @@ import (
 const (
 	ConfigTopicClusterTable = "cluster_table"
 	ConfigTopicGSLB         = "gslb"
+
+	// configTopicRouteRule same as iroute_conf.ConfigTopicRouteRule, cluster conf is exported with route rule
+	configTopicRouteRule = "route_rule"
 )
 
+// clusterChangedTopics are topics whose export data depends on cluster, sub cluster or pool
+var clusterChangedTopics = []string{
+	ConfigTopicClusterTable,
+	ConfigTopicGSLB,
+	configTopicRouteRule,
+	ConfigTopicActiveHealthCheck,
+}
+
 type ClusterTableConf struct {
 	cluster_table_conf.ClusterTableConf
 }
@@ -39,6 +52,21 @@ func (ctc *ClusterTableConf) UpdateVersion(version string) error {
 	return nil
 }
 
+// exportedBackends return backends of sub cluster exported to cluster_table.
+// BFE rejects sub cluster without backend whose weight > 0, so nil is returned for it,
+// like sub cluster whose instances are all disabled or whose selector matches nothing,
+// it's excluded from both cluster_table and gslb
+func exportedBackends(subCluster *SubCluster) []Instance {
+	instances := subCluster.Backends(stateful.DefaultConfig.RunTime.ExportDisabledInstance)
+	for _, instance := range instances {
+		if instance.Weight > 0 {
+			return instances
+		}
+	}
+
+	return nil
+}
+
 func (rm *ClusterManager) clusterTableConfGenerator(ctx context.Context) (*iversion_control.ExportData, error) {
 	clusters, err := rm.storager.FetchClusterList(ctx, nil)
 	if err != nil {
@@ -50,12 +78,13 @@ func (rm *ClusterManager) clusterTableConfGenerator(ctx context.Context) (*ivers
 		clusterBackend := map[string]cluster_table_conf.SubClusterBackend{}
 
 		for _, subCluster := range cluster.SubClusters {
-			if subCluster.InstancePool == nil || len(subCluster.InstancePool.Instances) == 0 {
+			instances := exportedBackends(subCluster)
+			if len(instances) == 0 {
 				continue
 			}
 
-			subClusterBackend := make(cluster_table_conf.SubClusterBackend, 0, len(subCluster.InstancePool.Instances))
-			for _, instance := range subCluster.InstancePool.Instances {
+			subClusterBackend := make(cluster_table_conf.SubClusterBackend, 0, len(instances))
+			for _, instance := range instances {
 				subClusterBackend = append(subClusterBackend, &cluster_table_conf.BackendConf{
 					Name:   lib.PString(instance.HostName),
 					Addr:   lib.PString(instance.IP),
@@ -87,13 +116,25 @@ func (rm *ClusterManager) clusterTableConfGenerator(ctx context.Context) (*ivers
 	}, nil
 }
 
-func (rm *ClusterManager) ExportClusterTable(ctx context.Context, lastVersion string) (*ClusterTableConf, error) {
-	ed, err := rm.versionControlManager.ExportConfig(ctx, ConfigTopicClusterTable, rm.clusterTableConfGenerator)
+// ExportClusterTable return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (rm *ClusterManager) ExportClusterTable(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *ClusterTableConf, err error) {
+
+	err = rm.versionControlManager.WaitChange(ctx, ConfigTopicClusterTable, wait, func(ctx context.Context) (bool, error) {
+		ed, err := rm.versionControlManager.ExportConfig(ctx, ConfigTopicClusterTable, rm.clusterTableConfGenerator,
+			func() iversion_control.VersionValuable { return &ClusterTableConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*ClusterTableConf)
+		return *conf.Version != lastVersion, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	conf := ed.DataWithoutVersion.(*ClusterTableConf)
 	if *conf.Version == lastVersion {
 		return nil, nil
 	}
@@ -113,6 +154,35 @@ func (gc *GSLBConf) UpdateVersion(version string) error {
 	return nil
 }
 
+// exportedLBMatrix drop sub clusters not exported to cluster_table from lbMatrix,
+// nil is returned if no weight > 0 left, the cluster is excluded from gslb then
+func exportedLBMatrix(cluster *Cluster, lbMatrix map[string]int) map[string]int {
+	exported := map[string]bool{BlackHole: true}
+	for _, subCluster := range cluster.SubClusters {
+		if len(exportedBackends(subCluster)) > 0 {
+			exported[subCluster.Name] = true
+		}
+	}
+
+	rst := map[string]int{}
+	total := 0
+	for name, weight := range lbMatrix {
+		if !exported[name] {
+			continue
+		}
+
+		rst[name] = weight
+		if weight > 0 {
+			total += weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	return rst
+}
+
 func (rm *ClusterManager) gslbConfGenerator(bfeClusterName string) func(ctx context.Context) (*iversion_control.ExportData, error) {
 	return func(ctx context.Context) (*iversion_control.ExportData, error) {
 		topic := ConfigTopicGSLB + "." + bfeClusterName
@@ -133,6 +203,11 @@ func (rm *ClusterManager) gslbConfGenerator(bfeClusterName string) func(ctx cont
 				return nil, xerror.WrapParamErrorWithMsg("BFECluster %s Not Exist", bfeClusterName)
 			}
 
+			lbMatraix = exportedLBMatrix(cluster, lbMatraix)
+			if lbMatraix == nil {
+				continue
+			}
+
 			gslbClustersConf[cluster.Name] = lbMatraix
 		}
 
@@ -155,14 +230,26 @@ func (rm *ClusterManager) gslbConfGenerator(bfeClusterName string) func(ctx cont
 	}
 }
 
-func (rm *ClusterManager) ExportGSLB(ctx context.Context, lastVersion, bfeClusterName string) (*GSLBConf, error) {
+// ExportGSLB return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (rm *ClusterManager) ExportGSLB(ctx context.Context, lastVersion, bfeClusterName string,
+	wait time.Duration) (conf *GSLBConf, err error) {
+
 	topic := ConfigTopicGSLB + "." + bfeClusterName
-	ed, err := rm.versionControlManager.ExportConfig(ctx, topic, rm.gslbConfGenerator(bfeClusterName))
+	err = rm.versionControlManager.WaitChange(ctx, topic, wait, func(ctx context.Context) (bool, error) {
+		ed, err := rm.versionControlManager.ExportConfig(ctx, topic, rm.gslbConfGenerator(bfeClusterName),
+			func() iversion_control.VersionValuable { return &GSLBConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*GSLBConf)
+		return conf.Version != lastVersion, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	conf := ed.DataWithoutVersion.(*GSLBConf)
 	if conf.Version == lastVersion {
 		return nil, nil
 	}
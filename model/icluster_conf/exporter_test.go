@@ -0,0 +1,106 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icluster_conf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+// fakeClusterStorager return clusters given, other methods are not implemented
+type fakeClusterStorager struct {
+	ClusterStorager
+	clusters []*Cluster
+}
+
+func (s *fakeClusterStorager) FetchClusterList(ctx context.Context, param *ClusterFilter) ([]*Cluster, error) {
+	return s.clusters, nil
+}
+
+func newExportTestManager() *ClusterManager {
+	pool := &Pool{
+		Name: "demo.pool",
+		Instances: []Instance{
+			{HostName: "host1", IP: "10.0.0.1", Port: 80, Weight: 10, Tags: map[string]string{"idc": "bj"}},
+			{HostName: "host2", IP: "10.0.0.2", Port: 80, Weight: 10, Tags: map[string]string{"idc": "gz"}, Disable: true},
+		},
+	}
+
+	return &ClusterManager{
+		storager: &fakeClusterStorager{
+			clusters: []*Cluster{
+				{
+					Name: "demo_cluster",
+					SubClusters: []*SubCluster{
+						{Name: "bj", InstancePool: pool, InstanceSelector: map[string]string{"idc": "bj"}},
+						// all instances disabled
+						{Name: "gz", InstancePool: pool, InstanceSelector: map[string]string{"idc": "gz"}},
+						// selector matches nothing
+						{Name: "sh", InstancePool: pool, InstanceSelector: map[string]string{"idc": "sh"}},
+					},
+					Scheduler: map[string]map[string]int{
+						"bfe": {"bj": 50, "gz": 30, "sh": 20, BlackHole: 0},
+					},
+				},
+				{
+					Name: "demo_cluster_down",
+					SubClusters: []*SubCluster{
+						{Name: "gz", InstancePool: pool, InstanceSelector: map[string]string{"idc": "gz"}},
+					},
+					Scheduler: map[string]map[string]int{
+						"bfe": {"gz": 100, BlackHole: 0},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExportSubClusterWithoutBackend(t *testing.T) {
+	for _, exportDisabled := range []bool{false, true} {
+		stateful.DefaultConfig = &stateful.Config{}
+		stateful.DefaultConfig.RunTime.ExportDisabledInstance = exportDisabled
+		rm := newExportTestManager()
+
+		ed, err := rm.clusterTableConfGenerator(context.Background())
+		if err != nil {
+			t.Fatalf("clusterTableConfGenerator(export disabled %v): %v", exportDisabled, err)
+		}
+		table := *ed.DataWithoutVersion.(*ClusterTableConf).Config
+		if backends := table["demo_cluster"]; len(backends) != 1 || backends["bj"] == nil {
+			t.Errorf("clusterTableConfGenerator(export disabled %v): want only sub cluster bj, got %v",
+				exportDisabled, backends)
+		}
+		if backends := table["demo_cluster_down"]; len(backends) != 0 {
+			t.Errorf("clusterTableConfGenerator(export disabled %v): want no sub cluster, got %v",
+				exportDisabled, backends)
+		}
+
+		ed, err = rm.gslbConfGenerator("bfe")(context.Background())
+		if err != nil {
+			t.Fatalf("gslbConfGenerator(export disabled %v): %v", exportDisabled, err)
+		}
+		gslb := *ed.DataWithoutVersion.(*GSLBConf).Clusters
+		if lb := gslb["demo_cluster"]; len(lb) != 2 || lb["bj"] != 50 || lb[BlackHole] != 0 {
+			t.Errorf("gslbConfGenerator(export disabled %v): want sub cluster bj and blackhole, got %v",
+				exportDisabled, lb)
+		}
+		if lb, ok := gslb["demo_cluster_down"]; ok {
+			t.Errorf("gslbConfGenerator(export disabled %v): want cluster excluded, got %v", exportDisabled, lb)
+		}
+	}
+}
@@ -16,12 +16,18 @@ package icluster_conf
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
 )
 
 var (
@@ -34,6 +40,8 @@ type PoolFilter struct {
 	IDs       []int64
 	ID        *int64
 	ProductID *int64
+
+	Pagination *lib.Pagination
 }
 
 type PoolParam struct {
@@ -54,6 +62,22 @@ type Pool struct {
 	Tag       int8
 }
 
+// Revision return hash of instances, it changes when pool be updated
+func (p *Pool) Revision() string {
+	bs, _ := json.Marshal(p.Instances)
+	sum := md5.Sum(bs)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (p *Pool) checkRevision(revision string) error {
+	if revision != "" && revision != p.Revision() {
+		return xerror.WrapPreconditionFailedErrorWithMsg("Pool %s Has Been Changed, Revision Is %s", p.Name, p.Revision())
+	}
+
+	return nil
+}
+
 type Instance struct {
 	HostName string            `json:"Name"`
 	IP       string            `json:"Addr"`
@@ -72,9 +96,46 @@ func (i *Instance) IPWithPort() string {
 	return fmt.Sprintf("%s:%d", i.IP, i.Port)
 }
 
+// Match return true if key equals to hostname, ip or ip:port of instance
+func (i *Instance) Match(key string) bool {
+	if key == i.HostName || key == i.IP {
+		return true
+	}
+
+	port, _ := i.NamedPort("")
+	return key == fmt.Sprintf("%s:%d", i.IP, port)
+}
+
+// NamedPort return port with name, default port be returned if name is empty
+func (i *Instance) NamedPort(name string) (int, bool) {
+	if name == "" {
+		if i.Port == 0 {
+			return i.Ports["Default"], true
+		}
+		return i.Port, true
+	}
+
+	port, ok := i.Ports[name]
+	return port, ok
+}
+
+// MatchSelector return true if all key-values in selector are in tags of instance
+func (i *Instance) MatchSelector(selector map[string]string) bool {
+	for k, v := range selector {
+		if tag, ok := i.Tags[k]; !ok || tag != v {
+			return false
+		}
+	}
+
+	return true
+}
+
 type PoolStorager interface {
 	FetchPool(ctx context.Context, name string) (*Pool, error)
+	// LockPool fetch pool and lock it until transaction be finished
+	LockPool(ctx context.Context, name string) (*Pool, error)
 	FetchPools(ctx context.Context, param *PoolFilter) ([]*Pool, error)
+	CountPools(ctx context.Context, param *PoolFilter) (int64, error)
 
 	CreatePool(ctx context.Context, product *ibasic.Product, data *PoolParam) (*Pool, error)
 	UpdatePool(ctx context.Context, oldData *Pool, diff *PoolParam) error
@@ -86,17 +147,45 @@ type PoolManager struct {
 	bfeClusterStorager ibasic.BFEClusterStorager
 	subClusterStorager SubClusterStorager
 	txn                itxn.TxnStorager
+
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
 }
 
 func NewPoolManager(txn itxn.TxnStorager, storager PoolStorager,
-	bfeClusterStorager ibasic.BFEClusterStorager, subClusterStorager SubClusterStorager) *PoolManager {
+	bfeClusterStorager ibasic.BFEClusterStorager, subClusterStorager SubClusterStorager,
+	versionControlManager *iversion_control.VersionControlManager, auditManager *iaudit.AuditManager) *PoolManager {
 
 	return &PoolManager{
 		txn:                txn,
 		storager:           storager,
 		bfeClusterStorager: bfeClusterStorager,
 		subClusterStorager: subClusterStorager,
+
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+func (rppm *PoolManager) recordAudit(ctx context.Context, product *ibasic.Product, action string,
+	before, after *Pool) error {
+
+	param := &iaudit.AuditParam{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		ResourceType: iaudit.ResourcePool,
+		Action:       action,
+	}
+	if before != nil {
+		param.ResourceName = before.Name
+		param.Before = before
 	}
+	if after != nil {
+		param.ResourceName = after.Name
+		param.After = after
+	}
+
+	return rppm.auditManager.Record(ctx, param)
 }
 
 func (rppm *PoolManager) FetchPoolByName(ctx context.Context, name string) (one *Pool, err error) {
@@ -141,6 +230,27 @@ func (rppm *PoolManager) FetchProductPools(ctx context.Context, product *ibasic.
 	return
 }
 
+// FetchProductPoolPage return pools of product in page of pagination, and number of all pools match pagination
+func (rppm *PoolManager) FetchProductPoolPage(ctx context.Context, product *ibasic.Product,
+	pagination *lib.Pagination) (list []*Pool, total int64, err error) {
+
+	filter := &PoolFilter{
+		ProductID:  &product.ID,
+		Pagination: pagination,
+	}
+	err = rppm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		total, err = rppm.storager.CountPools(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		list, err = rppm.storager.FetchPools(ctx, filter)
+		return err
+	})
+
+	return
+}
+
 func poolNameJudger(productName string, poolName string) (realName string, err error) {
 	ss := strings.SplitN(poolName, ".", 2)
 	if len(ss) == 2 {
@@ -181,18 +291,20 @@ func (rppm *PoolManager) CanDelete(ctx context.Context, pool *Pool) error {
 	return nil
 }
 
-func (rppm *PoolManager) DeleteBFEPool(ctx context.Context, name string) (one *Pool, err error) {
-	return rppm.DeleteProductPool(ctx, ibasic.BuildinProduct, name)
+func (rppm *PoolManager) DeleteBFEPool(ctx context.Context, name, revision string) (one *Pool, err error) {
+	return rppm.DeleteProductPool(ctx, ibasic.BuildinProduct, name, revision)
 }
 
-func (rppm *PoolManager) DeleteProductPool(ctx context.Context, product *ibasic.Product, name string) (one *Pool, err error) {
+// DeleteProductPool delete pool, if revision is not empty, it must be equal to revision of pool in storage
+func (rppm *PoolManager) DeleteProductPool(ctx context.Context, product *ibasic.Product, name,
+	revision string) (one *Pool, err error) {
 	name, err = poolNameJudger(product.Name, name)
 	if err != nil {
 		return
 	}
 
 	err = rppm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		one, err = rppm.storager.FetchPool(ctx, name)
+		one, err = rppm.storager.LockPool(ctx, name)
 		if err != nil {
 			return err
 		}
@@ -200,13 +312,23 @@ func (rppm *PoolManager) DeleteProductPool(ctx context.Context, product *ibasic.
 		if one == nil {
 			return xerror.WrapRecordNotExist("Pool")
 		}
+		if err = one.checkRevision(revision); err != nil {
+			return err
+		}
 
 		if err = rppm.CanDelete(ctx, one); err != nil {
 			return err
 		}
 
-		return rppm.storager.DeletePool(ctx, one)
+		if err = rppm.storager.DeletePool(ctx, one); err != nil {
+			return err
+		}
+
+		return rppm.recordAudit(ctx, product, iaudit.ActionDelete, one, nil)
 	})
+	if err == nil {
+		rppm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
@@ -236,21 +358,53 @@ func (rppm *PoolManager) CreateProductPool(ctx context.Context, product *ibasic.
 			return xerror.WrapRecordExisted()
 		}
 
-		one, err = rppm.storager.CreatePool(ctx, product, pool)
-		return err
+		if one, err = rppm.storager.CreatePool(ctx, product, pool); err != nil {
+			return err
+		}
+
+		return rppm.recordAudit(ctx, product, iaudit.ActionCreate, nil, one)
 	})
+	if err == nil {
+		rppm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
 
-func (rppm *PoolManager) UpdateBFEPool(ctx context.Context, pool *Pool, diff *PoolParam) (err error) {
-	return rppm.UpdateProductPool(ctx, ibasic.BuildinProduct, pool, diff)
+func (rppm *PoolManager) UpdateBFEPool(ctx context.Context, pool *Pool, diff *PoolParam, revision string) (err error) {
+	return rppm.UpdateProductPool(ctx, ibasic.BuildinProduct, pool, diff, revision)
 }
 
-func (rppm *PoolManager) UpdateProductPool(ctx context.Context, product *ibasic.Product, pool *Pool, diff *PoolParam) (err error) {
+// UpdateProductPool update pool, if revision is not empty, it must be equal to revision of pool in storage
+func (rppm *PoolManager) UpdateProductPool(ctx context.Context, product *ibasic.Product, pool *Pool, diff *PoolParam,
+	revision string) (err error) {
+
 	err = rppm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		return rppm.storager.UpdatePool(ctx, pool, diff)
+		pool, err := rppm.storager.LockPool(ctx, pool.Name)
+		if err != nil {
+			return err
+		}
+		if pool == nil {
+			return xerror.WrapRecordNotExist("Pool")
+		}
+		if err = pool.checkRevision(revision); err != nil {
+			return err
+		}
+
+		if err := rppm.storager.UpdatePool(ctx, pool, diff); err != nil {
+			return err
+		}
+
+		after, err := rppm.storager.FetchPool(ctx, pool.Name)
+		if err != nil {
+			return err
+		}
+
+		return rppm.recordAudit(ctx, product, iaudit.ActionUpdate, pool, after)
 	})
+	if err == nil {
+		rppm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
@@ -276,3 +430,121 @@ func (rppm *PoolManager) GetPoolByName(ctx context.Context, poolName *string) (p
 
 	return
 }
+
+// InstancePatchParam is changes of instances in pool, nil field means no change
+type InstancePatchParam struct {
+	Weight  *int64
+	Disable *bool
+}
+
+// patchPoolInstances lock pool and replace instances of pool with instances returned by patch
+func (rppm *PoolManager) patchPoolInstances(ctx context.Context, product *ibasic.Product, name, revision string,
+	patch func([]Instance) ([]Instance, error)) (after *Pool, err error) {
+
+	name, err = poolNameJudger(product.Name, name)
+	if err != nil {
+		return
+	}
+
+	err = rppm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		pool, err := rppm.storager.LockPool(ctx, name)
+		if err != nil {
+			return err
+		}
+		if pool == nil {
+			return xerror.WrapRecordNotExist("Pool")
+		}
+		if err = pool.checkRevision(revision); err != nil {
+			return err
+		}
+
+		instances, err := patch(append([]Instance{}, pool.Instances...))
+		if err != nil {
+			return err
+		}
+
+		if err = rppm.storager.UpdatePool(ctx, pool, &PoolParam{
+			Instances: instances,
+		}); err != nil {
+			return err
+		}
+
+		after, err = rppm.storager.FetchPool(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		return rppm.recordAudit(ctx, product, iaudit.ActionUpdate, pool, after)
+	})
+	if err == nil {
+		rppm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
+
+	return
+}
+
+// AddPoolInstance append instance to pool, hostname and ip:port of instance must be unique in pool
+func (rppm *PoolManager) AddPoolInstance(ctx context.Context, product *ibasic.Product, name string,
+	instance Instance, revision string) (*Pool, error) {
+
+	return rppm.patchPoolInstances(ctx, product, name, revision, func(instances []Instance) ([]Instance, error) {
+		port, _ := instance.NamedPort("")
+		for _, one := range instances {
+			if one.HostName == instance.HostName || one.Match(fmt.Sprintf("%s:%d", instance.IP, port)) {
+				return nil, xerror.WrapRecordExisted("Instance")
+			}
+		}
+
+		return append(instances, instance), nil
+	})
+}
+
+// RemovePoolInstance remove instances matching key from pool, key can be hostname, ip or ip:port
+func (rppm *PoolManager) RemovePoolInstance(ctx context.Context, product *ibasic.Product, name, key string,
+	revision string) (*Pool, error) {
+
+	return rppm.patchPoolInstances(ctx, product, name, revision, func(instances []Instance) ([]Instance, error) {
+		rst := []Instance{}
+		for _, one := range instances {
+			if !one.Match(key) {
+				rst = append(rst, one)
+			}
+		}
+		if len(rst) == len(instances) {
+			return nil, xerror.WrapRecordNotExist("Instance")
+		}
+		// pool is created with at least one instance, keep it so
+		if len(rst) == 0 {
+			return nil, xerror.WrapParamErrorWithMsg("Last Instance Of Pool Cant Be Removed")
+		}
+
+		return rst, nil
+	})
+}
+
+// UpdatePoolInstance reweight, enable or disable instances matching key in pool, key can be hostname, ip or ip:port
+func (rppm *PoolManager) UpdatePoolInstance(ctx context.Context, product *ibasic.Product, name, key string,
+	param *InstancePatchParam, revision string) (*Pool, error) {
+
+	return rppm.patchPoolInstances(ctx, product, name, revision, func(instances []Instance) ([]Instance, error) {
+		matched := false
+		for i := range instances {
+			if !instances[i].Match(key) {
+				continue
+			}
+
+			matched = true
+			if param.Weight != nil {
+				instances[i].Weight = *param.Weight
+			}
+			if param.Disable != nil {
+				instances[i].Disable = *param.Disable
+			}
+		}
+		if !matched {
+			return nil, xerror.WrapRecordNotExist("Instance")
+		}
+
+		return instances, nil
+	})
+}
@@ -17,9 +17,12 @@ package icluster_conf
 import (
 	"context"
 
+	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
 )
 
 type SubCluster struct {
@@ -33,6 +36,11 @@ type SubCluster struct {
 
 	InstancePool *Pool
 
+	// PortName choose port of instance from Instance.Ports, empty means default port
+	PortName string
+	// InstanceSelector choose instances of pool whose tags contain all key-values of it
+	InstanceSelector map[string]string
+
 	Capacity    int64
 	Enabled     bool
 	Ready       bool
@@ -50,6 +58,8 @@ type SubClusterFilter struct {
 	Product *ibasic.Product
 
 	ClusterIDs []int64
+
+	Pagination *lib.Pagination
 }
 
 type SubClusterParam struct {
@@ -67,10 +77,14 @@ type SubClusterParam struct {
 	ClusterIDs  []int64
 
 	Description *string
+
+	PortName         *string
+	InstanceSelector map[string]string
 }
 
 type SubClusterStorager interface {
 	FetchSubClusterList(ctx context.Context, param *SubClusterFilter) ([]*SubCluster, error)
+	CountSubClusters(ctx context.Context, param *SubClusterFilter) (int64, error)
 	CreateSubCluster(ctx context.Context, param *SubClusterParam) error
 	DeleteSubCluster(ctx context.Context, param *SubCluster) error
 	UpdateSubCluster(ctx context.Context, one *SubCluster, param *SubClusterParam) error
@@ -83,20 +97,92 @@ type SubClusterManager struct {
 	productStorager ibasic.ProductStorager
 	poolStorager    PoolStorager
 	clusterStorager ClusterStorager
+
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
 }
 
 func NewSubClusterManager(txn itxn.TxnStorager, storager SubClusterStorager,
 	productStorager ibasic.ProductStorager, poolStorager PoolStorager,
-	clusterStorager ClusterStorager) *SubClusterManager {
+	clusterStorager ClusterStorager, versionControlManager *iversion_control.VersionControlManager,
+	auditManager *iaudit.AuditManager) *SubClusterManager {
 	return &SubClusterManager{
 		txn:             txn,
 		storager:        storager,
 		productStorager: productStorager,
 		poolStorager:    poolStorager,
 		clusterStorager: clusterStorager,
+
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
 	}
 }
 
+// recordAudit save audit of sub cluster, after data is fetched from storager if sub cluster not be deleted
+func (scm *SubClusterManager) recordAudit(ctx context.Context, productID int64, productName string,
+	action string, name string, before *SubCluster) error {
+
+	param := &iaudit.AuditParam{
+		ProductID:    productID,
+		ProductName:  productName,
+		ResourceType: iaudit.ResourceSubCluster,
+		ResourceName: name,
+		Action:       action,
+	}
+	if before != nil {
+		param.Before = before
+	}
+
+	if action != iaudit.ActionDelete {
+		after, err := scm.FetchSubCluster(ctx, &SubClusterFilter{
+			Name: &name,
+			Product: &ibasic.Product{
+				ID: productID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		param.After = after
+	}
+
+	return scm.auditManager.Record(ctx, param)
+}
+
+// Backends return instances of pool selected by InstanceSelector, port of instance is chosen by PortName.
+// Instances without port named PortName are skipped, disabled instances are skipped
+// or be returned with weight 0 if keepDisabled is true.
+// Sub cluster without backend whose weight > 0 is excluded from cluster_table and gslb exported
+func (sc *SubCluster) Backends(keepDisabled bool) []Instance {
+	if sc.InstancePool == nil {
+		return nil
+	}
+
+	var rst []Instance
+	for _, instance := range sc.InstancePool.Instances {
+		if !instance.MatchSelector(sc.InstanceSelector) {
+			continue
+		}
+
+		port, ok := instance.NamedPort(sc.PortName)
+		if !ok {
+			continue
+		}
+		instance.Port = port
+
+		if instance.Disable {
+			if !keepDisabled {
+				continue
+			}
+			instance.Weight = 0
+		}
+
+		rst = append(rst, instance)
+	}
+
+	return rst
+}
+
 func SubClusterList2MapByName(list []*SubCluster) map[string]*SubCluster {
 	m := map[string]*SubCluster{}
 	for _, one := range list {
@@ -155,6 +241,21 @@ func (scm *SubClusterManager) SubClusterList(ctx context.Context, param *SubClus
 	return
 }
 
+// SubClusterPage return sub clusters in page of param.Pagination, and number of all sub clusters match param
+func (scm *SubClusterManager) SubClusterPage(ctx context.Context, param *SubClusterFilter) (list []*SubCluster, total int64, err error) {
+	err = scm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		total, err = scm.storager.CountSubClusters(ctx, param)
+		if err != nil {
+			return err
+		}
+
+		list, err = scm.storager.FetchSubClusterList(ctx, param)
+		return err
+	})
+
+	return
+}
+
 func (scm *SubClusterManager) FetchSubCluster(ctx context.Context, param *SubClusterFilter) (subCluster *SubCluster, err error) {
 	list, err := scm.storager.FetchSubClusterList(ctx, param)
 	if err != nil {
@@ -198,8 +299,15 @@ func (scm *SubClusterManager) CreateSubCluster(ctx context.Context, product *iba
 			Name: "unbinding",
 		}
 		param.Product = product
-		return scm.storager.CreateSubCluster(ctx, param)
+		if err = scm.storager.CreateSubCluster(ctx, param); err != nil {
+			return err
+		}
+
+		return scm.recordAudit(ctx, product.ID, product.Name, iaudit.ActionCreate, *param.Name, nil)
 	})
+	if err == nil {
+		scm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
@@ -209,16 +317,32 @@ func (scm *SubClusterManager) DeleteSubCluster(ctx context.Context, subCluster *
 		return xerror.WrapModelErrorWithMsg("SubCluster %s be Mounted With Cluster %d", subCluster.Name, subCluster.ClusterID)
 	}
 	err = scm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		return scm.storager.DeleteSubCluster(ctx, subCluster)
+		if err := scm.storager.DeleteSubCluster(ctx, subCluster); err != nil {
+			return err
+		}
+
+		return scm.recordAudit(ctx, subCluster.ProductID, subCluster.ProductName, iaudit.ActionDelete,
+			subCluster.Name, subCluster)
 	})
+	if err == nil {
+		scm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
 
 func (scm *SubClusterManager) UpdateSubCluster(ctx context.Context, subCluster *SubCluster, param *SubClusterParam) (err error) {
 	err = scm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		return scm.storager.UpdateSubCluster(ctx, subCluster, param)
+		if err := scm.storager.UpdateSubCluster(ctx, subCluster, param); err != nil {
+			return err
+		}
+
+		return scm.recordAudit(ctx, subCluster.ProductID, subCluster.ProductName, iaudit.ActionUpdate,
+			subCluster.Name, subCluster)
 	})
+	if err == nil {
+		scm.versionControlManager.NotifyChange(ctx, clusterChangedTopics...)
+	}
 
 	return
 }
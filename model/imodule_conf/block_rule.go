@@ -0,0 +1,247 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imodule_conf
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/bfe/bfe_basic/condition"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// ConfigTopicBlockRule topic of block_rules.data of BFE mod_block
+const ConfigTopicBlockRule = "block_rule"
+
+// actions supported by mod_block, both of them want no param
+const (
+	BlockActionClose = "CLOSE" // close the connection
+	BlockActionAllow = "ALLOW" // allow the request, the following rules are skipped
+)
+
+// BlockRule block request of product when Cond is matched,
+// rules of product are checked in order of Index, the first matched rule takes effect
+type BlockRule struct {
+	ProductID   int64
+	Index       int
+	Name        string
+	Description string
+
+	Cond   string
+	Action string
+}
+
+type BlockRuleFilter struct {
+	Product *ibasic.Product
+}
+
+type BlockRuleStorager interface {
+	// FetchBlockRules return rules sorted by product and index
+	FetchBlockRules(ctx context.Context, filter *BlockRuleFilter) ([]*BlockRule, error)
+	// UpsertProductBlockRules replace all block rules of product with rules
+	UpsertProductBlockRules(ctx context.Context, product *ibasic.Product, rules []*BlockRule) error
+}
+
+func (r *BlockRule) check() error {
+	if _, err := condition.Build(r.Cond); err != nil {
+		return xerror.WrapParamErrorWithMsg("Rule %s Cond %s Invalid: %v", r.Name, r.Cond, err)
+	}
+
+	if r.Action != BlockActionClose && r.Action != BlockActionAllow {
+		return xerror.WrapParamErrorWithMsg("Rule %s Action %s Not Support", r.Name, r.Action)
+	}
+
+	return nil
+}
+
+// BlockRuleConf is rule of block_rules.data
+type BlockRuleConf struct {
+	Cond   string
+	Name   string
+	Action *iroute_conf.ModuleAction
+}
+
+// BlockRuleConfData is the same as block_rules.data of BFE mod_block
+type BlockRuleConfData struct {
+	Version string
+	Config  map[string][]*BlockRuleConf // product name => rules
+}
+
+func (bc *BlockRuleConfData) UpdateVersion(version string) error {
+	bc.Version = version
+
+	return nil
+}
+
+func (r *BlockRule) toConf() *BlockRuleConf {
+	return &BlockRuleConf{
+		Cond: r.Cond,
+		Name: r.Name,
+		Action: &iroute_conf.ModuleAction{
+			Cmd:    r.Action,
+			Params: []string{},
+		},
+	}
+}
+
+type BlockRuleManager struct {
+	txn itxn.TxnStorager
+
+	storager        BlockRuleStorager
+	productStorager ibasic.ProductStorager
+
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
+}
+
+func NewBlockRuleManager(txn itxn.TxnStorager, storager BlockRuleStorager, productStorager ibasic.ProductStorager,
+	versionControlManager *iversion_control.VersionControlManager, auditManager *iaudit.AuditManager) *BlockRuleManager {
+
+	return &BlockRuleManager{
+		txn:                   txn,
+		storager:              storager,
+		productStorager:       productStorager,
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+func (m *BlockRuleManager) FetchProductBlockRules(ctx context.Context, product *ibasic.Product) (list []*BlockRule, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = m.storager.FetchBlockRules(ctx, &BlockRuleFilter{
+			Product: product,
+		})
+		return err
+	})
+
+	return
+}
+
+// UpsertProductBlockRules replace all block rules of product, rules are checked in the given order
+func (m *BlockRuleManager) UpsertProductBlockRules(ctx context.Context, product *ibasic.Product,
+	rules []*BlockRule) (list []*BlockRule, err error) {
+
+	names := make([]string, len(rules))
+	for i, one := range rules {
+		names[i] = one.Name
+	}
+	if err = checkRuleNames(names); err != nil {
+		return nil, err
+	}
+	for _, one := range rules {
+		if err = one.check(); err != nil {
+			return nil, err
+		}
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		filter := &BlockRuleFilter{
+			Product: product,
+		}
+		old, err := m.storager.FetchBlockRules(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		if err = m.storager.UpsertProductBlockRules(ctx, product, rules); err != nil {
+			return err
+		}
+
+		if list, err = m.storager.FetchBlockRules(ctx, filter); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceBlockRule,
+			ResourceName: product.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       old,
+			After:        list,
+		})
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicBlockRule)
+	}
+
+	return
+}
+
+func (m *BlockRuleManager) blockRuleConfGenerator(ctx context.Context) (*iversion_control.ExportData, error) {
+	rules, err := m.storager.FetchBlockRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := m.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	productMap := ibasic.ProductIDMap(products)
+
+	conf := &BlockRuleConfData{
+		Version: iversion_control.ZeroVersion,
+		Config:  map[string][]*BlockRuleConf{},
+	}
+	for _, rule := range rules {
+		product, ok := productMap[rule.ProductID]
+		if !ok {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Block Rule refer Not Exist Product %d", rule.ProductID)
+		}
+		if err := rule.check(); err != nil {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Product %s Block Rule %s: %v", product.Name, rule.Name, err)
+		}
+
+		conf.Config[product.Name] = append(conf.Config[product.Name], rule.toConf())
+	}
+
+	return &iversion_control.ExportData{
+		Topic:              ConfigTopicBlockRule,
+		DataWithoutVersion: conf,
+	}, nil
+}
+
+// ExportBlockRule return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (m *BlockRuleManager) ExportBlockRule(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *BlockRuleConfData, err error) {
+
+	err = m.versionControlManager.WaitChange(ctx, ConfigTopicBlockRule, wait, func(ctx context.Context) (bool, error) {
+		ed, err := m.versionControlManager.ExportConfig(ctx, ConfigTopicBlockRule, m.blockRuleConfGenerator,
+			func() iversion_control.VersionValuable { return &BlockRuleConfData{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*BlockRuleConfData)
+		return conf.Version != lastVersion, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Version == lastVersion {
+		return nil, nil
+	}
+
+	return conf, nil
+}
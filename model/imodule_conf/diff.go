@@ -0,0 +1,98 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imodule_conf
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+var (
+	_ iversion_control.Diffable = &RewriteConf{}
+	_ iversion_control.Diffable = &BlockRuleConfData{}
+	_ iversion_control.Diffable = &IPBlocklistConf{}
+)
+
+// Diff rewrite rules of products
+func (rc *RewriteConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*RewriteConf)
+
+	toMap := func(conf *RewriteConf) map[string]interface{} {
+		m := map[string]interface{}{}
+		for product, rules := range conf.Config {
+			m[product] = rules
+		}
+		return m
+	}
+
+	return iversion_control.DiffMap("rewrite_rule", toMap(o), toMap(rc))
+}
+
+// Diff block rules of products
+func (bc *BlockRuleConfData) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*BlockRuleConfData)
+
+	toMap := func(conf *BlockRuleConfData) map[string]interface{} {
+		m := map[string]interface{}{}
+		for product, rules := range conf.Config {
+			m[product] = rules
+		}
+		return m
+	}
+
+	return iversion_control.DiffMap("block_rule", toMap(o), toMap(bc))
+}
+
+// Diff items of ip blocklist, an item is added or removed only
+func (ic *IPBlocklistConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*IPBlocklistConf)
+
+	toMap := func(conf *IPBlocklistConf) map[string]interface{} {
+		m := map[string]interface{}{}
+		for _, one := range conf.Items {
+			m[one] = one
+		}
+		return m
+	}
+
+	return iversion_control.DiffMap("ip_blocklist", toMap(o), toMap(ic))
+}
+
+func (m *HeaderRuleManager) DiffHeader(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return m.versionControlManager.DiffConfig(ctx, ConfigTopicHeader, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &iroute_conf.HeaderConf{} })
+}
+
+func (m *RewriteRuleManager) DiffRewrite(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return m.versionControlManager.DiffConfig(ctx, ConfigTopicRewrite, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &RewriteConf{} })
+}
+
+func (m *RedirectRuleManager) DiffRedirect(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return m.versionControlManager.DiffConfig(ctx, ConfigTopicRedirect, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &iroute_conf.RedirectConf{} })
+}
+
+func (m *BlockRuleManager) DiffBlockRule(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return m.versionControlManager.DiffConfig(ctx, ConfigTopicBlockRule, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &BlockRuleConfData{} })
+}
+
+func (m *IPBlocklistManager) DiffIPBlocklist(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return m.versionControlManager.DiffConfig(ctx, ConfigTopicIPBlocklist, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &IPBlocklistConf{} })
+}
@@ -0,0 +1,378 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imodule_conf
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/bfe/bfe_basic/condition"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// ConfigTopicHeader same as iroute_conf.ConfigTopicHeader, HSTS rules of domains are exported with header rules
+const ConfigTopicHeader = iroute_conf.ConfigTopicHeader
+
+// headerActionParamNum is number of params wanted by each cmd of mod_header
+var headerActionParamNum = map[string]int{
+	"REQ_HEADER_SET":    2, // key, value
+	"REQ_HEADER_ADD":    2, // key, value
+	"REQ_HEADER_DEL":    1, // key
+	"REQ_HEADER_RENAME": 2, // old key, new key
+	"RSP_HEADER_SET":    2,
+	"RSP_HEADER_ADD":    2,
+	"RSP_HEADER_DEL":    1,
+	"RSP_HEADER_RENAME": 2,
+}
+
+type HeaderAction struct {
+	Cmd    string
+	Params []string
+}
+
+// HeaderRule modify request or response header when Cond is matched,
+// rules of product are executed in order of ID, the following rules are skipped if Last is true
+type HeaderRule struct {
+	ID          int64
+	ProductID   int64
+	Name        string
+	Description string
+
+	Cond    string
+	Actions []*HeaderAction
+	Last    bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type HeaderRuleParam struct {
+	Name        *string
+	Description *string
+
+	Cond    *string
+	Actions []*HeaderAction
+	Last    *bool
+}
+
+type HeaderRuleFilter struct {
+	Product *ibasic.Product
+	ID      *int64
+	Name    *string
+}
+
+type HeaderRuleStorager interface {
+	FetchHeaderRules(ctx context.Context, filter *HeaderRuleFilter) ([]*HeaderRule, error)
+	CreateHeaderRule(ctx context.Context, product *ibasic.Product, param *HeaderRuleParam) (int64, error)
+	UpdateHeaderRule(ctx context.Context, product *ibasic.Product, old *HeaderRule, param *HeaderRuleParam) error
+	DeleteHeaderRule(ctx context.Context, product *ibasic.Product, rule *HeaderRule) error
+}
+
+// merge return a copy of rule updated by param
+func (r *HeaderRule) merge(param *HeaderRuleParam) *HeaderRule {
+	tmp := *r
+	if param.Name != nil {
+		tmp.Name = *param.Name
+	}
+	if param.Description != nil {
+		tmp.Description = *param.Description
+	}
+	if param.Cond != nil {
+		tmp.Cond = *param.Cond
+	}
+	if param.Actions != nil {
+		tmp.Actions = param.Actions
+	}
+	if param.Last != nil {
+		tmp.Last = *param.Last
+	}
+
+	return &tmp
+}
+
+func (r *HeaderRule) check() error {
+	if _, err := condition.Build(r.Cond); err != nil {
+		return xerror.WrapParamErrorWithMsg("Cond %s Invalid: %v", r.Cond, err)
+	}
+
+	if len(r.Actions) == 0 {
+		return xerror.WrapParamErrorWithMsg("Actions Empty")
+	}
+	for i, action := range r.Actions {
+		num, ok := headerActionParamNum[action.Cmd]
+		if !ok {
+			return xerror.WrapParamErrorWithMsg("Action %d Cmd %s Not Support", i, action.Cmd)
+		}
+		if len(action.Params) != num {
+			return xerror.WrapParamErrorWithMsg("Action %d Cmd %s Want %d Params, But Got %d", i, action.Cmd, num, len(action.Params))
+		}
+		for _, p := range action.Params {
+			if p == "" {
+				return xerror.WrapParamErrorWithMsg("Action %d Cmd %s Has Empty Param", i, action.Cmd)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *HeaderRule) toConf() *iroute_conf.HeaderRule {
+	actions := make([]*iroute_conf.ModuleAction, len(r.Actions))
+	for i, one := range r.Actions {
+		actions[i] = &iroute_conf.ModuleAction{
+			Cmd:    one.Cmd,
+			Params: append([]string{}, one.Params...),
+		}
+	}
+
+	return &iroute_conf.HeaderRule{
+		Cond:    r.Cond,
+		Actions: actions,
+		Last:    r.Last,
+	}
+}
+
+type HeaderRuleManager struct {
+	txn itxn.TxnStorager
+
+	storager        HeaderRuleStorager
+	productStorager ibasic.ProductStorager
+
+	domainManager         *iroute_conf.DomainManager
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
+}
+
+func NewHeaderRuleManager(txn itxn.TxnStorager, storager HeaderRuleStorager, productStorager ibasic.ProductStorager,
+	domainManager *iroute_conf.DomainManager, versionControlManager *iversion_control.VersionControlManager,
+	auditManager *iaudit.AuditManager) *HeaderRuleManager {
+
+	return &HeaderRuleManager{
+		txn:                   txn,
+		storager:              storager,
+		productStorager:       productStorager,
+		domainManager:         domainManager,
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+func (m *HeaderRuleManager) FetchHeaderRules(ctx context.Context, filter *HeaderRuleFilter) (list []*HeaderRule, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = m.storager.FetchHeaderRules(ctx, filter)
+		return err
+	})
+
+	return
+}
+
+// fetchHeaderRule return nil if rule not exist
+func (m *HeaderRuleManager) fetchHeaderRule(ctx context.Context, product *ibasic.Product, filter *HeaderRuleFilter) (*HeaderRule, error) {
+	filter.Product = product
+	list, err := m.storager.FetchHeaderRules(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return list[0], nil
+}
+
+// checkNameUnique make sure no other rule of product named name
+func (m *HeaderRuleManager) checkNameUnique(ctx context.Context, product *ibasic.Product, name string, id int64) error {
+	one, err := m.fetchHeaderRule(ctx, product, &HeaderRuleFilter{
+		Name: &name,
+	})
+	if err != nil {
+		return err
+	}
+	if one != nil && one.ID != id {
+		return xerror.WrapRecordExisted("Header Rule")
+	}
+
+	return nil
+}
+
+func (m *HeaderRuleManager) recordAudit(ctx context.Context, product *ibasic.Product, action string,
+	before, after *HeaderRule) error {
+
+	param := &iaudit.AuditParam{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		ResourceType: iaudit.ResourceHeaderRule,
+		Action:       action,
+	}
+	if before != nil {
+		param.ResourceName = before.Name
+		param.Before = before
+	}
+	if after != nil {
+		param.ResourceName = after.Name
+		param.After = after
+	}
+
+	return m.auditManager.Record(ctx, param)
+}
+
+func (m *HeaderRuleManager) CreateHeaderRule(ctx context.Context, product *ibasic.Product,
+	param *HeaderRuleParam) (rule *HeaderRule, err error) {
+
+	tmp := (&HeaderRule{}).merge(param)
+	if err = tmp.check(); err != nil {
+		return nil, err
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := m.checkNameUnique(ctx, product, tmp.Name, 0); err != nil {
+			return err
+		}
+
+		id, err := m.storager.CreateHeaderRule(ctx, product, param)
+		if err != nil {
+			return err
+		}
+
+		if rule, err = m.fetchHeaderRule(ctx, product, &HeaderRuleFilter{ID: &id}); err != nil {
+			return err
+		}
+
+		return m.recordAudit(ctx, product, iaudit.ActionCreate, nil, rule)
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicHeader)
+	}
+
+	return
+}
+
+func (m *HeaderRuleManager) UpdateHeaderRule(ctx context.Context, product *ibasic.Product, old *HeaderRule,
+	param *HeaderRuleParam) (rule *HeaderRule, err error) {
+
+	tmp := old.merge(param)
+	if err = tmp.check(); err != nil {
+		return nil, err
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := m.checkNameUnique(ctx, product, tmp.Name, old.ID); err != nil {
+			return err
+		}
+
+		if err := m.storager.UpdateHeaderRule(ctx, product, old, param); err != nil {
+			return err
+		}
+
+		if rule, err = m.fetchHeaderRule(ctx, product, &HeaderRuleFilter{ID: &old.ID}); err != nil {
+			return err
+		}
+
+		return m.recordAudit(ctx, product, iaudit.ActionUpdate, old, rule)
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicHeader)
+	}
+
+	return
+}
+
+func (m *HeaderRuleManager) DeleteHeaderRule(ctx context.Context, product *ibasic.Product, rule *HeaderRule) (err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := m.storager.DeleteHeaderRule(ctx, product, rule); err != nil {
+			return err
+		}
+
+		return m.recordAudit(ctx, product, iaudit.ActionDelete, rule, nil)
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicHeader)
+	}
+
+	return
+}
+
+// headerConfGenerator export HSTS rules of domains and header rules of products,
+// HSTS rules are placed before header rules of the same product
+func (m *HeaderRuleManager) headerConfGenerator(ctx context.Context) (*iversion_control.ExportData, error) {
+	hstsRules, err := m.domainManager.HSTSHeaderRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := m.storager.FetchHeaderRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := m.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	productMap := ibasic.ProductIDMap(products)
+
+	conf := &iroute_conf.HeaderConf{
+		Version: iversion_control.ZeroVersion,
+		Config:  hstsRules,
+	}
+	for _, rule := range rules {
+		product, ok := productMap[rule.ProductID]
+		if !ok {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Header Rule refer Not Exist Product %d", rule.ProductID)
+		}
+		if err := rule.check(); err != nil {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Product %s Header Rule %s: %v", product.Name, rule.Name, err)
+		}
+
+		conf.Config[product.Name] = append(conf.Config[product.Name], rule.toConf())
+	}
+
+	return &iversion_control.ExportData{
+		Topic:              ConfigTopicHeader,
+		DataWithoutVersion: conf,
+	}, nil
+}
+
+// ExportHeader return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (m *HeaderRuleManager) ExportHeader(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *iroute_conf.HeaderConf, err error) {
+
+	err = m.versionControlManager.WaitChange(ctx, ConfigTopicHeader, wait, func(ctx context.Context) (bool, error) {
+		ed, err := m.versionControlManager.ExportConfig(ctx, ConfigTopicHeader, m.headerConfGenerator,
+			func() iversion_control.VersionValuable { return &iroute_conf.HeaderConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*iroute_conf.HeaderConf)
+		return conf.Version != lastVersion, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Version == lastVersion {
+		return nil, nil
+	}
+
+	return conf, nil
+}
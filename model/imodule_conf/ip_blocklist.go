@@ -0,0 +1,413 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imodule_conf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// ConfigTopicIPBlocklist topic of ip_blocklist.data of BFE mod_block
+const ConfigTopicIPBlocklist = "ip_blocklist"
+
+// ip blocklist of product is saved as extra file named mod_block/{product}/ip_blocklist.data
+const (
+	ipBlocklistModuleDir = "mod_block"
+	ipBlocklistFileName  = "ip_blocklist.data"
+)
+
+// IPBlocklistEntry single ip or cidr to be blocked, entry is dropped after ExpireTime
+type IPBlocklistEntry struct {
+	IP         string
+	ExpireTime *time.Time
+}
+
+// normalizeIP return canonical form of single ip or cidr, cidr of single ip is converted to ip
+func normalizeIP(ip string) (string, bool) {
+	if strings.Contains(ip, "/") {
+		_, ipNet, err := net.ParseCIDR(ip)
+		if err != nil {
+			return "", false
+		}
+		if ones, bits := ipNet.Mask.Size(); ones == bits {
+			return ipNet.IP.String(), true
+		}
+		return ipNet.String(), true
+	}
+
+	one := net.ParseIP(ip)
+	if one == nil {
+		return "", false
+	}
+	return one.String(), true
+}
+
+func (e *IPBlocklistEntry) expired(now time.Time) bool {
+	return e.ExpireTime != nil && !e.ExpireTime.After(now)
+}
+
+// ipRange return line of ip_blocklist.data, single ip or "start_ip end_ip"
+func (e *IPBlocklistEntry) ipRange() string {
+	_, ipNet, err := net.ParseCIDR(e.IP)
+	if err != nil {
+		return e.IP
+	}
+
+	start := ipNet.IP
+	end := make(net.IP, len(start))
+	for i := range start {
+		end[i] = start[i] | ^ipNet.Mask[i]
+	}
+	if bytes.Equal(start, end) {
+		return start.String()
+	}
+
+	return start.String() + " " + end.String()
+}
+
+// ParseIPBlocklist parse ip blocklist in text, each line is "ip_or_cidr [expire_time]",
+// expire_time is in RFC3339 format, defaultExpireTime is used if it is omitted.
+// Empty lines and lines start with # are ignored.
+func ParseIPBlocklist(content []byte, defaultExpireTime *time.Time) ([]*IPBlocklistEntry, error) {
+	list := []*IPBlocklistEntry{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for no := 1; scanner.Scan(); no++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, xerror.WrapParamErrorWithMsg("Line %d Illegal, Want \"ip_or_cidr [expire_time]\"", no)
+		}
+
+		ip, ok := normalizeIP(fields[0])
+		if !ok {
+			return nil, xerror.WrapParamErrorWithMsg("Line %d IP %s Illegal", no, fields[0])
+		}
+
+		entry := &IPBlocklistEntry{
+			IP:         ip,
+			ExpireTime: defaultExpireTime,
+		}
+		if len(fields) == 2 {
+			expireTime, err := time.Parse(time.RFC3339, fields[1])
+			if err != nil {
+				return nil, xerror.WrapParamErrorWithMsg("Line %d Expire Time %s Illegal, Want RFC3339", no, fields[1])
+			}
+			entry.ExpireTime = &expireTime
+		}
+
+		list = append(list, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerror.WrapParamErrorWithMsg("Read IP Blocklist: %v", err)
+	}
+
+	return list, nil
+}
+
+// formatIPBlocklist is the reverse of ParseIPBlocklist
+func formatIPBlocklist(list []*IPBlocklistEntry) []byte {
+	buf := &bytes.Buffer{}
+	for _, one := range list {
+		buf.WriteString(one.IP)
+		if one.ExpireTime != nil {
+			buf.WriteString(" ")
+			buf.WriteString(one.ExpireTime.Format(time.RFC3339))
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+func ipBlocklistFilePath(product *ibasic.Product) string {
+	return ibasic.ExtraFilePath(ipBlocklistModuleDir, product, ipBlocklistFileName)
+}
+
+// IPBlocklistConf is the same as ip_blocklist.data of BFE mod_block
+type IPBlocklistConf struct {
+	Version string
+	Items   []string // single ip or "start_ip end_ip"
+}
+
+func (ic *IPBlocklistConf) UpdateVersion(version string) error {
+	ic.Version = version
+
+	return nil
+}
+
+// FileContent return ip_blocklist.data, whose first line is meta info
+func (ic *IPBlocklistConf) FileContent() []byte {
+	single, pair := 0, 0
+	for _, one := range ic.Items {
+		if strings.Contains(one, " ") {
+			pair++
+		} else {
+			single++
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "#{\"version\": %q, \"singleIPNum\": %d, \"pairIPNum\": %d}\n", ic.Version, single, pair)
+	for _, one := range ic.Items {
+		buf.WriteString(one)
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+type IPBlocklistManager struct {
+	txn itxn.TxnStorager
+
+	extraFileStorager ibasic.ExtraFileStorager
+	productStorager   ibasic.ProductStorager
+
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
+}
+
+func NewIPBlocklistManager(txn itxn.TxnStorager, extraFileStorager ibasic.ExtraFileStorager, productStorager ibasic.ProductStorager,
+	versionControlManager *iversion_control.VersionControlManager, auditManager *iaudit.AuditManager) *IPBlocklistManager {
+
+	return &IPBlocklistManager{
+		txn:                   txn,
+		extraFileStorager:     extraFileStorager,
+		productStorager:       productStorager,
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+func (m *IPBlocklistManager) fetchProductIPBlocklist(ctx context.Context, product *ibasic.Product) ([]*IPBlocklistEntry, error) {
+	files, err := m.extraFileStorager.FetchExtraFiles(ctx, &ibasic.ExtraFileFilter{
+		Name: lib.PString(ipBlocklistFilePath(product)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return []*IPBlocklistEntry{}, nil
+	}
+
+	list, err := ParseIPBlocklist(files[0].Content, nil)
+	if err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Product %s IP Blocklist: %v", product.Name, err)
+	}
+
+	return list, nil
+}
+
+func (m *IPBlocklistManager) FetchProductIPBlocklist(ctx context.Context, product *ibasic.Product) (list []*IPBlocklistEntry, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = m.fetchProductIPBlocklist(ctx, product)
+		return err
+	})
+
+	return
+}
+
+// updateProductIPBlocklist save ip blocklist returned by change, expired entries are pruned
+func (m *IPBlocklistManager) updateProductIPBlocklist(ctx context.Context, product *ibasic.Product,
+	change func(old []*IPBlocklistEntry) []*IPBlocklistEntry) (list []*IPBlocklistEntry, err error) {
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		old, err := m.fetchProductIPBlocklist(ctx, product)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		list = []*IPBlocklistEntry{}
+		for _, one := range change(old) {
+			if !one.expired(now) {
+				list = append(list, one)
+			}
+		}
+
+		fileName := ipBlocklistFilePath(product)
+		if err = m.extraFileStorager.DeleteExtraFile(ctx, &ibasic.ExtraFileFilter{
+			Name: &fileName,
+		}); err != nil {
+			return err
+		}
+		if len(list) > 0 {
+			if err = m.extraFileStorager.CreateExtraFile(ctx, product, &ibasic.ExtraFileParam{
+				Name:    &fileName,
+				Content: formatIPBlocklist(list),
+			}); err != nil {
+				return err
+			}
+		}
+
+		// blocklist may be too large to be recorded
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceIPBlocklist,
+			ResourceName: product.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       map[string]interface{}{"Count": len(old)},
+			After:        map[string]interface{}{"Count": len(list)},
+		})
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicIPBlocklist, ibasic.ConfigTopicExtraFile)
+	}
+
+	return
+}
+
+// mergeIPBlocklist append entries to list, expire time of the existed ip is updated
+func mergeIPBlocklist(list, entries []*IPBlocklistEntry) []*IPBlocklistEntry {
+	index := map[string]int{}
+	rst := []*IPBlocklistEntry{}
+	for _, one := range append(append([]*IPBlocklistEntry{}, list...), entries...) {
+		if i, ok := index[one.IP]; ok {
+			rst[i] = one
+			continue
+		}
+
+		index[one.IP] = len(rst)
+		rst = append(rst, one)
+	}
+
+	return rst
+}
+
+// UpdateProductIPBlocklist add entries to and remove ips from ip blocklist of product
+func (m *IPBlocklistManager) UpdateProductIPBlocklist(ctx context.Context, product *ibasic.Product,
+	add []*IPBlocklistEntry, remove []string) ([]*IPBlocklistEntry, error) {
+
+	removed := map[string]bool{}
+	for _, one := range remove {
+		ip, ok := normalizeIP(one)
+		if !ok {
+			return nil, xerror.WrapParamErrorWithMsg("IP %s Illegal", one)
+		}
+		removed[ip] = true
+	}
+	for _, one := range add {
+		ip, ok := normalizeIP(one.IP)
+		if !ok {
+			return nil, xerror.WrapParamErrorWithMsg("IP %s Illegal", one.IP)
+		}
+		one.IP = ip
+	}
+
+	return m.updateProductIPBlocklist(ctx, product, func(old []*IPBlocklistEntry) []*IPBlocklistEntry {
+		list := []*IPBlocklistEntry{}
+		for _, one := range mergeIPBlocklist(old, add) {
+			if !removed[one.IP] {
+				list = append(list, one)
+			}
+		}
+		return list
+	})
+}
+
+// ImportProductIPBlocklist import entries parsed by ParseIPBlocklist,
+// entries are appended to ip blocklist of product if appendMode is true, otherwise ip blocklist is replaced
+func (m *IPBlocklistManager) ImportProductIPBlocklist(ctx context.Context, product *ibasic.Product,
+	entries []*IPBlocklistEntry, appendMode bool) ([]*IPBlocklistEntry, error) {
+
+	return m.updateProductIPBlocklist(ctx, product, func(old []*IPBlocklistEntry) []*IPBlocklistEntry {
+		if appendMode {
+			return mergeIPBlocklist(old, entries)
+		}
+		return mergeIPBlocklist(nil, entries)
+	})
+}
+
+// ipBlocklistConfGenerator export ip blocklists of all products, expired entries are pruned
+func (m *IPBlocklistManager) ipBlocklistConfGenerator(ctx context.Context) (*iversion_control.ExportData, error) {
+	products, err := m.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	conf := &IPBlocklistConf{
+		Version: iversion_control.ZeroVersion,
+		Items:   []string{},
+	}
+	existed := map[string]bool{}
+	for _, product := range products {
+		list, err := m.fetchProductIPBlocklist(ctx, product)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range list {
+			if one.expired(now) {
+				continue
+			}
+
+			line := one.ipRange()
+			if existed[line] {
+				continue
+			}
+			existed[line] = true
+			conf.Items = append(conf.Items, line)
+		}
+	}
+
+	return &iversion_control.ExportData{
+		Topic:              ConfigTopicIPBlocklist,
+		DataWithoutVersion: conf,
+	}, nil
+}
+
+// ExportIPBlocklist return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (m *IPBlocklistManager) ExportIPBlocklist(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *IPBlocklistConf, err error) {
+
+	err = m.versionControlManager.WaitChange(ctx, ConfigTopicIPBlocklist, wait, func(ctx context.Context) (bool, error) {
+		ed, err := m.versionControlManager.ExportConfig(ctx, ConfigTopicIPBlocklist, m.ipBlocklistConfGenerator,
+			func() iversion_control.VersionValuable { return &IPBlocklistConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*IPBlocklistConf)
+		return conf.Version != lastVersion, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Version == lastVersion {
+		return nil, nil
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imodule_conf
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bfenetworks/bfe/bfe_basic/condition"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// ConfigTopicRedirect same as iroute_conf.ConfigTopicRedirect, HTTPS redirect rules of domains are exported with redirect rules
+const ConfigTopicRedirect = iroute_conf.ConfigTopicRedirect
+
+// cmds supported by mod_redirect, each of them wants one param
+const (
+	RedirectCmdURLSet       = "URL_SET"        // redirect to the url
+	RedirectCmdURLPrefixAdd = "URL_PREFIX_ADD" // redirect to the url prefix + path and query of request
+	RedirectCmdSchemeSet    = "SCHEME_SET"     // redirect to the same url with the scheme, http or https
+)
+
+var redirectCmds = map[string]bool{
+	RedirectCmdURLSet:       true,
+	RedirectCmdURLPrefixAdd: true,
+	RedirectCmdSchemeSet:    true,
+}
+
+var redirectStatuses = map[int]bool{
+	301: true,
+	302: true,
+	303: true,
+	307: true,
+	308: true,
+}
+
+// RedirectRule redirect request when Cond is matched, mod_redirect supports one action for each rule only,
+// rules of product are checked in order of Index, the first matched rule takes effect
+type RedirectRule struct {
+	ProductID   int64
+	Index       int
+	Name        string
+	Description string
+
+	Cond   string
+	Cmd    string
+	Param  string
+	Status int
+}
+
+type RedirectRuleFilter struct {
+	Product *ibasic.Product
+}
+
+type RedirectRuleStorager interface {
+	// FetchRedirectRules return rules sorted by product and index
+	FetchRedirectRules(ctx context.Context, filter *RedirectRuleFilter) ([]*RedirectRule, error)
+	// UpsertProductRedirectRules replace all redirect rules of product with rules
+	UpsertProductRedirectRules(ctx context.Context, product *ibasic.Product, rules []*RedirectRule) error
+}
+
+func (r *RedirectRule) check() error {
+	if _, err := condition.Build(r.Cond); err != nil {
+		return xerror.WrapParamErrorWithMsg("Rule %s Cond %s Invalid: %v", r.Name, r.Cond, err)
+	}
+
+	if !redirectCmds[r.Cmd] {
+		return xerror.WrapParamErrorWithMsg("Rule %s Cmd %s Not Support", r.Name, r.Cmd)
+	}
+	if r.Param == "" {
+		return xerror.WrapParamErrorWithMsg("Rule %s Param Empty", r.Name)
+	}
+	if r.Cmd == RedirectCmdSchemeSet && r.Param != "http" && r.Param != "https" {
+		return xerror.WrapParamErrorWithMsg("Rule %s Scheme %s Illegal, Want http or https", r.Name, r.Param)
+	}
+
+	if !redirectStatuses[r.Status] {
+		return xerror.WrapParamErrorWithMsg("Rule %s Status %d Illegal, Want 301, 302, 303, 307 or 308", r.Name, r.Status)
+	}
+
+	return nil
+}
+
+func (r *RedirectRule) toConf() *iroute_conf.RedirectRule {
+	return &iroute_conf.RedirectRule{
+		Cond: r.Cond,
+		Actions: []*iroute_conf.ModuleAction{
+			{Cmd: r.Cmd, Params: []string{r.Param}},
+		},
+		Status: r.Status,
+	}
+}
+
+type RedirectRuleManager struct {
+	txn itxn.TxnStorager
+
+	storager        RedirectRuleStorager
+	productStorager ibasic.ProductStorager
+
+	domainManager         *iroute_conf.DomainManager
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
+}
+
+func NewRedirectRuleManager(txn itxn.TxnStorager, storager RedirectRuleStorager, productStorager ibasic.ProductStorager,
+	domainManager *iroute_conf.DomainManager, versionControlManager *iversion_control.VersionControlManager,
+	auditManager *iaudit.AuditManager) *RedirectRuleManager {
+
+	return &RedirectRuleManager{
+		txn:                   txn,
+		storager:              storager,
+		productStorager:       productStorager,
+		domainManager:         domainManager,
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+func (m *RedirectRuleManager) FetchProductRedirectRules(ctx context.Context, product *ibasic.Product) (list []*RedirectRule, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = m.storager.FetchRedirectRules(ctx, &RedirectRuleFilter{
+			Product: product,
+		})
+		return err
+	})
+
+	return
+}
+
+// UpsertProductRedirectRules replace all redirect rules of product, rules are checked in the given order
+func (m *RedirectRuleManager) UpsertProductRedirectRules(ctx context.Context, product *ibasic.Product,
+	rules []*RedirectRule) (list []*RedirectRule, err error) {
+
+	names := make([]string, len(rules))
+	for i, one := range rules {
+		names[i] = one.Name
+	}
+	if err = checkRuleNames(names); err != nil {
+		return nil, err
+	}
+	for _, one := range rules {
+		if one.Cmd == RedirectCmdSchemeSet {
+			one.Param = strings.ToLower(one.Param)
+		}
+		if err = one.check(); err != nil {
+			return nil, err
+		}
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		filter := &RedirectRuleFilter{
+			Product: product,
+		}
+		old, err := m.storager.FetchRedirectRules(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		if err = m.storager.UpsertProductRedirectRules(ctx, product, rules); err != nil {
+			return err
+		}
+
+		if list, err = m.storager.FetchRedirectRules(ctx, filter); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceRedirectRule,
+			ResourceName: product.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       old,
+			After:        list,
+		})
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicRedirect)
+	}
+
+	return
+}
+
+// redirectConfGenerator export HTTPS redirect rules of domains and redirect rules of products,
+// HTTPS redirect rules are placed before redirect rules of the same product
+func (m *RedirectRuleManager) redirectConfGenerator(ctx context.Context) (*iversion_control.ExportData, error) {
+	httpsRules, err := m.domainManager.HTTPSRedirectRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := m.storager.FetchRedirectRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := m.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	productMap := ibasic.ProductIDMap(products)
+
+	conf := &iroute_conf.RedirectConf{
+		Version: iversion_control.ZeroVersion,
+		Config:  httpsRules,
+	}
+	for _, rule := range rules {
+		product, ok := productMap[rule.ProductID]
+		if !ok {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Redirect Rule refer Not Exist Product %d", rule.ProductID)
+		}
+		if err := rule.check(); err != nil {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Product %s Redirect Rule %s: %v", product.Name, rule.Name, err)
+		}
+
+		conf.Config[product.Name] = append(conf.Config[product.Name], rule.toConf())
+	}
+
+	return &iversion_control.ExportData{
+		Topic:              ConfigTopicRedirect,
+		DataWithoutVersion: conf,
+	}, nil
+}
+
+// ExportRedirect return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (m *RedirectRuleManager) ExportRedirect(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *iroute_conf.RedirectConf, err error) {
+
+	err = m.versionControlManager.WaitChange(ctx, ConfigTopicRedirect, wait, func(ctx context.Context) (bool, error) {
+		ed, err := m.versionControlManager.ExportConfig(ctx, ConfigTopicRedirect, m.redirectConfGenerator,
+			func() iversion_control.VersionValuable { return &iroute_conf.RedirectConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*iroute_conf.RedirectConf)
+		return conf.Version != lastVersion, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Version == lastVersion {
+		return nil, nil
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imodule_conf
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/bfe/bfe_basic/action"
+	"github.com/bfenetworks/bfe/bfe_basic/condition"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// ConfigTopicRewrite topic of BFE mod_rewrite config
+const ConfigTopicRewrite = "rewrite"
+
+// rewriteActions are cmds supported by mod_rewrite, params are checked by action.ActionFileCheck
+var rewriteActions = map[string]bool{
+	action.ActionHostSet:               true,
+	action.ActionHostSetFromPathPrefix: true,
+
+	action.ActionPathSet:        true,
+	action.ActionPathPrefixAdd:  true,
+	action.ActionPathPrefixTrim: true,
+
+	action.ActionQueryAdd:          true,
+	action.ActionQueryDel:          true,
+	action.ActionQueryRename:       true,
+	action.ActionQueryDelAllExcept: true,
+}
+
+type RewriteAction struct {
+	Cmd    string
+	Params []string
+}
+
+// RewriteRule rewrite host, path or query of request when Cond is matched,
+// rules of product are executed in order of Index, the following rules are skipped if Last is true
+type RewriteRule struct {
+	ProductID   int64
+	Index       int
+	Name        string
+	Description string
+
+	Cond    string
+	Actions []*RewriteAction
+	Last    bool
+}
+
+type RewriteRuleFilter struct {
+	Product *ibasic.Product
+}
+
+type RewriteRuleStorager interface {
+	// FetchRewriteRules return rules sorted by product and index
+	FetchRewriteRules(ctx context.Context, filter *RewriteRuleFilter) ([]*RewriteRule, error)
+	// UpsertProductRewriteRules replace all rewrite rules of product with rules
+	UpsertProductRewriteRules(ctx context.Context, product *ibasic.Product, rules []*RewriteRule) error
+}
+
+func (r *RewriteRule) check() error {
+	if _, err := condition.Build(r.Cond); err != nil {
+		return xerror.WrapParamErrorWithMsg("Rule %s Cond %s Invalid: %v", r.Name, r.Cond, err)
+	}
+
+	if len(r.Actions) == 0 {
+		return xerror.WrapParamErrorWithMsg("Rule %s Actions Empty", r.Name)
+	}
+	for i, one := range r.Actions {
+		if !rewriteActions[one.Cmd] {
+			return xerror.WrapParamErrorWithMsg("Rule %s Action %d Cmd %s Not Support", r.Name, i, one.Cmd)
+		}
+		cmd := one.Cmd
+		if err := action.ActionFileCheck(action.ActionFile{Cmd: &cmd, Params: one.Params}); err != nil {
+			return xerror.WrapParamErrorWithMsg("Rule %s Action %d Invalid: %v", r.Name, i, err)
+		}
+		// ActionFileCheck accepts any number of params for these cmds, but nothing to do without params
+		if (cmd == action.ActionQueryDel || cmd == action.ActionQueryDelAllExcept) && len(one.Params) == 0 {
+			return xerror.WrapParamErrorWithMsg("Rule %s Action %d Cmd %s Want Params", r.Name, i, cmd)
+		}
+	}
+
+	return nil
+}
+
+// checkRuleNames make sure names of rules are not empty and unique
+func checkRuleNames(names []string) error {
+	nameMap := map[string]bool{}
+	for i, name := range names {
+		if name == "" {
+			return xerror.WrapParamErrorWithMsg("Rule %d Name Empty", i)
+		}
+		if nameMap[name] {
+			return xerror.WrapParamErrorWithMsg("Rule Name %s Duplicated", name)
+		}
+		nameMap[name] = true
+	}
+
+	return nil
+}
+
+// RewriteRuleConf is rule of rewrite.data
+type RewriteRuleConf struct {
+	Cond    string
+	Actions []*iroute_conf.ModuleAction
+	Last    bool
+}
+
+// RewriteConf is the same as rewrite.data of BFE mod_rewrite
+type RewriteConf struct {
+	Version string
+	Config  map[string][]*RewriteRuleConf // product name => rules
+}
+
+func (rc *RewriteConf) UpdateVersion(version string) error {
+	rc.Version = version
+
+	return nil
+}
+
+func (r *RewriteRule) toConf() *RewriteRuleConf {
+	actions := make([]*iroute_conf.ModuleAction, len(r.Actions))
+	for i, one := range r.Actions {
+		actions[i] = &iroute_conf.ModuleAction{
+			Cmd:    one.Cmd,
+			Params: append([]string{}, one.Params...),
+		}
+	}
+
+	return &RewriteRuleConf{
+		Cond:    r.Cond,
+		Actions: actions,
+		Last:    r.Last,
+	}
+}
+
+type RewriteRuleManager struct {
+	txn itxn.TxnStorager
+
+	storager        RewriteRuleStorager
+	productStorager ibasic.ProductStorager
+
+	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
+}
+
+func NewRewriteRuleManager(txn itxn.TxnStorager, storager RewriteRuleStorager, productStorager ibasic.ProductStorager,
+	versionControlManager *iversion_control.VersionControlManager, auditManager *iaudit.AuditManager) *RewriteRuleManager {
+
+	return &RewriteRuleManager{
+		txn:                   txn,
+		storager:              storager,
+		productStorager:       productStorager,
+		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+func (m *RewriteRuleManager) FetchProductRewriteRules(ctx context.Context, product *ibasic.Product) (list []*RewriteRule, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = m.storager.FetchRewriteRules(ctx, &RewriteRuleFilter{
+			Product: product,
+		})
+		return err
+	})
+
+	return
+}
+
+// UpsertProductRewriteRules replace all rewrite rules of product, rules are executed in the given order
+func (m *RewriteRuleManager) UpsertProductRewriteRules(ctx context.Context, product *ibasic.Product,
+	rules []*RewriteRule) (list []*RewriteRule, err error) {
+
+	names := make([]string, len(rules))
+	for i, one := range rules {
+		names[i] = one.Name
+	}
+	if err = checkRuleNames(names); err != nil {
+		return nil, err
+	}
+	for _, one := range rules {
+		if err = one.check(); err != nil {
+			return nil, err
+		}
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		filter := &RewriteRuleFilter{
+			Product: product,
+		}
+		old, err := m.storager.FetchRewriteRules(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		if err = m.storager.UpsertProductRewriteRules(ctx, product, rules); err != nil {
+			return err
+		}
+
+		if list, err = m.storager.FetchRewriteRules(ctx, filter); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceRewriteRule,
+			ResourceName: product.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       old,
+			After:        list,
+		})
+	})
+	if err == nil {
+		m.versionControlManager.NotifyChange(ctx, ConfigTopicRewrite)
+	}
+
+	return
+}
+
+func (m *RewriteRuleManager) rewriteConfGenerator(ctx context.Context) (*iversion_control.ExportData, error) {
+	rules, err := m.storager.FetchRewriteRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := m.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	productMap := ibasic.ProductIDMap(products)
+
+	conf := &RewriteConf{
+		Version: iversion_control.ZeroVersion,
+		Config:  map[string][]*RewriteRuleConf{},
+	}
+	for _, rule := range rules {
+		product, ok := productMap[rule.ProductID]
+		if !ok {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Rewrite Rule refer Not Exist Product %d", rule.ProductID)
+		}
+		if err := rule.check(); err != nil {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Product %s Rewrite Rule %s: %v", product.Name, rule.Name, err)
+		}
+
+		conf.Config[product.Name] = append(conf.Config[product.Name], rule.toConf())
+	}
+
+	return &iversion_control.ExportData{
+		Topic:              ConfigTopicRewrite,
+		DataWithoutVersion: conf,
+	}, nil
+}
+
+// ExportRewrite return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (m *RewriteRuleManager) ExportRewrite(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *RewriteConf, err error) {
+
+	err = m.versionControlManager.WaitChange(ctx, ConfigTopicRewrite, wait, func(ctx context.Context) (bool, error) {
+		ed, err := m.versionControlManager.ExportConfig(ctx, ConfigTopicRewrite, m.rewriteConfGenerator,
+			func() iversion_control.VersionValuable { return &RewriteConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*RewriteConf)
+		return conf.Version != lastVersion, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Version == lastVersion {
+		return nil, nil
+	}
+
+	return conf, nil
+}
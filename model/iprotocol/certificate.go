@@ -21,7 +21,9 @@ import (
 
 	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
 	"github.com/bfenetworks/api-server/model/itxn"
 	"github.com/bfenetworks/api-server/model/iversion_control"
 	"github.com/bfenetworks/bfe/bfe_tls"
@@ -44,6 +46,8 @@ type Certificate struct {
 type CertificateFilter struct {
 	CertName  *string
 	IsDefault *bool
+
+	Pagination *lib.Pagination
 }
 
 type CertificateParam struct {
@@ -62,6 +66,7 @@ type CertificateParam struct {
 
 type CertificateStorager interface {
 	FetchCertificates(context.Context, *CertificateFilter) ([]*Certificate, error)
+	CountCertificates(context.Context, *CertificateFilter) (int64, error)
 	DeleteCertificate(context.Context, *Certificate) error
 	CreateCertificate(context.Context, *CertificateParam) error
 	UpdateCertificate(context.Context, *Certificate, *CertificateParam) error
@@ -71,26 +76,91 @@ type CertificateManager struct {
 	storager          CertificateStorager
 	txn               itxn.TxnStorager
 	extraFileStorager ibasic.ExtraFileStorager
+	tlsRuleStorager   TLSRuleStorager
+	productStorager   ibasic.ProductStorager
+	domainStorager    iroute_conf.DomainStorager
 
 	versionControlManager *iversion_control.VersionControlManager
+	auditManager          *iaudit.AuditManager
 }
 
 func NewCertificateManager(txn itxn.TxnStorager, storager CertificateStorager,
 	versionControlManager *iversion_control.VersionControlManager,
-	extraFileStorager ibasic.ExtraFileStorager) *CertificateManager {
+	extraFileStorager ibasic.ExtraFileStorager, tlsRuleStorager TLSRuleStorager,
+	productStorager ibasic.ProductStorager, domainStorager iroute_conf.DomainStorager,
+	auditManager *iaudit.AuditManager) *CertificateManager {
 	return &CertificateManager{
 		txn:               txn,
 		storager:          storager,
 		extraFileStorager: extraFileStorager,
+		tlsRuleStorager:   tlsRuleStorager,
+		productStorager:   productStorager,
+		domainStorager:    domainStorager,
 
 		versionControlManager: versionControlManager,
+		auditManager:          auditManager,
+	}
+}
+
+// auditData return certificate data without content of cert/key file
+func (c *Certificate) auditData() interface{} {
+	return map[string]interface{}{
+		"CertName":     c.CertName,
+		"Description":  c.Description,
+		"IsDefault":    c.IsDefault,
+		"CertFileName": c.CertFileName,
+		"KeyFileName":  c.KeyFileName,
+		"ExpiredDate":  c.ExpiredDate,
+	}
+}
+
+// notifyChange wake up waiters of certificate and cert/key files
+func (pm *CertificateManager) notifyChange(ctx context.Context) {
+	pm.versionControlManager.NotifyChange(ctx, ConfigTopicServerCert, ibasic.ConfigTopicExtraFile)
+}
+
+func (pm *CertificateManager) recordAudit(ctx context.Context, action string, before, after *Certificate) error {
+	param := &iaudit.AuditParam{
+		ResourceType: iaudit.ResourceCertificate,
+		Action:       action,
+	}
+	if before != nil {
+		param.ResourceName = before.CertName
+		param.Before = before.auditData()
+	}
+	if after != nil {
+		param.ResourceName = after.CertName
+		param.After = after.auditData()
 	}
+
+	return pm.auditManager.Record(ctx, param)
 }
 
 func (pm *CertificateManager) FetchCertificates(ctx context.Context, param *CertificateFilter) (list []*Certificate, err error) {
 	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		list, err = pm.storager.FetchCertificates(ctx, param)
-		return err
+		if list, err = pm.storager.FetchCertificates(ctx, param); err != nil {
+			return err
+		}
+
+		return pm.fillProducts(ctx, list)
+	})
+
+	return
+}
+
+// FetchCertificatePage return certificates in page of param.Pagination, and number of all certificates match param
+func (pm *CertificateManager) FetchCertificatePage(ctx context.Context, param *CertificateFilter) (list []*Certificate, total int64, err error) {
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		total, err = pm.storager.CountCertificates(ctx, param)
+		if err != nil {
+			return err
+		}
+
+		if list, err = pm.storager.FetchCertificates(ctx, param); err != nil {
+			return err
+		}
+
+		return pm.fillProducts(ctx, list)
 	})
 
 	return
@@ -105,15 +175,24 @@ func (pm *CertificateManager) DeleteCertificate(ctx context.Context, certificate
 		return xerror.WrapModelErrorWithMsg("Cant Delete Certificate Be Refer By Product")
 	}
 
-	return pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
 		if err := pm.extraFileStorager.DeleteExtraFile(ctx, &ibasic.ExtraFileFilter{
 			Names: []string{certificate.CertFilePath, certificate.KeyFilePath},
 		}); err != nil {
 			return err
 		}
 
-		return pm.storager.DeleteCertificate(ctx, certificate)
+		if err := pm.storager.DeleteCertificate(ctx, certificate); err != nil {
+			return err
+		}
+
+		return pm.recordAudit(ctx, iaudit.ActionDelete, certificate, nil)
 	})
+	if err == nil {
+		pm.notifyChange(ctx)
+	}
+
+	return
 }
 
 func validateCertPair(certFileName string, certFileContent string, keyFileName string, keyFileContent string) error {
@@ -232,8 +311,25 @@ func (pm *CertificateManager) CreateCertificate(ctx context.Context, param *Cert
 			return err
 		}
 
-		return pm.storager.CreateCertificate(ctx, param)
+		if err := pm.storager.CreateCertificate(ctx, param); err != nil {
+			return err
+		}
+
+		list, err = pm.storager.FetchCertificates(ctx, &CertificateFilter{
+			CertName: param.CertName,
+		})
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return xerror.WrapRecordNotExist("Certificate")
+		}
+
+		return pm.recordAudit(ctx, iaudit.ActionCreate, nil, list[0])
 	})
+	if err == nil {
+		pm.notifyChange(ctx)
+	}
 
 	return
 }
@@ -243,8 +339,7 @@ func (pm *CertificateManager) UpdateAsDefaultCertificate(ctx context.Context, ce
 		return nil
 	}
 
-	return pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
 		list, err := pm.storager.FetchCertificates(ctx, &CertificateFilter{
 			IsDefault: lib.PBool(true),
 		})
@@ -259,8 +354,19 @@ func (pm *CertificateManager) UpdateAsDefaultCertificate(ctx context.Context, ce
 			}
 		}
 
-		return pm.storager.UpdateCertificate(ctx, cert, &CertificateParam{
+		if err = pm.storager.UpdateCertificate(ctx, cert, &CertificateParam{
 			IsDefault: lib.PBool(true),
-		})
+		}); err != nil {
+			return err
+		}
+
+		after := *cert
+		after.IsDefault = true
+		return pm.recordAudit(ctx, iaudit.ActionUpdate, cert, &after)
 	})
+	if err == nil {
+		pm.notifyChange(ctx)
+	}
+
+	return
 }
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iprotocol
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+var _ iversion_control.Diffable = &ServerCertConf{}
+
+func (scc *ServerCertConf) certMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	for name, conf := range scc.Config.CertConf {
+		m[name] = conf
+	}
+
+	return m
+}
+
+func (scc *ServerCertConf) tlsRuleMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if scc.TlsRuleConf == nil {
+		return m
+	}
+	for product, conf := range scc.TlsRuleConf.Config {
+		m[product] = conf
+	}
+
+	return m
+}
+
+// Diff certificates, the default one and tls rules of products
+func (scc *ServerCertConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*ServerCertConf)
+
+	entries := iversion_control.DiffMap("certificate", o.certMap(), scc.certMap())
+	entries = append(entries, iversion_control.DiffMap("default_certificate",
+		map[string]interface{}{"default": o.Config.Default},
+		map[string]interface{}{"default": scc.Config.Default})...)
+	entries = append(entries, iversion_control.DiffMap("tls_rule", o.tlsRuleMap(), scc.tlsRuleMap())...)
+
+	return entries
+}
+
+func (pm *CertificateManager) DiffServerCert(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return pm.versionControlManager.DiffConfig(ctx, ConfigTopicServerCert, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &ServerCertConf{} })
+}
@@ -17,24 +17,34 @@ package iprotocol
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/iversion_control"
 	"github.com/bfenetworks/bfe/bfe_config/bfe_tls_conf/server_cert_conf"
+	"github.com/bfenetworks/bfe/bfe_config/bfe_tls_conf/tls_rule_conf"
 )
 
 const (
-	ConfigTopicServerCert = "certificate"
+	ConfigTopicServerCert = ibasic.ConfigTopicServerCert
 
 	tlsConfDir = "tls_conf"
 )
 
+// ServerCertConf is server_cert_conf.data of BFE,
+// TlsRuleConf is tls_rule_conf.data which refer to certificates, they are exported together
 type ServerCertConf struct {
 	server_cert_conf.BfeServerCertConf
+
+	TlsRuleConf *tls_rule_conf.BfeTlsRuleConf `json:",omitempty"`
 }
 
 func (scc *ServerCertConf) UpdateVersion(version string) error {
 	scc.Version = version
+	if scc.TlsRuleConf != nil {
+		scc.TlsRuleConf.Version = version
+	}
 
 	for certFileName, certConfig := range scc.BfeServerCertConf.Config.CertConf {
 		i := strings.Index(certConfig.ServerCertFile, "/")
@@ -75,6 +85,11 @@ func (pm *CertificateManager) certificateGenerator(ctx context.Context) (*iversi
 		}
 	}
 
+	tlsRuleConf, err := pm.tlsRuleConfGenerator(ctx, certConf)
+	if err != nil {
+		return nil, err
+	}
+
 	scc := &ServerCertConf{
 		BfeServerCertConf: server_cert_conf.BfeServerCertConf{
 			Config: server_cert_conf.ServerCertConfMap{
@@ -82,6 +97,7 @@ func (pm *CertificateManager) certificateGenerator(ctx context.Context) (*iversi
 				CertConf: certConf,
 			},
 		},
+		TlsRuleConf: tlsRuleConf,
 	}
 	scc.UpdateVersion(iversion_control.ZeroVersion)
 
@@ -91,13 +107,77 @@ func (pm *CertificateManager) certificateGenerator(ctx context.Context) (*iversi
 	}, nil
 }
 
-func (pm *CertificateManager) ExportServerCert(ctx context.Context, lastVersion string) (*ServerCertConf, error) {
-	ed, err := pm.versionControlManager.ExportConfig(ctx, ConfigTopicServerCert, pm.certificateGenerator)
+// tlsRuleConfGenerator build tls rules of products, domains deleted after be bound are skipped
+func (pm *CertificateManager) tlsRuleConfGenerator(ctx context.Context,
+	certConf map[string]server_cert_conf.ServerCertConf) (*tls_rule_conf.BfeTlsRuleConf, error) {
+
+	rules, err := pm.tlsRuleStorager.FetchTLSRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := pm.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	productMap := ibasic.ProductIDMap(products)
+
+	domains, err := pm.domainStorager.FetchDomains(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	domainMap := map[string]int64{}
+	for _, one := range domains {
+		domainMap[one.Name] = one.ProductID
+	}
+
+	conf := &tls_rule_conf.BfeTlsRuleConf{
+		Version:           iversion_control.ZeroVersion,
+		Config:            tls_rule_conf.TlsRuleMap{},
+		DefaultNextProtos: []string{tls_rule_conf.HTTP11},
+	}
+	for _, rule := range rules {
+		product, ok := productMap[rule.ProductID]
+		if !ok {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("TLS Rule refer Not Exist Product %d", rule.ProductID)
+		}
+		if _, ok := certConf[rule.CertName]; !ok {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Product %s TLS Rule refer Not Exist Certificate %s",
+				product.Name, rule.CertName)
+		}
+
+		hosts := []string{}
+		for _, host := range rule.Hosts {
+			if productID, ok := domainMap[host]; ok && productID == rule.ProductID {
+				hosts = append(hosts, host)
+			}
+		}
+
+		conf.Config[product.Name] = rule.toConf(hosts)
+	}
+
+	return conf, nil
+}
+
+// ExportServerCert return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (pm *CertificateManager) ExportServerCert(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *ServerCertConf, err error) {
+
+	err = pm.versionControlManager.WaitChange(ctx, ConfigTopicServerCert, wait, func(ctx context.Context) (bool, error) {
+		ed, err := pm.versionControlManager.ExportConfig(ctx, ConfigTopicServerCert, pm.certificateGenerator,
+			func() iversion_control.VersionValuable { return &ServerCertConf{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*ServerCertConf)
+		return conf.Version != lastVersion, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	conf := ed.DataWithoutVersion.(*ServerCertConf)
 	if conf.Version == lastVersion {
 		return nil, nil
 	}
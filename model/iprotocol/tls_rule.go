@@ -0,0 +1,285 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iprotocol
+
+import (
+	"context"
+
+	"github.com/bfenetworks/bfe/bfe_config/bfe_tls_conf/server_cert_conf"
+	"github.com/bfenetworks/bfe/bfe_config/bfe_tls_conf/tls_rule_conf"
+	"github.com/bfenetworks/bfe/bfe_tls"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+)
+
+// TLSRule binds a certificate to product, it is exported as rule of product in tls_rule_conf.data,
+// BFE serves the certificate when server name of client hello is one of Hosts
+type TLSRule struct {
+	ProductID int64
+	CertName  string
+	Hosts     []string // domains of product, exported as SniConf
+
+	NextProtos    []string // protocols negotiated by ALPN, such as h2, http/1.1
+	Grade         string   // A+, A, B or C, decide tls versions and cipher suites allowed
+	Chacha20      bool     // enable chacha20-poly1305 cipher suites
+	DynamicRecord bool     // enable dynamic record size
+}
+
+type TLSRuleFilter struct {
+	Product  *ibasic.Product
+	CertName *string
+}
+
+type TLSRuleStorager interface {
+	// FetchTLSRules return rules sorted by product
+	FetchTLSRules(ctx context.Context, filter *TLSRuleFilter) ([]*TLSRule, error)
+	// UpsertProductTLSRule create tls rule of product or replace the existed one
+	UpsertProductTLSRule(ctx context.Context, product *ibasic.Product, rule *TLSRule) error
+	DeleteProductTLSRule(ctx context.Context, product *ibasic.Product) error
+}
+
+func (r *TLSRule) toConf(hosts []string) *tls_rule_conf.TlsRuleConf {
+	nextProtos := r.NextProtos
+	if nextProtos == nil {
+		nextProtos = []string{}
+	}
+
+	return &tls_rule_conf.TlsRuleConf{
+		VipConf:       []string{},
+		SniConf:       hosts,
+		CertName:      r.CertName,
+		NextProtos:    nextProtos,
+		Grade:         r.Grade,
+		Chacha20:      r.Chacha20,
+		DynamicRecord: r.DynamicRecord,
+	}
+}
+
+// check validate rule in the same way as BFE, Grade is normalized to upper case and default to C
+func (r *TLSRule) check() error {
+	if len(r.Hosts) == 0 {
+		return xerror.WrapParamErrorWithMsg("Domains Must Not Be Empty")
+	}
+
+	existed := map[string]bool{}
+	for _, host := range r.Hosts {
+		if existed[host] {
+			return xerror.WrapParamErrorWithMsg("Domain %s Duplicated", host)
+		}
+		existed[host] = true
+	}
+
+	conf := r.toConf(r.Hosts)
+	if err := tls_rule_conf.TlsRuleConfCheck(conf); err != nil {
+		return xerror.WrapParamErrorWithMsg("TLS Rule Invalid: %v", err)
+	}
+	r.Grade = conf.Grade
+
+	return nil
+}
+
+// certificateNames return names (CN and SANs) of certificate
+func (pm *CertificateManager) certificateNames(ctx context.Context, cert *Certificate) ([]string, error) {
+	files, err := pm.extraFileStorager.FetchExtraFiles(ctx, &ibasic.ExtraFileFilter{
+		Names: []string{cert.CertFilePath, cert.KeyFilePath},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contents := map[string][]byte{}
+	for _, one := range files {
+		contents[one.Name] = one.Content
+	}
+
+	pair, err := bfe_tls.X509KeyPair(contents[cert.CertFilePath], contents[cert.KeyFilePath])
+	if err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Certificate %s: %v", cert.CertName, err)
+	}
+
+	return server_cert_conf.GetNamesForCert(&pair), nil
+}
+
+// fillProducts set products bound to certificates
+func (pm *CertificateManager) fillProducts(ctx context.Context, certificates []*Certificate) error {
+	if len(certificates) == 0 {
+		return nil
+	}
+
+	rules, err := pm.tlsRuleStorager.FetchTLSRules(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	products, err := pm.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return err
+	}
+	productMap := ibasic.ProductIDMap(products)
+
+	cert2products := map[string][]*ibasic.Product{}
+	for _, rule := range rules {
+		if product, ok := productMap[rule.ProductID]; ok {
+			cert2products[rule.CertName] = append(cert2products[rule.CertName], product)
+		}
+	}
+
+	for _, one := range certificates {
+		one.Products = cert2products[one.CertName]
+	}
+
+	return nil
+}
+
+func (pm *CertificateManager) FetchProductTLSRule(ctx context.Context, product *ibasic.Product) (rule *TLSRule, err error) {
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		rule, err = pm.fetchProductTLSRule(ctx, product)
+		return err
+	})
+
+	return
+}
+
+// fetchProductTLSRule return nil if product has no tls rule
+func (pm *CertificateManager) fetchProductTLSRule(ctx context.Context, product *ibasic.Product) (*TLSRule, error) {
+	list, err := pm.tlsRuleStorager.FetchTLSRules(ctx, &TLSRuleFilter{
+		Product: product,
+	})
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return list[0], nil
+}
+
+// UpsertProductTLSRule bind certificate to product and its domains,
+// domains must belong to product and be covered by the certificate
+func (pm *CertificateManager) UpsertProductTLSRule(ctx context.Context, product *ibasic.Product,
+	rule *TLSRule) (after *TLSRule, err error) {
+
+	if err = rule.check(); err != nil {
+		return nil, err
+	}
+
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		certificates, err := pm.storager.FetchCertificates(ctx, &CertificateFilter{
+			CertName: &rule.CertName,
+		})
+		if err != nil {
+			return err
+		}
+		if len(certificates) == 0 {
+			return xerror.WrapRecordNotExist("Certificate")
+		}
+
+		domains, err := pm.domainStorager.FetchDomains(ctx, &iroute_conf.DomainFilter{
+			Product: product,
+		})
+		if err != nil {
+			return err
+		}
+		productDomains := map[string]bool{}
+		for _, one := range domains {
+			productDomains[one.Name] = true
+		}
+
+		certNames, err := pm.certificateNames(ctx, certificates[0])
+		if err != nil {
+			return err
+		}
+
+		for _, host := range rule.Hosts {
+			if !productDomains[host] {
+				return xerror.WrapParamErrorWithMsg("Domain %s Not Belong To Product %s", host, product.Name)
+			}
+			if !tls_rule_conf.MatchCertNames(certNames, host) {
+				return xerror.WrapModelErrorWithMsg("Domain %s Not Covered By Certificate %s", host, rule.CertName)
+			}
+		}
+
+		before, err := pm.fetchProductTLSRule(ctx, product)
+		if err != nil {
+			return err
+		}
+
+		if err = pm.tlsRuleStorager.UpsertProductTLSRule(ctx, product, rule); err != nil {
+			return err
+		}
+
+		if after, err = pm.fetchProductTLSRule(ctx, product); err != nil {
+			return err
+		}
+
+		action := iaudit.ActionUpdate
+		if before == nil {
+			action = iaudit.ActionCreate
+		}
+		return pm.recordTLSRuleAudit(ctx, product, action, before, after)
+	})
+	if err == nil {
+		pm.notifyChange(ctx)
+	}
+
+	return
+}
+
+// DeleteProductTLSRule unbind certificate from product
+func (pm *CertificateManager) DeleteProductTLSRule(ctx context.Context, product *ibasic.Product) (before *TLSRule, err error) {
+	err = pm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if before, err = pm.fetchProductTLSRule(ctx, product); err != nil {
+			return err
+		}
+		if before == nil {
+			return xerror.WrapRecordNotExist("TLS Rule")
+		}
+
+		if err = pm.tlsRuleStorager.DeleteProductTLSRule(ctx, product); err != nil {
+			return err
+		}
+
+		return pm.recordTLSRuleAudit(ctx, product, iaudit.ActionDelete, before, nil)
+	})
+	if err == nil {
+		pm.notifyChange(ctx)
+	}
+
+	return
+}
+
+func (pm *CertificateManager) recordTLSRuleAudit(ctx context.Context, product *ibasic.Product, action string,
+	before, after *TLSRule) error {
+
+	param := &iaudit.AuditParam{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		ResourceType: iaudit.ResourceTLSRule,
+		ResourceName: product.Name,
+		Action:       action,
+	}
+	if before != nil {
+		param.Before = before
+	}
+	if after != nil {
+		param.After = after
+	}
+
+	return pm.auditManager.Record(ctx, param)
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iroute_conf
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+var (
+	_ iversion_control.Diffable = &RouteRuleExportData{}
+	_ iversion_control.Diffable = &RedirectConf{}
+	_ iversion_control.Diffable = &HeaderConf{}
+)
+
+// hostMap return hostname => product
+func (rred *RouteRuleExportData) hostMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if rred.HostTable == nil || rred.HostTable.Hosts == nil || rred.HostTable.HostTags == nil {
+		return m
+	}
+
+	tag2Product := map[string]string{}
+	for product, tags := range *rred.HostTable.HostTags {
+		if tags == nil {
+			continue
+		}
+		for _, tag := range *tags {
+			tag2Product[tag] = product
+		}
+	}
+
+	for tag, hosts := range *rred.HostTable.Hosts {
+		if hosts == nil {
+			continue
+		}
+		for _, host := range *hosts {
+			m[host] = tag2Product[tag]
+		}
+	}
+
+	return m
+}
+
+func (rred *RouteRuleExportData) basicRuleMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if rred.RouteTable == nil || rred.RouteTable.BasicRule == nil {
+		return m
+	}
+
+	for product, rules := range *rred.RouteTable.BasicRule {
+		m[product] = rules
+	}
+
+	return m
+}
+
+func (rred *RouteRuleExportData) advanceRuleMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if rred.RouteTable == nil || rred.RouteTable.ProductRule == nil {
+		return m
+	}
+
+	for product, rules := range *rred.RouteTable.ProductRule {
+		m[product] = rules
+	}
+
+	return m
+}
+
+func (rred *RouteRuleExportData) clusterMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if rred.ClusterConf == nil || rred.ClusterConf.Config == nil {
+		return m
+	}
+
+	for name, conf := range *rred.ClusterConf.Config {
+		m[name] = conf
+	}
+
+	return m
+}
+
+// Diff hosts, route rules of products and clusters
+func (rred *RouteRuleExportData) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*RouteRuleExportData)
+
+	var entries []*iversion_control.DiffEntry
+	entries = append(entries, iversion_control.DiffMap("host", o.hostMap(), rred.hostMap())...)
+	entries = append(entries, iversion_control.DiffMap("basic_route_rule", o.basicRuleMap(), rred.basicRuleMap())...)
+	entries = append(entries, iversion_control.DiffMap("advance_route_rule", o.advanceRuleMap(), rred.advanceRuleMap())...)
+	entries = append(entries, iversion_control.DiffMap("cluster", o.clusterMap(), rred.clusterMap())...)
+
+	return entries
+}
+
+func (rm *RouteRuleManager) DiffRouteRule(ctx context.Context, oldVersion, newVersion string) (*iversion_control.ConfigDiff, error) {
+	return rm.versionControlManager.DiffConfig(ctx, ConfigTopicRouteRule, oldVersion, newVersion,
+		func() iversion_control.Diffable { return &RouteRuleExportData{} })
+}
+
+// Diff redirect rules of products
+func (rc *RedirectConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*RedirectConf)
+
+	toMap := func(conf *RedirectConf) map[string]interface{} {
+		m := map[string]interface{}{}
+		for product, rules := range conf.Config {
+			m[product] = rules
+		}
+		return m
+	}
+
+	return iversion_control.DiffMap("redirect_rule", toMap(o), toMap(rc))
+}
+
+// Diff header rules of products
+func (hc *HeaderConf) Diff(old iversion_control.Diffable) []*iversion_control.DiffEntry {
+	o := old.(*HeaderConf)
+
+	toMap := func(conf *HeaderConf) map[string]interface{} {
+		m := map[string]interface{}{}
+		for product, rules := range conf.Config {
+			m[product] = rules
+		}
+		return m
+	}
+
+	return iversion_control.DiffMap("header_rule", toMap(o), toMap(hc))
+}
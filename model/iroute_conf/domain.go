@@ -22,7 +22,9 @@ import (
 
 	"github.com/bfenetworks/bfe/bfe_config/bfe_route_conf/host_rule_conf"
 
+	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/itxn"
 )
@@ -34,11 +36,18 @@ type Domain struct {
 
 	UsingAdvancedRedirect int8
 	UsingAdvancedHsts     int8
+
+	// options of Strict-Transport-Security header, used when UsingAdvancedHsts is set
+	HstsMaxAge            int64
+	HstsIncludeSubDomains bool
+	HstsPreload           bool
 }
 
 type DomainFilter struct {
 	Product *ibasic.Product
 	Name    *string
+
+	Pagination *lib.Pagination
 }
 
 type DomainParam struct {
@@ -47,6 +56,10 @@ type DomainParam struct {
 
 	UsingAdvancedRedirect *int8
 	UsingAdvancedHsts     *int8
+
+	HstsMaxAge            *int64
+	HstsIncludeSubDomains *bool
+	HstsPreload           *bool
 }
 
 func newHostTableConf(version string, productMapID2Name map[int64]string,
@@ -102,7 +115,9 @@ func newHostTableConf(version string, productMapID2Name map[int64]string,
 
 type DomainStorager interface {
 	FetchDomains(ctx context.Context, param *DomainFilter) ([]*Domain, error)
+	CountDomains(ctx context.Context, param *DomainFilter) (int64, error)
 	CreateDomain(ctx context.Context, product *ibasic.Product, param *DomainParam) error
+	UpdateDomain(ctx context.Context, domain *Domain, param *DomainParam) error
 	DeleteDomain(ctx context.Context, product *ibasic.Product, domain *Domain) error
 }
 
@@ -111,15 +126,17 @@ type DomainManager struct {
 
 	storager         DomainStorager
 	routeRuleManager *RouteRuleManager
+	auditManager     *iaudit.AuditManager
 }
 
 func NewDomainManager(txn itxn.TxnStorager, storager DomainStorager,
-	routeRuleManager *RouteRuleManager) *DomainManager {
+	routeRuleManager *RouteRuleManager, auditManager *iaudit.AuditManager) *DomainManager {
 
 	return &DomainManager{
 		txn:              txn,
 		storager:         storager,
 		routeRuleManager: routeRuleManager,
+		auditManager:     auditManager,
 	}
 }
 
@@ -132,6 +149,21 @@ func (m *DomainManager) DomainList(ctx context.Context, param *DomainFilter) (li
 	return
 }
 
+// DomainPage return domains in page of param.Pagination, and number of all domains match param
+func (m *DomainManager) DomainPage(ctx context.Context, param *DomainFilter) (list []*Domain, total int64, err error) {
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		total, err = m.storager.CountDomains(ctx, param)
+		if err != nil {
+			return err
+		}
+
+		list, err = m.storager.FetchDomains(ctx, param)
+		return err
+	})
+
+	return
+}
+
 func (m *DomainManager) CreateDomain(ctx context.Context, product *ibasic.Product, param *DomainParam) (err error) {
 	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
 		param.ProductID = &product.ID
@@ -184,8 +216,23 @@ func (m *DomainManager) CreateDomain(ctx context.Context, product *ibasic.Produc
 			}
 		}
 
-		return m.storager.CreateDomain(ctx, product, param)
+		if err = m.storager.CreateDomain(ctx, product, param); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceDomain,
+			ResourceName: domainName,
+			Action:       iaudit.ActionCreate,
+			After:        param,
+		})
 	})
+	if err == nil {
+		// tls rules of product only export hosts which are domains of product
+		m.routeRuleManager.versionControlManager.NotifyChange(ctx, ConfigTopicRouteRule, ibasic.ConfigTopicServerCert)
+	}
 
 	return
 }
@@ -199,9 +246,25 @@ func (m *DomainManager) DeleteDomain(ctx context.Context, product *ibasic.Produc
 		return xerror.WrapDependentUnReadyErrorWithMsg(dbui.String())
 	}
 
-	return m.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		return m.storager.DeleteDomain(ctx, product, domain)
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := m.storager.DeleteDomain(ctx, product, domain); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceDomain,
+			ResourceName: domain.Name,
+			Action:       iaudit.ActionDelete,
+			Before:       domain,
+		})
 	})
+	if err == nil {
+		m.routeRuleManager.versionControlManager.NotifyChange(ctx, ConfigTopicRouteRule, ibasic.ConfigTopicServerCert)
+	}
+
+	return
 }
 
 func (m *DomainManager) BeUsed(ctx context.Context, product *ibasic.Product, domain *Domain) (*DomainBeUsedInfo, error) {
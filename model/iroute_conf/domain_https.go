@@ -0,0 +1,279 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iroute_conf
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bfenetworks/bfe/bfe_basic/condition"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+)
+
+const (
+	// ConfigTopicRedirect topic of BFE mod_redirect config
+	ConfigTopicRedirect = "redirect"
+	// ConfigTopicHeader topic of BFE mod_header config
+	ConfigTopicHeader = "header"
+)
+
+// hstsPreloadMinMaxAge is the minimal max-age required by HSTS preload list, one year
+const hstsPreloadMinMaxAge int64 = 31536000
+
+// merge return a copy of domain updated by https options of param
+func (d *Domain) merge(param *DomainParam) *Domain {
+	tmp := *d
+	if param.UsingAdvancedRedirect != nil {
+		tmp.UsingAdvancedRedirect = *param.UsingAdvancedRedirect
+	}
+	if param.UsingAdvancedHsts != nil {
+		tmp.UsingAdvancedHsts = *param.UsingAdvancedHsts
+	}
+	if param.HstsMaxAge != nil {
+		tmp.HstsMaxAge = *param.HstsMaxAge
+	}
+	if param.HstsIncludeSubDomains != nil {
+		tmp.HstsIncludeSubDomains = *param.HstsIncludeSubDomains
+	}
+	if param.HstsPreload != nil {
+		tmp.HstsPreload = *param.HstsPreload
+	}
+
+	return &tmp
+}
+
+func (d *Domain) checkHTTPS() error {
+	if d.UsingAdvancedHsts == 0 {
+		return nil
+	}
+
+	if d.HstsMaxAge <= 0 {
+		return xerror.WrapParamErrorWithMsg("HstsMaxAge Must Be Greater Than 0 When HSTS Enabled")
+	}
+
+	if d.HstsPreload {
+		if !d.HstsIncludeSubDomains {
+			return xerror.WrapParamErrorWithMsg("HstsPreload Requires HstsIncludeSubDomains")
+		}
+		if d.HstsMaxAge < hstsPreloadMinMaxAge {
+			return xerror.WrapParamErrorWithMsg("HstsPreload Requires HstsMaxAge Not Less Than %d", hstsPreloadMinMaxAge)
+		}
+	}
+
+	return nil
+}
+
+// hstsHeader return value of Strict-Transport-Security header
+func (d *Domain) hstsHeader() string {
+	value := fmt.Sprintf("max-age=%d", d.HstsMaxAge)
+	if d.HstsIncludeSubDomains {
+		value += "; includeSubDomains"
+	}
+	if d.HstsPreload {
+		value += "; preload"
+	}
+
+	return value
+}
+
+// hostCond return condition expression matching host of domain,
+// wildcard domain like *.example.com matches one level sub domain only
+func (d *Domain) hostCond() string {
+	if !strings.HasPrefix(d.Name, "*.") {
+		return fmt.Sprintf("req_host_in(%s)", strconv.Quote(d.Name))
+	}
+
+	pattern := "(?i)^[^.]+" + regexp.QuoteMeta(strings.TrimPrefix(d.Name, "*")) + "$"
+	return fmt.Sprintf("req_host_regmatch(%s)", strconv.Quote(pattern))
+}
+
+// UpdateDomainHTTPS update HTTPS redirect and HSTS options of domain
+func (m *DomainManager) UpdateDomainHTTPS(ctx context.Context, product *ibasic.Product, domain *Domain,
+	param *DomainParam) (err error) {
+
+	after := domain.merge(param)
+	if err = after.checkHTTPS(); err != nil {
+		return err
+	}
+
+	err = m.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := m.storager.UpdateDomain(ctx, domain, &DomainParam{
+			UsingAdvancedRedirect: param.UsingAdvancedRedirect,
+			UsingAdvancedHsts:     param.UsingAdvancedHsts,
+			HstsMaxAge:            param.HstsMaxAge,
+			HstsIncludeSubDomains: param.HstsIncludeSubDomains,
+			HstsPreload:           param.HstsPreload,
+		}); err != nil {
+			return err
+		}
+
+		return m.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceDomain,
+			ResourceName: domain.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       domain,
+			After:        after,
+		})
+	})
+	if err == nil {
+		m.routeRuleManager.versionControlManager.NotifyChange(ctx, ConfigTopicRedirect, ConfigTopicHeader)
+	}
+
+	return
+}
+
+// ModuleAction is action of BFE module rule, such as mod_redirect and mod_header
+type ModuleAction struct {
+	Cmd    string
+	Params []string
+}
+
+type RedirectRule struct {
+	Cond    string
+	Actions []*ModuleAction
+	Status  int
+}
+
+// RedirectConf is the same as redirect.data of BFE mod_redirect
+type RedirectConf struct {
+	Version string
+	Config  map[string][]*RedirectRule // product name => rules
+}
+
+func (rc *RedirectConf) UpdateVersion(version string) error {
+	rc.Version = version
+
+	return nil
+}
+
+type HeaderRule struct {
+	Cond    string
+	Actions []*ModuleAction
+	Last    bool
+}
+
+// HeaderConf is the same as header_rule.data of BFE mod_header
+type HeaderConf struct {
+	Version string
+	Config  map[string][]*HeaderRule // product name => rules
+}
+
+func (hc *HeaderConf) UpdateVersion(version string) error {
+	hc.Version = version
+
+	return nil
+}
+
+// fetchProductDomains return product name => domains, domains are sorted by name
+func (m *DomainManager) fetchProductDomains(ctx context.Context) (map[string][]*Domain, error) {
+	domains, err := m.storager.FetchDomains(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := m.routeRuleManager.productStorager.FetchProducts(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	productMap := ibasic.ProductIDMap(products)
+
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].Name < domains[j].Name
+	})
+
+	rst := map[string][]*Domain{}
+	for _, domain := range domains {
+		product, ok := productMap[domain.ProductID]
+		if !ok {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Domain refer Not Exist Product %d", domain.ProductID)
+		}
+		rst[product.Name] = append(rst[product.Name], domain)
+	}
+
+	return rst, nil
+}
+
+// HTTPSRedirectRules return product name => redirect rules switching scheme to https for domains enabled HTTPS redirect,
+// rules of each product are sorted by domain name
+func (m *DomainManager) HTTPSRedirectRules(ctx context.Context) (map[string][]*RedirectRule, error) {
+	productDomains, err := m.fetchProductDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := map[string][]*RedirectRule{}
+	for productName, domains := range productDomains {
+		for _, domain := range domains {
+			if domain.UsingAdvancedRedirect == 0 {
+				continue
+			}
+
+			rule := &RedirectRule{
+				Cond: "!req_proto_secure() && " + domain.hostCond(),
+				Actions: []*ModuleAction{
+					{Cmd: "SCHEME_SET", Params: []string{"https"}},
+				},
+				Status: 301,
+			}
+			if _, err := condition.Build(rule.Cond); err != nil {
+				return nil, xerror.WrapDirtyDataErrorWithMsg("Domain %s Redirect Condition: %v", domain.Name, err)
+			}
+			rst[productName] = append(rst[productName], rule)
+		}
+	}
+
+	return rst, nil
+}
+
+// HSTSHeaderRules return product name => header rules setting Strict-Transport-Security for domains enabled HSTS,
+// rules of each product are sorted by domain name
+func (m *DomainManager) HSTSHeaderRules(ctx context.Context) (map[string][]*HeaderRule, error) {
+	productDomains, err := m.fetchProductDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := map[string][]*HeaderRule{}
+	for productName, domains := range productDomains {
+		for _, domain := range domains {
+			if domain.UsingAdvancedHsts == 0 {
+				continue
+			}
+
+			rule := &HeaderRule{
+				Cond: "req_proto_secure() && " + domain.hostCond(),
+				Actions: []*ModuleAction{
+					{Cmd: "RSP_HEADER_SET", Params: []string{"Strict-Transport-Security", domain.hstsHeader()}},
+				},
+				Last: false,
+			}
+			if _, err := condition.Build(rule.Cond); err != nil {
+				return nil, xerror.WrapDirtyDataErrorWithMsg("Domain %s HSTS Condition: %v", domain.Name, err)
+			}
+			rst[productName] = append(rst[productName], rule)
+		}
+	}
+
+	return rst, nil
+}
@@ -16,8 +16,8 @@ package iroute_conf
 
 import (
 	"context"
+	"time"
 
-	"github.com/bfenetworks/bfe/bfe_config/bfe_cluster_conf/cluster_conf"
 	"github.com/bfenetworks/bfe/bfe_config/bfe_route_conf/host_rule_conf"
 	"github.com/bfenetworks/bfe/bfe_config/bfe_route_conf/route_rule_conf"
 
@@ -32,7 +32,7 @@ type RouteRuleExportData struct {
 	Version     string
 	HostTable   *host_rule_conf.HostTableConf
 	RouteTable  *route_rule_conf.RouteTableFile
-	ClusterConf *cluster_conf.BfeClusterConf
+	ClusterConf *icluster_conf.BfeClusterConf
 }
 
 func (rred *RouteRuleExportData) UpdateVersion(version string) error {
@@ -48,13 +48,25 @@ const (
 	ConfigTopicRouteRule = "route_rule"
 )
 
-func (rm *RouteRuleManager) ExportRouteRule(ctx context.Context, lastVersion string) (*RouteRuleExportData, error) {
-	ed, err := rm.versionControlManager.ExportConfig(ctx, ConfigTopicRouteRule, rm.exportRouteRule)
+// ExportRouteRule return nil if version not changed,
+// if wait > 0, request will be held until version changed or wait timeout
+func (rm *RouteRuleManager) ExportRouteRule(ctx context.Context, lastVersion string,
+	wait time.Duration) (conf *RouteRuleExportData, err error) {
+
+	err = rm.versionControlManager.WaitChange(ctx, ConfigTopicRouteRule, wait, func(ctx context.Context) (bool, error) {
+		ed, err := rm.versionControlManager.ExportConfig(ctx, ConfigTopicRouteRule, rm.exportRouteRule,
+			func() iversion_control.VersionValuable { return &RouteRuleExportData{} })
+		if err != nil {
+			return false, err
+		}
+
+		conf = ed.DataWithoutVersion.(*RouteRuleExportData)
+		return conf.Version != lastVersion, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	conf := ed.DataWithoutVersion.(*RouteRuleExportData)
 	if conf.Version == lastVersion {
 		return nil, nil
 	}
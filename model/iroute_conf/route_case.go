@@ -0,0 +1,334 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iroute_conf
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/bfenetworks/bfe/bfe_basic"
+	"github.com/bfenetworks/bfe/bfe_basic/condition"
+	"github.com/bfenetworks/bfe/bfe_config/bfe_route_conf/route_rule_conf"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+)
+
+type RouteCaseFilter struct {
+	Product *ibasic.Product
+	ID      *int64
+}
+
+type RouteCaseParam struct {
+	Description   *string
+	URL           *string
+	Method        *string
+	Header        map[string]string
+	ExpectCluster *string
+}
+
+type RouteCaseStorager interface {
+	FetchRouteCases(ctx context.Context, filter *RouteCaseFilter) ([]*RouteRuleCase, error)
+	CreateRouteCase(ctx context.Context, product *ibasic.Product, param *RouteCaseParam) (int64, error)
+	UpdateRouteCase(ctx context.Context, product *ibasic.Product, old *RouteRuleCase, param *RouteCaseParam) error
+	DeleteRouteCase(ctx context.Context, product *ibasic.Product, rc *RouteRuleCase) error
+}
+
+// Request build bfe request from route case
+func (rc *RouteRuleCase) Request() (*bfe_basic.Request, error) {
+	return newRouteRequest(rc.URL, rc.Method, rc.Header)
+}
+
+// RouteRuleMatchResult the rule and cluster hit by a request
+type RouteRuleMatchResult struct {
+	BasicRouteRule   *BasicRouteRule
+	AdvanceRouteRule *AdvanceRouteRule
+	ClusterName      string
+}
+
+// RouteRuleRunner match request with product route rule, the same as bfe_route.HostTable.LookupCluster
+type RouteRuleRunner struct {
+	basicRules   []*BasicRouteRule
+	basicTree    *route_rule_conf.BasicRouteRuleTree
+	advanceRules []*AdvanceRouteRule
+	conditions   []condition.Condition
+}
+
+func NewRouteRuleRunner(rule *ProductRouteRule) (*RouteRuleRunner, error) {
+	runner := &RouteRuleRunner{
+		basicTree: route_rule_conf.NewBasicRouteRuleTree(),
+	}
+	if rule == nil {
+		return runner, nil
+	}
+
+	runner.basicRules = rule.BasicRouteRules
+	for i, one := range rule.BasicRouteRules {
+		// use rule index as value of tree node, so the hit rule can be found
+		index := strconv.Itoa(i)
+		if err := runner.basicTree.Insert(&route_rule_conf.BasicRouteRuleFile{
+			Hostname:    append([]string{}, one.HostNames...),
+			Path:        append([]string{}, one.Paths...),
+			ClusterName: &index,
+		}); err != nil {
+			return nil, xerror.WrapParamErrorWithMsg("Basic Rule %d Invalid: %v", i, err)
+		}
+	}
+
+	runner.advanceRules = rule.AdvanceRouteRules
+	for _, one := range rule.AdvanceRouteRules {
+		cond, err := condition.Build(one.Expression)
+		if err != nil {
+			return nil, xerror.WrapParamErrorWithMsg("Rule %s Expression %s Invalid: %v", one.Name, one.Expression, err)
+		}
+		runner.conditions = append(runner.conditions, cond)
+	}
+
+	return runner, nil
+}
+
+// Match return nil if no rule be hit
+func (r *RouteRuleRunner) Match(req *bfe_basic.Request) *RouteRuleMatchResult {
+	host := strings.SplitN(req.HttpRequest.Host, ":", 2)[0]
+	path := ""
+	if req.HttpRequest.URL != nil {
+		path = req.HttpRequest.URL.Path
+	}
+
+	if value, found := r.basicTree.Get(host, path); found {
+		index, _ := strconv.Atoi(value)
+		rule := r.basicRules[index]
+		if rule.ClusterName != icluster_conf.RouteAdvancedModeClusterName4DP {
+			return &RouteRuleMatchResult{
+				BasicRouteRule: rule,
+				ClusterName:    rule.ClusterName,
+			}
+		}
+	}
+
+	for i, cond := range r.conditions {
+		if cond.Match(req) {
+			return &RouteRuleMatchResult{
+				AdvanceRouteRule: r.advanceRules[i],
+				ClusterName:      r.advanceRules[i].ClusterName,
+			}
+		}
+	}
+
+	return nil
+}
+
+// RunCases run route cases with route rule
+func (r *RouteRuleRunner) RunCases(cases []*RouteRuleCase) ([]*RouteRuleRunCaseResult, error) {
+	results := []*RouteRuleRunCaseResult{}
+	for _, one := range cases {
+		req, err := one.Request()
+		if err != nil {
+			return nil, err
+		}
+
+		var actualCluster string
+		if mr := r.Match(req); mr != nil {
+			actualCluster = mr.ClusterName
+		}
+
+		results = append(results, &RouteRuleRunCaseResult{
+			RouteRuleCase: *one,
+			ActualCluster: actualCluster,
+			Pass:          actualCluster == one.ExpectCluster,
+		})
+	}
+
+	return results, nil
+}
+
+func (rm *RouteRuleManager) runRouteCases(ctx context.Context, product *ibasic.Product,
+	rule *ProductRouteRule) ([]*RouteRuleRunCaseResult, error) {
+
+	cases, err := rm.routeCaseStorager.FetchRouteCases(ctx, &RouteCaseFilter{
+		Product: product,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, nil
+	}
+
+	runner, err := NewRouteRuleRunner(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return runner.RunCases(cases)
+}
+
+// RunRouteCases run all route cases of product with route rule stored
+func (rm *RouteRuleManager) RunRouteCases(ctx context.Context, product *ibasic.Product) (list []*RouteRuleRunCaseResult, err error) {
+	err = rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		rule, err := rm.fetchProductRule(ctx, product)
+		if err != nil {
+			return err
+		}
+
+		list, err = rm.runRouteCases(ctx, product, rule)
+		return err
+	})
+
+	return
+}
+
+func (rm *RouteRuleManager) RouteCaseList(ctx context.Context, filter *RouteCaseFilter) (list []*RouteRuleCase, err error) {
+	err = rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = rm.routeCaseStorager.FetchRouteCases(ctx, filter)
+		return err
+	})
+
+	return
+}
+
+func (rm *RouteRuleManager) routeCaseCheck(ctx context.Context, product *ibasic.Product, rc *RouteRuleCase) error {
+	if _, err := rc.Request(); err != nil {
+		return err
+	}
+
+	clusters, err := rm.clusterStorager.FetchClusterList(ctx, &icluster_conf.ClusterFilter{
+		Name:    &rc.ExpectCluster,
+		Product: product,
+	})
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		return xerror.WrapModelErrorWithMsg("Cluster %s Not Exist", rc.ExpectCluster)
+	}
+
+	return nil
+}
+
+func (rm *RouteRuleManager) CreateRouteCase(ctx context.Context, product *ibasic.Product,
+	param *RouteCaseParam) (rc *RouteRuleCase, err error) {
+
+	err = rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := rm.routeCaseCheck(ctx, product, routeCaseMerge(&RouteRuleCase{}, param)); err != nil {
+			return err
+		}
+
+		id, err := rm.routeCaseStorager.CreateRouteCase(ctx, product, param)
+		if err != nil {
+			return err
+		}
+
+		if rc, err = rm.fetchRouteCase(ctx, product, id); err != nil {
+			return err
+		}
+
+		return rm.recordRouteCaseAudit(ctx, product, iaudit.ActionCreate, nil, rc)
+	})
+
+	return
+}
+
+func (rm *RouteRuleManager) UpdateRouteCase(ctx context.Context, product *ibasic.Product, old *RouteRuleCase,
+	param *RouteCaseParam) (rc *RouteRuleCase, err error) {
+
+	err = rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		tmp := *old
+		if err := rm.routeCaseCheck(ctx, product, routeCaseMerge(&tmp, param)); err != nil {
+			return err
+		}
+
+		if err := rm.routeCaseStorager.UpdateRouteCase(ctx, product, old, param); err != nil {
+			return err
+		}
+
+		if rc, err = rm.fetchRouteCase(ctx, product, old.ID); err != nil {
+			return err
+		}
+
+		return rm.recordRouteCaseAudit(ctx, product, iaudit.ActionUpdate, old, rc)
+	})
+
+	return
+}
+
+func (rm *RouteRuleManager) DeleteRouteCase(ctx context.Context, product *ibasic.Product, rc *RouteRuleCase) error {
+	return rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := rm.routeCaseStorager.DeleteRouteCase(ctx, product, rc); err != nil {
+			return err
+		}
+
+		return rm.recordRouteCaseAudit(ctx, product, iaudit.ActionDelete, rc, nil)
+	})
+}
+
+func (rm *RouteRuleManager) recordRouteCaseAudit(ctx context.Context, product *ibasic.Product, action string,
+	before, after *RouteRuleCase) error {
+
+	param := &iaudit.AuditParam{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		ResourceType: iaudit.ResourceRouteCase,
+		Action:       action,
+	}
+	if before != nil {
+		param.ResourceName = strconv.FormatInt(before.ID, 10)
+		param.Before = before
+	}
+	if after != nil {
+		param.ResourceName = strconv.FormatInt(after.ID, 10)
+		param.After = after
+	}
+
+	return rm.auditManager.Record(ctx, param)
+}
+
+func (rm *RouteRuleManager) fetchRouteCase(ctx context.Context, product *ibasic.Product, id int64) (*RouteRuleCase, error) {
+	list, err := rm.routeCaseStorager.FetchRouteCases(ctx, &RouteCaseFilter{
+		Product: product,
+		ID:      &id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, xerror.WrapRecordNotExist("Route Case")
+	}
+
+	return list[0], nil
+}
+
+func routeCaseMerge(rc *RouteRuleCase, param *RouteCaseParam) *RouteRuleCase {
+	if param.Description != nil {
+		rc.Description = *param.Description
+	}
+	if param.URL != nil {
+		rc.URL = *param.URL
+	}
+	if param.Method != nil {
+		rc.Method = *param.Method
+	}
+	if param.Header != nil {
+		rc.Header = param.Header
+	}
+	if param.ExpectCluster != nil {
+		rc.ExpectCluster = *param.ExpectCluster
+	}
+
+	return rc
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iroute_conf
+
+import (
+	"testing"
+
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+)
+
+// TestRouteRuleRunnerMatch follow the order of bfe_route.HostTable.LookupCluster:
+// basic rules are matched by host and path first, rules of advanced mode and requests
+// not matching any basic rule fall through to advanced rules, which are matched in order
+func TestRouteRuleRunnerMatch(t *testing.T) {
+	rule := &ProductRouteRule{
+		BasicRouteRules: []*BasicRouteRule{
+			{HostNames: []string{"example.org"}, Paths: []string{"/static/*"}, ClusterName: "static"},
+			{HostNames: []string{"example.org"}, Paths: []string{"/login"}, ClusterName: "login"},
+			{HostNames: []string{"example.org"}, ClusterName: icluster_conf.RouteAdvancedModeClusterName4DP},
+			{HostNames: []string{"*.example.com"}, ClusterName: "wildcard"},
+		},
+		AdvanceRouteRules: []*AdvanceRouteRule{
+			{Name: "canary", Expression: `req_header_value_in("X-Canary", "1", false)`, ClusterName: "canary"},
+			{Name: "legacy", Expression: `req_path_prefix_in("/old", false)`, ClusterName: "legacy"},
+			{Name: "default", Expression: "default_t()", ClusterName: "default"},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		url     string
+		header  map[string]string
+		cluster string
+		basic   bool
+	}{
+		{name: "host and path prefix", url: "http://example.org/static/a.js", cluster: "static", basic: true},
+		{name: "host and exact path", url: "http://example.org/login", cluster: "login", basic: true},
+		{name: "port of host ignored", url: "http://example.org:8080/login", cluster: "login", basic: true},
+		{name: "wildcard host", url: "http://www.example.com/any", cluster: "wildcard", basic: true},
+		{
+			name:    "basic rule before advanced rules",
+			url:     "http://www.example.com/old",
+			header:  map[string]string{"X-Canary": "1"},
+			cluster: "wildcard",
+			basic:   true,
+		},
+		{name: "advanced mode of host", url: "http://example.org/old/page", cluster: "legacy"},
+		{
+			name:    "advanced rules in order",
+			url:     "http://example.org/old/page",
+			header:  map[string]string{"X-Canary": "1"},
+			cluster: "canary",
+		},
+		{name: "host not in basic rules", url: "http://other.org/old", cluster: "legacy"},
+		{name: "default cluster", url: "http://other.org/", cluster: "default"},
+	}
+
+	runner, err := NewRouteRuleRunner(rule)
+	if err != nil {
+		t.Fatalf("NewRouteRuleRunner: %v", err)
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, err := (&RouteDryRunParam{URL: c.url, Header: c.header}).Request()
+			if err != nil {
+				t.Fatalf("Request: %v", err)
+			}
+
+			mr := runner.Match(req)
+			if mr == nil {
+				t.Fatalf("Match: want cluster %s, got no match", c.cluster)
+			}
+			if mr.ClusterName != c.cluster {
+				t.Errorf("Match: want cluster %s, got %s", c.cluster, mr.ClusterName)
+			}
+			if got := mr.BasicRouteRule != nil; got != c.basic || got == (mr.AdvanceRouteRule != nil) {
+				t.Errorf("Match: want basic rule hit %v, got %+v", c.basic, mr)
+			}
+		})
+	}
+}
+
+func TestRouteRuleRunnerNoMatch(t *testing.T) {
+	cases := []struct {
+		name string
+		rule *ProductRouteRule
+	}{
+		{name: "no rule", rule: nil},
+		{
+			name: "no rule matches",
+			rule: &ProductRouteRule{
+				BasicRouteRules: []*BasicRouteRule{
+					{HostNames: []string{"example.org"}, ClusterName: "basic"},
+				},
+				AdvanceRouteRules: []*AdvanceRouteRule{
+					{Name: "legacy", Expression: `req_path_prefix_in("/old", false)`, ClusterName: "legacy"},
+				},
+			},
+		},
+		{
+			name: "advanced mode without advanced rule matching",
+			rule: &ProductRouteRule{
+				BasicRouteRules: []*BasicRouteRule{
+					{HostNames: []string{"other.org"}, ClusterName: icluster_conf.RouteAdvancedModeClusterName4DP},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			runner, err := NewRouteRuleRunner(c.rule)
+			if err != nil {
+				t.Fatalf("NewRouteRuleRunner: %v", err)
+			}
+
+			req, err := (&RouteDryRunParam{URL: "http://other.org/new"}).Request()
+			if err != nil {
+				t.Fatalf("Request: %v", err)
+			}
+			if mr := runner.Match(req); mr != nil {
+				t.Errorf("Match: want no match, got %+v", mr)
+			}
+		})
+	}
+}
+
+func TestRouteRuleRunnerInvalid(t *testing.T) {
+	_, err := NewRouteRuleRunner(&ProductRouteRule{
+		AdvanceRouteRules: []*AdvanceRouteRule{
+			{Name: "bad", Expression: "req_host_in(", ClusterName: "bad"},
+		},
+	})
+	if err == nil {
+		t.Fatalf("NewRouteRuleRunner: want error for invalid expression")
+	}
+}
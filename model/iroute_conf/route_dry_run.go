@@ -0,0 +1,105 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iroute_conf
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/bfenetworks/bfe/bfe_basic"
+	"github.com/bfenetworks/bfe/bfe_http"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+)
+
+// RouteDryRunParam a synthetic request to be matched with route rule
+type RouteDryRunParam struct {
+	URL      string
+	Method   string
+	Header   map[string]string
+	Cookies  map[string]string
+	ClientIP string
+
+	// Rule to be evaluated, the rule stored will be used if nil
+	Rule *ProductRouteRule
+}
+
+func newRouteRequest(url, method string, header map[string]string) (*bfe_basic.Request, error) {
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := lib.ReqFactory(url, header, nil, method)
+	if err != nil {
+		return nil, xerror.WrapParamErrorWithMsg("URL %s Invalid: %v", url, err)
+	}
+
+	return req, nil
+}
+
+// Request build bfe request from dry run param
+func (p *RouteDryRunParam) Request() (*bfe_basic.Request, error) {
+	req, err := newRouteRequest(p.URL, p.Method, p.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range p.Cookies {
+		req.HttpRequest.AddCookie(&bfe_http.Cookie{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	if p.ClientIP != "" {
+		ip := net.ParseIP(p.ClientIP)
+		if ip == nil {
+			return nil, xerror.WrapParamErrorWithMsg("Client IP %s Invalid", p.ClientIP)
+		}
+		req.ClientAddr = &net.TCPAddr{IP: ip}
+		req.Session.RemoteAddr = req.ClientAddr
+		req.RemoteAddr = req.ClientAddr
+	}
+
+	return req, nil
+}
+
+// RouteDryRun return the rule and cluster hit by the request, nil if no rule be hit
+func (rm *RouteRuleManager) RouteDryRun(ctx context.Context, product *ibasic.Product,
+	param *RouteDryRunParam) (mr *RouteRuleMatchResult, err error) {
+
+	req, err := param.Request()
+	if err != nil {
+		return nil, err
+	}
+
+	rule := param.Rule
+	if rule == nil {
+		rule, err = rm.FetchProductRule(ctx, product)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	runner, err := NewRouteRuleRunner(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return runner.Match(req), nil
+}
@@ -16,6 +16,9 @@ package iroute_conf
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -24,6 +27,7 @@ import (
 
 	"github.com/bfenetworks/api-server/lib"
 	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/icluster_conf"
 	"github.com/bfenetworks/api-server/model/itxn"
@@ -48,6 +52,7 @@ type AdvanceRouteRule struct {
 }
 
 type RouteRuleCase struct {
+	ID            int64
 	Description   string
 	URL           string
 	Method        string
@@ -62,6 +67,47 @@ type ProductRouteRule struct {
 	RouteCases []*RouteRuleCase
 }
 
+// Revision return hash of route rules, it changes when route rules be updated
+func (prr *ProductRouteRule) Revision() string {
+	type basicRule struct {
+		HostNames   []string
+		Paths       []string
+		ClusterName string
+		Description string
+	}
+	type advanceRule struct {
+		Name        string
+		Description string
+		Expression  string
+		ClusterName string
+	}
+
+	basicRules, advanceRules := []basicRule{}, []advanceRule{}
+	if prr != nil {
+		for _, one := range prr.BasicRouteRules {
+			basicRules = append(basicRules, basicRule{
+				HostNames:   one.HostNames,
+				Paths:       one.Paths,
+				ClusterName: one.ClusterName,
+				Description: one.Description,
+			})
+		}
+		for _, one := range prr.AdvanceRouteRules {
+			advanceRules = append(advanceRules, advanceRule{
+				Name:        one.Name,
+				Description: one.Description,
+				Expression:  one.Expression,
+				ClusterName: one.ClusterName,
+			})
+		}
+	}
+
+	bs, _ := json.Marshal([]interface{}{basicRules, advanceRules})
+	sum := md5.Sum(bs)
+
+	return hex.EncodeToString(sum[:])
+}
+
 type HostUsedInfo struct {
 	Type   string
 	Detail string
@@ -141,6 +187,8 @@ func (pfr *ProductRouteRule) Convert() (*ProductRouteRuleConvertResult, error) {
 }
 
 type RouteRuleStorager interface {
+	// LockProductRule lock route rules of product until transaction be finished
+	LockProductRule(ctx context.Context, product *ibasic.Product) error
 	UpsertProductRule(ctx context.Context, product *ibasic.Product, rule *ProductRouteRule) error
 	FetchProductRule(ctx context.Context, product *ibasic.Product,
 		clusterList []*icluster_conf.Cluster) (*ProductRouteRule, error)
@@ -150,7 +198,8 @@ type RouteRuleStorager interface {
 
 func NewRouteRuleManager(txn itxn.TxnStorager, storager RouteRuleStorager, clusterStorager icluster_conf.ClusterStorager,
 	productStorager ibasic.ProductStorager, versionControlManager *iversion_control.VersionControlManager,
-	domainStorager DomainStorager) *RouteRuleManager {
+	domainStorager DomainStorager, routeCaseStorager RouteCaseStorager,
+	auditManager *iaudit.AuditManager) *RouteRuleManager {
 	return &RouteRuleManager{
 		txn:             txn,
 		storager:        storager,
@@ -159,6 +208,8 @@ func NewRouteRuleManager(txn itxn.TxnStorager, storager RouteRuleStorager, clust
 
 		versionControlManager: versionControlManager,
 		domainStorager:        domainStorager,
+		routeCaseStorager:     routeCaseStorager,
+		auditManager:          auditManager,
 	}
 }
 
@@ -170,6 +221,9 @@ type RouteRuleManager struct {
 	clusterStorager icluster_conf.ClusterStorager
 	productStorager ibasic.ProductStorager
 	domainStorager  DomainStorager
+
+	routeCaseStorager RouteCaseStorager
+	auditManager      *iaudit.AuditManager
 }
 
 func (rm *RouteRuleManager) ExpressionVerify(ctx context.Context, expression string) (err error) {
@@ -179,37 +233,58 @@ func (rm *RouteRuleManager) ExpressionVerify(ctx context.Context, expression str
 
 func (rm *RouteRuleManager) FetchProductRule(ctx context.Context, product *ibasic.Product) (prr *ProductRouteRule, err error) {
 	err = rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
-		clusters, err := rm.clusterStorager.FetchClusterList(ctx, &icluster_conf.ClusterFilter{
-			Product: product,
-		})
-		if err != nil {
-			return err
-		}
-
-		clusters = icluster_conf.AppendAdvancedRuleCluster(clusters)
+		prr, err = rm.fetchProductRule(ctx, product)
+		return err
+	})
 
-		m, err := rm.storager.FetchRoutRules(ctx, []*ibasic.Product{product}, clusters)
-		if err != nil {
-			return err
-		}
-		prr = m[product.ID]
+	return
+}
 
-		return nil
+func (rm *RouteRuleManager) fetchProductRule(ctx context.Context, product *ibasic.Product) (*ProductRouteRule, error) {
+	clusters, err := rm.clusterStorager.FetchClusterList(ctx, &icluster_conf.ClusterFilter{
+		Product: product,
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return
+	clusters = icluster_conf.AppendAdvancedRuleCluster(clusters)
+
+	m, err := rm.storager.FetchRoutRules(ctx, []*ibasic.Product{product}, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	return m[product.ID], nil
 }
 
-func (rm *RouteRuleManager) UpsertProductRule(ctx context.Context, product *ibasic.Product, rule *ProductRouteRule) error {
+// UpsertProductRule update product route rule, route cases of product must pass with the new rule,
+// if revision is not empty, it must be equal to revision of route rule in storage
+func (rm *RouteRuleManager) UpsertProductRule(ctx context.Context, product *ibasic.Product,
+	rule *ProductRouteRule, revision string) (caseResults []*RouteRuleRunCaseResult, err error) {
+
 	cr, err := rule.Convert()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var clusterList []*icluster_conf.Cluster
 	var clusterMap map[string]*icluster_conf.Cluster
 
 	err = rm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := rm.storager.LockProductRule(ctx, product); err != nil {
+			return err
+		}
+
+		old, err := rm.fetchProductRule(ctx, product)
+		if err != nil {
+			return err
+		}
+		if revision != "" && revision != old.Revision() {
+			return xerror.WrapPreconditionFailedErrorWithMsg("Route Rule Of Product %s Has Been Changed, Revision Is %s",
+				product.Name, old.Revision())
+		}
+
 		// verify cluster
 		if referClusters := cr.ReferClusterNames; len(referClusters) > 0 {
 			clusterList, err = rm.clusterStorager.FetchClusterList(ctx, &icluster_conf.ClusterFilter{
@@ -247,14 +322,36 @@ func (rm *RouteRuleManager) UpsertProductRule(ctx context.Context, product *ibas
 			one.ClusterID = clusterMap[one.ClusterName].ID
 		}
 
+		caseResults, err = rm.runRouteCases(ctx, product, rule)
+		if err != nil {
+			return err
+		}
+		for _, one := range caseResults {
+			if !one.Pass {
+				return xerror.WrapModelErrorWithMsg("Route Case %s Fail, URL: %s, Expect Cluster: %s, Actual Cluster: %s",
+					one.Description, one.URL, one.ExpectCluster, one.ActualCluster)
+			}
+		}
+
 		if err := rm.storager.UpsertProductRule(ctx, product, rule); err != nil {
 			return err
 		}
 
-		return nil
+		return rm.auditManager.Record(ctx, &iaudit.AuditParam{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ResourceType: iaudit.ResourceRouteRule,
+			ResourceName: product.Name,
+			Action:       iaudit.ActionUpdate,
+			Before:       old,
+			After:        rule,
+		})
 	})
+	if err == nil {
+		rm.versionControlManager.NotifyChange(ctx, ConfigTopicRouteRule)
+	}
 
-	return err
+	return
 }
 
 func (rm *RouteRuleManager) ClusterDeleteChecker(ctx context.Context, product *ibasic.Product, cluster *icluster_conf.Cluster) error {
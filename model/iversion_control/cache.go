@@ -0,0 +1,122 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iversion_control
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+)
+
+// exportCall is an in-flight or completed generation of a topic
+type exportCall struct {
+	// finished be closed when generation finished
+	finished chan struct{}
+
+	data *ExportData
+	err  error
+
+	done bool
+	// expireAt zero means never expire
+	expireAt time.Time
+}
+
+func (call *exportCall) alive(now time.Time) bool {
+	return !call.done || call.expireAt.IsZero() || now.Before(call.expireAt)
+}
+
+// ExportCache cache last exported data of topics
+// concurrent exports of the same topic share one generation
+// cached data be dropped when topic be invalidated or ttl passed
+type ExportCache struct {
+	lock  sync.Mutex
+	calls map[string]*exportCall
+}
+
+func NewExportCache() *ExportCache {
+	return &ExportCache{
+		calls: map[string]*exportCall{},
+	}
+}
+
+// Get return cached data of topic, or call generator to generate it
+// ttl <= 0 means cached data will only be dropped by Invalidate
+// generation is shared by callers and runs on a context detached from theirs, so it won't be canceled by any of them,
+// caller returns ctx.Err() if ctx is done before generation finished.
+// failed generation is only returned to the caller starting it, other callers waiting for it generate again
+func (c *ExportCache) Get(ctx context.Context, topic string, ttl time.Duration,
+	generator func(ctx context.Context) (*ExportData, error)) (*ExportData, error) {
+
+	for {
+		c.lock.Lock()
+		call, ok := c.calls[topic]
+		started := false
+		if !ok || !call.alive(time.Now()) {
+			call = &exportCall{finished: make(chan struct{})}
+			c.calls[topic] = call
+			started = true
+			go c.generate(lib.DetachLogContext(ctx), topic, ttl, call, generator)
+		}
+		c.lock.Unlock()
+
+		select {
+		case <-call.finished:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if call.err == nil || started {
+			return call.data, call.err
+		}
+		// failed call has been removed, a new generation will be started or shared
+	}
+}
+
+func (c *ExportCache) generate(ctx context.Context, topic string, ttl time.Duration, call *exportCall,
+	generator func(ctx context.Context) (*ExportData, error)) {
+
+	call.data, call.err = generator(ctx)
+
+	c.lock.Lock()
+	call.done = true
+	if ttl > 0 {
+		call.expireAt = time.Now().Add(ttl)
+	}
+	// call may be invalidated during generation, it has been removed already
+	if call.err != nil && c.calls[topic] == call {
+		delete(c.calls, topic)
+	}
+	c.lock.Unlock()
+	close(call.finished)
+}
+
+// Invalidate drop cached data of topics, in-flight generations will not be shared any more
+// sub topics will be dropped too, eg: gslb.{bfe_cluster} will be dropped by gslb
+func (c *ExportCache) Invalidate(topics ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for cacheTopic := range c.calls {
+		for _, topic := range topics {
+			if cacheTopic == topic || strings.HasPrefix(cacheTopic, topic+".") {
+				delete(c.calls, cacheTopic)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iversion_control
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExportCacheDetached(t *testing.T) {
+	cache := NewExportCache()
+
+	release := make(chan struct{})
+	generator := func(ctx context.Context) (*ExportData, error) {
+		<-release
+		return &ExportData{Topic: "route_conf"}, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := cache.Get(ctx, "route_conf", 0, generator)
+		first <- err
+	}()
+
+	waiter := make(chan *ExportData)
+	go func() {
+		// wait for the generation started by the first caller
+		for {
+			cache.lock.Lock()
+			_, ok := cache.calls["route_conf"]
+			cache.lock.Unlock()
+			if ok {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		data, _ := cache.Get(context.Background(), "route_conf", 0, generator)
+		waiter <- data
+	}()
+
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Fatalf("Get: want canceled caller return context.Canceled, got %v", err)
+	}
+
+	close(release)
+	if data := <-waiter; data == nil || data.Topic != "route_conf" {
+		t.Fatalf("Get: want waiter get data generated on detached context, got %v", data)
+	}
+}
+
+func TestExportCacheErrorNotShared(t *testing.T) {
+	cache := NewExportCache()
+
+	errGen := errors.New("generate fail")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	generator := func(ctx context.Context) (*ExportData, error) {
+		calls++
+		if calls == 1 {
+			close(started)
+			<-release
+			return nil, errGen
+		}
+		return &ExportData{Topic: "route_conf"}, nil
+	}
+
+	first := make(chan error)
+	go func() {
+		_, err := cache.Get(context.Background(), "route_conf", 0, generator)
+		first <- err
+	}()
+	<-started
+
+	waiter := make(chan error)
+	go func() {
+		_, err := cache.Get(context.Background(), "route_conf", 0, generator)
+		waiter <- err
+	}()
+
+	// let waiter wait for the failed generation
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if err := <-first; err != errGen {
+		t.Fatalf("Get: want error of generation, got %v", err)
+	}
+	if err := <-waiter; err != nil {
+		t.Fatalf("Get: want waiter generate again, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("Get: want 2 generations, got %d", calls)
+	}
+}
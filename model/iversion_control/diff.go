@@ -0,0 +1,189 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iversion_control
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+)
+
+type DiffAction string
+
+const (
+	DiffActionAdded    DiffAction = "added"
+	DiffActionRemoved  DiffAction = "removed"
+	DiffActionModified DiffAction = "modified"
+)
+
+// DiffEntry one changed item between two versions
+type DiffEntry struct {
+	Resource string
+	Key      string
+	Action   DiffAction
+	Old      interface{}
+	New      interface{}
+}
+
+type ConfigDiff struct {
+	Topic      string
+	OldVersion string
+	NewVersion string
+
+	Entries []*DiffEntry
+}
+
+// Diffable exported data which can be compared with data of other version
+type Diffable interface {
+	VersionValuable
+
+	// Diff return changes from old to the data
+	Diff(old Diffable) []*DiffEntry
+}
+
+// DiffMap compare items with the same key, the result is sorted by key
+func DiffMap(resource string, old, new map[string]interface{}) []*DiffEntry {
+	keys := map[string]bool{}
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	entries := []*DiffEntry{}
+	for _, k := range sortedKeys {
+		o, inOld := old[k]
+		n, inNew := new[k]
+
+		entry := &DiffEntry{
+			Resource: resource,
+			Key:      k,
+			Old:      o,
+			New:      n,
+		}
+		switch {
+		case !inOld:
+			entry.Action = DiffActionAdded
+		case !inNew:
+			entry.Action = DiffActionRemoved
+		case !reflect.DeepEqual(o, n):
+			entry.Action = DiffActionModified
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func (vcm *VersionControlManager) versionData(ctx context.Context, topic, version string,
+	newData func() Diffable) (Diffable, error) {
+
+	cv, err := vcm.fetchConfigVersion(ctx, topic, version, true)
+	if err != nil {
+		return nil, err
+	}
+	if cv.Snapshot == nil {
+		return nil, xerror.WrapModelErrorWithMsg("Topic %s Version %s Has No Snapshot", topic, version)
+	}
+
+	bs, err := Decompress(cv.Snapshot)
+	if err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Topic %s Version %s Snapshot Invalid: %v", topic, version, err)
+	}
+
+	data := newData()
+	if err := json.Unmarshal(bs, data); err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Topic %s Version %s Snapshot Invalid: %v", topic, version, err)
+	}
+
+	// version is not a change
+	if err := data.UpdateVersion(ZeroVersion); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DiffConfig compare two versions of topic
+// if newVersion is empty, the latest version is used
+// if oldVersion is empty, the version previous to newVersion is used
+func (vcm *VersionControlManager) DiffConfig(ctx context.Context, topic, oldVersion, newVersion string,
+	newData func() Diffable) (diff *ConfigDiff, err error) {
+
+	err = vcm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		if oldVersion == "" || newVersion == "" {
+			// sorted by version desc
+			versions, err := vcm.storager.FetchConfigVersions(ctx, &ConfigVersionFilter{
+				Name: &topic,
+			})
+			if err != nil {
+				return err
+			}
+
+			if newVersion == "" {
+				if len(versions) == 0 {
+					return xerror.WrapRecordNotExist("Config Version")
+				}
+				newVersion = versions[0].Version
+			}
+
+			if oldVersion == "" {
+				for i, one := range versions {
+					if one.Version == newVersion && i+1 < len(versions) {
+						oldVersion = versions[i+1].Version
+						break
+					}
+				}
+				if oldVersion == "" {
+					return xerror.WrapModelErrorWithMsg("Topic %s Has No Version Before %s", topic, newVersion)
+				}
+			}
+		}
+
+		oldData, err := vcm.versionData(ctx, topic, oldVersion, newData)
+		if err != nil {
+			return err
+		}
+
+		newOne, err := vcm.versionData(ctx, topic, newVersion, newData)
+		if err != nil {
+			return err
+		}
+
+		diff = &ConfigDiff{
+			Topic:      topic,
+			OldVersion: oldVersion,
+			NewVersion: newVersion,
+			Entries:    newOne.Diff(oldData),
+		}
+
+		return nil
+	})
+
+	return
+}
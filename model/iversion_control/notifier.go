@@ -0,0 +1,146 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iversion_control
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeNotifier wake up waiters of topic when config of topic may be changed
+// it works in process, waiters in other process will not be notified
+type ChangeNotifier struct {
+	lock    sync.Mutex
+	waiters map[string]chan struct{}
+}
+
+func NewChangeNotifier() *ChangeNotifier {
+	return &ChangeNotifier{
+		waiters: map[string]chan struct{}{},
+	}
+}
+
+// Watch return a channel which will be closed when topic be notified next time
+func (n *ChangeNotifier) Watch(topic string) <-chan struct{} {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	ch, ok := n.waiters[topic]
+	if !ok {
+		ch = make(chan struct{})
+		n.waiters[topic] = ch
+	}
+
+	return ch
+}
+
+// Notify wake up waiters of topics,
+// sub topics will be notified too, eg: waiters of gslb.{bfe_cluster} will be notified by gslb
+func (n *ChangeNotifier) Notify(topics ...string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for waitTopic, ch := range n.waiters {
+		for _, topic := range topics {
+			if waitTopic == topic || strings.HasPrefix(waitTopic, topic+".") {
+				close(ch)
+				delete(n.waiters, waitTopic)
+				break
+			}
+		}
+	}
+}
+
+type deferredTopicsKey struct{}
+
+type deferredTopics struct {
+	topics []string
+}
+
+// DeferNotify return a context in which NotifyChange only collects topics,
+// it's used when changes of several managers are made in one outer transaction,
+// topics collected are notified by NotifyDeferred after the outer transaction be committed
+func DeferNotify(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deferredTopicsKey{}, &deferredTopics{})
+}
+
+// NotifyChange drop cached export data and wake up waiters of topics,
+// it should be called after changes be committed
+func (vcm *VersionControlManager) NotifyChange(ctx context.Context, topics ...string) {
+	if deferred, ok := ctx.Value(deferredTopicsKey{}).(*deferredTopics); ok {
+		deferred.topics = append(deferred.topics, topics...)
+		return
+	}
+
+	vcm.notify(topics...)
+}
+
+// NotifyDeferred notify topics collected in ctx returned by DeferNotify, each topic is notified once
+func (vcm *VersionControlManager) NotifyDeferred(ctx context.Context) {
+	deferred, ok := ctx.Value(deferredTopicsKey{}).(*deferredTopics)
+	if !ok || len(deferred.topics) == 0 {
+		return
+	}
+
+	topics := []string{}
+	existed := map[string]bool{}
+	for _, topic := range deferred.topics {
+		if !existed[topic] {
+			existed[topic] = true
+			topics = append(topics, topic)
+		}
+	}
+	deferred.topics = nil
+
+	vcm.notify(topics...)
+}
+
+func (vcm *VersionControlManager) notify(topics ...string) {
+	vcm.cache.Invalidate(topics...)
+	vcm.notifier.Notify(topics...)
+}
+
+// WaitChange call changed until it returns true, or wait timeout, or ctx done
+// changed will be called again only after topic be notified
+func (vcm *VersionControlManager) WaitChange(ctx context.Context, topic string, wait time.Duration,
+	changed func(context.Context) (bool, error)) error {
+
+	var timeout <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		// watch before check, changes between check and wait will not be lost
+		ch := vcm.notifier.Watch(topic)
+
+		ok, err := changed(ctx)
+		if err != nil || ok || timeout == nil {
+			return err
+		}
+
+		select {
+		case <-ch:
+		case <-timeout:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iversion_control
+
+import (
+	"context"
+	"testing"
+)
+
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestNotifyDeferred(t *testing.T) {
+	vcm := NewVersionControllerManager(nil, nil, nil)
+
+	ch := vcm.notifier.Watch("route_conf")
+	ctx := DeferNotify(context.Background())
+	vcm.NotifyChange(ctx, "route_conf")
+	vcm.NotifyChange(ctx, "route_conf", "cluster_conf")
+	if closed(ch) {
+		t.Fatalf("NotifyChange: want topic not notified before NotifyDeferred")
+	}
+
+	vcm.NotifyDeferred(ctx)
+	if !closed(ch) {
+		t.Fatalf("NotifyDeferred: want topic notified")
+	}
+
+	ch = vcm.notifier.Watch("route_conf")
+	vcm.NotifyDeferred(ctx)
+	if closed(ch) {
+		t.Fatalf("NotifyDeferred: want topics notified only once")
+	}
+
+	vcm.NotifyChange(context.Background(), "route_conf")
+	if !closed(ch) {
+		t.Fatalf("NotifyChange: want topic notified at once without DeferNotify")
+	}
+}
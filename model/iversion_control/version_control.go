@@ -15,13 +15,19 @@
 package iversion_control
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"time"
 
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/itxn"
+	"github.com/bfenetworks/api-server/stateful"
 )
 
 func Version(t time.Time) string {
@@ -38,12 +44,44 @@ func Sign(data interface{}) (string, error) {
 	return fmt.Sprintf("%x", md5.Sum(bs)), nil
 }
 
+// Compress marshal data to json and compress it with gzip
+func Compress(data interface{}) ([]byte, error) {
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(bs); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decompress return json data compressed by Compress
+func Decompress(snapshot []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(snapshot))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
 type ExportData struct {
 	Topic              string
 	DataWithoutVersion VersionValuable
 
 	version                string
 	DataSignWithoutVersion string
+
+	snapshot []byte
 }
 
 type VersionValuable interface {
@@ -54,37 +92,118 @@ func (cs *ExportData) Version() string {
 	return cs.version
 }
 
+// CalculateVersion create new version and snapshot of data with the version
 func (cs *ExportData) CalculateVersion() (string, error) {
 	cs.version = Version(time.Now())
+
+	if err := cs.DataWithoutVersion.UpdateVersion(cs.version); err != nil {
+		return "", err
+	}
+
+	snapshot, err := Compress(cs.DataWithoutVersion)
+	if err != nil {
+		return "", xerror.WrapModelError(err)
+	}
+	cs.snapshot = snapshot
+
 	return cs.version, nil
 }
 
+// Snapshot return compressed data, only available after CalculateVersion
+func (cs *ExportData) Snapshot() []byte {
+	return cs.snapshot
+}
+
+type ConfigVersion struct {
+	ID        int64
+	Name      string
+	DataSign  string
+	Version   string
+	CreatedAt time.Time
+
+	// Snapshot compressed exported data, may be nil for versions exported before snapshot supported
+	Snapshot []byte
+}
+
+type ConfigVersionFilter struct {
+	Name    *string
+	Version *string
+
+	// WithSnapshot load snapshot if true
+	WithSnapshot bool
+}
+
+type ConfigPin struct {
+	Name      string
+	Version   string
+	UpdatedAt time.Time
+}
+
 type VersionControlStorager interface {
 
 	// UpsertConfigLastExportedVersion will got last export data
-	// if config changed, create new version and return
+	// if config changed, create new version and snapshot, return new version
 	// if not, return last version
 	UpsertConfigLastExportedVersion(ctx context.Context, css *ExportData) (string, error)
+
+	FetchConfigVersions(ctx context.Context, filter *ConfigVersionFilter) ([]*ConfigVersion, error)
+
+	FetchConfigPins(ctx context.Context, name *string) ([]*ConfigPin, error)
+	UpsertConfigPin(ctx context.Context, pin *ConfigPin) error
+	DeleteConfigPin(ctx context.Context, name string) error
 }
 
 type VersionControlManager struct {
-	storager VersionControlStorager
-	txn      itxn.TxnStorager
+	storager     VersionControlStorager
+	txn          itxn.TxnStorager
+	auditManager *iaudit.AuditManager
+	notifier     *ChangeNotifier
+	cache        *ExportCache
 }
 
-func NewVersionControllerManager(txn itxn.TxnStorager, storager VersionControlStorager) *VersionControlManager {
+func NewVersionControllerManager(txn itxn.TxnStorager, storager VersionControlStorager,
+	auditManager *iaudit.AuditManager) *VersionControlManager {
 	return &VersionControlManager{
-		storager: storager,
-		txn:      txn,
+		storager:     storager,
+		txn:          txn,
+		auditManager: auditManager,
+		notifier:     NewChangeNotifier(),
+		cache:        NewExportCache(),
 	}
 }
 
 type ConfigGenerator func(ctx context.Context) (*ExportData, error)
 
+// ExportConfig generate config of topic and return it with version
+// if topic be pinned, snapshot of pinned version will be decoded with newData and returned
+// result will be cached until topic be notified or cache ttl passed, returned data must not be modified
 func (vcm *VersionControlManager) ExportConfig(ctx context.Context, configTopic string,
-	generaotr ConfigGenerator) (lrv *ExportData, err error) {
+	generaotr ConfigGenerator, newData func() VersionValuable) (*ExportData, error) {
+
+	cacheConf := stateful.DefaultConfig.RunTime.ExportCache
+	if cacheConf.Disable {
+		return vcm.exportConfig(ctx, configTopic, generaotr, newData)
+	}
+
+	return vcm.cache.Get(ctx, configTopic, time.Duration(cacheConf.TTLInS)*time.Second,
+		func(ctx context.Context) (*ExportData, error) {
+			return vcm.exportConfig(ctx, configTopic, generaotr, newData)
+		})
+}
+
+func (vcm *VersionControlManager) exportConfig(ctx context.Context, configTopic string,
+	generaotr ConfigGenerator, newData func() VersionValuable) (lrv *ExportData, err error) {
 
 	err = vcm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		pins, err := vcm.storager.FetchConfigPins(ctx, &configTopic)
+		if err != nil {
+			return err
+		}
+		if len(pins) > 0 {
+			lrv, err = vcm.pinnedConfig(ctx, configTopic, pins[0].Version, newData)
+			return err
+		}
+
 		lrv, err = generaotr(ctx)
 		if err != nil {
 			return err
@@ -109,3 +228,165 @@ func (vcm *VersionControlManager) ExportConfig(ctx context.Context, configTopic
 
 	return
 }
+
+func (vcm *VersionControlManager) fetchConfigVersion(ctx context.Context, topic, version string,
+	withSnapshot bool) (*ConfigVersion, error) {
+
+	list, err := vcm.storager.FetchConfigVersions(ctx, &ConfigVersionFilter{
+		Name:         &topic,
+		Version:      &version,
+		WithSnapshot: withSnapshot,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, xerror.WrapRecordNotExist("Config Version")
+	}
+
+	return list[0], nil
+}
+
+func (vcm *VersionControlManager) pinnedConfig(ctx context.Context, topic, version string,
+	newData func() VersionValuable) (*ExportData, error) {
+
+	cv, err := vcm.fetchConfigVersion(ctx, topic, version, true)
+	if err != nil {
+		return nil, err
+	}
+	if cv.Snapshot == nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Topic %s Version %s Has No Snapshot", topic, version)
+	}
+
+	bs, err := Decompress(cv.Snapshot)
+	if err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Topic %s Version %s Snapshot Invalid: %v", topic, version, err)
+	}
+
+	data := newData()
+	if err := json.Unmarshal(bs, data); err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Topic %s Version %s Snapshot Invalid: %v", topic, version, err)
+	}
+
+	return &ExportData{
+		Topic:                  topic,
+		DataWithoutVersion:     data,
+		version:                cv.Version,
+		DataSignWithoutVersion: cv.DataSign,
+		snapshot:               cv.Snapshot,
+	}, nil
+}
+
+// ConfigVersionList list versions of topic, snapshot not included
+func (vcm *VersionControlManager) ConfigVersionList(ctx context.Context, topic string) (list []*ConfigVersion, err error) {
+	err = vcm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = vcm.storager.FetchConfigVersions(ctx, &ConfigVersionFilter{
+			Name: &topic,
+		})
+		return err
+	})
+
+	return
+}
+
+// ConfigSnapshot return the version and its decompressed exported data
+func (vcm *VersionControlManager) ConfigSnapshot(ctx context.Context, topic, version string) (cv *ConfigVersion, data []byte, err error) {
+	err = vcm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		cv, err = vcm.fetchConfigVersion(ctx, topic, version, true)
+		if err != nil {
+			return err
+		}
+		if cv.Snapshot == nil {
+			return xerror.WrapRecordNotExist("Config Snapshot")
+		}
+
+		data, err = Decompress(cv.Snapshot)
+		if err != nil {
+			return xerror.WrapDirtyDataErrorWithMsg("Topic %s Version %s Snapshot Invalid: %v", topic, version, err)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+func (vcm *VersionControlManager) ConfigPinList(ctx context.Context) (list []*ConfigPin, err error) {
+	err = vcm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		list, err = vcm.storager.FetchConfigPins(ctx, nil)
+		return err
+	})
+
+	return
+}
+
+// PinConfig make ExportConfig serve the snapshot of version instead of generating fresh data
+func (vcm *VersionControlManager) PinConfig(ctx context.Context, topic, version string) (err error) {
+	err = vcm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		cv, err := vcm.fetchConfigVersion(ctx, topic, version, true)
+		if err != nil {
+			return err
+		}
+		if cv.Snapshot == nil {
+			return xerror.WrapModelErrorWithMsg("Topic %s Version %s Has No Snapshot, Cant Be Pinned", topic, version)
+		}
+
+		pins, err := vcm.storager.FetchConfigPins(ctx, &topic)
+		if err != nil {
+			return err
+		}
+
+		pin := &ConfigPin{
+			Name:    topic,
+			Version: version,
+		}
+		if err = vcm.storager.UpsertConfigPin(ctx, pin); err != nil {
+			return err
+		}
+
+		param := &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceConfigPin,
+			ResourceName: topic,
+			Action:       iaudit.ActionCreate,
+			After:        pin,
+		}
+		if len(pins) > 0 {
+			param.Action = iaudit.ActionUpdate
+			param.Before = pins[0]
+		}
+		return vcm.auditManager.Record(ctx, param)
+	})
+	if err == nil {
+		vcm.NotifyChange(ctx, topic)
+	}
+
+	return
+}
+
+func (vcm *VersionControlManager) UnpinConfig(ctx context.Context, topic string) (err error) {
+	err = vcm.txn.AtomExecute(ctx, func(ctx context.Context) error {
+		pins, err := vcm.storager.FetchConfigPins(ctx, &topic)
+		if err != nil {
+			return err
+		}
+		if len(pins) == 0 {
+			return xerror.WrapRecordNotExist("Config Pin")
+		}
+
+		if err = vcm.storager.DeleteConfigPin(ctx, topic); err != nil {
+			return err
+		}
+
+		return vcm.auditManager.Record(ctx, &iaudit.AuditParam{
+			ResourceType: iaudit.ResourceConfigPin,
+			ResourceName: topic,
+			Action:       iaudit.ActionDelete,
+			Before:       pins[0],
+		})
+	})
+	if err == nil {
+		vcm.NotifyChange(ctx, topic)
+	}
+
+	return
+}
@@ -29,12 +29,31 @@ type ServerConfig struct {
 	GracefulTimeOutInMs int `validate:"required,min=1"` // time out setting for graceful shutdown
 }
 
+type PasswordPolicyConfig struct {
+	MinLength      int `validate:"min=1"`
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+type ExportCacheConfig struct {
+	Disable bool
+	TTLInS  int `validate:"min=0"` // 0 means cached data only be dropped when config changed
+}
+
 type RunTimeConfig struct {
-	SessionExpireInDay int  `validate:"required,min=1"`
-	SkipTokenValidate  bool // skip user identify, you can open it when debug
-	RecordSQL          bool
-	StaticFilePath     string
-	Debug              bool
+	SessionExpireInDay   int  `validate:"required,min=1"`
+	SkipTokenValidate    bool // skip user identify, you can open it when debug
+	SkipPasswordValidate bool // accept "SKIP" as password of any user, you can open it when debug
+	RecordSQL            bool
+	StaticFilePath       string
+	Debug                bool
+	PasswordPolicy       PasswordPolicyConfig
+	ExportCache          ExportCacheConfig
+
+	// ExportDisabledInstance export disabled instances to cluster table with weight 0, otherwise drop them
+	ExportDisabledInstance bool
 }
 
 type Config struct {
@@ -59,6 +78,12 @@ func LoadConfig(file string) error {
 		},
 		RunTime: RunTimeConfig{
 			StaticFilePath: "./static",
+			PasswordPolicy: PasswordPolicyConfig{
+				MinLength: 6,
+			},
+			ExportCache: ExportCacheConfig{
+				TTLInS: 10,
+			},
 		},
 		Vars: map[string]string{},
 		Databases: map[string]*DbConfig{
@@ -22,12 +22,20 @@ import (
 
 	"github.com/bfenetworks/api-server/lib"
 	"github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite3"
 )
 
 type DbConfig struct {
 	mysql.Config
 
-	Driver string `validate:"required,min=1"`
+	Driver string `validate:"required,oneof=mysql sqlite3"`
+	// File is path of database file when Driver is sqlite3, ":memory:" means in-memory database
+	File string
 
 	ConnMaxIdleTimeInMs int
 	ConnMaxLifetimeInMs int
@@ -35,8 +43,31 @@ type DbConfig struct {
 	MaxIdleConns      	int // max idle connections in database connection pool
 }
 
+// DSN return data source name of database
+func (dbConfig *DbConfig) DSN() string {
+	if dbConfig.Driver != DriverSQLite {
+		return dbConfig.FormatDSN()
+	}
+
+	// sqlite has no row lock, transaction acquire write lock when begin instead of "SELECT ... FOR UPDATE"
+	params := "_txlock=immediate&_busy_timeout=5000&_loc=auto"
+	if dbConfig.inMemory() {
+		return "file::memory:?" + params
+	}
+
+	return "file:" + dbConfig.File + "?_journal_mode=WAL&" + params
+}
+
+func (dbConfig *DbConfig) inMemory() bool {
+	return dbConfig.Driver == DriverSQLite && dbConfig.File == ":memory:"
+}
+
 func NewDB(dbConfig *DbConfig) (*sql.DB, error) {
-	db, err := sql.Open(dbConfig.Driver, dbConfig.FormatDSN())
+	if dbConfig.Driver == DriverSQLite && dbConfig.File == "" {
+		return nil, fmt.Errorf("File of sqlite3 database want be set")
+	}
+
+	db, err := sql.Open(dbConfig.Driver, dbConfig.DSN())
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +81,20 @@ func NewDB(dbConfig *DbConfig) (*sql.DB, error) {
 	db.SetConnMaxIdleTime(time.Duration(dbConfig.ConnMaxIdleTimeInMs) * time.Millisecond)
 	db.SetConnMaxLifetime(time.Duration(dbConfig.ConnMaxLifetimeInMs) * time.Millisecond)
 
+	// every connection has its own in-memory database, keep the only one forever
+	if dbConfig.inMemory() {
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxIdleTime(0)
+		db.SetConnMaxLifetime(0)
+	}
+
 	return db, nil
 }
 
 func (d *Config) InitDB() error {
 	tmp := map[string]*sql.DB{}
+	drivers := map[string]string{}
 
 	for name, dbConfig := range d.Databases {
 		db, err := NewDB(dbConfig)
@@ -62,15 +102,29 @@ func (d *Config) InitDB() error {
 			return err
 		}
 		tmp[name] = db
+		drivers[name] = dbConfig.Driver
 	}
 
 	DBs = tmp
+	dbDrivers = drivers
 
 	return nil
 }
 
 var DBs map[string]*sql.DB
 
+// dbDrivers driver of each database in DBs
+var dbDrivers map[string]string
+
+// DbDriver return driver of database, sql should be written in the dialect of it
+func DbDriver(name string) string {
+	return dbDrivers[name]
+}
+
+func BFEDBDriver() string {
+	return DbDriver("bfe_db")
+}
+
 func DbGet(name string) (*sql.DB, error) {
 	db, ok := DBs[name]
 	if !ok {
@@ -99,6 +153,9 @@ func BFEDB(db ...*sql.DB) (*sql.DB, error) {
 func NewBFEDBContext(ctx context.Context, ops ...*lib.Op) (*lib.DBContext, error) {
 	dc, ok := ctx.(*lib.DBContext)
 	if ok {
+		if lib.WantBlockWrite(ops...) {
+			return dc.WithBlockWrite(), nil
+		}
 		return dc, nil
 	}
 
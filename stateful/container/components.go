@@ -15,9 +15,12 @@
 package container
 
 import (
+	"github.com/bfenetworks/api-server/model/iaudit"
 	"github.com/bfenetworks/api-server/model/iauth"
 	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/ibundle"
 	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
 	"github.com/bfenetworks/api-server/model/iprotocol"
 	"github.com/bfenetworks/api-server/model/iroute_conf"
 	"github.com/bfenetworks/api-server/model/itxn"
@@ -28,6 +31,7 @@ var (
 	TxnStoragerSingleton            itxn.TxnStorager
 	VersionControlStoragerSingleton iversion_control.VersionControlStorager
 	RouteRuleStoragerSingleton      iroute_conf.RouteRuleStorager
+	RouteCaseStoragerSingleton      iroute_conf.RouteCaseStorager
 	ProductStoragerSingleton        ibasic.ProductStorager
 	BFEClusterStoragerSingleton     ibasic.BFEClusterStorager
 	DomainStoragerSingleton         iroute_conf.DomainStorager
@@ -38,6 +42,14 @@ var (
 	AuthenticateStoragerSingleton   iauth.AuthenticateStorager
 	AuthorizeStoragerSingleton      iauth.AuthorizeStorager
 	ExtraFileStoragerSingleton      ibasic.ExtraFileStorager
+	AuditStoragerSingleton          iaudit.AuditStorager
+
+	ActiveHealthCheckStoragerSingleton icluster_conf.ActiveHealthCheckStorager
+	HeaderRuleStoragerSingleton        imodule_conf.HeaderRuleStorager
+	RewriteRuleStoragerSingleton       imodule_conf.RewriteRuleStorager
+	RedirectRuleStoragerSingleton      imodule_conf.RedirectRuleStorager
+	BlockRuleStoragerSingleton         imodule_conf.BlockRuleStorager
+	TLSRuleStoragerSingleton           iprotocol.TLSRuleStorager
 
 	ExtraFileManager      *ibasic.ExtraFileManager
 	ProductManager        *ibasic.ProductManager
@@ -51,4 +63,13 @@ var (
 	AuthenticateManager   *iauth.AuthenticateManager
 	AuthorizeManager      *iauth.AuthorizeManager
 	PoolManager           *icluster_conf.PoolManager
+	AuditManager          *iaudit.AuditManager
+
+	ActiveHealthCheckManager *icluster_conf.ActiveHealthCheckManager
+	HeaderRuleManager        *imodule_conf.HeaderRuleManager
+	RewriteRuleManager       *imodule_conf.RewriteRuleManager
+	RedirectRuleManager      *imodule_conf.RedirectRuleManager
+	BlockRuleManager         *imodule_conf.BlockRuleManager
+	IPBlocklistManager       *imodule_conf.IPBlocklistManager
+	BundleManager            *ibundle.BundleManager
 )
@@ -0,0 +1,186 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/ibundle"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// InitManagers create managers with storager singletons, storagers must be set before it be called
+func InitManagers() {
+	AuditManager = iaudit.NewAuditManager(
+		TxnStoragerSingleton,
+		AuditStoragerSingleton)
+
+	VersionControlManager = iversion_control.NewVersionControllerManager(
+		TxnStoragerSingleton,
+		VersionControlStoragerSingleton,
+		AuditManager)
+	ExtraFileManager = ibasic.NewExtraFileManager(
+		ExtraFileStoragerSingleton,
+		VersionControlManager)
+
+	BFEClusterManager = ibasic.NewBFEClusterManager(
+		TxnStoragerSingleton,
+		BFEClusterStoragerSingleton,
+		VersionControlManager,
+		AuditManager)
+
+	CertificateManager = iprotocol.NewCertificateManager(
+		TxnStoragerSingleton,
+		CertificateStoragerSingleton,
+		VersionControlManager,
+		ExtraFileStoragerSingleton,
+		TLSRuleStoragerSingleton,
+		ProductStoragerSingleton,
+		DomainStoragerSingleton,
+		AuditManager)
+
+	ProductManager = ibasic.NewProductManager(
+		TxnStoragerSingleton,
+		ProductStoragerSingleton,
+		VersionControlManager,
+		AuditManager,
+		iroute_conf.ConfigTopicRouteRule,
+		icluster_conf.ConfigTopicClusterTable,
+		icluster_conf.ConfigTopicGSLB,
+		icluster_conf.ConfigTopicActiveHealthCheck,
+		imodule_conf.ConfigTopicRedirect,
+		imodule_conf.ConfigTopicHeader,
+		imodule_conf.ConfigTopicRewrite,
+		imodule_conf.ConfigTopicBlockRule,
+		imodule_conf.ConfigTopicIPBlocklist,
+		iprotocol.ConfigTopicServerCert)
+
+	RouteRuleManager = iroute_conf.NewRouteRuleManager(
+		TxnStoragerSingleton,
+		RouteRuleStoragerSingleton,
+		ClusterStoragerSingleton,
+		ProductStoragerSingleton,
+		VersionControlManager,
+		DomainStoragerSingleton,
+		RouteCaseStoragerSingleton,
+		AuditManager)
+
+	ClusterManager = icluster_conf.NewClusterManager(
+		TxnStoragerSingleton,
+		ClusterStoragerSingleton,
+		SubClusterStoragerSingleton,
+		BFEClusterStoragerSingleton,
+		VersionControlManager,
+		map[string]func(context.Context, *ibasic.Product, *icluster_conf.Cluster) error{
+			"rules": RouteRuleManager.ClusterDeleteChecker,
+		},
+		AuditManager)
+
+	SubClusterManager = icluster_conf.NewSubClusterManager(
+		TxnStoragerSingleton,
+		SubClusterStoragerSingleton,
+		ProductStoragerSingleton,
+		PoolStoragerSingleton,
+		ClusterStoragerSingleton,
+		VersionControlManager,
+		AuditManager)
+
+	DomainManager = iroute_conf.NewDomainManager(
+		TxnStoragerSingleton,
+		DomainStoragerSingleton,
+		RouteRuleManager,
+		AuditManager)
+
+	AuthenticateManager = iauth.NewAuthenticateManager(
+		TxnStoragerSingleton,
+		AuthenticateStoragerSingleton,
+		AuthorizeStoragerSingleton,
+		AuditManager,
+	)
+	AuthorizeManager = iauth.NewAuthorizeManager(
+		TxnStoragerSingleton,
+		AuthorizeStoragerSingleton,
+		AuditManager)
+
+	PoolManager = icluster_conf.NewPoolManager(
+		TxnStoragerSingleton,
+		PoolStoragerSingleton,
+		BFEClusterStoragerSingleton,
+		SubClusterStoragerSingleton,
+		VersionControlManager,
+		AuditManager)
+
+	ActiveHealthCheckManager = icluster_conf.NewActiveHealthCheckManager(
+		TxnStoragerSingleton,
+		ActiveHealthCheckStoragerSingleton,
+		ClusterStoragerSingleton,
+		VersionControlManager,
+		AuditManager)
+
+	HeaderRuleManager = imodule_conf.NewHeaderRuleManager(
+		TxnStoragerSingleton,
+		HeaderRuleStoragerSingleton,
+		ProductStoragerSingleton,
+		DomainManager,
+		VersionControlManager,
+		AuditManager)
+
+	RewriteRuleManager = imodule_conf.NewRewriteRuleManager(
+		TxnStoragerSingleton,
+		RewriteRuleStoragerSingleton,
+		ProductStoragerSingleton,
+		VersionControlManager,
+		AuditManager)
+
+	RedirectRuleManager = imodule_conf.NewRedirectRuleManager(
+		TxnStoragerSingleton,
+		RedirectRuleStoragerSingleton,
+		ProductStoragerSingleton,
+		DomainManager,
+		VersionControlManager,
+		AuditManager)
+
+	BlockRuleManager = imodule_conf.NewBlockRuleManager(
+		TxnStoragerSingleton,
+		BlockRuleStoragerSingleton,
+		ProductStoragerSingleton,
+		VersionControlManager,
+		AuditManager)
+
+	IPBlocklistManager = imodule_conf.NewIPBlocklistManager(
+		TxnStoragerSingleton,
+		ExtraFileStoragerSingleton,
+		ProductStoragerSingleton,
+		VersionControlManager,
+		AuditManager)
+
+	BundleManager = ibundle.NewBundleManager(
+		TxnStoragerSingleton,
+		VersionControlManager,
+		ProductManager,
+		PoolManager,
+		SubClusterManager,
+		ClusterManager,
+		DomainManager,
+		RouteRuleManager,
+		CertificateManager)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory init container with in-memory storagers, for unit and integration tests.
+package memory
+
+import (
+	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/api-server/storage/memory"
+)
+
+// Init set storagers and managers of container, all data are stored in a new memory db,
+// so calling it again drops data created before.
+func Init() {
+	db := memory.NewDB()
+
+	container.TxnStoragerSingleton = memory.NewTxnStorager(db)
+	container.VersionControlStoragerSingleton = memory.NewVersionControlStorager(db)
+	container.RouteRuleStoragerSingleton = memory.NewRouteRuleStorager(db)
+	container.RouteCaseStoragerSingleton = memory.NewRouteCaseStorager(db)
+	container.ProductStoragerSingleton = memory.NewProductStorager(db)
+	container.BFEClusterStoragerSingleton = memory.NewBFEClusterStorager(db)
+	container.PoolStoragerSingleton = memory.NewPoolStorager(
+		db,
+		container.ProductStoragerSingleton)
+	container.SubClusterStoragerSingleton = memory.NewSubClusterStorager(
+		db,
+		container.PoolStoragerSingleton)
+	container.ClusterStoragerSingleton = memory.NewClusterStorager(
+		db,
+		container.SubClusterStoragerSingleton)
+	container.ActiveHealthCheckStoragerSingleton = memory.NewActiveHealthCheckStorager(db)
+	container.CertificateStoragerSingleton = memory.NewCertificateStorager(db)
+	container.TLSRuleStoragerSingleton = memory.NewTLSRuleStorager(db)
+	container.AuthenticateStoragerSingleton = memory.NewAuthenticateStorager(db)
+	container.AuthorizeStoragerSingleton = memory.NewAuthorizeStorager(db,
+		container.ProductStoragerSingleton,
+		container.AuthenticateStoragerSingleton,
+	)
+	container.DomainStoragerSingleton = memory.NewDomainStorager(db)
+	container.ExtraFileStoragerSingleton = memory.NewExtraFileStorager(db)
+	container.AuditStoragerSingleton = memory.NewAuditStorager(db)
+	container.HeaderRuleStoragerSingleton = memory.NewHeaderRuleStorager(db)
+	container.RewriteRuleStoragerSingleton = memory.NewRewriteRuleStorager(db)
+	container.RedirectRuleStoragerSingleton = memory.NewRedirectRuleStorager(db)
+	container.BlockRuleStoragerSingleton = memory.NewBlockRuleStorager(db)
+
+	container.InitManagers()
+}
@@ -15,19 +15,13 @@
 package rdb
 
 import (
-	"context"
-
-	"github.com/bfenetworks/api-server/model/iauth"
-	"github.com/bfenetworks/api-server/model/ibasic"
-	"github.com/bfenetworks/api-server/model/icluster_conf"
-	"github.com/bfenetworks/api-server/model/iprotocol"
-	"github.com/bfenetworks/api-server/model/iroute_conf"
-	"github.com/bfenetworks/api-server/model/iversion_control"
 	"github.com/bfenetworks/api-server/stateful"
 	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/api-server/storage/rdb/audit"
 	"github.com/bfenetworks/api-server/storage/rdb/auth"
 	"github.com/bfenetworks/api-server/storage/rdb/basic"
 	"github.com/bfenetworks/api-server/storage/rdb/cluster_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/module_conf"
 	"github.com/bfenetworks/api-server/storage/rdb/protocol"
 	"github.com/bfenetworks/api-server/storage/rdb/route_conf"
 	"github.com/bfenetworks/api-server/storage/rdb/txn"
@@ -40,6 +34,7 @@ func Init() {
 	container.RouteRuleStoragerSingleton = route_conf.NewRouteRuleStorager(
 		stateful.NewBFEDBContext,
 		container.VersionControlStoragerSingleton)
+	container.RouteCaseStoragerSingleton = route_conf.NewRouteCaseStorager(stateful.NewBFEDBContext)
 	container.ProductStoragerSingleton = basic.NewProductManager(stateful.NewBFEDBContext)
 	container.BFEClusterStoragerSingleton = basic.NewRDBBFEClusterStorager(stateful.NewBFEDBContext)
 	container.PoolStoragerSingleton = cluster_conf.NewRDBPoolStorager(
@@ -52,7 +47,9 @@ func Init() {
 	container.ClusterStoragerSingleton = cluster_conf.NewRDBClusterStorager(
 		stateful.NewBFEDBContext,
 		container.SubClusterStoragerSingleton)
+	container.ActiveHealthCheckStoragerSingleton = cluster_conf.NewRDBActiveHealthCheckStorager(stateful.NewBFEDBContext)
 	container.CertificateStoragerSingleton = protocol.NewCertificateStorager(stateful.NewBFEDBContext)
+	container.TLSRuleStoragerSingleton = protocol.NewTLSRuleStorager(stateful.NewBFEDBContext)
 	container.AuthenticateStoragerSingleton = auth.NewAuthenticateStorager(stateful.NewBFEDBContext)
 	container.AuthorizeStoragerSingleton = auth.NewAuthorizeStorager(stateful.NewBFEDBContext,
 		container.ProductStoragerSingleton,
@@ -60,68 +57,11 @@ func Init() {
 	)
 	container.DomainStoragerSingleton = route_conf.NewDomainStorager(stateful.NewBFEDBContext)
 	container.ExtraFileStoragerSingleton = basic.NewRDBExtraFileStorager(stateful.NewBFEDBContext)
+	container.AuditStoragerSingleton = audit.NewAuditStorager(stateful.NewBFEDBContext)
+	container.HeaderRuleStoragerSingleton = module_conf.NewHeaderRuleStorager(stateful.NewBFEDBContext)
+	container.RewriteRuleStoragerSingleton = module_conf.NewRewriteRuleStorager(stateful.NewBFEDBContext)
+	container.RedirectRuleStoragerSingleton = module_conf.NewRedirectRuleStorager(stateful.NewBFEDBContext)
+	container.BlockRuleStoragerSingleton = module_conf.NewBlockRuleStorager(stateful.NewBFEDBContext)
 
-	container.ExtraFileManager = ibasic.NewExtraFileManager(container.ExtraFileStoragerSingleton)
-	container.VersionControlManager = iversion_control.NewVersionControllerManager(
-		container.TxnStoragerSingleton,
-		container.VersionControlStoragerSingleton)
-
-	container.BFEClusterManager = ibasic.NewBFEClusterManager(
-		container.TxnStoragerSingleton,
-		container.BFEClusterStoragerSingleton)
-
-	container.CertificateManager = iprotocol.NewCertificateManager(
-		container.TxnStoragerSingleton,
-		container.CertificateStoragerSingleton,
-		container.VersionControlManager,
-		container.ExtraFileStoragerSingleton)
-
-	container.ProductManager = ibasic.NewProductManager(
-		container.TxnStoragerSingleton,
-		container.ProductStoragerSingleton)
-
-	container.RouteRuleManager = iroute_conf.NewRouteRuleManager(
-		container.TxnStoragerSingleton,
-		container.RouteRuleStoragerSingleton,
-		container.ClusterStoragerSingleton,
-		container.ProductStoragerSingleton,
-		container.VersionControlManager,
-		container.DomainStoragerSingleton)
-
-	container.ClusterManager = icluster_conf.NewClusterManager(
-		container.TxnStoragerSingleton,
-		container.ClusterStoragerSingleton,
-		container.SubClusterStoragerSingleton,
-		container.BFEClusterStoragerSingleton,
-		container.VersionControlManager,
-		map[string]func(context.Context, *ibasic.Product, *icluster_conf.Cluster) error{
-			"rules": container.RouteRuleManager.ClusterDeleteChecker,
-		})
-
-	container.SubClusterManager = icluster_conf.NewSubClusterManager(
-		container.TxnStoragerSingleton,
-		container.SubClusterStoragerSingleton,
-		container.ProductStoragerSingleton,
-		container.PoolStoragerSingleton,
-		container.ClusterStoragerSingleton)
-
-	container.DomainManager = iroute_conf.NewDomainManager(
-		container.TxnStoragerSingleton,
-		container.DomainStoragerSingleton,
-		container.RouteRuleManager)
-
-	container.AuthenticateManager = iauth.NewAuthenticateManager(
-		container.TxnStoragerSingleton,
-		container.AuthenticateStoragerSingleton,
-		container.AuthorizeStoragerSingleton,
-	)
-	container.AuthorizeManager = iauth.NewAuthorizeManager(
-		container.TxnStoragerSingleton,
-		container.AuthorizeStoragerSingleton)
-
-	container.PoolManager = icluster_conf.NewPoolManager(
-		container.TxnStoragerSingleton,
-		container.PoolStoragerSingleton,
-		container.BFEClusterStoragerSingleton,
-		container.SubClusterStoragerSingleton)
+	container.InitManagers()
 }
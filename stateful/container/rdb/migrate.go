@@ -0,0 +1,32 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rdb
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/schema"
+)
+
+// Migrate create or upgrade tables of bfe_db, do nothing if driver has no migrations
+func Migrate() error {
+	db, err := stateful.BFEDB()
+	if err != nil {
+		return err
+	}
+
+	return schema.Migrate(context.Background(), db, stateful.BFEDBDriver())
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+)
+
+type ActiveHealthCheckStorager struct {
+	db *DB
+}
+
+var _ icluster_conf.ActiveHealthCheckStorager = &ActiveHealthCheckStorager{}
+
+func NewActiveHealthCheckStorager(db *DB) *ActiveHealthCheckStorager {
+	return &ActiveHealthCheckStorager{
+		db: db,
+	}
+}
+
+func (as *ActiveHealthCheckStorager) FetchActiveHealthChecks(ctx context.Context,
+	filter *icluster_conf.ActiveHealthCheckFilter) (rst []*icluster_conf.ActiveHealthCheck, err error) {
+
+	err = as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		rst = []*icluster_conf.ActiveHealthCheck{}
+		for _, one := range t.activeHealthChecks {
+			if filter != nil && (!eqInt64(filter.ClusterID, one.ClusterID) || !inInt64s(filter.ClusterIDs, one.ClusterID)) {
+				continue
+			}
+			rst = append(rst, deepCopy(one).(*icluster_conf.ActiveHealthCheck))
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (as *ActiveHealthCheckStorager) CreateActiveHealthCheck(ctx context.Context, product *ibasic.Product,
+	cluster *icluster_conf.Cluster, param *icluster_conf.ActiveHealthCheckParam) error {
+
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.activeHealthChecks {
+			if one.ClusterID == cluster.ID {
+				return xerror.WrapRecordExisted("ActiveHealthCheck")
+			}
+		}
+
+		now := time.Now()
+		one := &icluster_conf.ActiveHealthCheck{
+			ID:        t.nextID(tableActiveHealthChecks),
+			ProductID: product.ID,
+			ClusterID: cluster.ID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		activeHealthCheckAssign(one, param)
+
+		t.activeHealthChecks = append(t.activeHealthChecks, one)
+		return nil
+	})
+}
+
+func (as *ActiveHealthCheckStorager) UpdateActiveHealthCheck(ctx context.Context,
+	old *icluster_conf.ActiveHealthCheck, param *icluster_conf.ActiveHealthCheckParam) error {
+
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.activeHealthChecks {
+			if one.ID == old.ID {
+				activeHealthCheckAssign(one, param)
+				one.UpdatedAt = time.Now()
+			}
+		}
+		return nil
+	})
+}
+
+func (as *ActiveHealthCheckStorager) DeleteActiveHealthCheck(ctx context.Context,
+	old *icluster_conf.ActiveHealthCheck) error {
+
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*icluster_conf.ActiveHealthCheck{}
+		for _, one := range t.activeHealthChecks {
+			if one.ID != old.ID {
+				list = append(list, one)
+			}
+		}
+		t.activeHealthChecks = list
+
+		return nil
+	})
+}
+
+func activeHealthCheckAssign(one *icluster_conf.ActiveHealthCheck, param *icluster_conf.ActiveHealthCheckParam) {
+	if param.Schema != nil {
+		one.Schema = *param.Schema
+	}
+	if param.Interval != nil {
+		one.Interval = *param.Interval
+	}
+	if param.Timeout != nil {
+		one.Timeout = *param.Timeout
+	}
+	if param.SuccNum != nil {
+		one.SuccNum = *param.SuccNum
+	}
+	if param.FailNum != nil {
+		one.FailNum = *param.FailNum
+	}
+	if param.Host != nil {
+		one.Host = *param.Host
+	}
+	if param.Uri != nil {
+		one.Uri = *param.Uri
+	}
+	if param.StatusCode != nil {
+		one.StatusCode = *param.StatusCode
+	}
+}
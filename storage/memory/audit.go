@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/model/iaudit"
+)
+
+type AuditStorager struct {
+	db *DB
+}
+
+var _ iaudit.AuditStorager = &AuditStorager{}
+
+func NewAuditStorager(db *DB) *AuditStorager {
+	return &AuditStorager{
+		db: db,
+	}
+}
+
+func (as *AuditStorager) CreateAudit(ctx context.Context, audit *iaudit.Audit) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		one := deepCopy(audit).(*iaudit.Audit)
+		one.ID = t.nextID(tableAudits)
+		one.CreatedAt = time.Now()
+
+		t.audits = append(t.audits, one)
+		return nil
+	})
+}
+
+// FetchAudits return audits match filter, newest first
+func (as *AuditStorager) FetchAudits(ctx context.Context, filter *iaudit.AuditFilter) ([]*iaudit.Audit, int64, error) {
+	if filter == nil {
+		filter = &iaudit.AuditFilter{}
+	}
+
+	rst := []*iaudit.Audit{}
+	var total int64
+	err := as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*iaudit.Audit{}
+		for i := len(t.audits) - 1; i >= 0; i-- {
+			one := t.audits[i]
+			if eqInt64(filter.ProductID, one.ProductID) && eqString(filter.Visitor, one.Visitor) &&
+				eqString(filter.ResourceType, one.ResourceType) && eqString(filter.ResourceName, one.ResourceName) &&
+				eqString(filter.Action, one.Action) &&
+				(filter.StartTime == nil || !one.CreatedAt.Before(*filter.StartTime)) &&
+				(filter.EndTime == nil || one.CreatedAt.Before(*filter.EndTime)) {
+				list = append(list, one)
+			}
+		}
+
+		total = int64(len(list))
+		if filter.PageSize > 0 {
+			page := filter.Page
+			if page < 1 {
+				page = 1
+			}
+
+			start, end := (page-1)*filter.PageSize, page*filter.PageSize
+			if start > len(list) {
+				start = len(list)
+			}
+			if end > len(list) {
+				end = len(list)
+			}
+			list = list[start:end]
+		}
+
+		for _, one := range list {
+			rst = append(rst, deepCopy(one).(*iaudit.Audit))
+		}
+		return nil
+	})
+
+	return rst, total, err
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iauth"
+)
+
+// userRow users and tokens are stored in the same table, distinguished by Type
+type userRow struct {
+	ID              int64
+	Name            string
+	Type            int8
+	Password        string
+	Scopes          string
+	Ticket          string
+	TicketCreatedAt time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type AuthenticateStorager struct {
+	db *DB
+}
+
+var _ iauth.AuthenticateStorager = &AuthenticateStorager{}
+
+func NewAuthenticateStorager(db *DB) *AuthenticateStorager {
+	return &AuthenticateStorager{
+		db: db,
+	}
+}
+
+func (as *AuthenticateStorager) FetchUserList(ctx context.Context, filter *iauth.UserFilter) ([]*iauth.User, error) {
+	rst := []*iauth.User{}
+	err := as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterUsers(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		idx := paginate(p, len(list), func(i int) pageKey {
+			return pageKey{list[i].Name, list[i].CreatedAt, list[i].UpdatedAt}
+		})
+
+		for _, i := range idx {
+			rst = append(rst, userR2M(list[i]))
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (as *AuthenticateStorager) CountUsers(ctx context.Context, filter *iauth.UserFilter) (int64, error) {
+	var total int64
+	err := as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterUsers(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		total = countPage(p, len(list), func(i int) pageKey {
+			return pageKey{Name: list[i].Name}
+		})
+		return nil
+	})
+
+	return total, err
+}
+
+func (as *AuthenticateStorager) FetchUser(ctx context.Context, filter *iauth.UserFilter) (*iauth.User, error) {
+	list, err := as.FetchUserList(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, err
+	}
+
+	return list[0], nil
+}
+
+func (as *AuthenticateStorager) UpdateUser(ctx context.Context, user *iauth.User, param *iauth.UserParam) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.users {
+			if one.ID != user.ID {
+				continue
+			}
+
+			if param.Name != nil && *param.Name != one.Name && t.userByName(*param.Name, iauth.UserTypeNormal) != nil {
+				return xerror.WrapRecordExisted("User")
+			}
+
+			userAssign(one, param)
+			one.Type = iauth.UserTypeNormal
+			one.UpdatedAt = time.Now()
+		}
+
+		return nil
+	})
+}
+
+func (as *AuthenticateStorager) CreateUser(ctx context.Context, param *iauth.UserParam) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		one := &userRow{
+			Type:      iauth.UserTypeNormal,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		userAssign(one, param)
+
+		if t.userByName(one.Name, one.Type) != nil {
+			return xerror.WrapRecordExisted("User")
+		}
+
+		one.ID = t.nextID(tableUsers)
+		t.users = append(t.users, one)
+		return nil
+	})
+}
+
+func (as *AuthenticateStorager) DeleteUser(ctx context.Context, user *iauth.User) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		t.deleteUsers(func(one *userRow) bool {
+			return one.ID == user.ID
+		})
+		return nil
+	})
+}
+
+func (as *AuthenticateStorager) FetchTokens(ctx context.Context, filter *iauth.TokenFilter) ([]*iauth.Token, error) {
+	if filter == nil {
+		filter = &iauth.TokenFilter{}
+	}
+
+	rst := []*iauth.Token{}
+	err := as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.users {
+			if one.Type == iauth.UserTypeToken && inInt64s(filter.IDs, one.ID) &&
+				eqString(filter.Name, one.Name) && eqString(filter.Token, one.Ticket) {
+				rst = append(rst, &iauth.Token{
+					ID:    one.ID,
+					Name:  one.Name,
+					Token: one.Ticket,
+					Scope: one.Scopes,
+				})
+			}
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (as *AuthenticateStorager) CreateToken(ctx context.Context, param *iauth.TokenParam) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		one := &userRow{
+			Type:      iauth.UserTypeToken,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if param.Name != nil {
+			one.Name = *param.Name
+		}
+		if param.Token != nil {
+			one.Ticket = *param.Token
+		}
+		if param.Scope != nil {
+			one.Scopes = *param.Scope
+		}
+
+		if t.userByName(one.Name, one.Type) != nil {
+			return xerror.WrapRecordExisted("Token")
+		}
+
+		one.ID = t.nextID(tableUsers)
+		t.users = append(t.users, one)
+		return nil
+	})
+}
+
+func (as *AuthenticateStorager) DeleteToken(ctx context.Context, token *iauth.Token) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		t.deleteUsers(func(one *userRow) bool {
+			return one.Name == token.Name && one.Type == iauth.UserTypeToken
+		})
+		return nil
+	})
+}
+
+func userR2M(one *userRow) *iauth.User {
+	return &iauth.User{
+		ID:                 one.ID,
+		Name:               one.Name,
+		Type:               one.Type,
+		Admin:              strings.Contains(one.Scopes, iauth.ScopeSystem),
+		Password:           one.Password,
+		SessionKey:         one.Ticket,
+		SessionKeyCreateAt: one.TicketCreatedAt,
+	}
+}
+
+func userAssign(one *userRow, param *iauth.UserParam) {
+	if param.Name != nil {
+		one.Name = *param.Name
+	}
+	if param.Password != nil {
+		one.Password = *param.Password
+	}
+	if param.Scopes != nil {
+		one.Scopes = strings.Join(param.Scopes, ",")
+	}
+	if param.SessionKey != nil {
+		one.Ticket = *param.SessionKey
+	}
+	if param.SessionKeyCreateAt != nil {
+		one.TicketCreatedAt = *param.SessionKeyCreateAt
+	}
+}
+
+// filterUsers return normal users match filter, Type of filter is ignored
+func (t *tables) filterUsers(filter *iauth.UserFilter) []*userRow {
+	if filter == nil {
+		filter = &iauth.UserFilter{}
+	}
+
+	rst := []*userRow{}
+	for _, one := range t.users {
+		if one.Type == iauth.UserTypeNormal && inInt64s(filter.IDs, one.ID) && eqString(filter.Name, one.Name) &&
+			eqString(filter.SessionKey, one.Ticket) && inInt8s(filter.Types, one.Type) {
+			rst = append(rst, one)
+		}
+	}
+	return rst
+}
+
+func (t *tables) userByName(name string, typ int8) *userRow {
+	for _, one := range t.users {
+		if one.Name == name && one.Type == typ {
+			return one
+		}
+	}
+	return nil
+}
+
+func (t *tables) deleteUsers(match func(*userRow) bool) {
+	list := []*userRow{}
+	for _, one := range t.users {
+		if !match(one) {
+			list = append(list, one)
+		}
+	}
+	t.users = list
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+)
+
+// userProductRow binding of user or token and product
+type userProductRow struct {
+	UserID    int64
+	ProductID int64
+}
+
+type AuthorizeStorager struct {
+	db *DB
+
+	productStorager      ibasic.ProductStorager
+	authenticateStorager iauth.AuthenticateStorager
+}
+
+var _ iauth.AuthorizeStorager = &AuthorizeStorager{}
+
+func NewAuthorizeStorager(db *DB,
+	productStorager ibasic.ProductStorager,
+	authenticateStorager iauth.AuthenticateStorager) *AuthorizeStorager {
+	return &AuthorizeStorager{
+		db:                   db,
+		productStorager:      productStorager,
+		authenticateStorager: authenticateStorager,
+	}
+}
+
+func (as *AuthorizeStorager) UnbindUserProduct(ctx context.Context, user *iauth.User, product *ibasic.Product) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		t.deleteUserProducts(func(one *userProductRow) bool {
+			return one.UserID == user.ID && one.ProductID == product.ID
+		})
+		return nil
+	})
+}
+
+func (as *AuthorizeStorager) UnbindUserAllProduct(ctx context.Context, user *iauth.User) error {
+	return as.unbindAllProduct(ctx, user.ID)
+}
+
+func (as *AuthorizeStorager) UnbindTokenAllProduct(ctx context.Context, token *iauth.Token) error {
+	return as.unbindAllProduct(ctx, token.ID)
+}
+
+func (as *AuthorizeStorager) unbindAllProduct(ctx context.Context, userID int64) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		t.deleteUserProducts(func(one *userProductRow) bool {
+			return one.UserID == userID
+		})
+		return nil
+	})
+}
+
+func (as *AuthorizeStorager) BindUserProduct(ctx context.Context, user *iauth.User, product *ibasic.Product) error {
+	return as.bindProduct(ctx, user.ID, product)
+}
+
+func (as *AuthorizeStorager) BindTokenProduct(ctx context.Context, token *iauth.Token, product *ibasic.Product) error {
+	return as.bindProduct(ctx, token.ID, product)
+}
+
+func (as *AuthorizeStorager) bindProduct(ctx context.Context, userID int64, product *ibasic.Product) error {
+	return as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if t.userProduct(userID, product.ID) != nil {
+			return nil
+		}
+
+		t.userProducts = append(t.userProducts, &userProductRow{
+			UserID:    userID,
+			ProductID: product.ID,
+		})
+		return nil
+	})
+}
+
+func (as *AuthorizeStorager) FetchUserProducts(ctx context.Context, user *iauth.User) ([]*ibasic.Product, error) {
+	return as.fetchProducts(ctx, user.ID)
+}
+
+func (as *AuthorizeStorager) FetchTokenProduct(ctx context.Context, token *iauth.Token) (*ibasic.Product, error) {
+	list, err := as.fetchProducts(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return list[0], nil
+}
+
+func (as *AuthorizeStorager) fetchProducts(ctx context.Context, userID int64) (list []*ibasic.Product, err error) {
+	err = as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		productIDs := map[int64]bool{}
+		for _, one := range t.userProducts {
+			if one.UserID == userID {
+				productIDs[one.ProductID] = true
+			}
+		}
+		if len(productIDs) == 0 {
+			return nil
+		}
+
+		list, err = as.productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+			IDs: lib.Int64BoolMap2Slice(productIDs),
+		})
+		return err
+	})
+
+	return list, err
+}
+
+func (as *AuthorizeStorager) BatchFetchTokenProduct(ctx context.Context, tokens []*iauth.Token) (map[int64]*ibasic.Product, error) {
+	if len(tokens) == 0 {
+		return map[int64]*ibasic.Product{}, nil
+	}
+
+	var rst map[int64]*ibasic.Product
+	err := as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		tokenIDs := map[int64]bool{}
+		for _, one := range tokens {
+			tokenIDs[one.ID] = true
+		}
+
+		bindList := []*userProductRow{}
+		productIDs := map[int64]bool{}
+		for _, one := range t.userProducts {
+			if tokenIDs[one.UserID] {
+				bindList = append(bindList, one)
+				productIDs[one.ProductID] = true
+			}
+		}
+		if len(bindList) == 0 {
+			return nil
+		}
+
+		productList, err := as.productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+			IDs: lib.Int64BoolMap2Slice(productIDs),
+		})
+		if err != nil {
+			return err
+		}
+
+		productMap := ibasic.ProductIDMap(productList)
+		rst = map[int64]*ibasic.Product{}
+		for _, one := range bindList {
+			rst[one.UserID] = productMap[one.ProductID]
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (as *AuthorizeStorager) FetchProductUsers(ctx context.Context, product *ibasic.Product) (list []*iauth.User, err error) {
+	err = as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		userIDs := t.productUserIDs(product.ID)
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		list, err = as.authenticateStorager.FetchUserList(ctx, &iauth.UserFilter{
+			IDs: userIDs,
+		})
+		return err
+	})
+
+	return list, err
+}
+
+func (as *AuthorizeStorager) FetchProductTokens(ctx context.Context, product *ibasic.Product) (list []*iauth.Token, err error) {
+	err = as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		tokenIDs := t.productUserIDs(product.ID)
+		if len(tokenIDs) == 0 {
+			return nil
+		}
+
+		list, err = as.authenticateStorager.FetchTokens(ctx, &iauth.TokenFilter{
+			IDs: tokenIDs,
+		})
+		return err
+	})
+
+	return list, err
+}
+
+func (as *AuthorizeStorager) UpdateUserScopes(ctx context.Context, user *iauth.User, scopes []string) error {
+	return as.authenticateStorager.UpdateUser(ctx, user, &iauth.UserParam{
+		Scopes: scopes,
+	})
+}
+
+func (as *AuthorizeStorager) IsUserProductGranted(ctx context.Context, user *iauth.User, product *ibasic.Product) (bool, error) {
+	return as.isGranted(ctx, user.ID, product)
+}
+
+func (as *AuthorizeStorager) IsTokenProductGranted(ctx context.Context, token *iauth.Token, product *ibasic.Product) (bool, error) {
+	return as.isGranted(ctx, token.ID, product)
+}
+
+func (as *AuthorizeStorager) isGranted(ctx context.Context, userID int64, product *ibasic.Product) (granted bool, err error) {
+	err = as.db.view(ctx, func(ctx context.Context, t *tables) error {
+		granted = t.userProduct(userID, product.ID) != nil
+		return nil
+	})
+
+	return granted, err
+}
+
+func (t *tables) userProduct(userID, productID int64) *userProductRow {
+	for _, one := range t.userProducts {
+		if one.UserID == userID && one.ProductID == productID {
+			return one
+		}
+	}
+	return nil
+}
+
+func (t *tables) productUserIDs(productID int64) []int64 {
+	userIDs := map[int64]bool{}
+	for _, one := range t.userProducts {
+		if one.ProductID == productID {
+			userIDs[one.UserID] = true
+		}
+	}
+
+	return lib.Int64BoolMap2Slice(userIDs)
+}
+
+func (t *tables) deleteUserProducts(match func(*userProductRow) bool) {
+	list := []*userProductRow{}
+	for _, one := range t.userProducts {
+		if !match(one) {
+			list = append(list, one)
+		}
+	}
+	t.userProducts = list
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+)
+
+type BFEClusterStorager struct {
+	db *DB
+}
+
+var _ ibasic.BFEClusterStorager = &BFEClusterStorager{}
+
+func NewBFEClusterStorager(db *DB) *BFEClusterStorager {
+	return &BFEClusterStorager{
+		db: db,
+	}
+}
+
+func (bs *BFEClusterStorager) FetchBFEClusters(ctx context.Context, filter *ibasic.BFEClusterFilter) ([]*ibasic.BFECluster, error) {
+	var rst []*ibasic.BFECluster
+	err := bs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.bfeClusters {
+			if filter != nil && (!eqString(filter.Name, one.Name) || !eqString(filter.Pool, one.Pool)) {
+				continue
+			}
+			rst = append(rst, deepCopy(one).(*ibasic.BFECluster))
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (bs *BFEClusterStorager) DeleteBFECluster(ctx context.Context, pp *ibasic.BFECluster) error {
+	return bs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*ibasic.BFECluster{}
+		for _, one := range t.bfeClusters {
+			if one.Name != pp.Name {
+				list = append(list, one)
+			}
+		}
+		t.bfeClusters = list
+
+		return nil
+	})
+}
+
+func (bs *BFEClusterStorager) CreateBFECluster(ctx context.Context, pp *ibasic.BFEClusterParam) error {
+	return bs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		pool := t.poolByName(*pp.Pool)
+		if pool == nil || pool.Tag != icluster_conf.PoolTagBFE {
+			return xerror.WrapParamErrorWithMsg("Pool %s Not Exist", *pp.Pool)
+		}
+
+		for _, one := range t.bfeClusters {
+			if one.Name == *pp.Name {
+				return xerror.WrapRecordExisted("BFE Cluster")
+			}
+		}
+
+		one := &ibasic.BFECluster{
+			ID:      t.nextID(tableBFEClusters),
+			Name:    *pp.Name,
+			Pool:    pool.Name,
+			Enabled: true,
+		}
+		if pp.Capacity != nil {
+			one.Capacity = *pp.Capacity
+		}
+
+		t.bfeClusters = append(t.bfeClusters, one)
+		return nil
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+)
+
+type certificateRow struct {
+	ID int64
+	iprotocol.Certificate
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CertificateStorager struct {
+	db *DB
+}
+
+var _ iprotocol.CertificateStorager = &CertificateStorager{}
+
+func NewCertificateStorager(db *DB) *CertificateStorager {
+	return &CertificateStorager{
+		db: db,
+	}
+}
+
+func (cs *CertificateStorager) DeleteCertificate(ctx context.Context, certificate *iprotocol.Certificate) error {
+	return cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*certificateRow{}
+		for _, one := range t.certificates {
+			if one.CertName != certificate.CertName {
+				list = append(list, one)
+			}
+		}
+		t.certificates = list
+
+		return nil
+	})
+}
+
+func (cs *CertificateStorager) CreateCertificate(ctx context.Context, pp *iprotocol.CertificateParam) error {
+	return cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if pp.CertName != nil && t.certificateByName(*pp.CertName) != nil {
+			return xerror.WrapRecordExisted("Certificate")
+		}
+
+		now := time.Now()
+		one := &certificateRow{
+			ID: t.nextID(tableCertificates),
+			Certificate: iprotocol.Certificate{
+				Description: "no desc",
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		certificateAssign(one, pp)
+
+		t.certificates = append(t.certificates, one)
+		return nil
+	})
+}
+
+func (cs *CertificateStorager) UpdateCertificate(ctx context.Context, certificate *iprotocol.Certificate,
+	pp *iprotocol.CertificateParam) error {
+
+	return cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		one := t.certificateByName(certificate.CertName)
+		if one == nil {
+			return nil
+		}
+		if pp.CertName != nil && *pp.CertName != one.CertName && t.certificateByName(*pp.CertName) != nil {
+			return xerror.WrapRecordExisted("Certificate")
+		}
+
+		certificateAssign(one, pp)
+		one.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (cs *CertificateStorager) FetchCertificates(ctx context.Context,
+	filter *iprotocol.CertificateFilter) (rst []*iprotocol.Certificate, err error) {
+
+	err = cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterCertificates(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		idx := paginate(p, len(list), func(i int) pageKey {
+			return pageKey{list[i].CertName, list[i].CreatedAt, list[i].UpdatedAt}
+		})
+
+		rst = make([]*iprotocol.Certificate, len(idx))
+		for i, j := range idx {
+			rst[i] = deepCopy(&list[j].Certificate).(*iprotocol.Certificate)
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (cs *CertificateStorager) CountCertificates(ctx context.Context,
+	filter *iprotocol.CertificateFilter) (total int64, err error) {
+
+	err = cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterCertificates(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		total = countPage(p, len(list), func(i int) pageKey {
+			return pageKey{Name: list[i].CertName}
+		})
+		return nil
+	})
+
+	return total, err
+}
+
+func certificateAssign(one *certificateRow, pp *iprotocol.CertificateParam) {
+	if pp.CertName != nil {
+		one.CertName = *pp.CertName
+	}
+	if pp.Description != nil {
+		one.Description = *pp.Description
+	}
+	if pp.IsDefault != nil {
+		one.IsDefault = *pp.IsDefault
+	}
+	if pp.CertFileName != nil {
+		one.CertFileName = *pp.CertFileName
+	}
+	if pp.CertFilePath != nil {
+		one.CertFilePath = *pp.CertFilePath
+	}
+	if pp.KeyFileName != nil {
+		one.KeyFileName = *pp.KeyFileName
+	}
+	if pp.KeyFilePath != nil {
+		one.KeyFilePath = *pp.KeyFilePath
+	}
+	if pp.ExpiredDate != nil {
+		one.ExpiredDate = *pp.ExpiredDate
+	}
+}
+
+func (t *tables) filterCertificates(filter *iprotocol.CertificateFilter) []*certificateRow {
+	if filter == nil {
+		return t.certificates
+	}
+
+	rst := []*certificateRow{}
+	for _, one := range t.certificates {
+		if eqString(filter.CertName, one.CertName) && eqBool(filter.IsDefault, one.IsDefault) {
+			rst = append(rst, one)
+		}
+	}
+	return rst
+}
+
+func (t *tables) certificateByName(name string) *certificateRow {
+	for _, one := range t.certificates {
+		if one.CertName == name {
+			return one
+		}
+	}
+	return nil
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+)
+
+// clusterRow holds cluster without sub clusters and scheduler, they are stored in other tables
+type clusterRow struct {
+	icluster_conf.Cluster
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ClusterStorager struct {
+	db *DB
+
+	subClusterStorager icluster_conf.SubClusterStorager
+}
+
+var _ icluster_conf.ClusterStorager = &ClusterStorager{}
+
+func NewClusterStorager(db *DB, subClusterStorager icluster_conf.SubClusterStorager) *ClusterStorager {
+	return &ClusterStorager{
+		db:                 db,
+		subClusterStorager: subClusterStorager,
+	}
+}
+
+func (cs *ClusterStorager) ClusterCreate(ctx context.Context, product *ibasic.Product,
+	param *icluster_conf.ClusterParam, subClusters []*icluster_conf.SubCluster) (clusterID int64, err error) {
+
+	err = cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		now := time.Now()
+		one := newClusterRow()
+		one.CreatedAt, one.UpdatedAt = now, now
+		clusterAssign(one, param)
+
+		if t.clusterExisted(one.Name, 0) {
+			return xerror.WrapRecordExisted("Cluster")
+		}
+
+		if param.ID == nil {
+			one.ID = t.nextID(tableClusters)
+		}
+		t.clusters = append(t.clusters, one)
+
+		if mlb := param.Scheduler; mlb != nil {
+			t.lbMatrices[one.ID] = deepCopy(mlb).(map[string]map[string]int)
+		}
+
+		clusterID = one.ID
+		return nil
+	})
+
+	return clusterID, err
+}
+
+func (cs *ClusterStorager) ClusterUpdate(ctx context.Context, product *ibasic.Product, old *icluster_conf.Cluster,
+	param *icluster_conf.ClusterParam) error {
+
+	return cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if mlb := param.Scheduler; mlb != nil {
+			t.lbMatrices[old.ID] = deepCopy(mlb).(map[string]map[string]int)
+		}
+
+		for _, one := range t.clusters {
+			if one.ID != old.ID {
+				continue
+			}
+
+			if param.Name != nil && *param.Name != one.Name && t.clusterExisted(*param.Name, one.ID) {
+				return xerror.WrapRecordExisted("Cluster")
+			}
+
+			clusterAssign(one, param)
+			one.UpdatedAt = time.Now()
+		}
+
+		return nil
+	})
+}
+
+func (cs *ClusterStorager) ClusterDelete(ctx context.Context, product *ibasic.Product, cluster *icluster_conf.Cluster) error {
+	return cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		delete(t.lbMatrices, cluster.ID)
+
+		checks := []*icluster_conf.ActiveHealthCheck{}
+		for _, one := range t.activeHealthChecks {
+			if one.ClusterID != cluster.ID {
+				checks = append(checks, one)
+			}
+		}
+		t.activeHealthChecks = checks
+
+		list := []*clusterRow{}
+		for _, one := range t.clusters {
+			if one.ID != cluster.ID {
+				list = append(list, one)
+			}
+		}
+		t.clusters = list
+
+		return nil
+	})
+}
+
+func (cs *ClusterStorager) BindSubCluster(ctx context.Context, cluster *icluster_conf.Cluster,
+	appendSubClusters, unbindSubClusters []*icluster_conf.SubCluster) error {
+
+	return cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		now := time.Now()
+		if len(unbindSubClusters) > 0 {
+			ids := icluster_conf.SubClusterList2IDSlice(unbindSubClusters)
+			for _, one := range t.subClusters {
+				if one.ClusterID == cluster.ID && inInt64s(ids, one.ID) {
+					one.ClusterID = -1
+					one.UpdatedAt = now
+				}
+			}
+		}
+
+		if len(appendSubClusters) > 0 {
+			ids := icluster_conf.SubClusterList2IDSlice(appendSubClusters)
+			for _, one := range t.subClusters {
+				if inInt64s(ids, one.ID) {
+					one.ClusterID = cluster.ID
+					one.UpdatedAt = now
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (cs *ClusterStorager) FetchClusterList(ctx context.Context,
+	filter *icluster_conf.ClusterFilter) (rst []*icluster_conf.Cluster, err error) {
+
+	err = cs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterClusters(filter)
+		if len(list) == 0 {
+			return nil
+		}
+
+		clusterIDs := []int64{}
+		for _, one := range list {
+			clusterIDs = append(clusterIDs, one.ID)
+		}
+
+		_subClusterList, err := cs.subClusterStorager.FetchSubClusterList(ctx, &icluster_conf.SubClusterFilter{
+			ClusterIDs: clusterIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		rst = []*icluster_conf.Cluster{}
+		for _, one := range list {
+			cluster := deepCopy(&one.Cluster).(*icluster_conf.Cluster)
+
+			cluster.SubClusters = []*icluster_conf.SubCluster{}
+			for _, subCluster := range _subClusterList {
+				if subCluster.ClusterID == one.ID {
+					cluster.SubClusters = append(cluster.SubClusters, subCluster)
+				}
+			}
+
+			if scheduler, ok := t.lbMatrices[one.ID]; ok {
+				cluster.Scheduler = deepCopy(scheduler).(map[string]map[string]int)
+			}
+
+			rst = append(rst, cluster)
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (cs *ClusterStorager) FetchCluster(ctx context.Context, filter *icluster_conf.ClusterFilter) (*icluster_conf.Cluster, error) {
+	list, err := cs.FetchClusterList(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+// LockCluster fetch cluster, DB is locked by transaction until it be finished
+func (cs *ClusterStorager) LockCluster(ctx context.Context, filter *icluster_conf.ClusterFilter) (*icluster_conf.Cluster, error) {
+	return cs.FetchCluster(ctx, filter)
+}
+
+// newClusterRow create cluster with default values, the same as db_ddl.sql
+func newClusterRow() *clusterRow {
+	return &clusterRow{
+		Cluster: icluster_conf.Cluster{
+			Description: "no desc",
+			Ready:       true,
+
+			Basic: &icluster_conf.ClusterBasic{
+				Protocol:    icluster_conf.ClusterProtocolHTTP,
+				BalanceMode: icluster_conf.ClusterBalanceModeWRR,
+				Connection: &icluster_conf.ClusterBasicConnection{
+					MaxIdleConnPerRs: 2,
+				},
+				Retries: &icluster_conf.ClusterBasicRetries{
+					MaxRetryInSubcluster: 3,
+				},
+				Buffers: &icluster_conf.ClusterBasicBuffers{
+					ReqWriteBufferSize: 512,
+					ResFlushInterval:   20,
+				},
+				Timeouts: &icluster_conf.ClusterBasicTimeouts{
+					TimeoutConnServ:        50000,
+					TimeoutResponseHeader:  50000,
+					TimeoutReadbodyClient:  30000,
+					TimeoutReadClientAgain: 30000,
+					TimeoutWriteClient:     60000,
+				},
+			},
+
+			StickySessions: &icluster_conf.ClusterStickySessions{
+				HashHeader: "Cookie:BAIDUID",
+			},
+
+			PassiveHealthCheck: &icluster_conf.ClusterPassiveHealthCheck{
+				Schema:     icluster_conf.ClusterHealthCheckHTTP,
+				Interval:   1000,
+				Failnum:    10,
+				Statuscode: 200,
+			},
+		},
+	}
+}
+
+func clusterAssign(one *clusterRow, param *icluster_conf.ClusterParam) {
+	if param == nil {
+		return
+	}
+
+	setString := func(dst *string, src *string) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setInt32 := func(dst *int32, src *int32) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setInt8 := func(dst *int8, src *int8) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setBool := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+
+	if param.ID != nil {
+		one.ID = *param.ID
+	}
+	if param.ProductID != nil {
+		one.ProductID = *param.ProductID
+	}
+	setString(&one.Name, param.Name)
+	setString(&one.Description, param.Description)
+
+	if basic := param.Basic; basic != nil {
+		setString(&one.Basic.Protocol, basic.Protocol)
+		setString(&one.Basic.BalanceMode, basic.BalanceMode)
+		setString(&one.Basic.OutlierDetectionHttpCode, basic.OutlierDetectionHttpCode)
+
+		if conn := basic.Connection; conn != nil {
+			if conn.MaxIdleConnPerRs != nil {
+				one.Basic.Connection.MaxIdleConnPerRs = *conn.MaxIdleConnPerRs
+			}
+			setInt32(&one.Basic.Connection.MaxConnPerRs, conn.MaxConnPerRs)
+			setBool(&one.Basic.Connection.CancelOnClientClose, conn.CancelOnClientClose)
+			setBool(&one.Basic.Connection.ClientIPCarry, conn.ClientIPCarry)
+			setBool(&one.Basic.Connection.ClientPortCarry, conn.ClientPortCarry)
+		}
+
+		if retries := basic.Retries; retries != nil {
+			setInt8(&one.Basic.Retries.MaxRetryInSubcluster, retries.MaxRetryInSubcluster)
+			setInt8(&one.Basic.Retries.MaxRetryCrossSubcluster, retries.MaxRetryCrossSubcluster)
+		}
+
+		if buffers := basic.Buffers; buffers != nil {
+			setInt32(&one.Basic.Buffers.ReqWriteBufferSize, buffers.ReqWriteBufferSize)
+			setInt32(&one.Basic.Buffers.ReqFlushInterval, buffers.ReqFlushInterval)
+			setInt32(&one.Basic.Buffers.ResFlushInterval, buffers.ResFlushInterval)
+		}
+
+		if timeouts := basic.Timeouts; timeouts != nil {
+			setInt32(&one.Basic.Timeouts.TimeoutConnServ, timeouts.TimeoutConnServ)
+			setInt32(&one.Basic.Timeouts.TimeoutResponseHeader, timeouts.TimeoutResponseHeader)
+			setInt32(&one.Basic.Timeouts.TimeoutReadbodyClient, timeouts.TimeoutReadbodyClient)
+			setInt32(&one.Basic.Timeouts.TimeoutReadClientAgain, timeouts.TimeoutReadClientAgain)
+			setInt32(&one.Basic.Timeouts.TimeoutWriteClient, timeouts.TimeoutWriteClient)
+		}
+	}
+
+	if stickySessions := param.StickySessions; stickySessions != nil {
+		setInt32(&one.StickySessions.HashStrategy, stickySessions.HashStrategy)
+		setString(&one.StickySessions.HashHeader, stickySessions.HashHeader)
+		setBool(&one.StickySessions.SessionSticky, stickySessions.SessionSticky)
+	}
+
+	if passive := param.PassiveHealthCheck; passive != nil {
+		setString(&one.PassiveHealthCheck.Schema, passive.Schema)
+		setInt32(&one.PassiveHealthCheck.Interval, passive.Interval)
+		setInt32(&one.PassiveHealthCheck.Failnum, passive.Failnum)
+		setString(&one.PassiveHealthCheck.Host, passive.Host)
+		setString(&one.PassiveHealthCheck.Uri, passive.Uri)
+		setInt32(&one.PassiveHealthCheck.Statuscode, passive.Statuscode)
+	}
+}
+
+func (t *tables) filterClusters(filter *icluster_conf.ClusterFilter) []*clusterRow {
+	if filter == nil {
+		return t.clusters
+	}
+
+	rst := []*clusterRow{}
+	for _, one := range t.clusters {
+		if !eqInt64(filter.ID, one.ID) || !inInt64s(filter.IDs, one.ID) ||
+			!eqString(filter.Name, one.Name) || !inStrings(filter.Names, one.Name) {
+			continue
+		}
+		if filter.Product != nil && filter.Product.ID != one.ProductID {
+			continue
+		}
+
+		rst = append(rst, one)
+	}
+	return rst
+}
+
+// clusterExisted check whether name is used by other cluster
+func (t *tables) clusterExisted(name string, exceptID int64) bool {
+	for _, one := range t.clusters {
+		if one.ID != exceptID && one.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory implements all storagers in memory, data is lost when process exit.
+// It is designed for unit and integration tests which don't want to depend on a database.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/model/iauth"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+// DB holds tables of all storagers, it can be shared by storagers only
+type DB struct {
+	mu sync.Mutex
+	t  *tables
+}
+
+type dbCtxKey struct{}
+
+// NewDB create DB with build-in records, the same as db_ddl.sql
+func NewDB() *DB {
+	t := &tables{
+		lastIDs:    map[string]int64{},
+		routeRules: map[int64]*routeRuleRow{},
+		lbMatrices: map[int64]map[string]map[string]int{},
+
+		rewriteRules:  map[int64][]*imodule_conf.RewriteRule{},
+		redirectRules: map[int64][]*imodule_conf.RedirectRule{},
+		blockRules:    map[int64][]*imodule_conf.BlockRule{},
+
+		tlsRules: map[int64]*iprotocol.TLSRule{},
+	}
+
+	now := time.Now()
+	t.users = append(t.users, &userRow{
+		ID:        t.nextID(tableUsers),
+		Name:      "admin",
+		Type:      iauth.UserTypeNormal,
+		Password:  "admin",
+		Scopes:    iauth.ScopeSystem,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	t.products = append(t.products, &ibasic.Product{
+		ID:                t.nextID(tableProducts),
+		Name:              "BFE",
+		Description:       "Build-in Product, User by System Manager",
+		MailList:          []string{"bfe@cncf.com"},
+		ContactPersonList: []string{"bfe"},
+		PhoneList:         []string{"no sms"},
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+
+	return &DB{
+		t: t,
+	}
+}
+
+// view run do with tables exclusively, do joins the transaction if ctx is in one.
+// Storagers call each other with ctx passed to do.
+func (db *DB) view(ctx context.Context, do func(context.Context, *tables) error) error {
+	if ctx.Value(dbCtxKey{}) == db {
+		return do(ctx, db.t)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return do(context.WithValue(ctx, dbCtxKey{}, db), db.t)
+}
+
+// atomExecute run do in transaction, all changes made by do are dropped if it fails.
+// Nested transaction will be finished by the outermost one.
+func (db *DB) atomExecute(ctx context.Context, do func(context.Context) error) (err error) {
+	if ctx.Value(dbCtxKey{}) == db {
+		return do(ctx)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	snapshot := deepCopy(db.t).(*tables)
+	committed := false
+	defer func() {
+		if !committed {
+			db.t = snapshot
+		}
+	}()
+
+	if err = do(context.WithValue(ctx, dbCtxKey{}, db)); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
+
+const (
+	tableProducts           = "products"
+	tableBFEClusters        = "bfe_clusters"
+	tableExtraFiles         = "extra_files"
+	tableUsers              = "users"
+	tableAudits             = "audits"
+	tableConfigVersions     = "config_versions"
+	tableDomains            = "domains"
+	tableRouteCases         = "route_cases"
+	tablePools              = "pools"
+	tableSubClusters        = "sub_clusters"
+	tableClusters           = "clusters"
+	tableActiveHealthChecks = "active_health_checks"
+	tableCertificates       = "certificates"
+	tableHeaderRules        = "mod_header_rules"
+)
+
+// tables records of each table are sorted by id
+type tables struct {
+	lastIDs map[string]int64
+
+	products     []*ibasic.Product
+	bfeClusters  []*ibasic.BFECluster
+	extraFiles   []*ibasic.ExtraFile
+	users        []*userRow
+	userProducts []*userProductRow
+	audits       []*iaudit.Audit
+
+	configVersions []*iversion_control.ConfigVersion
+	configPins     []*iversion_control.ConfigPin
+
+	domains    []*domainRow
+	routeRules map[int64]*routeRuleRow // key is product id
+	routeCases []*routeCaseRow
+
+	pools              []*poolRow
+	subClusters        []*subClusterRow
+	clusters           []*clusterRow
+	lbMatrices         map[int64]map[string]map[string]int // key is cluster id
+	activeHealthChecks []*icluster_conf.ActiveHealthCheck
+
+	certificates []*certificateRow
+	tlsRules     map[int64]*iprotocol.TLSRule // key is product id
+
+	headerRules   []*imodule_conf.HeaderRule
+	rewriteRules  map[int64][]*imodule_conf.RewriteRule  // key is product id
+	redirectRules map[int64][]*imodule_conf.RedirectRule // key is product id
+	blockRules    map[int64][]*imodule_conf.BlockRule    // key is product id
+}
+
+// nextID return auto increment id of table
+func (t *tables) nextID(table string) int64 {
+	t.lastIDs[table]++
+	return t.lastIDs[table]
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+)
+
+type domainRow struct {
+	iroute_conf.Domain
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type DomainStorager struct {
+	db *DB
+}
+
+var _ iroute_conf.DomainStorager = &DomainStorager{}
+
+func NewDomainStorager(db *DB) *DomainStorager {
+	return &DomainStorager{
+		db: db,
+	}
+}
+
+func (ds *DomainStorager) FetchDomains(ctx context.Context, filter *iroute_conf.DomainFilter) ([]*iroute_conf.Domain, error) {
+	var rst []*iroute_conf.Domain
+	err := ds.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterDomains(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		idx := paginate(p, len(list), func(i int) pageKey {
+			return pageKey{list[i].Name, list[i].CreatedAt, list[i].UpdatedAt}
+		})
+
+		rst = make([]*iroute_conf.Domain, len(idx))
+		for i, j := range idx {
+			one := list[j].Domain
+			rst[i] = &one
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (ds *DomainStorager) CountDomains(ctx context.Context, filter *iroute_conf.DomainFilter) (int64, error) {
+	var total int64
+	err := ds.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterDomains(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		total = countPage(p, len(list), func(i int) pageKey {
+			return pageKey{Name: list[i].Name}
+		})
+		return nil
+	})
+
+	return total, err
+}
+
+func (ds *DomainStorager) CreateDomain(ctx context.Context, product *ibasic.Product, param *iroute_conf.DomainParam) error {
+	param.ProductID = &product.ID
+
+	return ds.db.view(ctx, func(ctx context.Context, t *tables) error {
+		now := time.Now()
+		one := &domainRow{
+			Domain: iroute_conf.Domain{
+				ProductID: product.ID,
+			},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		domainAssign(one, param)
+
+		for _, old := range t.domains {
+			if old.Name == one.Name {
+				return xerror.WrapRecordExisted("Domain")
+			}
+		}
+
+		one.ID = t.nextID(tableDomains)
+		t.domains = append(t.domains, one)
+		return nil
+	})
+}
+
+func (ds *DomainStorager) UpdateDomain(ctx context.Context, domain *iroute_conf.Domain, param *iroute_conf.DomainParam) error {
+	return ds.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.domains {
+			if one.ID != domain.ID {
+				continue
+			}
+
+			if param.Name != nil && *param.Name != one.Name {
+				for _, old := range t.domains {
+					if old.Name == *param.Name {
+						return xerror.WrapRecordExisted("Domain")
+					}
+				}
+			}
+
+			domainAssign(one, param)
+			one.UpdatedAt = time.Now()
+		}
+
+		return nil
+	})
+}
+
+func (ds *DomainStorager) DeleteDomain(ctx context.Context, product *ibasic.Product, domain *iroute_conf.Domain) error {
+	return ds.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*domainRow{}
+		for _, one := range t.domains {
+			if one.ID != domain.ID {
+				list = append(list, one)
+			}
+		}
+		t.domains = list
+
+		return nil
+	})
+}
+
+func domainAssign(one *domainRow, param *iroute_conf.DomainParam) {
+	if param.ProductID != nil {
+		one.ProductID = *param.ProductID
+	}
+	if param.Name != nil {
+		one.Name = *param.Name
+	}
+	if param.UsingAdvancedRedirect != nil {
+		one.UsingAdvancedRedirect = *param.UsingAdvancedRedirect
+	}
+	if param.UsingAdvancedHsts != nil {
+		one.UsingAdvancedHsts = *param.UsingAdvancedHsts
+	}
+	if param.HstsMaxAge != nil {
+		one.HstsMaxAge = *param.HstsMaxAge
+	}
+	if param.HstsIncludeSubDomains != nil {
+		one.HstsIncludeSubDomains = *param.HstsIncludeSubDomains
+	}
+	if param.HstsPreload != nil {
+		one.HstsPreload = *param.HstsPreload
+	}
+}
+
+func (t *tables) filterDomains(filter *iroute_conf.DomainFilter) []*domainRow {
+	if filter == nil {
+		return t.domains
+	}
+
+	rst := []*domainRow{}
+	for _, one := range t.domains {
+		if (filter.Product == nil || filter.Product.ID == one.ProductID) && eqString(filter.Name, one.Name) {
+			rst = append(rst, one)
+		}
+	}
+	return rst
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+)
+
+type ExtraFileStorager struct {
+	db *DB
+}
+
+var _ ibasic.ExtraFileStorager = &ExtraFileStorager{}
+
+func NewExtraFileStorager(db *DB) *ExtraFileStorager {
+	return &ExtraFileStorager{
+		db: db,
+	}
+}
+
+func (es *ExtraFileStorager) FetchExtraFiles(ctx context.Context, filter *ibasic.ExtraFileFilter) ([]*ibasic.ExtraFile, error) {
+	var rst []*ibasic.ExtraFile
+	err := es.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.extraFiles {
+			if extraFileMatch(filter, one) {
+				rst = append(rst, deepCopy(one).(*ibasic.ExtraFile))
+			}
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (es *ExtraFileStorager) DeleteExtraFile(ctx context.Context, filter *ibasic.ExtraFileFilter) error {
+	return es.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*ibasic.ExtraFile{}
+		for _, one := range t.extraFiles {
+			if !extraFileMatch(filter, one) {
+				list = append(list, one)
+			}
+		}
+		t.extraFiles = list
+
+		return nil
+	})
+}
+
+func (es *ExtraFileStorager) CreateExtraFile(ctx context.Context, product *ibasic.Product, pps ...*ibasic.ExtraFileParam) error {
+	return es.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*ibasic.ExtraFile{}
+		for _, pp := range pps {
+			if pp.Md5 == nil {
+				pp.Md5 = []byte(fmt.Sprintf("%x", md5.Sum(pp.Content)))
+			}
+
+			one := &ibasic.ExtraFile{
+				ProductID: product.ID,
+				Md5:       append([]byte{}, pp.Md5...),
+				Content:   append([]byte{}, pp.Content...),
+			}
+			if pp.Name != nil {
+				one.Name = *pp.Name
+			}
+			if pp.Description != nil {
+				one.Description = *pp.Description
+			}
+
+			if extraFileExisted(t.extraFiles, one) || extraFileExisted(list, one) {
+				return xerror.WrapRecordExisted("Extra File")
+			}
+			list = append(list, one)
+		}
+
+		for _, one := range list {
+			one.ID = t.nextID(tableExtraFiles)
+			t.extraFiles = append(t.extraFiles, one)
+		}
+
+		return nil
+	})
+}
+
+func extraFileMatch(filter *ibasic.ExtraFileFilter, one *ibasic.ExtraFile) bool {
+	return filter == nil || (eqString(filter.Name, one.Name) && inStrings(filter.Names, one.Name))
+}
+
+func extraFileExisted(list []*ibasic.ExtraFile, one *ibasic.ExtraFile) bool {
+	for _, old := range list {
+		if old.Name == one.Name && old.ProductID == one.ProductID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+// helpers to match conditions of filter, nil condition matches everything like where clause of rdb
+
+func eqInt64(cond *int64, v int64) bool {
+	return cond == nil || *cond == v
+}
+
+func eqInt8(cond *int8, v int8) bool {
+	return cond == nil || *cond == v
+}
+
+func eqString(cond *string, v string) bool {
+	return cond == nil || *cond == v
+}
+
+func eqBool(cond *bool, v bool) bool {
+	return cond == nil || *cond == v
+}
+
+// inInt64s is the same as IN condition, empty but not nil list matches nothing
+func inInt64s(cond []int64, v int64) bool {
+	if cond == nil {
+		return true
+	}
+
+	for _, one := range cond {
+		if one == v {
+			return true
+		}
+	}
+	return false
+}
+
+func inInt8s(cond []int8, v int8) bool {
+	if cond == nil {
+		return true
+	}
+
+	for _, one := range cond {
+		if one == v {
+			return true
+		}
+	}
+	return false
+}
+
+func inStrings(cond []string, v string) bool {
+	if cond == nil {
+		return true
+	}
+
+	for _, one := range cond {
+		if one == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+)
+
+type HeaderRuleStorager struct {
+	db *DB
+}
+
+var _ imodule_conf.HeaderRuleStorager = &HeaderRuleStorager{}
+
+func NewHeaderRuleStorager(db *DB) *HeaderRuleStorager {
+	return &HeaderRuleStorager{
+		db: db,
+	}
+}
+
+func (hs *HeaderRuleStorager) FetchHeaderRules(ctx context.Context,
+	filter *imodule_conf.HeaderRuleFilter) (rst []*imodule_conf.HeaderRule, err error) {
+
+	err = hs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		rst = []*imodule_conf.HeaderRule{}
+		for _, one := range t.headerRules {
+			if filter != nil && (!eqInt64(filter.ID, one.ID) || !eqString(filter.Name, one.Name) ||
+				(filter.Product != nil && filter.Product.ID != one.ProductID)) {
+				continue
+			}
+			rst = append(rst, deepCopy(one).(*imodule_conf.HeaderRule))
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (hs *HeaderRuleStorager) CreateHeaderRule(ctx context.Context, product *ibasic.Product,
+	param *imodule_conf.HeaderRuleParam) (id int64, err error) {
+
+	err = hs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if param.Name != nil && t.headerRuleByName(product.ID, *param.Name) != nil {
+			return xerror.WrapRecordExisted("Header Rule")
+		}
+
+		now := time.Now()
+		one := &imodule_conf.HeaderRule{
+			ID:        t.nextID(tableHeaderRules),
+			ProductID: product.ID,
+			Actions:   []*imodule_conf.HeaderAction{},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		headerRuleAssign(one, param)
+
+		t.headerRules = append(t.headerRules, one)
+
+		id = one.ID
+		return nil
+	})
+
+	return id, err
+}
+
+func (hs *HeaderRuleStorager) UpdateHeaderRule(ctx context.Context, product *ibasic.Product,
+	old *imodule_conf.HeaderRule, param *imodule_conf.HeaderRuleParam) error {
+
+	return hs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if param.Name != nil {
+			if other := t.headerRuleByName(product.ID, *param.Name); other != nil && other.ID != old.ID {
+				return xerror.WrapRecordExisted("Header Rule")
+			}
+		}
+
+		for _, one := range t.headerRules {
+			if one.ID == old.ID && one.ProductID == product.ID {
+				headerRuleAssign(one, param)
+				one.UpdatedAt = time.Now()
+			}
+		}
+		return nil
+	})
+}
+
+func (hs *HeaderRuleStorager) DeleteHeaderRule(ctx context.Context, product *ibasic.Product,
+	rule *imodule_conf.HeaderRule) error {
+
+	return hs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*imodule_conf.HeaderRule{}
+		for _, one := range t.headerRules {
+			if one.ID != rule.ID || one.ProductID != product.ID {
+				list = append(list, one)
+			}
+		}
+		t.headerRules = list
+
+		return nil
+	})
+}
+
+func (t *tables) headerRuleByName(productID int64, name string) *imodule_conf.HeaderRule {
+	for _, one := range t.headerRules {
+		if one.ProductID == productID && one.Name == name {
+			return one
+		}
+	}
+
+	return nil
+}
+
+func headerRuleAssign(one *imodule_conf.HeaderRule, param *imodule_conf.HeaderRuleParam) {
+	if param.Name != nil {
+		one.Name = *param.Name
+	}
+	if param.Description != nil {
+		one.Description = *param.Description
+	}
+	if param.Cond != nil {
+		one.Cond = *param.Cond
+	}
+	if param.Actions != nil {
+		one.Actions = deepCopy(param.Actions).([]*imodule_conf.HeaderAction)
+	}
+	if param.Last != nil {
+		one.Last = *param.Last
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/ibundle"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/model/iversion_control"
+	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/api-server/stateful/container/memory"
+)
+
+// createProduct init container with a new memory db, and create product in it
+func createProduct(t *testing.T, name string) *ibasic.Product {
+	ctx := context.Background()
+	memory.Init()
+
+	if err := container.ProductManager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              &name,
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	list, err := container.ProductManager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: &name,
+	})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchProducts: %v %v", list, err)
+	}
+
+	return list[0]
+}
+
+func TestAtomExecuteRollback(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	errAbort := errors.New("abort")
+	err := container.TxnStoragerSingleton.AtomExecute(ctx, func(ctx context.Context) error {
+		if err := container.DomainManager.CreateDomain(ctx, product, &iroute_conf.DomainParam{
+			Name: lib.PString("example.org"),
+		}); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("AtomExecute: want %v, got %v", errAbort, err)
+	}
+
+	list, err := container.DomainManager.DomainList(ctx, &iroute_conf.DomainFilter{
+		Product: product,
+	})
+	if err != nil {
+		t.Fatalf("DomainList: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("DomainList: want no domain after rollback, got %d", len(list))
+	}
+}
+
+func TestPoolInstances(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	name := "demo.pool"
+	if _, err := container.PoolManager.CreateProductPool(ctx, product, &icluster_conf.PoolParam{
+		Name: &name,
+		Instances: []icluster_conf.Instance{
+			{HostName: "host1", IP: "10.0.0.1", Port: 80, Weight: 1},
+		},
+	}); err != nil {
+		t.Fatalf("CreateProductPool: %v", err)
+	}
+
+	if _, err := container.PoolManager.RemovePoolInstance(ctx, product, name, "host1", ""); err == nil {
+		t.Fatalf("RemovePoolInstance: want error removing the last instance")
+	}
+
+	if _, err := container.PoolManager.AddPoolInstance(ctx, product, name, icluster_conf.Instance{
+		HostName: "host2", IP: "10.0.0.2", Port: 80, Weight: 1,
+	}, ""); err != nil {
+		t.Fatalf("AddPoolInstance: %v", err)
+	}
+
+	pool, err := container.PoolManager.RemovePoolInstance(ctx, product, name, "10.0.0.1", "")
+	if err != nil {
+		t.Fatalf("RemovePoolInstance: %v", err)
+	}
+	if len(pool.Instances) != 1 || pool.Instances[0].HostName != "host2" {
+		t.Fatalf("RemovePoolInstance: want host2 left, got %+v", pool.Instances)
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	name := "demo.pool"
+	if _, err := container.PoolManager.CreateProductPool(ctx, product, &icluster_conf.PoolParam{
+		Name: &name,
+		Instances: []icluster_conf.Instance{
+			{HostName: "host1", IP: "10.0.0.1", Port: 80, Weight: 1},
+		},
+	}); err != nil {
+		t.Fatalf("CreateProductPool: %v", err)
+	}
+	if err := container.DomainManager.CreateDomain(ctx, product, &iroute_conf.DomainParam{
+		Name: lib.PString("example.org"),
+	}); err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+
+	if err := container.ProductManager.DeleteProduct(ctx, product); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+
+	products, err := container.ProductManager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString("demo"),
+	})
+	if err != nil || len(products) != 0 {
+		t.Fatalf("FetchProducts: want no product, got %v %v", products, err)
+	}
+	if one, err := container.PoolManager.FetchPoolByName(ctx, name); err != nil || one != nil {
+		t.Fatalf("FetchPoolByName: want nil, got %v %v", one, err)
+	}
+	domains, err := container.DomainManager.DomainList(ctx, &iroute_conf.DomainFilter{
+		Name: lib.PString("example.org"),
+	})
+	if err != nil || len(domains) != 0 {
+		t.Fatalf("DomainList: want no domain, got %v %v", domains, err)
+	}
+}
+
+func TestFetchProductPage(t *testing.T) {
+	ctx := context.Background()
+	createProduct(t, "demo")
+	for _, name := range []string{"demo_b", "demo_a", "other"} {
+		if err := container.ProductManager.CreateProduct(ctx, &ibasic.ProductParam{
+			Name:              lib.PString(name),
+			Description:       lib.PString(""),
+			MailList:          []string{},
+			PhoneList:         []string{},
+			ContactPersonList: []string{},
+		}); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+	}
+
+	list, total, err := container.ProductManager.FetchProductPage(ctx, &ibasic.ProductFilter{
+		Pagination: &lib.Pagination{
+			Page:     2,
+			PageSize: 2,
+			Filter:   "demo",
+		},
+	})
+	if err != nil {
+		t.Fatalf("FetchProductPage: %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("FetchProductPage: want total 3, got %d", total)
+	}
+	if len(list) != 1 || list[0].Name != "demo_b" {
+		t.Fatalf("FetchProductPage: want [demo_b], got %v", list)
+	}
+}
+
+func TestApplyProductBundle(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	plan := func(current *ibundle.Bundle) ([]*iversion_control.DiffEntry, error) {
+		return []*iversion_control.DiffEntry{
+			{Resource: ibundle.ResourcePool, Key: "demo.pool", Action: iversion_control.DiffActionAdded},
+			{Resource: ibundle.ResourceSubCluster, Key: "demo_sub", Action: iversion_control.DiffActionAdded},
+		}, nil
+	}
+	newParam := func(poolName string) *ibundle.BundleParam {
+		return &ibundle.BundleParam{
+			Pools: []*icluster_conf.PoolParam{
+				{
+					Name: lib.PString("demo.pool"),
+					Instances: []icluster_conf.Instance{
+						{HostName: "host1", IP: "10.0.0.1", Port: 80, Weight: 1},
+					},
+				},
+			},
+			SubClusters: []*icluster_conf.SubClusterParam{
+				{Name: lib.PString("demo_sub"), PoolName: lib.PString(poolName)},
+			},
+		}
+	}
+	fetch := func() *ibundle.Bundle {
+		bundle, err := container.BundleManager.FetchProductBundle(ctx, product)
+		if err != nil {
+			t.Fatalf("FetchProductBundle: %v", err)
+		}
+		return bundle
+	}
+
+	changes, err := container.BundleManager.ApplyProductBundle(ctx, product.Name, newParam("demo.pool"), plan, true)
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("ApplyProductBundle dry run: want 2 changes, got %v %v", changes, err)
+	}
+	if bundle := fetch(); len(bundle.Pools) != 0 {
+		t.Fatalf("ApplyProductBundle dry run: want nothing applied, got %v", bundle.Pools)
+	}
+
+	// sub cluster fails to be created, pool created before it must be rolled back
+	if _, err = container.BundleManager.ApplyProductBundle(ctx, product.Name, newParam("demo.none"), plan, false); err == nil {
+		t.Fatalf("ApplyProductBundle: want error for sub cluster of unknown pool")
+	}
+	if bundle := fetch(); len(bundle.Pools) != 0 {
+		t.Fatalf("ApplyProductBundle: want pools rolled back, got %v", bundle.Pools)
+	}
+
+	if _, err = container.BundleManager.ApplyProductBundle(ctx, product.Name, newParam("demo.pool"), plan, false); err != nil {
+		t.Fatalf("ApplyProductBundle: %v", err)
+	}
+	bundle := fetch()
+	if len(bundle.Pools) != 1 || len(bundle.SubClusters) != 1 {
+		t.Fatalf("ApplyProductBundle: want pool and sub cluster created, got %v %v", bundle.Pools, bundle.SubClusters)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+)
+
+// pageKey fields of record which can be used by pagination
+type pageKey struct {
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// paginate return indexes of records in page p, the same as PageParam of rdb.
+// n is number of records sorted by id, key return pageKey of the i-th record.
+// Names are matched and sorted case-insensitively like utf8 collation of mysql.
+func paginate(p *lib.Pagination, n int, key func(int) pageKey) []int {
+	rst := []int{}
+	for i := 0; i < n; i++ {
+		if p == nil || p.Filter == "" ||
+			strings.HasPrefix(strings.ToLower(key(i).Name), strings.ToLower(p.Filter)) {
+			rst = append(rst, i)
+		}
+	}
+	if p == nil {
+		return rst
+	}
+
+	sort.SliceStable(rst, func(i, j int) bool {
+		a, b := key(rst[i]), key(rst[j])
+		if p.Desc() {
+			a, b = b, a
+		}
+
+		switch p.SortField() {
+		case lib.SortByCreatedAt:
+			return a.CreatedAt.Before(b.CreatedAt)
+		case lib.SortByUpdatedAt:
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	if p.PageSize > 0 {
+		offset := p.Offset()
+		if offset > len(rst) {
+			offset = len(rst)
+		}
+		end := offset + p.PageSize
+		if end > len(rst) {
+			end = len(rst)
+		}
+		rst = rst[offset:end]
+	}
+
+	return rst
+}
+
+// countPage return number of records match p, page and sort are ignored
+func countPage(p *lib.Pagination, n int, key func(int) pageKey) int64 {
+	if p != nil {
+		p = &lib.Pagination{
+			Filter: p.Filter,
+		}
+	}
+
+	return int64(len(paginate(p, n, key)))
+}
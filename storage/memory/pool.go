@@ -0,0 +1,245 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+)
+
+type poolRow struct {
+	ID        int64
+	Name      string
+	ProductID int64
+	Ready     bool
+	Instances []icluster_conf.Instance
+	Tag       int8
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PoolStorager struct {
+	db *DB
+
+	productStorager ibasic.ProductStorager
+}
+
+var _ icluster_conf.PoolStorager = &PoolStorager{}
+
+func NewPoolStorager(db *DB, productStorager ibasic.ProductStorager) *PoolStorager {
+	return &PoolStorager{
+		db:              db,
+		productStorager: productStorager,
+	}
+}
+
+func (ps *PoolStorager) CreatePool(ctx context.Context, product *ibasic.Product,
+	data *icluster_conf.PoolParam) (*icluster_conf.Pool, error) {
+
+	data.ProductID = &product.ID
+
+	var pool *icluster_conf.Pool
+	err := ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		now := time.Now()
+		one := &poolRow{
+			Ready:     true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		poolAssign(one, data)
+
+		if t.poolByName(one.Name) != nil {
+			return xerror.WrapRecordExisted("Pool")
+		}
+
+		if data.ID == nil {
+			one.ID = t.nextID(tablePools)
+		}
+		t.pools = append(t.pools, one)
+
+		pool = &icluster_conf.Pool{
+			ID:        one.ID,
+			Name:      *data.Name,
+			Instances: data.Instances,
+		}
+		return nil
+	})
+
+	return pool, err
+}
+
+func (ps *PoolStorager) FetchPool(ctx context.Context, name string) (*icluster_conf.Pool, error) {
+	list, err := ps.FetchPools(ctx, &icluster_conf.PoolFilter{
+		Name: &name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+// LockPool fetch pool, DB is locked by transaction until it be finished
+func (ps *PoolStorager) LockPool(ctx context.Context, name string) (*icluster_conf.Pool, error) {
+	return ps.FetchPool(ctx, name)
+}
+
+func (ps *PoolStorager) FetchPools(ctx context.Context, filter *icluster_conf.PoolFilter) (rst []*icluster_conf.Pool, err error) {
+	err = ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterPools(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		idx := paginate(p, len(list), func(i int) pageKey {
+			return pageKey{list[i].Name, list[i].CreatedAt, list[i].UpdatedAt}
+		})
+		if len(idx) == 0 {
+			return nil
+		}
+
+		productIDs := map[int64]bool{}
+		for _, i := range idx {
+			productIDs[list[i].ProductID] = true
+		}
+		productList, err := ps.productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+			IDs: lib.Int64BoolMap2Slice(productIDs),
+		})
+		if err != nil {
+			return err
+		}
+		productMap := ibasic.ProductIDMap(productList)
+
+		rst = []*icluster_conf.Pool{}
+		for _, i := range idx {
+			one := list[i]
+			rst = append(rst, &icluster_conf.Pool{
+				ID:        one.ID,
+				Name:      one.Name,
+				Ready:     one.Ready,
+				Product:   productMap[one.ProductID],
+				Instances: deepCopy(one.Instances).([]icluster_conf.Instance),
+				Tag:       one.Tag,
+			})
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (ps *PoolStorager) CountPools(ctx context.Context, filter *icluster_conf.PoolFilter) (total int64, err error) {
+	err = ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterPools(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		total = countPage(p, len(list), func(i int) pageKey {
+			return pageKey{Name: list[i].Name}
+		})
+		return nil
+	})
+
+	return total, err
+}
+
+func (ps *PoolStorager) UpdatePool(ctx context.Context, oldData *icluster_conf.Pool,
+	diff *icluster_conf.PoolParam) error {
+
+	return ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.pools {
+			if one.ID != oldData.ID {
+				continue
+			}
+
+			if diff.Name != nil && *diff.Name != one.Name && t.poolByName(*diff.Name) != nil {
+				return xerror.WrapRecordExisted("Pool")
+			}
+
+			poolAssign(one, diff)
+			one.UpdatedAt = time.Now()
+		}
+
+		return nil
+	})
+}
+
+func (ps *PoolStorager) DeletePool(ctx context.Context, pool *icluster_conf.Pool) error {
+	return ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*poolRow{}
+		for _, one := range t.pools {
+			if one.Name != pool.Name {
+				list = append(list, one)
+			}
+		}
+		t.pools = list
+
+		return nil
+	})
+}
+
+func poolAssign(one *poolRow, data *icluster_conf.PoolParam) {
+	if data.ID != nil {
+		one.ID = *data.ID
+	}
+	if data.Name != nil {
+		one.Name = *data.Name
+	}
+	if data.ProductID != nil {
+		one.ProductID = *data.ProductID
+	}
+	if data.Instances != nil {
+		one.Instances = deepCopy(data.Instances).([]icluster_conf.Instance)
+	}
+	if data.Tag != nil {
+		one.Tag = *data.Tag
+	}
+}
+
+func (t *tables) filterPools(filter *icluster_conf.PoolFilter) []*poolRow {
+	if filter == nil {
+		return t.pools
+	}
+
+	rst := []*poolRow{}
+	for _, one := range t.pools {
+		if eqInt64(filter.ID, one.ID) && inInt64s(filter.IDs, one.ID) &&
+			eqString(filter.Name, one.Name) && eqInt64(filter.ProductID, one.ProductID) {
+			rst = append(rst, one)
+		}
+	}
+	return rst
+}
+
+func (t *tables) poolByName(name string) *poolRow {
+	for _, one := range t.pools {
+		if one.Name == name {
+			return one
+		}
+	}
+	return nil
+}
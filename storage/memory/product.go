@@ -0,0 +1,276 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+)
+
+type ProductStorager struct {
+	db *DB
+}
+
+var _ ibasic.ProductStorager = &ProductStorager{}
+
+func NewProductStorager(db *DB) *ProductStorager {
+	return &ProductStorager{
+		db: db,
+	}
+}
+
+// DeleteProduct delete product and all resources belong to it
+func (ps *ProductStorager) DeleteProduct(ctx context.Context, product *ibasic.Product) error {
+	return ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		t.deleteProduct(product.ID)
+		return nil
+	})
+}
+
+func (ps *ProductStorager) CreateProduct(ctx context.Context, pp *ibasic.ProductParam) error {
+	return ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if pp.Name != nil && t.productByName(*pp.Name) != nil {
+			return xerror.WrapRecordExisted("Product")
+		}
+
+		now := time.Now()
+		one := &ibasic.Product{
+			Description:       "no desc",
+			MailList:          []string{},
+			PhoneList:         []string{"no sms"},
+			ContactPersonList: []string{},
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		productAssign(one, pp)
+		if pp.ID == nil {
+			one.ID = t.nextID(tableProducts)
+		}
+
+		t.products = append(t.products, one)
+		return nil
+	})
+}
+
+func (ps *ProductStorager) UpdateProduct(ctx context.Context, product *ibasic.Product,
+	pp *ibasic.ProductParam) error {
+
+	return ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		one := t.productByName(product.Name)
+		if one == nil {
+			return nil
+		}
+		if pp.Name != nil && *pp.Name != one.Name && t.productByName(*pp.Name) != nil {
+			return xerror.WrapRecordExisted("Product")
+		}
+
+		productAssign(one, pp)
+		one.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (ps *ProductStorager) FetchProducts(ctx context.Context, filter *ibasic.ProductFilter) ([]*ibasic.Product, error) {
+	var rst []*ibasic.Product
+	err := ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterProducts(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		idx := paginate(p, len(list), func(i int) pageKey {
+			return pageKey{list[i].Name, list[i].CreatedAt, list[i].UpdatedAt}
+		})
+
+		rst = make([]*ibasic.Product, len(idx))
+		for i, j := range idx {
+			rst[i] = deepCopy(list[j]).(*ibasic.Product)
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (ps *ProductStorager) CountProducts(ctx context.Context, filter *ibasic.ProductFilter) (int64, error) {
+	var total int64
+	err := ps.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterProducts(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		total = countPage(p, len(list), func(i int) pageKey {
+			return pageKey{Name: list[i].Name}
+		})
+		return nil
+	})
+
+	return total, err
+}
+
+func productAssign(one *ibasic.Product, pp *ibasic.ProductParam) {
+	if pp.ID != nil {
+		one.ID = *pp.ID
+	}
+	if pp.Name != nil {
+		one.Name = *pp.Name
+	}
+	if pp.Description != nil {
+		one.Description = *pp.Description
+	}
+	if pp.MailList != nil {
+		one.MailList = append([]string{}, pp.MailList...)
+	}
+	if pp.PhoneList != nil {
+		one.PhoneList = append([]string{}, pp.PhoneList...)
+	}
+	if pp.ContactPersonList != nil {
+		one.ContactPersonList = append([]string{}, pp.ContactPersonList...)
+	}
+}
+
+func (t *tables) filterProducts(filter *ibasic.ProductFilter) []*ibasic.Product {
+	if filter == nil {
+		return t.products
+	}
+
+	rst := []*ibasic.Product{}
+	for _, one := range t.products {
+		if eqInt64(filter.ID, one.ID) && (filter.NeID == nil || *filter.NeID != one.ID) &&
+			inInt64s(filter.IDs, one.ID) && eqString(filter.Name, one.Name) {
+			rst = append(rst, one)
+		}
+	}
+	return rst
+}
+
+func (t *tables) productByName(name string) *ibasic.Product {
+	for _, one := range t.products {
+		if one.Name == name {
+			return one
+		}
+	}
+	return nil
+}
+
+func (t *tables) productByID(id int64) *ibasic.Product {
+	for _, one := range t.products {
+		if one.ID == id {
+			return one
+		}
+	}
+	return nil
+}
+
+// deleteProduct delete product and all resources belong to it, the same as TProductDeleteByProductID of rdb
+func (t *tables) deleteProduct(productID int64) {
+	products := []*ibasic.Product{}
+	for _, one := range t.products {
+		if one.ID != productID {
+			products = append(products, one)
+		}
+	}
+	t.products = products
+
+	domains := []*domainRow{}
+	for _, one := range t.domains {
+		if one.ProductID != productID {
+			domains = append(domains, one)
+		}
+	}
+	t.domains = domains
+
+	clusters := []*clusterRow{}
+	for _, one := range t.clusters {
+		if one.ProductID != productID {
+			clusters = append(clusters, one)
+			continue
+		}
+		delete(t.lbMatrices, one.ID)
+	}
+	t.clusters = clusters
+
+	subClusters := []*subClusterRow{}
+	for _, one := range t.subClusters {
+		if one.ProductID != productID {
+			subClusters = append(subClusters, one)
+		}
+	}
+	t.subClusters = subClusters
+
+	pools := []*poolRow{}
+	for _, one := range t.pools {
+		if one.ProductID != productID {
+			pools = append(pools, one)
+		}
+	}
+	t.pools = pools
+
+	delete(t.routeRules, productID)
+
+	routeCases := []*routeCaseRow{}
+	for _, one := range t.routeCases {
+		if one.ProductID != productID {
+			routeCases = append(routeCases, one)
+		}
+	}
+	t.routeCases = routeCases
+
+	userProducts := []*userProductRow{}
+	for _, one := range t.userProducts {
+		if one.ProductID != productID {
+			userProducts = append(userProducts, one)
+		}
+	}
+	t.userProducts = userProducts
+
+	extraFiles := []*ibasic.ExtraFile{}
+	for _, one := range t.extraFiles {
+		if one.ProductID != productID {
+			extraFiles = append(extraFiles, one)
+		}
+	}
+	t.extraFiles = extraFiles
+
+	activeHealthChecks := []*icluster_conf.ActiveHealthCheck{}
+	for _, one := range t.activeHealthChecks {
+		if one.ProductID != productID {
+			activeHealthChecks = append(activeHealthChecks, one)
+		}
+	}
+	t.activeHealthChecks = activeHealthChecks
+
+	headerRules := []*imodule_conf.HeaderRule{}
+	for _, one := range t.headerRules {
+		if one.ProductID != productID {
+			headerRules = append(headerRules, one)
+		}
+	}
+	t.headerRules = headerRules
+
+	delete(t.rewriteRules, productID)
+	delete(t.redirectRules, productID)
+	delete(t.blockRules, productID)
+	delete(t.tlsRules, productID)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+)
+
+type RedirectRuleStorager struct {
+	db *DB
+}
+
+var _ imodule_conf.RedirectRuleStorager = &RedirectRuleStorager{}
+
+func NewRedirectRuleStorager(db *DB) *RedirectRuleStorager {
+	return &RedirectRuleStorager{
+		db: db,
+	}
+}
+
+func (rs *RedirectRuleStorager) FetchRedirectRules(ctx context.Context,
+	filter *imodule_conf.RedirectRuleFilter) (rst []*imodule_conf.RedirectRule, err error) {
+
+	err = rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		rst = []*imodule_conf.RedirectRule{}
+		// products are sorted by id, so as rules
+		for _, product := range t.products {
+			if filter != nil && filter.Product != nil && filter.Product.ID != product.ID {
+				continue
+			}
+			for _, one := range t.redirectRules[product.ID] {
+				rst = append(rst, deepCopy(one).(*imodule_conf.RedirectRule))
+			}
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (rs *RedirectRuleStorager) UpsertProductRedirectRules(ctx context.Context, product *ibasic.Product,
+	rules []*imodule_conf.RedirectRule) error {
+
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if len(rules) == 0 {
+			delete(t.redirectRules, product.ID)
+			return nil
+		}
+
+		list := deepCopy(rules).([]*imodule_conf.RedirectRule)
+		for i, one := range list {
+			one.ProductID = product.ID
+			one.Index = i
+		}
+		t.redirectRules[product.ID] = list
+		return nil
+	})
+}
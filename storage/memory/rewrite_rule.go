@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+)
+
+type RewriteRuleStorager struct {
+	db *DB
+}
+
+var _ imodule_conf.RewriteRuleStorager = &RewriteRuleStorager{}
+
+func NewRewriteRuleStorager(db *DB) *RewriteRuleStorager {
+	return &RewriteRuleStorager{
+		db: db,
+	}
+}
+
+func (rs *RewriteRuleStorager) FetchRewriteRules(ctx context.Context,
+	filter *imodule_conf.RewriteRuleFilter) (rst []*imodule_conf.RewriteRule, err error) {
+
+	err = rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		rst = []*imodule_conf.RewriteRule{}
+		// products are sorted by id, so as rules
+		for _, product := range t.products {
+			if filter != nil && filter.Product != nil && filter.Product.ID != product.ID {
+				continue
+			}
+			for _, one := range t.rewriteRules[product.ID] {
+				rst = append(rst, deepCopy(one).(*imodule_conf.RewriteRule))
+			}
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (rs *RewriteRuleStorager) UpsertProductRewriteRules(ctx context.Context, product *ibasic.Product,
+	rules []*imodule_conf.RewriteRule) error {
+
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if len(rules) == 0 {
+			delete(t.rewriteRules, product.ID)
+			return nil
+		}
+
+		list := deepCopy(rules).([]*imodule_conf.RewriteRule)
+		for i, one := range list {
+			one.ProductID = product.ID
+			one.Index = i
+		}
+		t.rewriteRules[product.ID] = list
+		return nil
+	})
+}
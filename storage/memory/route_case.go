@@ -0,0 +1,133 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+)
+
+type routeCaseRow struct {
+	iroute_conf.RouteRuleCase
+
+	ProductID int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RouteCaseStorager struct {
+	db *DB
+}
+
+var _ iroute_conf.RouteCaseStorager = &RouteCaseStorager{}
+
+func NewRouteCaseStorager(db *DB) *RouteCaseStorager {
+	return &RouteCaseStorager{
+		db: db,
+	}
+}
+
+func (rs *RouteCaseStorager) FetchRouteCases(ctx context.Context, filter *iroute_conf.RouteCaseFilter) ([]*iroute_conf.RouteRuleCase, error) {
+	rst := []*iroute_conf.RouteRuleCase{}
+	err := rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.routeCases {
+			if filter != nil && (!eqInt64(filter.ID, one.ID) ||
+				(filter.Product != nil && filter.Product.ID != one.ProductID)) {
+				continue
+			}
+
+			rst = append(rst, deepCopy(&one.RouteRuleCase).(*iroute_conf.RouteRuleCase))
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (rs *RouteCaseStorager) CreateRouteCase(ctx context.Context, product *ibasic.Product,
+	param *iroute_conf.RouteCaseParam) (id int64, err error) {
+
+	err = rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		now := time.Now()
+		one := &routeCaseRow{
+			RouteRuleCase: iroute_conf.RouteRuleCase{
+				Header: map[string]string{},
+			},
+			ProductID: product.ID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		routeCaseAssign(one, param)
+
+		one.ID = t.nextID(tableRouteCases)
+		t.routeCases = append(t.routeCases, one)
+
+		id = one.ID
+		return nil
+	})
+
+	return id, err
+}
+
+func (rs *RouteCaseStorager) UpdateRouteCase(ctx context.Context, product *ibasic.Product,
+	old *iroute_conf.RouteRuleCase, param *iroute_conf.RouteCaseParam) error {
+
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.routeCases {
+			if one.ID == old.ID && one.ProductID == product.ID {
+				routeCaseAssign(one, param)
+				one.UpdatedAt = time.Now()
+			}
+		}
+		return nil
+	})
+}
+
+func (rs *RouteCaseStorager) DeleteRouteCase(ctx context.Context, product *ibasic.Product,
+	rc *iroute_conf.RouteRuleCase) error {
+
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*routeCaseRow{}
+		for _, one := range t.routeCases {
+			if one.ID != rc.ID || one.ProductID != product.ID {
+				list = append(list, one)
+			}
+		}
+		t.routeCases = list
+
+		return nil
+	})
+}
+
+func routeCaseAssign(one *routeCaseRow, param *iroute_conf.RouteCaseParam) {
+	if param.Description != nil {
+		one.Description = *param.Description
+	}
+	if param.URL != nil {
+		one.URL = *param.URL
+	}
+	if param.Method != nil {
+		one.Method = *param.Method
+	}
+	if param.Header != nil {
+		one.Header = deepCopy(param.Header).(map[string]string)
+	}
+	if param.ExpectCluster != nil {
+		one.ExpectCluster = *param.ExpectCluster
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+)
+
+// routeRuleRow route rules of product, cluster is referenced by ClusterID
+type routeRuleRow struct {
+	BasicRouteRules   []*iroute_conf.BasicRouteRule
+	AdvanceRouteRules []*iroute_conf.AdvanceRouteRule
+}
+
+type RouteRuleStorager struct {
+	db *DB
+}
+
+var _ iroute_conf.RouteRuleStorager = &RouteRuleStorager{}
+
+func NewRouteRuleStorager(db *DB) *RouteRuleStorager {
+	return &RouteRuleStorager{
+		db: db,
+	}
+}
+
+// LockProductRule do nothing but wait for other transactions,
+// DB is locked by transaction until it be finished
+func (rs *RouteRuleStorager) LockProductRule(ctx context.Context, product *ibasic.Product) error {
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		return nil
+	})
+}
+
+func (rs *RouteRuleStorager) UpsertProductRule(ctx context.Context, product *ibasic.Product,
+	rule *iroute_conf.ProductRouteRule) error {
+
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		if len(rule.BasicRouteRules) == 0 && len(rule.AdvanceRouteRules) == 0 {
+			delete(t.routeRules, product.ID)
+			return nil
+		}
+
+		t.routeRules[product.ID] = &routeRuleRow{
+			BasicRouteRules:   deepCopy(rule.BasicRouteRules).([]*iroute_conf.BasicRouteRule),
+			AdvanceRouteRules: deepCopy(rule.AdvanceRouteRules).([]*iroute_conf.AdvanceRouteRule),
+		}
+		return nil
+	})
+}
+
+func (rs *RouteRuleStorager) FetchProductRule(ctx context.Context, product *ibasic.Product,
+	clusterList []*icluster_conf.Cluster) (*iroute_conf.ProductRouteRule, error) {
+
+	m, err := rs.FetchRoutRules(ctx, []*ibasic.Product{product}, clusterList)
+	if err != nil {
+		return nil, err
+	}
+
+	return m[product.ID], nil
+}
+
+// FetchRoutRules return route rules of products, rules whose cluster not in clusterList are dropped
+func (rs *RouteRuleStorager) FetchRoutRules(ctx context.Context, products []*ibasic.Product,
+	clusterList []*icluster_conf.Cluster) (map[int64]*iroute_conf.ProductRouteRule, error) {
+
+	clusterMap := icluster_conf.ClusterList2MapByID(clusterList)
+
+	rst := map[int64]*iroute_conf.ProductRouteRule{}
+	err := rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, product := range products {
+			row := t.routeRules[product.ID]
+			if row == nil {
+				continue
+			}
+
+			rule := &iroute_conf.ProductRouteRule{}
+			for _, one := range row.AdvanceRouteRules {
+				cluster := clusterMap[one.ClusterID]
+				if cluster == nil {
+					continue
+				}
+
+				advanceRule := deepCopy(one).(*iroute_conf.AdvanceRouteRule)
+				advanceRule.ClusterName = cluster.Name
+				rule.AdvanceRouteRules = append(rule.AdvanceRouteRules, advanceRule)
+			}
+
+			for _, one := range row.BasicRouteRules {
+				cluster := clusterMap[one.ClusterID]
+				if cluster == nil {
+					continue
+				}
+
+				basicRule := deepCopy(one).(*iroute_conf.BasicRouteRule)
+				basicRule.ClusterName = cluster.Name
+				rule.BasicRouteRules = append(rule.BasicRouteRules, basicRule)
+			}
+
+			rst[product.ID] = rule
+		}
+		return nil
+	})
+
+	return rst, err
+}
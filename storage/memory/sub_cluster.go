@@ -0,0 +1,253 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+)
+
+type subClusterRow struct {
+	ID               int64
+	Name             string
+	ClusterID        int64
+	ProductID        int64
+	PoolID           int64
+	Description      string
+	Enabled          bool
+	PortName         string
+	InstanceSelector map[string]string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SubClusterStorager struct {
+	db *DB
+
+	poolStorager icluster_conf.PoolStorager
+}
+
+var _ icluster_conf.SubClusterStorager = &SubClusterStorager{}
+
+func NewSubClusterStorager(db *DB, poolStorager icluster_conf.PoolStorager) *SubClusterStorager {
+	return &SubClusterStorager{
+		db:           db,
+		poolStorager: poolStorager,
+	}
+}
+
+func (ss *SubClusterStorager) FetchSubClusterList(ctx context.Context,
+	filter *icluster_conf.SubClusterFilter) (rst []*icluster_conf.SubCluster, err error) {
+
+	err = ss.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterSubClusters(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		idx := paginate(p, len(list), func(i int) pageKey {
+			return pageKey{list[i].Name, list[i].CreatedAt, list[i].UpdatedAt}
+		})
+		if len(idx) == 0 {
+			return nil
+		}
+
+		poolIDs := map[int64]bool{}
+		for _, i := range idx {
+			poolIDs[list[i].PoolID] = true
+		}
+		poolList, err := ss.poolStorager.FetchPools(ctx, &icluster_conf.PoolFilter{
+			IDs: lib.Int64BoolMap2Slice(poolIDs),
+		})
+		if err != nil {
+			return err
+		}
+		poolMap := icluster_conf.PoolList2Map(poolList)
+
+		rst = []*icluster_conf.SubCluster{}
+		for _, i := range idx {
+			one := list[i]
+			subCluster := &icluster_conf.SubCluster{
+				ID:          one.ID,
+				Name:        one.Name,
+				Enabled:     one.Enabled,
+				Description: one.Description,
+				ClusterID:   one.ClusterID,
+
+				InstancePool: poolMap[one.PoolID],
+
+				PortName:         one.PortName,
+				InstanceSelector: deepCopy(one.InstanceSelector).(map[string]string),
+			}
+			if subCluster.InstancePool != nil {
+				subCluster.Ready = subCluster.InstancePool.Ready
+			}
+			rst = append(rst, subCluster)
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (ss *SubClusterStorager) CountSubClusters(ctx context.Context,
+	filter *icluster_conf.SubClusterFilter) (total int64, err error) {
+
+	err = ss.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterSubClusters(filter)
+
+		var p *lib.Pagination
+		if filter != nil {
+			p = filter.Pagination
+		}
+		total = countPage(p, len(list), func(i int) pageKey {
+			return pageKey{Name: list[i].Name}
+		})
+		return nil
+	})
+
+	return total, err
+}
+
+func (ss *SubClusterStorager) CreateSubCluster(ctx context.Context, param *icluster_conf.SubClusterParam) error {
+	return ss.db.view(ctx, func(ctx context.Context, t *tables) error {
+		now := time.Now()
+		one := &subClusterRow{
+			Description: "no desc",
+			Enabled:     true,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		subClusterAssign(one, param)
+
+		if t.subClusterExisted(one.Name, one.ProductID, 0) {
+			return xerror.WrapRecordExisted("SubCluster")
+		}
+
+		if param.ID == nil {
+			one.ID = t.nextID(tableSubClusters)
+		}
+		t.subClusters = append(t.subClusters, one)
+
+		return nil
+	})
+}
+
+func (ss *SubClusterStorager) DeleteSubCluster(ctx context.Context, oldOne *icluster_conf.SubCluster) error {
+	return ss.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*subClusterRow{}
+		for _, one := range t.subClusters {
+			if one.ID != oldOne.ID {
+				list = append(list, one)
+			}
+		}
+		t.subClusters = list
+
+		return nil
+	})
+}
+
+func (ss *SubClusterStorager) UpdateSubCluster(ctx context.Context, oldOne *icluster_conf.SubCluster,
+	param *icluster_conf.SubClusterParam) error {
+
+	return ss.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.subClusters {
+			if one.ID != oldOne.ID {
+				continue
+			}
+
+			tmp := *one
+			subClusterAssign(&tmp, param)
+			if t.subClusterExisted(tmp.Name, tmp.ProductID, one.ID) {
+				return xerror.WrapRecordExisted("SubCluster")
+			}
+
+			tmp.UpdatedAt = time.Now()
+			*one = tmp
+		}
+
+		return nil
+	})
+}
+
+func subClusterAssign(one *subClusterRow, data *icluster_conf.SubClusterParam) {
+	if data.ID != nil {
+		one.ID = *data.ID
+	}
+	if data.Name != nil {
+		one.Name = *data.Name
+	}
+	if data.PoolID != nil {
+		one.PoolID = *data.PoolID
+	}
+	if data.Description != nil {
+		one.Description = *data.Description
+	}
+	if data.PortName != nil {
+		one.PortName = *data.PortName
+	}
+	if data.InstanceSelector != nil {
+		one.InstanceSelector = deepCopy(data.InstanceSelector).(map[string]string)
+	}
+	if data.Product != nil {
+		one.ProductID = data.Product.ID
+	}
+	if data.Cluster != nil {
+		one.ClusterID = data.Cluster.ID
+	}
+	if data.InstancePool != nil {
+		one.PoolID = data.InstancePool.ID
+	}
+}
+
+func (t *tables) filterSubClusters(filter *icluster_conf.SubClusterFilter) []*subClusterRow {
+	if filter == nil {
+		return t.subClusters
+	}
+
+	rst := []*subClusterRow{}
+	for _, one := range t.subClusters {
+		if !eqInt64(filter.ID, one.ID) || !inInt64s(filter.PoolIDs, one.PoolID) ||
+			!eqString(filter.Name, one.Name) || !inStrings(filter.Names, one.Name) ||
+			!inInt64s(filter.ClusterIDs, one.ClusterID) {
+			continue
+		}
+		if filter.Product != nil && filter.Product.ID != one.ProductID {
+			continue
+		}
+		if filter.InstancePool != nil && filter.InstancePool.ID != one.PoolID {
+			continue
+		}
+
+		rst = append(rst, one)
+	}
+	return rst
+}
+
+// subClusterExisted check whether name is used by other sub cluster of the product
+func (t *tables) subClusterExisted(name string, productID int64, exceptID int64) bool {
+	for _, one := range t.subClusters {
+		if one.ID != exceptID && one.Name == name && one.ProductID == productID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+)
+
+type TLSRuleStorager struct {
+	db *DB
+}
+
+var _ iprotocol.TLSRuleStorager = &TLSRuleStorager{}
+
+func NewTLSRuleStorager(db *DB) *TLSRuleStorager {
+	return &TLSRuleStorager{
+		db: db,
+	}
+}
+
+func (rs *TLSRuleStorager) FetchTLSRules(ctx context.Context,
+	filter *iprotocol.TLSRuleFilter) (rst []*iprotocol.TLSRule, err error) {
+
+	err = rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		rst = []*iprotocol.TLSRule{}
+		// products are sorted by id, so as rules
+		for _, product := range t.products {
+			one, ok := t.tlsRules[product.ID]
+			if !ok {
+				continue
+			}
+			if filter != nil {
+				if filter.Product != nil && filter.Product.ID != product.ID {
+					continue
+				}
+				if !eqString(filter.CertName, one.CertName) {
+					continue
+				}
+			}
+			rst = append(rst, deepCopy(one).(*iprotocol.TLSRule))
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (rs *TLSRuleStorager) UpsertProductTLSRule(ctx context.Context, product *ibasic.Product,
+	rule *iprotocol.TLSRule) error {
+
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		one := deepCopy(rule).(*iprotocol.TLSRule)
+		one.ProductID = product.ID
+		t.tlsRules[product.ID] = one
+		return nil
+	})
+}
+
+func (rs *TLSRuleStorager) DeleteProductTLSRule(ctx context.Context, product *ibasic.Product) error {
+	return rs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		delete(t.tlsRules, product.ID)
+		return nil
+	})
+}
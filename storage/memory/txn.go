@@ -0,0 +1,38 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/model/itxn"
+)
+
+type TxnStorager struct {
+	db *DB
+}
+
+func NewTxnStorager(db *DB) *TxnStorager {
+	return &TxnStorager{
+		db: db,
+	}
+}
+
+var _ itxn.TxnStorager = &TxnStorager{}
+
+// AtomExecute run do exclusively, tables are rolled back to the snapshot taken before do if it fails
+func (ts *TxnStorager) AtomExecute(ctx context.Context, do func(context.Context) error) error {
+	return ts.db.atomExecute(ctx, do)
+}
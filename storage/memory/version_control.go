@@ -0,0 +1,154 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bfenetworks/api-server/model/iversion_control"
+)
+
+type VersionControlStorager struct {
+	db *DB
+}
+
+var _ iversion_control.VersionControlStorager = &VersionControlStorager{}
+
+func NewVersionControlStorager(db *DB) *VersionControlStorager {
+	return &VersionControlStorager{
+		db: db,
+	}
+}
+
+func (vcs *VersionControlStorager) UpsertConfigLastExportedVersion(ctx context.Context, css *iversion_control.ExportData) (string, error) {
+	var version string
+	err := vcs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterConfigVersions(&css.Topic, nil)
+		if len(list) > 0 && list[0].DataSign == css.DataSignWithoutVersion {
+			version = list[0].Version
+			return nil
+		}
+
+		var err error
+		if version, err = css.CalculateVersion(); err != nil {
+			return err
+		}
+
+		t.configVersions = append(t.configVersions, &iversion_control.ConfigVersion{
+			ID:        t.nextID(tableConfigVersions),
+			Name:      css.Topic,
+			DataSign:  css.DataSignWithoutVersion,
+			Version:   version,
+			CreatedAt: time.Now(),
+			Snapshot:  deepCopy(css.Snapshot()).([]byte),
+		})
+		return nil
+	})
+
+	return version, err
+}
+
+func (vcs *VersionControlStorager) FetchConfigVersions(ctx context.Context,
+	filter *iversion_control.ConfigVersionFilter) ([]*iversion_control.ConfigVersion, error) {
+
+	if filter == nil {
+		filter = &iversion_control.ConfigVersionFilter{}
+	}
+
+	var rst []*iversion_control.ConfigVersion
+	err := vcs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := t.filterConfigVersions(filter.Name, filter.Version)
+
+		rst = make([]*iversion_control.ConfigVersion, len(list))
+		for i, one := range list {
+			rst[i] = deepCopy(one).(*iversion_control.ConfigVersion)
+			if !filter.WithSnapshot {
+				rst[i].Snapshot = nil
+			}
+		}
+		return nil
+	})
+
+	return rst, err
+}
+
+func (vcs *VersionControlStorager) FetchConfigPins(ctx context.Context, name *string) ([]*iversion_control.ConfigPin, error) {
+	var rst []*iversion_control.ConfigPin
+	err := vcs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		rst = []*iversion_control.ConfigPin{}
+		for _, one := range t.configPins {
+			if eqString(name, one.Name) {
+				rst = append(rst, deepCopy(one).(*iversion_control.ConfigPin))
+			}
+		}
+
+		sort.SliceStable(rst, func(i, j int) bool {
+			return rst[i].Name < rst[j].Name
+		})
+		return nil
+	})
+
+	return rst, err
+}
+
+func (vcs *VersionControlStorager) UpsertConfigPin(ctx context.Context, pin *iversion_control.ConfigPin) error {
+	return vcs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		for _, one := range t.configPins {
+			if one.Name == pin.Name {
+				one.Version = pin.Version
+				one.UpdatedAt = time.Now()
+				return nil
+			}
+		}
+
+		t.configPins = append(t.configPins, &iversion_control.ConfigPin{
+			Name:      pin.Name,
+			Version:   pin.Version,
+			UpdatedAt: time.Now(),
+		})
+		return nil
+	})
+}
+
+func (vcs *VersionControlStorager) DeleteConfigPin(ctx context.Context, name string) error {
+	return vcs.db.view(ctx, func(ctx context.Context, t *tables) error {
+		list := []*iversion_control.ConfigPin{}
+		for _, one := range t.configPins {
+			if one.Name != name {
+				list = append(list, one)
+			}
+		}
+		t.configPins = list
+
+		return nil
+	})
+}
+
+// filterConfigVersions return versions match conditions, newest first
+func (t *tables) filterConfigVersions(name, version *string) []*iversion_control.ConfigVersion {
+	rst := []*iversion_control.ConfigVersion{}
+	for _, one := range t.configVersions {
+		if eqString(name, one.Name) && eqString(version, one.Version) {
+			rst = append(rst, one)
+		}
+	}
+
+	sort.SliceStable(rst, func(i, j int) bool {
+		return rst[i].Version > rst[j].Version
+	})
+	return rst
+}
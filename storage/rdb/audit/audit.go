@@ -0,0 +1,111 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/iaudit"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+var _ iaudit.AuditStorager = &AuditStorager{}
+
+func NewAuditStorager(dbCtxFactory lib.DBContextFactory) *AuditStorager {
+	return &AuditStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+type AuditStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+func (as *AuditStorager) CreateAudit(ctx context.Context, audit *iaudit.Audit) error {
+	dbCtx, err := as.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TAuditCreate(dbCtx, &dao.TAuditParam{
+		Visitor:      &audit.Visitor,
+		ProductID:    &audit.ProductID,
+		ProductName:  &audit.ProductName,
+		ResourceType: &audit.ResourceType,
+		ResourceName: &audit.ResourceName,
+		Action:       &audit.Action,
+		BeforeData:   &audit.Before,
+		AfterData:    &audit.After,
+	})
+
+	return err
+}
+
+func (as *AuditStorager) FetchAudits(ctx context.Context, filter *iaudit.AuditFilter) ([]*iaudit.Audit, int64, error) {
+	dbCtx, err := as.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where := &dao.TAuditParam{
+		OrderBy: lib.PString("id DESC"),
+	}
+	if filter != nil {
+		where.ProductID = filter.ProductID
+		where.Visitor = filter.Visitor
+		where.ResourceType = filter.ResourceType
+		where.ResourceName = filter.ResourceName
+		where.Action = filter.Action
+		where.CreatedAtStart = filter.StartTime
+		where.CreatedAtEnd = filter.EndTime
+
+		if filter.PageSize > 0 {
+			page := filter.Page
+			if page < 1 {
+				page = 1
+			}
+			where.Limit = []uint{uint((page - 1) * filter.PageSize), uint(filter.PageSize)}
+		}
+	}
+
+	total, err := dao.TAuditCount(dbCtx, where)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list, err := dao.TAuditList(dbCtx, where)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rst := make([]*iaudit.Audit, len(list))
+	for i, one := range list {
+		rst[i] = &iaudit.Audit{
+			ID:           one.ID,
+			Visitor:      one.Visitor,
+			ProductID:    one.ProductID,
+			ProductName:  one.ProductName,
+			ResourceType: one.ResourceType,
+			ResourceName: one.ResourceName,
+			Action:       one.Action,
+			Before:       one.BeforeData,
+			After:        one.AfterData,
+			CreatedAt:    one.CreatedAt,
+		}
+	}
+
+	return rst, total, nil
+}
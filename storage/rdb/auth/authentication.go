@@ -58,6 +58,19 @@ func (ps *RDBAuthenticateStorager) FetchUserList(ctx context.Context, filter *ia
 	return rst, nil
 }
 
+func (ps *RDBAuthenticateStorager) CountUsers(ctx context.Context, filter *iauth.UserFilter) (int64, error) {
+	dbCtx, err := ps.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if filter == nil {
+		filter = &iauth.UserFilter{}
+	}
+	filter.Type = &iauth.UserTypeNormal
+	return dao.TUserCount(dbCtx, userFilter2Param(filter))
+}
+
 func (ps *RDBAuthenticateStorager) FetchUser(ctx context.Context, filter *iauth.UserFilter) (*iauth.User, error) {
 	list, err := ps.FetchUserList(ctx, filter)
 	if err != nil {
@@ -131,13 +144,16 @@ func userFilter2Param(filter *iauth.UserFilter) *dao.TUserParam {
 		return nil
 	}
 
-	return &dao.TUserParam{
+	tmp := &dao.TUserParam{
 		IDs:    filter.IDs,
 		Name:   filter.Name,
 		Ticket: filter.SessionKey,
 		Type:   filter.Type,
 		Types:  filter.Types,
 	}
+	tmp.OrderBy, tmp.Limit, tmp.NameLike = dao.PageParam(filter.Pagination, "name")
+
+	return tmp
 }
 
 func userParamM2D(param *iauth.UserParam) *dao.TUserParam {
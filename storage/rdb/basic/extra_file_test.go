@@ -0,0 +1,83 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+// ip blocklist of product is saved as extra file, whose content must be kept byte by byte
+func TestExtraFileStorager(t *testing.T) {
+	ctx := context.Background()
+	ps := newTestProductStorager(t)
+	es := NewRDBExtraFileStorager(stateful.NewBFEDBContext)
+
+	if err := ps.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString("demo"),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	products, err := ps.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString("demo"),
+	})
+	if err != nil || len(products) != 1 {
+		t.Fatalf("FetchProducts: %v %v", products, err)
+	}
+	product := products[0]
+
+	name := "mod_block/demo/ip_blocklist.data"
+	content := []byte("10.0.0.1\n10.1.0.0/16 2030-01-01T00:00:00Z\n")
+	if err = es.CreateExtraFile(ctx, product, &ibasic.ExtraFileParam{
+		Name:    &name,
+		Content: content,
+	}); err != nil {
+		t.Fatalf("CreateExtraFile: %v", err)
+	}
+
+	filter := &ibasic.ExtraFileFilter{Name: &name}
+	list, err := es.FetchExtraFiles(ctx, filter)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchExtraFiles: %v %v", list, err)
+	}
+	file := list[0]
+	if file.ProductID != product.ID || string(file.Content) != string(content) {
+		t.Fatalf("FetchExtraFiles: unexpected file %+v", file)
+	}
+	if md5sum := fmt.Sprintf("%x", md5.Sum(content)); string(file.Md5) != md5sum {
+		t.Fatalf("FetchExtraFiles: want md5 %s, got %s", md5sum, file.Md5)
+	}
+
+	if err = es.DeleteExtraFile(ctx, filter); err != nil {
+		t.Fatalf("DeleteExtraFile: %v", err)
+	}
+	list, err = es.FetchExtraFiles(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchExtraFiles: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchExtraFiles: want no file, got %d", len(list))
+	}
+}
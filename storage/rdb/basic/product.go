@@ -85,17 +85,29 @@ func (ps *RDBProductStorager) FetchProducts(ctx context.Context, filter *ibasic.
 	return rst, nil
 }
 
+func (ps *RDBProductStorager) CountProducts(ctx context.Context, filter *ibasic.ProductFilter) (int64, error) {
+	dbCtx, err := ps.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dao.TProductCount(dbCtx, productFilter2Param(filter))
+}
+
 func productFilter2Param(filter *ibasic.ProductFilter) *dao.TProductParam {
 	if filter == nil {
 		return nil
 	}
 
-	return &dao.TProductParam{
+	tmp := &dao.TProductParam{
 		IDs:  filter.IDs,
 		Id:   filter.ID,
 		NeId: filter.NeID,
 		Name: filter.Name,
 	}
+	tmp.OrderBy, tmp.Limit, tmp.NameLike = dao.PageParam(filter.Pagination, "name")
+
+	return tmp
 }
 
 func productParami2d(pp *ibasic.ProductParam) *dao.TProductParam {
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+func newTestProductStorager(t *testing.T) *RDBProductStorager {
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	return NewProductManager(stateful.NewBFEDBContext)
+}
+
+func TestFetchProductsByNamePrefix(t *testing.T) {
+	ctx := context.Background()
+	ps := newTestProductStorager(t)
+
+	for _, name := range []string{"a_b", "axb", "a%b", `a\b`} {
+		if err := ps.CreateProduct(ctx, &ibasic.ProductParam{
+			Name:              lib.PString(name),
+			Description:       lib.PString(""),
+			MailList:          []string{},
+			PhoneList:         []string{},
+			ContactPersonList: []string{},
+		}); err != nil {
+			t.Fatalf("CreateProduct %s: %v", name, err)
+		}
+	}
+
+	// wildcards of LIKE in filter match themselves only
+	cases := map[string][]string{
+		"a":    {"a%b", `a\b`, "a_b", "axb"},
+		"a_":   {"a_b"},
+		"a%":   {"a%b"},
+		`a\`:   {`a\b`},
+		"ax":   {"axb"},
+		"a_bc": {},
+	}
+	for filter, want := range cases {
+		list, err := ps.FetchProducts(ctx, &ibasic.ProductFilter{
+			Pagination: &lib.Pagination{Filter: filter},
+		})
+		if err != nil {
+			t.Fatalf("FetchProducts %q: %v", filter, err)
+		}
+
+		got := []string{}
+		for _, one := range list {
+			got = append(got, one.Name)
+		}
+		if len(got) != len(want) {
+			t.Errorf("FetchProducts %q: want %v, got %v", filter, want, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("FetchProducts %q: want %v, got %v", filter, want, got)
+				break
+			}
+		}
+	}
+}
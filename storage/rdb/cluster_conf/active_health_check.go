@@ -0,0 +1,139 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster_conf
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+var _ icluster_conf.ActiveHealthCheckStorager = &RDBActiveHealthCheckStorager{}
+
+func NewRDBActiveHealthCheckStorager(dbCtxFactory lib.DBContextFactory) *RDBActiveHealthCheckStorager {
+	return &RDBActiveHealthCheckStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+type RDBActiveHealthCheckStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+func (rs *RDBActiveHealthCheckStorager) FetchActiveHealthChecks(ctx context.Context,
+	filter *icluster_conf.ActiveHealthCheckFilter) ([]*icluster_conf.ActiveHealthCheck, error) {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := &dao.TActiveHealthCheckParam{
+		OrderBy: lib.PString("id"),
+	}
+	if filter != nil {
+		where.ClusterID = filter.ClusterID
+		where.ClusterIDs = filter.ClusterIDs
+	}
+
+	list, err := dao.TActiveHealthCheckList(dbCtx, where)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*icluster_conf.ActiveHealthCheck, len(list))
+	for i, one := range list {
+		rst[i] = activeHealthCheckd2i(one)
+	}
+
+	return rst, nil
+}
+
+func (rs *RDBActiveHealthCheckStorager) CreateActiveHealthCheck(ctx context.Context, product *ibasic.Product,
+	cluster *icluster_conf.Cluster, param *icluster_conf.ActiveHealthCheckParam) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	p := activeHealthCheckParami2d(param)
+	p.ProductID = &product.ID
+	p.ClusterID = &cluster.ID
+	_, err = dao.TActiveHealthCheckCreate(dbCtx, p)
+	return err
+}
+
+func (rs *RDBActiveHealthCheckStorager) UpdateActiveHealthCheck(ctx context.Context,
+	old *icluster_conf.ActiveHealthCheck, param *icluster_conf.ActiveHealthCheckParam) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TActiveHealthCheckUpdate(dbCtx, activeHealthCheckParami2d(param), &dao.TActiveHealthCheckParam{
+		ID: &old.ID,
+	})
+	return err
+}
+
+func (rs *RDBActiveHealthCheckStorager) DeleteActiveHealthCheck(ctx context.Context,
+	one *icluster_conf.ActiveHealthCheck) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TActiveHealthCheckDelete(dbCtx, &dao.TActiveHealthCheckParam{
+		ID: &one.ID,
+	})
+	return err
+}
+
+func activeHealthCheckParami2d(param *icluster_conf.ActiveHealthCheckParam) *dao.TActiveHealthCheckParam {
+	return &dao.TActiveHealthCheckParam{
+		Schema:     param.Schema,
+		Interval:   param.Interval,
+		Timeout:    param.Timeout,
+		SuccNum:    param.SuccNum,
+		FailNum:    param.FailNum,
+		Host:       param.Host,
+		Uri:        param.Uri,
+		StatusCode: param.StatusCode,
+	}
+}
+
+func activeHealthCheckd2i(one *dao.TActiveHealthCheck) *icluster_conf.ActiveHealthCheck {
+	return &icluster_conf.ActiveHealthCheck{
+		ID:         one.ID,
+		ProductID:  one.ProductID,
+		ClusterID:  one.ClusterID,
+		Schema:     one.Schema,
+		Interval:   one.Interval,
+		Timeout:    one.Timeout,
+		SuccNum:    one.SuccNum,
+		FailNum:    one.FailNum,
+		Host:       one.Host,
+		Uri:        one.Uri,
+		StatusCode: one.StatusCode,
+		CreatedAt:  one.CreatedAt,
+		UpdatedAt:  one.UpdatedAt,
+	}
+}
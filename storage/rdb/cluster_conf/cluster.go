@@ -109,6 +109,12 @@ func (rm *RDBClusterStorager) ClusterDelete(ctx context.Context, product *ibasic
 		return err
 	}
 
+	if _, err = dao.TActiveHealthCheckDelete(dbCtx, &dao.TActiveHealthCheckParam{
+		ClusterID: &clusterID,
+	}); err != nil {
+		return err
+	}
+
 	_, err = dao.TClusterDelete(dbCtx, &dao.TClusterParam{
 		ID: &clusterID,
 	})
@@ -221,9 +227,15 @@ func newCluster(dc *dao.TCluster, subClusters []*icluster_conf.SubCluster, capac
 		Description: dc.Description,
 
 		Basic: &icluster_conf.ClusterBasic{
+			Protocol:                 dc.Protocol,
+			BalanceMode:              dc.BalanceMode,
+			OutlierDetectionHttpCode: dc.OutlierDetectionCode,
 			Connection: &icluster_conf.ClusterBasicConnection{
 				MaxIdleConnPerRs:    dc.MaxIdleConnPerHost,
+				MaxConnPerRs:        dc.MaxConnsPerHost,
 				CancelOnClientClose: dc.CancelOnClientClose,
+				ClientIPCarry:       dc.ClientipCarry,
+				ClientPortCarry:     dc.PortCarry,
 			},
 			Retries: &icluster_conf.ClusterBasicRetries{
 				MaxRetryInSubcluster:    dc.MaxRetryInCluster,
@@ -296,9 +308,16 @@ func newDaoClusterParam(param *icluster_conf.ClusterParam) *dao.TClusterParam {
 	}
 
 	if basic := param.Basic; basic != nil {
+		dc.Protocol = basic.Protocol
+		dc.BalanceMode = basic.BalanceMode
+		dc.OutlierDetectionCode = basic.OutlierDetectionHttpCode
+
 		if conn := basic.Connection; conn != nil {
 			dc.MaxIdleConnPerHost = conn.MaxIdleConnPerRs
+			dc.MaxConnsPerHost = conn.MaxConnPerRs
 			dc.CancelOnClientClose = conn.CancelOnClientClose
+			dc.ClientipCarry = conn.ClientIPCarry
+			dc.PortCarry = conn.ClientPortCarry
 		}
 
 		if retries := basic.Retries; retries != nil {
@@ -395,3 +414,18 @@ func (rm *RDBClusterStorager) FetchCluster(ctx context.Context, filter *icluster
 
 	return nil, nil
 }
+
+// LockCluster fetch cluster and lock it until transaction be finished
+func (rm *RDBClusterStorager) LockCluster(ctx context.Context, filter *icluster_conf.ClusterFilter) (*icluster_conf.Cluster, error) {
+	dbCtx, err := rm.dbCtxFactory(ctx, lib.BlockWrite())
+	if err != nil {
+		return nil, err
+	}
+
+	one, err := dao.TClusterOne(dbCtx, clusterFilter2Param(filter))
+	if err != nil || one == nil {
+		return nil, err
+	}
+
+	return rm.FetchCluster(ctx, filter)
+}
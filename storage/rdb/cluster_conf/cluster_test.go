@@ -0,0 +1,125 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster_conf_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bfenetworks/api-server/endpoints/openapi_v1/product_cluster"
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/basic"
+	"github.com/bfenetworks/api-server/storage/rdb/cluster_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+func TestClusterClientCarry(t *testing.T) {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	productStorager := basic.NewProductManager(stateful.NewBFEDBContext)
+	poolStorager := cluster_conf.NewRDBPoolStorager(stateful.NewBFEDBContext, productStorager)
+	subClusterStorager := cluster_conf.NewRDBSubClusterStorager(stateful.NewBFEDBContext, poolStorager, productStorager)
+	cs := cluster_conf.NewRDBClusterStorager(stateful.NewBFEDBContext, subClusterStorager)
+
+	products, err := productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString(ibasic.BuildinProduct.Name),
+	})
+	if err != nil || len(products) != 1 {
+		t.Fatalf("FetchProducts: %v %v", products, err)
+	}
+	product := products[0]
+
+	// param of create api
+	maxIdleConn := int16(0)
+	param := &product_cluster.UpsertParam{
+		Name: lib.PString("demo_cluster"),
+		Basic: &product_cluster.BasicParam{
+			Connection: &product_cluster.ConnectionParam{
+				MaxIdleConnPerRs:    &maxIdleConn,
+				CancelOnClientClose: lib.PBool(false),
+				ClientIPCarry:       lib.PBool(true),
+				ClientPortCarry:     lib.PBool(true),
+			},
+			Retries: &product_cluster.RetriesParam{
+				MaxRetryInSubcluster:    lib.PInt8(1),
+				MaxRetryCrossSubcluster: lib.PInt8(0),
+			},
+			Buffers: &product_cluster.BuffersParam{
+				ReqWriteBufferSize: lib.PInt32(512),
+			},
+			Timeouts: &product_cluster.TimeoutsParam{
+				TimeoutConnServ:        lib.PInt32(1000),
+				TimeoutResponseHeader:  lib.PInt32(1000),
+				TimeoutReadbodyClient:  lib.PInt32(1000),
+				TimeoutReadClientAgain: lib.PInt32(1000),
+				TimeoutWriteClient:     lib.PInt32(1000),
+			},
+		},
+		StickySessions: &product_cluster.StickySessionsParam{
+			SessionStickyType: lib.PString("INSTANCE"),
+			HashStrategy:      lib.PString("CLIENT_IP_ONLY"),
+		},
+		PassiveHealthCheck: &product_cluster.PassiveHealthCheckParam{
+			Interval:   lib.PInt32(1000),
+			Failnum:    lib.PInt32(3),
+			Statuscode: lib.PInt32(200),
+			Host:       lib.PString("example.org"),
+			Uri:        lib.PString("/health"),
+		},
+	}
+	clusterParam := product_cluster.ClusterParamControlModel(param)
+	clusterParam.ProductID = &product.ID
+	if _, err = cs.ClusterCreate(ctx, product, clusterParam, nil); err != nil {
+		t.Fatalf("ClusterCreate: %v", err)
+	}
+
+	cluster, err := cs.FetchCluster(ctx, &icluster_conf.ClusterFilter{
+		Name: param.Name,
+	})
+	if err != nil || cluster == nil {
+		t.Fatalf("FetchCluster: %v %v", cluster, err)
+	}
+
+	// data of one api
+	conn := product_cluster.ClusterModel2Control(cluster).Basic.Connection
+	if !conn.ClientIPCarry || !conn.ClientPortCarry {
+		t.Fatalf("ClusterModel2Control: want client ip and port carried, got %+v", conn)
+	}
+
+	// cluster_conf.data exported
+	bs, err := json.Marshal(icluster_conf.NewBfeClusterConf("1", []*icluster_conf.Cluster{cluster}))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	conf := struct {
+		Config map[string]struct {
+			ClusterBasic map[string]interface{}
+		}
+	}{}
+	if err = json.Unmarshal(bs, &conf); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	basic := conf.Config["demo_cluster"].ClusterBasic
+	if basic["ClientIPCarry"] != true || basic["ClientPortCarry"] != true || basic["CancelOnClientClose"] != false {
+		t.Fatalf("NewBfeClusterConf: want client ip and port carried, got %s", bs)
+	}
+}
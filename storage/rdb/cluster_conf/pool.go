@@ -47,12 +47,15 @@ func poolFilter2Param(filter *icluster_conf.PoolFilter) *dao.TPoolsParam {
 		return nil
 	}
 
-	return &dao.TPoolsParam{
+	tmp := &dao.TPoolsParam{
 		Id:        filter.ID,
 		Ids:       filter.IDs,
 		Name:      filter.Name,
 		ProductID: filter.ProductID,
 	}
+	tmp.OrderBy, tmp.Limit, tmp.NameLike = dao.PageParam(filter.Pagination, "name")
+
+	return tmp
 }
 
 func poolParami2d(data *icluster_conf.PoolParam) (*dao.TPoolsParam, error) {
@@ -119,6 +122,23 @@ func (rpps *RDBPoolStorager) FetchPool(ctx context.Context, name string) (*iclus
 	return nil, nil
 }
 
+// LockPool fetch pool and lock it until transaction be finished
+func (rpps *RDBPoolStorager) LockPool(ctx context.Context, name string) (*icluster_conf.Pool, error) {
+	dbCtx, err := rpps.dbCtxFactory(ctx, lib.BlockWrite())
+	if err != nil {
+		return nil, err
+	}
+
+	one, err := dao.TPoolsOne(dbCtx, &dao.TPoolsParam{
+		Name: &name,
+	})
+	if err != nil || one == nil {
+		return nil, err
+	}
+
+	return rpps.FetchPool(ctx, name)
+}
+
 func newPool(pp *dao.TPools, product *ibasic.Product) (*icluster_conf.Pool, error) {
 	data := &icluster_conf.Pool{
 		ID:    pp.Id,
@@ -181,6 +201,15 @@ func (rpps *RDBPoolStorager) FetchPools(ctx context.Context, filter *icluster_co
 	return rst, nil
 }
 
+func (rpps *RDBPoolStorager) CountPools(ctx context.Context, filter *icluster_conf.PoolFilter) (int64, error) {
+	dbCtx, err := rpps.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dao.TPoolsCount(dbCtx, poolFilter2Param(filter))
+}
+
 func (rpps *RDBPoolStorager) UpdatePool(ctx context.Context, oldData *icluster_conf.Pool,
 	diff *icluster_conf.PoolParam) error {
 
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster_conf
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/icluster_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/basic"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+	"github.com/bfenetworks/api-server/storage/rdb/txn"
+)
+
+func TestLockPoolInTransaction(t *testing.T) {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	productStorager := basic.NewProductManager(stateful.NewBFEDBContext)
+	ps := NewRDBPoolStorager(stateful.NewBFEDBContext, productStorager)
+	ts := txn.NewRDBTxnStorager(stateful.NewBFEDBContext)
+
+	products, err := productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString(ibasic.BuildinProduct.Name),
+	})
+	if err != nil || len(products) != 1 {
+		t.Fatalf("FetchProducts: %v %v", products, err)
+	}
+
+	name := "BFE.pool"
+	if _, err = ps.CreatePool(ctx, products[0], &icluster_conf.PoolParam{
+		Name: &name,
+		Instances: []icluster_conf.Instance{
+			{HostName: "host1", IP: "10.0.0.1", Port: 80, Weight: 1},
+		},
+		Tag: &icluster_conf.PoolTagBFE,
+	}); err != nil {
+		t.Fatalf("CreatePool: %v", err)
+	}
+
+	update := func(weight int64, fail error) error {
+		return ts.AtomExecute(ctx, func(ctx context.Context) error {
+			pool, err := ps.LockPool(ctx, name)
+			if err != nil {
+				return err
+			}
+			if pool == nil {
+				return errors.New("pool not found")
+			}
+
+			pool.Instances[0].Weight = weight
+			if err = ps.UpdatePool(ctx, pool, &icluster_conf.PoolParam{
+				Instances: pool.Instances,
+			}); err != nil {
+				return err
+			}
+
+			return fail
+		})
+	}
+
+	if err = update(2, nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// changes are rolled back with the transaction
+	errAbort := errors.New("abort")
+	if err = update(3, errAbort); err != errAbort {
+		t.Fatalf("update: want %v, got %v", errAbort, err)
+	}
+
+	pool, err := ps.FetchPool(ctx, name)
+	if err != nil {
+		t.Fatalf("FetchPool: %v", err)
+	}
+	if pool.Instances[0].Weight != 2 {
+		t.Fatalf("weight: want 2, got %d", pool.Instances[0].Weight)
+	}
+}
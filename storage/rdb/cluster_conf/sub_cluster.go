@@ -16,8 +16,10 @@ package cluster_conf
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
 	"github.com/bfenetworks/api-server/model/ibasic"
 	"github.com/bfenetworks/api-server/model/icluster_conf"
 	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
@@ -64,12 +66,14 @@ func subClusterFilter2Param(filter *icluster_conf.SubClusterFilter) *dao.TSubClu
 		tmp.PoolsID = &filter.InstancePool.ID
 	}
 
+	tmp.OrderBy, tmp.Limit, tmp.NameLike = dao.PageParam(filter.Pagination, "name")
+
 	return tmp
 }
 
-func subClusterParami2d(data *icluster_conf.SubClusterParam) *dao.TSubClusterParam {
+func subClusterParami2d(data *icluster_conf.SubClusterParam) (*dao.TSubClusterParam, error) {
 	if data == nil {
-		return nil
+		return nil, nil
 	}
 
 	tmp := &dao.TSubClusterParam{
@@ -79,6 +83,16 @@ func subClusterParami2d(data *icluster_conf.SubClusterParam) *dao.TSubClusterPar
 
 		ClusterIDs:  data.ClusterIDs,
 		Description: data.Description,
+
+		PortName: data.PortName,
+	}
+
+	if data.InstanceSelector != nil {
+		bs, err := json.Marshal(data.InstanceSelector)
+		if err != nil {
+			return nil, xerror.WrapParamErrorWithMsg("InstanceSelector Marshal, err: %s", err)
+		}
+		tmp.InstanceSelector = lib.PString(string(bs))
 	}
 
 	if data.Product != nil {
@@ -93,10 +107,10 @@ func subClusterParami2d(data *icluster_conf.SubClusterParam) *dao.TSubClusterPar
 		tmp.PoolsID = &data.InstancePool.ID
 	}
 
-	return tmp
+	return tmp, nil
 }
 
-func newSubCluster(pp *dao.TSubCluster, pool *icluster_conf.Pool, product *ibasic.Product) *icluster_conf.SubCluster {
+func newSubCluster(pp *dao.TSubCluster, pool *icluster_conf.Pool, product *ibasic.Product) (*icluster_conf.SubCluster, error) {
 	data := &icluster_conf.SubCluster{
 		ID:          pp.ID,
 		Name:        pp.Name,
@@ -105,13 +119,21 @@ func newSubCluster(pp *dao.TSubCluster, pool *icluster_conf.Pool, product *ibasi
 		ClusterID:   pp.ClusterID,
 
 		InstancePool: pool,
+
+		PortName: pp.PortName,
+	}
+
+	if pp.InstanceSelector != "" {
+		if err := json.Unmarshal([]byte(pp.InstanceSelector), &data.InstanceSelector); err != nil {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("sub cluster %s, raw: %s, err: %v", pp.Name, pp.InstanceSelector, err)
+		}
 	}
 
 	if pool != nil {
 		data.Ready = pool.Ready
 	}
 
-	return data
+	return data, nil
 }
 
 func (rpps *RDBSubClusterStorager) FetchSubClusterList(ctx context.Context,
@@ -155,19 +177,39 @@ func (rpps *RDBSubClusterStorager) FetchSubClusterList(ctx context.Context,
 
 	rst := []*icluster_conf.SubCluster{}
 	for _, one := range subClusterList {
-		rst = append(rst, newSubCluster(one, poolMap[one.PoolsID], productMap[one.ProductID]))
+		subCluster, err := newSubCluster(one, poolMap[one.PoolsID], productMap[one.ProductID])
+		if err != nil {
+			return nil, err
+		}
+		rst = append(rst, subCluster)
 	}
 
 	return rst, nil
 }
 
+func (rpps *RDBSubClusterStorager) CountSubClusters(ctx context.Context,
+	filter *icluster_conf.SubClusterFilter) (int64, error) {
+
+	dbCtx, err := rpps.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dao.TSubClusterCount(dbCtx, subClusterFilter2Param(filter))
+}
+
 func (rpps *RDBSubClusterStorager) CreateSubCluster(ctx context.Context, param *icluster_conf.SubClusterParam) error {
 	dbCtx, err := rpps.dbCtxFactory(ctx)
 	if err != nil {
 		return err
 	}
 
-	_, err = dao.TSubClusterCreate(dbCtx, subClusterParami2d(param))
+	data, err := subClusterParami2d(param)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TSubClusterCreate(dbCtx, data)
 	if err != nil {
 		return err
 	}
@@ -192,7 +234,12 @@ func (rpps *RDBSubClusterStorager) UpdateSubCluster(ctx context.Context, oldOne
 		return err
 	}
 
-	_, err = dao.TSubClusterUpdate(dbCtx, subClusterParami2d(param), &dao.TSubClusterParam{ID: &oldOne.ID})
+	data, err := subClusterParami2d(param)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TSubClusterUpdate(dbCtx, data, &dao.TSubClusterParam{ID: &oldOne.ID})
 
 	return err
 }
@@ -140,7 +140,7 @@ func (s *SelectBuilder) Select(selectFields []string) *SelectBuilder {
 }
 
 func (s *SelectBuilder) Compile() (sql string, args []interface{}, err error) {
-	return builder.BuildSelect(s.table, s.where, s.fields)
+	return dialect(builder.BuildSelect(s.table, s.where, s.fields))
 }
 
 var _ SQLBuilder = (*SelectBuilder)(nil)
@@ -272,7 +272,7 @@ func (i *InsertBuilder) Compile() (sql string, args []interface{}, err error) {
 	case insertCommon:
 		return builder.BuildInsert(i.table, i.assigns)
 	case insertIgnore:
-		return builder.BuildInsertIgnore(i.table, i.assigns)
+		return dialect(builder.BuildInsertIgnore(i.table, i.assigns))
 	case insertReplace:
 		return builder.BuildReplaceInsert(i.table, i.assigns)
 	default:
@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"strings"
 	"time"
 
 	"github.com/didi/gendry/scanner"
@@ -41,6 +42,12 @@ func queryList(dbCtx lib.DBContexter, table string, where interface{}, rst inter
 	if tmp != nil && queryOne {
 		tmp["_limit"] = []uint{0, 1}
 	}
+	if dbCtx.BlockWrite() {
+		if tmp == nil {
+			tmp = map[string]interface{}{}
+		}
+		tmp["_lockMode"] = "exclusive"
+	}
 
 	build := NewSelectBuilder(table, tmp, nil)
 	sql, args, err := build.Compile()
@@ -69,6 +76,39 @@ func queryList(dbCtx lib.DBContexter, table string, where interface{}, rst inter
 	return xerror.WrapDaoError(record.Err)
 }
 
+// Count return number of records match where, _orderby/_limit are ignored
+func Count(dbCtx lib.DBContexter, table string, where interface{}) (int64, error) {
+	tmp := Struct2Where(where)
+	for k := range tmp {
+		if strings.HasPrefix(k, "_") {
+			delete(tmp, k)
+		}
+	}
+
+	build := NewSelectBuilder(table, tmp, []string{"count(*)"})
+	sql, args, err := build.Compile()
+	if err != nil {
+		return 0, xerror.WrapDaoError(err)
+	}
+
+	now := time.Now()
+	var total int64
+	err = dbCtx.Conn().QueryRowContext(dbCtx, sql, args...).Scan(&total)
+	record := &stateful.SQLRecord{
+		SQL:  sql,
+		Args: args,
+		Err:  err,
+		Cost: time.Since(now),
+	}
+	defer record.Print(dbCtx)
+
+	if err != nil {
+		return 0, xerror.WrapDaoError(err)
+	}
+
+	return total, nil
+}
+
 func Create(dbCtx lib.DBContexter, table string, data ...interface{}) (int64, error) {
 	build := NewInsertBuilder(table, Struct2AssignList(data...))
 	sql, args, err := build.Compile()
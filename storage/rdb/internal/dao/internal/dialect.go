@@ -0,0 +1,40 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"strings"
+
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+// sqliteReplacer rewrite mysql syntax generated by gendry to sqlite.
+// sqlite has no row lock, writer is serialized by transaction begin immediately instead.
+// sqlite has no default escape character for LIKE, patterns are escaped by backslash as mysql does.
+var sqliteReplacer = strings.NewReplacer(
+	" FOR UPDATE", "",
+	" LOCK IN SHARE MODE", "",
+	"INSERT IGNORE INTO", "INSERT OR IGNORE INTO",
+	" LIKE ?", ` LIKE ? ESCAPE '\'`,
+)
+
+// dialect rewrite sql to the dialect of bfe_db
+func dialect(sql string, args []interface{}, err error) (string, []interface{}, error) {
+	if err != nil || stateful.BFEDBDriver() != stateful.DriverSQLite {
+		return sql, args, err
+	}
+
+	return sqliteReplacer.Replace(sql), args, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"strings"
+
+	"github.com/bfenetworks/api-server/lib"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// PageParam convert pagination to _orderby, _limit and prefix match of nameColumn
+// sort by name means sort by nameColumn, all return values are empty if p is nil
+func PageParam(p *lib.Pagination, nameColumn string) (orderBy *string, limit []uint, nameLike *string) {
+	if p == nil {
+		return nil, nil, nil
+	}
+
+	field := p.SortField()
+	if field == lib.SortByName {
+		field = nameColumn
+	}
+	if p.Desc() {
+		field += " DESC"
+	}
+	orderBy = &field
+
+	if p.PageSize > 0 {
+		limit = []uint{uint(p.Offset()), uint(p.PageSize)}
+	}
+
+	if p.Filter != "" {
+		nameLike = lib.PString(likeEscaper.Replace(p.Filter) + "%")
+	}
+
+	return
+}
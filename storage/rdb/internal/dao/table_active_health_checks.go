@@ -0,0 +1,120 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tActiveHealthCheckTableName = "active_health_checks"
+
+// TActiveHealthCheck Query Result
+type TActiveHealthCheck struct {
+	ID         int64     `db:"id"`
+	ProductID  int64     `db:"product_id"`
+	ClusterID  int64     `db:"cluster_id"`
+	Schema     string    `db:"check_schema"`
+	Interval   int32     `db:"check_interval"`
+	Timeout    int32     `db:"check_timeout"`
+	SuccNum    int32     `db:"succ_num"`
+	FailNum    int32     `db:"fail_num"`
+	Host       string    `db:"host"`
+	Uri        string    `db:"uri"`
+	StatusCode int32     `db:"status_code"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// TActiveHealthCheckOne Query One
+// return (nil, nil) if record not existed
+func TActiveHealthCheckOne(dbCtx lib.DBContexter, where *TActiveHealthCheckParam) (*TActiveHealthCheck, error) {
+	t := &TActiveHealthCheck{}
+	err := internal.QueryOne(dbCtx, tActiveHealthCheckTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TActiveHealthCheckList Query Multiple
+func TActiveHealthCheckList(dbCtx lib.DBContexter, where *TActiveHealthCheckParam) ([]*TActiveHealthCheck, error) {
+	t := []*TActiveHealthCheck{}
+	err := internal.QueryList(dbCtx, tActiveHealthCheckTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TActiveHealthCheckParam Create/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TActiveHealthCheckParam struct {
+	ID         *int64     `db:"id"`
+	ProductID  *int64     `db:"product_id"`
+	ClusterID  *int64     `db:"cluster_id"`
+	ClusterIDs []int64    `db:"cluster_id,in"`
+	Schema     *string    `db:"check_schema"`
+	Interval   *int32     `db:"check_interval"`
+	Timeout    *int32     `db:"check_timeout"`
+	SuccNum    *int32     `db:"succ_num"`
+	FailNum    *int32     `db:"fail_num"`
+	Host       *string    `db:"host"`
+	Uri        *string    `db:"uri"`
+	StatusCode *int32     `db:"status_code"`
+	CreatedAt  *time.Time `db:"created_at"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+
+	OrderBy *string `db:"_orderby"`
+}
+
+// TActiveHealthCheckCreate One/Multiple
+func TActiveHealthCheckCreate(dbCtx lib.DBContexter, data ...*TActiveHealthCheckParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tActiveHealthCheckTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tActiveHealthCheckTableName, list...)
+}
+
+// TActiveHealthCheckUpdate Update One
+func TActiveHealthCheckUpdate(dbCtx lib.DBContexter, val, where *TActiveHealthCheckParam) (int64, error) {
+	return internal.Update(dbCtx, tActiveHealthCheckTableName, where, val)
+}
+
+// TActiveHealthCheckDelete Delete One/Multiple
+func TActiveHealthCheckDelete(dbCtx lib.DBContexter, where *TActiveHealthCheckParam) (int64, error) {
+	return internal.Delete(dbCtx, tActiveHealthCheckTableName, where)
+}
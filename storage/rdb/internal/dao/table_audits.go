@@ -0,0 +1,98 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tAuditTableName = "audits"
+
+// TAudit Query Result
+type TAudit struct {
+	ID           int64     `db:"id"`
+	Visitor      string    `db:"visitor"`
+	ProductID    int64     `db:"product_id"`
+	ProductName  string    `db:"product_name"`
+	ResourceType string    `db:"resource_type"`
+	ResourceName string    `db:"resource_name"`
+	Action       string    `db:"action"`
+	BeforeData   string    `db:"before_data"`
+	AfterData    string    `db:"after_data"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// TAuditList Query Multiple
+func TAuditList(dbCtx lib.DBContexter, where *TAuditParam) ([]*TAudit, error) {
+	t := []*TAudit{}
+	err := internal.QueryList(dbCtx, tAuditTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TAuditCount Query Count
+func TAuditCount(dbCtx lib.DBContexter, where *TAuditParam) (int64, error) {
+	return internal.Count(dbCtx, tAuditTableName, where)
+}
+
+// TAuditParam Create/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TAuditParam struct {
+	ID             *int64     `db:"id"`
+	Visitor        *string    `db:"visitor"`
+	ProductID      *int64     `db:"product_id"`
+	ProductName    *string    `db:"product_name"`
+	ResourceType   *string    `db:"resource_type"`
+	ResourceName   *string    `db:"resource_name"`
+	Action         *string    `db:"action"`
+	BeforeData     *string    `db:"before_data"`
+	AfterData      *string    `db:"after_data"`
+	CreatedAt      *time.Time `db:"created_at"`
+	CreatedAtStart *time.Time `db:"created_at,>="`
+	CreatedAtEnd   *time.Time `db:"created_at,<"`
+
+	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
+}
+
+// TAuditCreate One/Multiple
+func TAuditCreate(dbCtx lib.DBContexter, data ...*TAuditParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tAuditTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tAuditTableName, list...)
+}
@@ -66,6 +66,11 @@ func TCertificateList(dbCtx lib.DBContexter, where *TCertificateParam) ([]*TCert
 	return nil, err
 }
 
+// TCertificateCount Query Count
+func TCertificateCount(dbCtx lib.DBContexter, where *TCertificateParam) (int64, error) {
+	return internal.Count(dbCtx, tCertificateTableName, where)
+}
+
 // TCertificateParamCreate/Update/Where Data Carrier
 // See: https://github.com/didi/gendry/blob/master/builder/README.md
 type TCertificateParam struct {
@@ -83,7 +88,10 @@ type TCertificateParam struct {
 	CreatedAt    *time.Time `db:"created_at"`
 	UpdatedAt    *time.Time `db:"updated_at"`
 
+	NameLike *string `db:"cert_name,like"`
+
 	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
 }
 
 // TCertificateCreate One/Multiple
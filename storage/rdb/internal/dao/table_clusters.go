@@ -56,6 +56,10 @@ type TCluster struct {
 	ResFlushInterval       int32     `db:"res_flush_interval"`
 	CancelOnClientClose    bool      `db:"cancel_on_client_close"`
 	FailureStatus          bool      `db:"failure_status"`
+	MaxConnsPerHost        int32     `db:"max_conns_per_host"`
+	Protocol               string    `db:"protocol"`
+	BalanceMode            string    `db:"balance_mode"`
+	OutlierDetectionCode   string    `db:"outlier_detection_code"`
 	CreatedAt              time.Time `db:"created_at"`
 	UpdatedAt              time.Time `db:"updated_at"`
 }
@@ -123,6 +127,10 @@ type TClusterParam struct {
 	ResFlushInterval       *int32     `db:"res_flush_interval"`
 	CancelOnClientClose    *bool      `db:"cancel_on_client_close"`
 	FailureStatus          *bool      `db:"failure_status"`
+	MaxConnsPerHost        *int32     `db:"max_conns_per_host"`
+	Protocol               *string    `db:"protocol"`
+	BalanceMode            *string    `db:"balance_mode"`
+	OutlierDetectionCode   *string    `db:"outlier_detection_code"`
 	CreatedAt              *time.Time `db:"created_at"`
 	UpdatedAt              *time.Time `db:"updated_at"`
 
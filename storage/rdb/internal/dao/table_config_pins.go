@@ -0,0 +1,103 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tConfigPinTableName = "config_pins"
+
+// TConfigPin Query Result
+type TConfigPin struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Version   string    `db:"version"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// TConfigPinOne Query One
+// return (nil, nil) if record not existed
+func TConfigPinOne(dbCtx lib.DBContexter, where *TConfigPinParam) (*TConfigPin, error) {
+	t := &TConfigPin{}
+	err := internal.QueryOne(dbCtx, tConfigPinTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TConfigPinList Query Multiple
+func TConfigPinList(dbCtx lib.DBContexter, where *TConfigPinParam) ([]*TConfigPin, error) {
+	t := []*TConfigPin{}
+	err := internal.QueryList(dbCtx, tConfigPinTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TConfigPinParamCreate/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TConfigPinParam struct {
+	ID        *int64     `db:"id"`
+	Name      *string    `db:"name"`
+	Version   *string    `db:"version"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+
+	OrderBy *string `db:"_orderby"`
+}
+
+// TConfigPinCreate One/Multiple
+func TConfigPinCreate(dbCtx lib.DBContexter, data ...*TConfigPinParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tConfigPinTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tConfigPinTableName, list...)
+}
+
+// TConfigPinUpdate Update One
+func TConfigPinUpdate(dbCtx lib.DBContexter, val, where *TConfigPinParam) (int64, error) {
+	return internal.Update(dbCtx, tConfigPinTableName, where, val)
+}
+
+// TConfigPinDelete Delete One/Multiple
+func TConfigPinDelete(dbCtx lib.DBContexter, where *TConfigPinParam) (int64, error) {
+	return internal.Delete(dbCtx, tConfigPinTableName, where)
+}
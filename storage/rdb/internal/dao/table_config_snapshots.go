@@ -0,0 +1,103 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tConfigSnapshotTableName = "config_snapshots"
+
+// TConfigSnapshot Query Result
+type TConfigSnapshot struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Version   string    `db:"version"`
+	Data      []byte    `db:"data"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// TConfigSnapshotOne Query One
+// return (nil, nil) if record not existed
+func TConfigSnapshotOne(dbCtx lib.DBContexter, where *TConfigSnapshotParam) (*TConfigSnapshot, error) {
+	t := &TConfigSnapshot{}
+	err := internal.QueryOne(dbCtx, tConfigSnapshotTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TConfigSnapshotList Query Multiple
+func TConfigSnapshotList(dbCtx lib.DBContexter, where *TConfigSnapshotParam) ([]*TConfigSnapshot, error) {
+	t := []*TConfigSnapshot{}
+	err := internal.QueryList(dbCtx, tConfigSnapshotTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TConfigSnapshotParamCreate/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TConfigSnapshotParam struct {
+	ID        *int64     `db:"id"`
+	Name      *string    `db:"name"`
+	Version   *string    `db:"version"`
+	Data      []byte     `db:"data"`
+	CreatedAt *time.Time `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+// TConfigSnapshotCreate One/Multiple
+func TConfigSnapshotCreate(dbCtx lib.DBContexter, data ...*TConfigSnapshotParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tConfigSnapshotTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tConfigSnapshotTableName, list...)
+}
+
+// TConfigSnapshotUpdate Update One
+func TConfigSnapshotUpdate(dbCtx lib.DBContexter, val, where *TConfigSnapshotParam) (int64, error) {
+	return internal.Update(dbCtx, tConfigSnapshotTableName, where, val)
+}
+
+// TConfigSnapshotDelete Delete One/Multiple
+func TConfigSnapshotDelete(dbCtx lib.DBContexter, where *TConfigSnapshotParam) (int64, error) {
+	return internal.Delete(dbCtx, tConfigSnapshotTableName, where)
+}
@@ -36,6 +36,9 @@ type TDomain struct {
 	Type                  int32     `db:"type"`
 	UsingAdvancedRedirect int8      `db:"using_advanced_redirect"`
 	UsingAdvancedHsts     int8      `db:"using_advanced_hsts"`
+	HstsMaxAge            int64     `db:"hsts_max_age"`
+	HstsIncludeSubdomains bool      `db:"hsts_include_subdomains"`
+	HstsPreload           bool      `db:"hsts_preload"`
 	CreatedAt             time.Time `db:"created_at"`
 	UpdatedAt             time.Time `db:"updated_at"`
 }
@@ -67,6 +70,11 @@ func TDomainList(dbCtx lib.DBContexter, where *TDomainParam) ([]*TDomain, error)
 	return nil, err
 }
 
+// TDomainCount Query Count
+func TDomainCount(dbCtx lib.DBContexter, where *TDomainParam) (int64, error) {
+	return internal.Count(dbCtx, tDomainTableName, where)
+}
+
 // TDomainParamCreate/Update/Where Data Carrier
 // See: https://github.com/didi/gendry/blob/master/builder/README.md
 type TDomainParam struct {
@@ -78,10 +86,16 @@ type TDomainParam struct {
 	Type                  *int32     `db:"type"`
 	UsingAdvancedRedirect *int8      `db:"using_advanced_redirect"`
 	UsingAdvancedHsts     *int8      `db:"using_advanced_hsts"`
+	HstsMaxAge            *int64     `db:"hsts_max_age"`
+	HstsIncludeSubdomains *bool      `db:"hsts_include_subdomains"`
+	HstsPreload           *bool      `db:"hsts_preload"`
 	CreatedAt             *time.Time `db:"created_at"`
 	UpdatedAt             *time.Time `db:"updated_at"`
 
+	NameLike *string `db:"name,like"`
+
 	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
 }
 
 // TDomainCreate One/Multiple
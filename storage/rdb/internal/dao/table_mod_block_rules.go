@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tModBlockRuleTableName = "mod_block_rules"
+
+// TModBlockRule Query Result
+type TModBlockRule struct {
+	ID          int64     `db:"id"`
+	ProductID   int64     `db:"product_id"`
+	RuleIndex   int       `db:"rule_index"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Cond        string    `db:"cond"`
+	Action      string    `db:"action"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// TModBlockRuleOne Query One
+// return (nil, nil) if record not existed
+func TModBlockRuleOne(dbCtx lib.DBContexter, where *TModBlockRuleParam) (*TModBlockRule, error) {
+	t := &TModBlockRule{}
+	err := internal.QueryOne(dbCtx, tModBlockRuleTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TModBlockRuleList Query Multiple
+func TModBlockRuleList(dbCtx lib.DBContexter, where *TModBlockRuleParam) ([]*TModBlockRule, error) {
+	t := []*TModBlockRule{}
+	err := internal.QueryList(dbCtx, tModBlockRuleTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TModBlockRuleParam Create/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TModBlockRuleParam struct {
+	ID          *int64     `db:"id"`
+	IDs         []int64    `db:"id,in"`
+	ProductID   *int64     `db:"product_id"`
+	ProductIDs  []int64    `db:"product_id,in"`
+	RuleIndex   *int       `db:"rule_index"`
+	Name        *string    `db:"name"`
+	Description *string    `db:"description"`
+	Cond        *string    `db:"cond"`
+	Action      *string    `db:"action"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+
+	OrderBy *string `db:"_orderby"`
+}
+
+// TModBlockRuleCreate One/Multiple
+func TModBlockRuleCreate(dbCtx lib.DBContexter, data ...*TModBlockRuleParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tModBlockRuleTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tModBlockRuleTableName, list...)
+}
+
+// TModBlockRuleUpdate Update One
+func TModBlockRuleUpdate(dbCtx lib.DBContexter, val, where *TModBlockRuleParam) (int64, error) {
+	return internal.Update(dbCtx, tModBlockRuleTableName, where, val)
+}
+
+// TModBlockRuleDelete Delete One/Multiple
+func TModBlockRuleDelete(dbCtx lib.DBContexter, where *TModBlockRuleParam) (int64, error) {
+	return internal.Delete(dbCtx, tModBlockRuleTableName, where)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tModHeaderRuleTableName = "mod_header_rules"
+
+// TModHeaderRule Query Result
+type TModHeaderRule struct {
+	ID          int64     `db:"id"`
+	ProductID   int64     `db:"product_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Cond        string    `db:"cond"`
+	Actions     string    `db:"actions"`
+	IsLast      bool      `db:"is_last"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// TModHeaderRuleOne Query One
+// return (nil, nil) if record not existed
+func TModHeaderRuleOne(dbCtx lib.DBContexter, where *TModHeaderRuleParam) (*TModHeaderRule, error) {
+	t := &TModHeaderRule{}
+	err := internal.QueryOne(dbCtx, tModHeaderRuleTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TModHeaderRuleList Query Multiple
+func TModHeaderRuleList(dbCtx lib.DBContexter, where *TModHeaderRuleParam) ([]*TModHeaderRule, error) {
+	t := []*TModHeaderRule{}
+	err := internal.QueryList(dbCtx, tModHeaderRuleTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TModHeaderRuleParam Create/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TModHeaderRuleParam struct {
+	ID          *int64     `db:"id"`
+	IDs         []int64    `db:"id,in"`
+	ProductID   *int64     `db:"product_id"`
+	ProductIDs  []int64    `db:"product_id,in"`
+	Name        *string    `db:"name"`
+	Description *string    `db:"description"`
+	Cond        *string    `db:"cond"`
+	Actions     *string    `db:"actions"`
+	IsLast      *bool      `db:"is_last"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+
+	OrderBy *string `db:"_orderby"`
+}
+
+// TModHeaderRuleCreate One/Multiple
+func TModHeaderRuleCreate(dbCtx lib.DBContexter, data ...*TModHeaderRuleParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tModHeaderRuleTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tModHeaderRuleTableName, list...)
+}
+
+// TModHeaderRuleUpdate Update One
+func TModHeaderRuleUpdate(dbCtx lib.DBContexter, val, where *TModHeaderRuleParam) (int64, error) {
+	return internal.Update(dbCtx, tModHeaderRuleTableName, where, val)
+}
+
+// TModHeaderRuleDelete Delete One/Multiple
+func TModHeaderRuleDelete(dbCtx lib.DBContexter, where *TModHeaderRuleParam) (int64, error) {
+	return internal.Delete(dbCtx, tModHeaderRuleTableName, where)
+}
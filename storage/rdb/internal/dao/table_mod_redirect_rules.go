@@ -0,0 +1,117 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tModRedirectRuleTableName = "mod_redirect_rules"
+
+// TModRedirectRule Query Result
+type TModRedirectRule struct {
+	ID          int64     `db:"id"`
+	ProductID   int64     `db:"product_id"`
+	RuleIndex   int       `db:"rule_index"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Cond        string    `db:"cond"`
+	Cmd         string    `db:"cmd"`
+	Param       string    `db:"param"`
+	Status      int       `db:"status"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// TModRedirectRuleOne Query One
+// return (nil, nil) if record not existed
+func TModRedirectRuleOne(dbCtx lib.DBContexter, where *TModRedirectRuleParam) (*TModRedirectRule, error) {
+	t := &TModRedirectRule{}
+	err := internal.QueryOne(dbCtx, tModRedirectRuleTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TModRedirectRuleList Query Multiple
+func TModRedirectRuleList(dbCtx lib.DBContexter, where *TModRedirectRuleParam) ([]*TModRedirectRule, error) {
+	t := []*TModRedirectRule{}
+	err := internal.QueryList(dbCtx, tModRedirectRuleTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TModRedirectRuleParam Create/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TModRedirectRuleParam struct {
+	ID          *int64     `db:"id"`
+	IDs         []int64    `db:"id,in"`
+	ProductID   *int64     `db:"product_id"`
+	ProductIDs  []int64    `db:"product_id,in"`
+	RuleIndex   *int       `db:"rule_index"`
+	Name        *string    `db:"name"`
+	Description *string    `db:"description"`
+	Cond        *string    `db:"cond"`
+	Cmd         *string    `db:"cmd"`
+	Param       *string    `db:"param"`
+	Status      *int       `db:"status"`
+	CreatedAt   *time.Time `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+
+	OrderBy *string `db:"_orderby"`
+}
+
+// TModRedirectRuleCreate One/Multiple
+func TModRedirectRuleCreate(dbCtx lib.DBContexter, data ...*TModRedirectRuleParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tModRedirectRuleTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tModRedirectRuleTableName, list...)
+}
+
+// TModRedirectRuleUpdate Update One
+func TModRedirectRuleUpdate(dbCtx lib.DBContexter, val, where *TModRedirectRuleParam) (int64, error) {
+	return internal.Update(dbCtx, tModRedirectRuleTableName, where, val)
+}
+
+// TModRedirectRuleDelete Delete One/Multiple
+func TModRedirectRuleDelete(dbCtx lib.DBContexter, where *TModRedirectRuleParam) (int64, error) {
+	return internal.Delete(dbCtx, tModRedirectRuleTableName, where)
+}
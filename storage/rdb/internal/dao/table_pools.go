@@ -63,6 +63,11 @@ func TPoolsList(dbCtx lib.DBContexter, where *TPoolsParam) ([]*TPools, error) {
 	return nil, err
 }
 
+// TPoolsCount Query Count
+func TPoolsCount(dbCtx lib.DBContexter, where *TPoolsParam) (int64, error) {
+	return internal.Count(dbCtx, tPoolsTableName, where)
+}
+
 // TPoolsParamCreate/Update/Where Data Carrier
 // See: https://github.com/didi/gendry/blob/master/builder/README.md
 type TPoolsParam struct {
@@ -79,7 +84,10 @@ type TPoolsParam struct {
 	CreatedAt      *time.Time `db:"created_at"`
 	UpdatedAt      *time.Time `db:"updated_at"`
 
+	NameLike *string `db:"name,like"`
+
 	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
 }
 
 // TPoolsCreate One/Multiple
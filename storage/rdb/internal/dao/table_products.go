@@ -64,6 +64,11 @@ func TProductList(dbCtx lib.DBContexter, where *TProductParam) ([]*TProduct, err
 	return nil, err
 }
 
+// TProductCount Query Count
+func TProductCount(dbCtx lib.DBContexter, where *TProductParam) (int64, error) {
+	return internal.Count(dbCtx, tProductTableName, where)
+}
+
 // TProductParamCreate/Update/Where Data Carrier
 // See: https://github.com/didi/gendry/blob/master/builder/README.md
 type TProductParam struct {
@@ -81,7 +86,10 @@ type TProductParam struct {
 	IDs  []int64 `db:"id,in"`
 	NeId *int64  `db:"id,!="`
 
+	NameLike *string `db:"name,like"`
+
 	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
 }
 
 // TProductCreate One/Multiple
@@ -123,8 +131,15 @@ DELETE FROM sub_clusters  			WHERE product_id = xxx;
 DELETE FROM pools  					WHERE product_id = xxx;
 DELETE FROM route_basic_rules  		WHERE product_id = xxx;
 DELETE FROM route_advance_rules 	WHERE product_id = xxx;
+DELETE FROM route_cases 			WHERE product_id = xxx;
 DELETE FROM user_products  			WHERE product_id = xxx;
-DELETE FROM extra_files  			WHERE product_id = xxx; `
+DELETE FROM extra_files  			WHERE product_id = xxx;
+DELETE FROM active_health_checks 	WHERE product_id = xxx;
+DELETE FROM mod_header_rules 		WHERE product_id = xxx;
+DELETE FROM mod_rewrite_rules 		WHERE product_id = xxx;
+DELETE FROM mod_redirect_rules 		WHERE product_id = xxx;
+DELETE FROM mod_block_rules 		WHERE product_id = xxx;
+DELETE FROM tls_rules 				WHERE product_id = xxx; `
 
 func TProductDeleteByProductID(dbCtx lib.DBContexter, productID int64) error {
 	sql := strings.Replace(deleteSQL, "xxx", fmt.Sprintf("%d", productID), -1)
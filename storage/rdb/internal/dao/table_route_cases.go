@@ -0,0 +1,117 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tRouteCaseTableName = "route_cases"
+
+// TRouteCase Query Result
+type TRouteCase struct {
+	ID            int64     `db:"id"`
+	Description   string    `db:"description"`
+	ProductID     int64     `db:"product_id"`
+	URL           string    `db:"url"`
+	Method        string    `db:"method"`
+	Protocol      string    `db:"protocol"`
+	Header        string    `db:"header"`
+	Body          string    `db:"body"`
+	ExpectCluster string    `db:"expect_cluster"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// TRouteCaseOne Query One
+// return (nil, nil) if record not existed
+func TRouteCaseOne(dbCtx lib.DBContexter, where *TRouteCaseParam) (*TRouteCase, error) {
+	t := &TRouteCase{}
+	err := internal.QueryOne(dbCtx, tRouteCaseTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TRouteCaseList Query Multiple
+func TRouteCaseList(dbCtx lib.DBContexter, where *TRouteCaseParam) ([]*TRouteCase, error) {
+	t := []*TRouteCase{}
+	err := internal.QueryList(dbCtx, tRouteCaseTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TRouteCaseParam Create/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TRouteCaseParam struct {
+	ID            *int64     `db:"id"`
+	IDs           []int64    `db:"id,in"`
+	Description   *string    `db:"description"`
+	ProductID     *int64     `db:"product_id"`
+	ProductIDs    []int64    `db:"product_id,in"`
+	URL           *string    `db:"url"`
+	Method        *string    `db:"method"`
+	Protocol      *string    `db:"protocol"`
+	Header        *string    `db:"header"`
+	Body          *string    `db:"body"`
+	ExpectCluster *string    `db:"expect_cluster"`
+	CreatedAt     *time.Time `db:"created_at"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+
+	OrderBy *string `db:"_orderby"`
+}
+
+// TRouteCaseCreate One/Multiple
+func TRouteCaseCreate(dbCtx lib.DBContexter, data ...*TRouteCaseParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tRouteCaseTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tRouteCaseTableName, list...)
+}
+
+// TRouteCaseUpdate Update One
+func TRouteCaseUpdate(dbCtx lib.DBContexter, val, where *TRouteCaseParam) (int64, error) {
+	return internal.Update(dbCtx, tRouteCaseTableName, where, val)
+}
+
+// TRouteCaseDelete Delete One/Multiple
+func TRouteCaseDelete(dbCtx lib.DBContexter, where *TRouteCaseParam) (int64, error) {
+	return internal.Delete(dbCtx, tRouteCaseTableName, where)
+}
@@ -35,6 +35,9 @@ type TSubCluster struct {
 	Enabled     bool      `db:"enabled"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
+
+	PortName         string `db:"port_name"`
+	InstanceSelector string `db:"instance_selector"`
 }
 
 // TSubClusterOne Query One
@@ -63,6 +66,11 @@ func TSubClusterList(dbCtx lib.DBContexter, where *TSubClusterParam) ([]*TSubClu
 	return nil, err
 }
 
+// TSubClusterCount Query Count
+func TSubClusterCount(dbCtx lib.DBContexter, where *TSubClusterParam) (int64, error) {
+	return internal.Count(dbCtx, tSubClusterTableName, where)
+}
+
 // TSubClusterParamCreate/Update/Where Data Carrier
 // See: https://github.com/didi/gendry/blob/master/builder/README.md
 type TSubClusterParam struct {
@@ -81,7 +89,13 @@ type TSubClusterParam struct {
 	CreatedAt   *time.Time `db:"created_at"`
 	UpdatedAt   *time.Time `db:"updated_at"`
 
+	PortName         *string `db:"port_name"`
+	InstanceSelector *string `db:"instance_selector"`
+
+	NameLike *string `db:"name,like"`
+
 	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
 }
 
 // TSubClusterCreate One/Multiple
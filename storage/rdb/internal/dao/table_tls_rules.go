@@ -0,0 +1,115 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"time"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao/internal"
+)
+
+const tTLSRuleTableName = "tls_rules"
+
+// TTLSRule Query Result
+type TTLSRule struct {
+	ID            int64     `db:"id"`
+	ProductID     int64     `db:"product_id"`
+	CertName      string    `db:"cert_name"`
+	Hosts         string    `db:"hosts"`
+	NextProtos    string    `db:"next_protos"`
+	Grade         string    `db:"grade"`
+	Chacha20      bool      `db:"chacha20"`
+	DynamicRecord bool      `db:"dynamic_record"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// TTLSRuleOne Query One
+// return (nil, nil) if record not existed
+func TTLSRuleOne(dbCtx lib.DBContexter, where *TTLSRuleParam) (*TTLSRule, error) {
+	t := &TTLSRule{}
+	err := internal.QueryOne(dbCtx, tTLSRuleTableName, where, t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TTLSRuleList Query Multiple
+func TTLSRuleList(dbCtx lib.DBContexter, where *TTLSRuleParam) ([]*TTLSRule, error) {
+	t := []*TTLSRule{}
+	err := internal.QueryList(dbCtx, tTLSRuleTableName, where, &t)
+	if err == nil {
+		return t, nil
+	}
+	if xerror.Cause(err) == internal.ErrRecordNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// TTLSRuleParam Create/Update/Where Data Carrier
+// See: https://github.com/didi/gendry/blob/master/builder/README.md
+type TTLSRuleParam struct {
+	ID            *int64     `db:"id"`
+	IDs           []int64    `db:"id,in"`
+	ProductID     *int64     `db:"product_id"`
+	ProductIDs    []int64    `db:"product_id,in"`
+	CertName      *string    `db:"cert_name"`
+	Hosts         *string    `db:"hosts"`
+	NextProtos    *string    `db:"next_protos"`
+	Grade         *string    `db:"grade"`
+	Chacha20      *bool      `db:"chacha20"`
+	DynamicRecord *bool      `db:"dynamic_record"`
+	CreatedAt     *time.Time `db:"created_at"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+
+	OrderBy *string `db:"_orderby"`
+}
+
+// TTLSRuleCreate One/Multiple
+func TTLSRuleCreate(dbCtx lib.DBContexter, data ...*TTLSRuleParam) (int64, error) {
+	if len(data) == 1 {
+		if data[0].CreatedAt == nil {
+			data[0].CreatedAt = internal.PTimeNow()
+		}
+		return internal.Create(dbCtx, tTLSRuleTableName, data[0])
+	}
+
+	list := make([]interface{}, len(data))
+	for i, one := range data {
+		if one.CreatedAt == nil {
+			one.CreatedAt = internal.PTimeNow()
+		}
+		list[i] = one
+	}
+
+	return internal.Create(dbCtx, tTLSRuleTableName, list...)
+}
+
+// TTLSRuleUpdate Update One
+func TTLSRuleUpdate(dbCtx lib.DBContexter, val, where *TTLSRuleParam) (int64, error) {
+	return internal.Update(dbCtx, tTLSRuleTableName, where, val)
+}
+
+// TTLSRuleDelete Delete One/Multiple
+func TTLSRuleDelete(dbCtx lib.DBContexter, where *TTLSRuleParam) (int64, error) {
+	return internal.Delete(dbCtx, tTLSRuleTableName, where)
+}
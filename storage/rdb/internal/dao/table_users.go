@@ -64,6 +64,11 @@ func TUserList(dbCtx lib.DBContexter, where *TUserParam) ([]*TUser, error) {
 	return nil, err
 }
 
+// TUserCount Query Count
+func TUserCount(dbCtx lib.DBContexter, where *TUserParam) (int64, error) {
+	return internal.Count(dbCtx, tUserTableName, where)
+}
+
 // TUserParamCreate/Update/Where Data Carrier
 // See: https://github.com/didi/gendry/blob/master/builder/README.md
 type TUserParam struct {
@@ -80,7 +85,10 @@ type TUserParam struct {
 	CreatedAt       *time.Time `db:"created_at"`
 	UpdatedAt       *time.Time `db:"updated_at"`
 
+	NameLike *string `db:"name,like"`
+
 	OrderBy *string `db:"_orderby"`
+	Limit   []uint  `db:"_limit"`
 }
 
 // TUserCreate One/Multiple
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+var _ imodule_conf.BlockRuleStorager = &BlockRuleStorager{}
+
+func NewBlockRuleStorager(dbCtxFactory lib.DBContextFactory) *BlockRuleStorager {
+	return &BlockRuleStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+type BlockRuleStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+func (rs *BlockRuleStorager) FetchBlockRules(ctx context.Context, filter *imodule_conf.BlockRuleFilter) ([]*imodule_conf.BlockRule, error) {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := &dao.TModBlockRuleParam{
+		OrderBy: lib.PString("product_id, rule_index"),
+	}
+	if filter != nil && filter.Product != nil {
+		where.ProductID = &filter.Product.ID
+	}
+
+	list, err := dao.TModBlockRuleList(dbCtx, where)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*imodule_conf.BlockRule, len(list))
+	for i, one := range list {
+		rst[i] = blockRuled2i(one)
+	}
+
+	return rst, nil
+}
+
+func (rs *BlockRuleStorager) UpsertProductBlockRules(ctx context.Context, product *ibasic.Product,
+	rules []*imodule_conf.BlockRule) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err = dao.TModBlockRuleDelete(dbCtx, &dao.TModBlockRuleParam{
+		ProductID: &product.ID,
+	}); err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	list := make([]*dao.TModBlockRuleParam, len(rules))
+	for i, rule := range rules {
+		list[i] = &dao.TModBlockRuleParam{
+			ProductID:   &product.ID,
+			RuleIndex:   lib.PInt(i),
+			Name:        lib.PString(rule.Name),
+			Description: lib.PString(rule.Description),
+			Cond:        lib.PString(rule.Cond),
+			Action:      lib.PString(rule.Action),
+		}
+	}
+
+	_, err = dao.TModBlockRuleCreate(dbCtx, list...)
+	return err
+}
+
+func blockRuled2i(p *dao.TModBlockRule) *imodule_conf.BlockRule {
+	return &imodule_conf.BlockRule{
+		ProductID:   p.ProductID,
+		Index:       p.RuleIndex,
+		Name:        p.Name,
+		Description: p.Description,
+		Cond:        p.Cond,
+		Action:      p.Action,
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+func TestBlockRuleStorager(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+	rs := NewBlockRuleStorager(stateful.NewBFEDBContext)
+
+	rules := []*imodule_conf.BlockRule{
+		{
+			Name:   "allow_office",
+			Cond:   "req_cip_range(\"10.0.0.0\", \"10.255.255.255\")",
+			Action: imodule_conf.BlockActionAllow,
+		},
+		{
+			Name:   "block_admin",
+			Cond:   "req_path_prefix_in(\"/admin\", false)",
+			Action: imodule_conf.BlockActionClose,
+		},
+	}
+	if err := rs.UpsertProductBlockRules(ctx, product, rules); err != nil {
+		t.Fatalf("UpsertProductBlockRules: %v", err)
+	}
+
+	filter := &imodule_conf.BlockRuleFilter{Product: product}
+	list, err := rs.FetchBlockRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchBlockRules: %v", err)
+	}
+	for i, rule := range rules {
+		rule.ProductID, rule.Index = product.ID, i
+	}
+	if !reflect.DeepEqual(list, rules) {
+		t.Fatalf("FetchBlockRules: want %+v, got %+v", rules, list)
+	}
+
+	if err = rs.UpsertProductBlockRules(ctx, product, nil); err != nil {
+		t.Fatalf("UpsertProductBlockRules: %v", err)
+	}
+	list, err = rs.FetchBlockRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchBlockRules: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchBlockRules: want no rule, got %d", len(list))
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bfenetworks/bfe/bfe_basic/condition"
+	"github.com/bfenetworks/bfe/bfe_modules/mod_block"
+	"github.com/bfenetworks/bfe/bfe_modules/mod_header"
+	"github.com/bfenetworks/bfe/bfe_modules/mod_redirect"
+	"github.com/bfenetworks/bfe/bfe_modules/mod_rewrite"
+	"github.com/bfenetworks/bfe/bfe_util/ipdict"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/api-server/stateful/container/rdb"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+// createProduct init container with a new sqlite db, and create product in it
+func createProduct(t *testing.T, name string) *ibasic.Product {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	stateful.DefaultConfig.RunTime.ExportCache.Disable = true
+	rdb.Init()
+
+	if err := container.ProductManager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString(name),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	list, err := container.ProductManager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString(name),
+	})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchProducts: %v %v", list, err)
+	}
+
+	return list[0]
+}
+
+// writeConf write exported config to a file for BFE loaders
+func writeConf(t *testing.T, conf interface{}) string {
+	bs, err := json.Marshal(conf)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "conf.data")
+	if err = ioutil.WriteFile(filename, bs, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	return filename
+}
+
+func TestExportHeaderLoadedByBFE(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	if _, err := container.HeaderRuleManager.CreateHeaderRule(ctx, product, &imodule_conf.HeaderRuleParam{
+		Name:        lib.PString("set_demo"),
+		Description: lib.PString(""),
+		Cond:        lib.PString("req_path_prefix_in(\"/demo\", false)"),
+		Actions: []*imodule_conf.HeaderAction{
+			{Cmd: "REQ_HEADER_SET", Params: []string{"X-Demo", "1"}},
+		},
+		Last: lib.PBool(false),
+	}); err != nil {
+		t.Fatalf("CreateHeaderRule: %v", err)
+	}
+
+	conf, err := container.HeaderRuleManager.ExportHeader(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportHeader: %v", err)
+	}
+	loaded, err := mod_header.HeaderConfLoad(writeConf(t, conf))
+	if err != nil {
+		t.Fatalf("HeaderConfLoad: %v", err)
+	}
+	if loaded.Version != conf.Version {
+		t.Fatalf("HeaderConfLoad: want version %s, got %s", conf.Version, loaded.Version)
+	}
+	count := 0
+	for _, rules := range loaded.Config["demo"] {
+		count += len(*rules)
+	}
+	if count != 1 {
+		t.Fatalf("HeaderConfLoad: want 1 rule of demo, got %d", count)
+	}
+}
+
+func TestExportRewriteLoadedByBFE(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	if _, err := container.RewriteRuleManager.UpsertProductRewriteRules(ctx, product, []*imodule_conf.RewriteRule{
+		{
+			Name: "trim_api",
+			Cond: "req_path_prefix_in(\"/api\", false)",
+			Actions: []*imodule_conf.RewriteAction{
+				{Cmd: "PATH_PREFIX_TRIM", Params: []string{"/api"}},
+			},
+			Last: true,
+		},
+	}); err != nil {
+		t.Fatalf("UpsertProductRewriteRules: %v", err)
+	}
+
+	conf, err := container.RewriteRuleManager.ExportRewrite(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportRewrite: %v", err)
+	}
+	loaded, err := mod_rewrite.ReWriteConfLoad(writeConf(t, conf))
+	if err != nil {
+		t.Fatalf("ReWriteConfLoad: %v", err)
+	}
+	if loaded.Version != conf.Version {
+		t.Fatalf("ReWriteConfLoad: want version %s, got %s", conf.Version, loaded.Version)
+	}
+	if rules := loaded.Config["demo"]; rules == nil || len(*rules) != 1 {
+		t.Fatalf("ReWriteConfLoad: want 1 rule of demo, got %+v", loaded.Config)
+	}
+}
+
+func TestExportRedirectLoadedByBFE(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	if _, err := container.RedirectRuleManager.UpsertProductRedirectRules(ctx, product, []*imodule_conf.RedirectRule{
+		{
+			Name:   "old_path",
+			Cond:   "req_path_in(\"/old\", false)",
+			Cmd:    "URL_SET",
+			Param:  "https://example.org/new",
+			Status: 301,
+		},
+	}); err != nil {
+		t.Fatalf("UpsertProductRedirectRules: %v", err)
+	}
+
+	conf, err := container.RedirectRuleManager.ExportRedirect(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportRedirect: %v", err)
+	}
+
+	// mod_redirect exports no loader, decode and check the file as it does
+	bs, err := ioutil.ReadFile(writeConf(t, conf))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	var loaded mod_redirect.RedirectConfFile
+	if err = json.Unmarshal(bs, &loaded); err != nil {
+		t.Fatalf("decode config: %v", err)
+	}
+	if err = mod_redirect.RedirectConfCheck(loaded); err != nil {
+		t.Fatalf("RedirectConfCheck: %v", err)
+	}
+	rules := (*loaded.Config)["demo"]
+	if rules == nil || len(*rules) != 1 {
+		t.Fatalf("RedirectConfCheck: want 1 rule of demo, got %s", bs)
+	}
+	if _, err = condition.Build(*(*rules)[0].Cond); err != nil {
+		t.Fatalf("condition.Build: %v", err)
+	}
+}
+
+func TestExportBlockRuleLoadedByBFE(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	if _, err := container.BlockRuleManager.UpsertProductBlockRules(ctx, product, []*imodule_conf.BlockRule{
+		{
+			Name:   "block_admin",
+			Cond:   "req_path_prefix_in(\"/admin\", false)",
+			Action: imodule_conf.BlockActionClose,
+		},
+	}); err != nil {
+		t.Fatalf("UpsertProductBlockRules: %v", err)
+	}
+
+	conf, err := container.BlockRuleManager.ExportBlockRule(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportBlockRule: %v", err)
+	}
+	loaded, err := mod_block.ProductRuleConfLoad(writeConf(t, conf))
+	if err != nil {
+		t.Fatalf("ProductRuleConfLoad: %v", err)
+	}
+	if loaded.Version != conf.Version {
+		t.Fatalf("ProductRuleConfLoad: want version %s, got %s", conf.Version, loaded.Version)
+	}
+	if rules := loaded.Config["demo"]; rules == nil || len(*rules) != 1 {
+		t.Fatalf("ProductRuleConfLoad: want 1 rule of demo, got %+v", loaded.Config)
+	}
+}
+
+func TestExportIPBlocklistLoadedByBFE(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	expired := time.Now().Add(-time.Hour)
+	if _, err := container.IPBlocklistManager.UpdateProductIPBlocklist(ctx, product, []*imodule_conf.IPBlocklistEntry{
+		{IP: "10.0.0.1"},
+		{IP: "10.1.0.0/16"},
+		{IP: "10.2.0.1", ExpireTime: &expired},
+	}, nil); err != nil {
+		t.Fatalf("UpdateProductIPBlocklist: %v", err)
+	}
+
+	conf, err := container.IPBlocklistManager.ExportIPBlocklist(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportIPBlocklist: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "ip_blocklist.data")
+	if err = ioutil.WriteFile(filename, conf.FileContent(), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	items, err := mod_block.GlobalIPTableLoad(filename)
+	if err != nil {
+		t.Fatalf("GlobalIPTableLoad: %v", err)
+	}
+	if items.Version != conf.Version {
+		t.Fatalf("GlobalIPTableLoad: want version %s, got %s", conf.Version, items.Version)
+	}
+
+	table := ipdict.NewIPTable()
+	table.Update(items)
+	cases := map[string]bool{
+		"10.0.0.1":   true,
+		"10.0.0.2":   false,
+		"10.1.255.1": true,
+		"10.2.0.1":   false,
+	}
+	for ip, want := range cases {
+		if got := table.Search(net.ParseIP(ip)); got != want {
+			t.Errorf("Search %s: want %v, got %v", ip, want, got)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+var _ imodule_conf.HeaderRuleStorager = &HeaderRuleStorager{}
+
+func NewHeaderRuleStorager(dbCtxFactory lib.DBContextFactory) *HeaderRuleStorager {
+	return &HeaderRuleStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+type HeaderRuleStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+func (hs *HeaderRuleStorager) FetchHeaderRules(ctx context.Context, filter *imodule_conf.HeaderRuleFilter) ([]*imodule_conf.HeaderRule, error) {
+	dbCtx, err := hs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := dao.TModHeaderRuleList(dbCtx, headerRuleFilter2Param(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*imodule_conf.HeaderRule, len(list))
+	for i, one := range list {
+		if rst[i], err = headerRuled2i(one); err != nil {
+			return nil, err
+		}
+	}
+
+	return rst, nil
+}
+
+func (hs *HeaderRuleStorager) CreateHeaderRule(ctx context.Context, product *ibasic.Product,
+	param *imodule_conf.HeaderRuleParam) (int64, error) {
+
+	_p, err := headerRuleParami2d(param)
+	if err != nil {
+		return 0, err
+	}
+	_p.ProductID = &product.ID
+
+	dbCtx, err := hs.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dao.TModHeaderRuleCreate(dbCtx, _p)
+}
+
+func (hs *HeaderRuleStorager) UpdateHeaderRule(ctx context.Context, product *ibasic.Product,
+	old *imodule_conf.HeaderRule, param *imodule_conf.HeaderRuleParam) error {
+
+	_p, err := headerRuleParami2d(param)
+	if err != nil {
+		return err
+	}
+	_p.UpdatedAt = lib.PTimeNow()
+
+	dbCtx, err := hs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TModHeaderRuleUpdate(dbCtx, _p, &dao.TModHeaderRuleParam{
+		ID:        &old.ID,
+		ProductID: &product.ID,
+	})
+	return err
+}
+
+func (hs *HeaderRuleStorager) DeleteHeaderRule(ctx context.Context, product *ibasic.Product,
+	rule *imodule_conf.HeaderRule) error {
+
+	dbCtx, err := hs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TModHeaderRuleDelete(dbCtx, &dao.TModHeaderRuleParam{
+		ID:        &rule.ID,
+		ProductID: &product.ID,
+	})
+	return err
+}
+
+func headerRuleFilter2Param(filter *imodule_conf.HeaderRuleFilter) *dao.TModHeaderRuleParam {
+	if filter == nil {
+		return &dao.TModHeaderRuleParam{
+			OrderBy: lib.PString("id"),
+		}
+	}
+
+	var pid *int64
+	if filter.Product != nil {
+		pid = &filter.Product.ID
+	}
+	return &dao.TModHeaderRuleParam{
+		ID:        filter.ID,
+		ProductID: pid,
+		Name:      filter.Name,
+		OrderBy:   lib.PString("id"),
+	}
+}
+
+func headerRuleParami2d(p *imodule_conf.HeaderRuleParam) (*dao.TModHeaderRuleParam, error) {
+	_p := &dao.TModHeaderRuleParam{
+		Name:        p.Name,
+		Description: p.Description,
+		Cond:        p.Cond,
+		IsLast:      p.Last,
+	}
+
+	if p.Actions != nil {
+		bs, err := json.Marshal(p.Actions)
+		if err != nil {
+			return nil, xerror.WrapDirtyDataError(err)
+		}
+		_p.Actions = lib.PString(string(bs))
+	}
+
+	return _p, nil
+}
+
+func headerRuled2i(p *dao.TModHeaderRule) (*imodule_conf.HeaderRule, error) {
+	rule := &imodule_conf.HeaderRule{
+		ID:          p.ID,
+		ProductID:   p.ProductID,
+		Name:        p.Name,
+		Description: p.Description,
+		Cond:        p.Cond,
+		Last:        p.IsLast,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(p.Actions), &rule.Actions); err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Actions: %s, err: %v", p.Actions, err)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/basic"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+// createProduct open a new sqlite db and create product in it
+func createProduct(t *testing.T, name string) *ibasic.Product {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	productStorager := basic.NewProductManager(stateful.NewBFEDBContext)
+	if err := productStorager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString(name),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	products, err := productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString(name),
+	})
+	if err != nil || len(products) != 1 {
+		t.Fatalf("FetchProducts: %v %v", products, err)
+	}
+
+	return products[0]
+}
+
+func TestHeaderRuleStorager(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+	hs := NewHeaderRuleStorager(stateful.NewBFEDBContext)
+
+	actions := []*imodule_conf.HeaderAction{
+		{Cmd: "REQ_HEADER_SET", Params: []string{"X-Demo", "1"}},
+	}
+	id, err := hs.CreateHeaderRule(ctx, product, &imodule_conf.HeaderRuleParam{
+		Name:        lib.PString("set_demo"),
+		Description: lib.PString(""),
+		Cond:        lib.PString("default_t()"),
+		Actions:     actions,
+		Last:        lib.PBool(false),
+	})
+	if err != nil {
+		t.Fatalf("CreateHeaderRule: %v", err)
+	}
+
+	filter := &imodule_conf.HeaderRuleFilter{
+		Product: product,
+		ID:      &id,
+	}
+	list, err := hs.FetchHeaderRules(ctx, filter)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchHeaderRules: %v %v", list, err)
+	}
+	rule := list[0]
+	if rule.Name != "set_demo" || rule.Cond != "default_t()" || rule.Last ||
+		!reflect.DeepEqual(rule.Actions, actions) {
+		t.Fatalf("FetchHeaderRules: unexpected rule %+v", rule)
+	}
+
+	actions = []*imodule_conf.HeaderAction{
+		{Cmd: "RSP_HEADER_DEL", Params: []string{"Server"}},
+	}
+	if err = hs.UpdateHeaderRule(ctx, product, rule, &imodule_conf.HeaderRuleParam{
+		Actions: actions,
+		Last:    lib.PBool(true),
+	}); err != nil {
+		t.Fatalf("UpdateHeaderRule: %v", err)
+	}
+	list, err = hs.FetchHeaderRules(ctx, filter)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchHeaderRules: %v %v", list, err)
+	}
+	rule = list[0]
+	if rule.Name != "set_demo" || !rule.Last || !reflect.DeepEqual(rule.Actions, actions) {
+		t.Fatalf("FetchHeaderRules: unexpected rule %+v", rule)
+	}
+
+	if err = hs.DeleteHeaderRule(ctx, product, rule); err != nil {
+		t.Fatalf("DeleteHeaderRule: %v", err)
+	}
+	list, err = hs.FetchHeaderRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchHeaderRules: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchHeaderRules: want no rule, got %d", len(list))
+	}
+}
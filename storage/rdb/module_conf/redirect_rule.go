@@ -0,0 +1,111 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+var _ imodule_conf.RedirectRuleStorager = &RedirectRuleStorager{}
+
+func NewRedirectRuleStorager(dbCtxFactory lib.DBContextFactory) *RedirectRuleStorager {
+	return &RedirectRuleStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+type RedirectRuleStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+func (rs *RedirectRuleStorager) FetchRedirectRules(ctx context.Context, filter *imodule_conf.RedirectRuleFilter) ([]*imodule_conf.RedirectRule, error) {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := &dao.TModRedirectRuleParam{
+		OrderBy: lib.PString("product_id, rule_index"),
+	}
+	if filter != nil && filter.Product != nil {
+		where.ProductID = &filter.Product.ID
+	}
+
+	list, err := dao.TModRedirectRuleList(dbCtx, where)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*imodule_conf.RedirectRule, len(list))
+	for i, one := range list {
+		rst[i] = redirectRuled2i(one)
+	}
+
+	return rst, nil
+}
+
+func (rs *RedirectRuleStorager) UpsertProductRedirectRules(ctx context.Context, product *ibasic.Product,
+	rules []*imodule_conf.RedirectRule) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err = dao.TModRedirectRuleDelete(dbCtx, &dao.TModRedirectRuleParam{
+		ProductID: &product.ID,
+	}); err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	list := make([]*dao.TModRedirectRuleParam, len(rules))
+	for i, rule := range rules {
+		list[i] = &dao.TModRedirectRuleParam{
+			ProductID:   &product.ID,
+			RuleIndex:   lib.PInt(i),
+			Name:        lib.PString(rule.Name),
+			Description: lib.PString(rule.Description),
+			Cond:        lib.PString(rule.Cond),
+			Cmd:         lib.PString(rule.Cmd),
+			Param:       lib.PString(rule.Param),
+			Status:      lib.PInt(rule.Status),
+		}
+	}
+
+	_, err = dao.TModRedirectRuleCreate(dbCtx, list...)
+	return err
+}
+
+func redirectRuled2i(p *dao.TModRedirectRule) *imodule_conf.RedirectRule {
+	return &imodule_conf.RedirectRule{
+		ProductID:   p.ProductID,
+		Index:       p.RuleIndex,
+		Name:        p.Name,
+		Description: p.Description,
+		Cond:        p.Cond,
+		Cmd:         p.Cmd,
+		Param:       p.Param,
+		Status:      p.Status,
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+func TestRedirectRuleStorager(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+	rs := NewRedirectRuleStorager(stateful.NewBFEDBContext)
+
+	rules := []*imodule_conf.RedirectRule{
+		{
+			Name:   "old_path",
+			Cond:   "req_path_in(\"/old\", false)",
+			Cmd:    "URL_SET",
+			Param:  "https://example.org/new",
+			Status: 301,
+		},
+		{
+			Name:   "https",
+			Cond:   "default_t()",
+			Cmd:    "SCHEME_SET",
+			Param:  "https",
+			Status: 302,
+		},
+	}
+	if err := rs.UpsertProductRedirectRules(ctx, product, rules); err != nil {
+		t.Fatalf("UpsertProductRedirectRules: %v", err)
+	}
+
+	filter := &imodule_conf.RedirectRuleFilter{Product: product}
+	list, err := rs.FetchRedirectRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchRedirectRules: %v", err)
+	}
+	for i, rule := range rules {
+		rule.ProductID, rule.Index = product.ID, i
+	}
+	if !reflect.DeepEqual(list, rules) {
+		t.Fatalf("FetchRedirectRules: want %+v, got %+v", rules, list)
+	}
+
+	if err = rs.UpsertProductRedirectRules(ctx, product, nil); err != nil {
+		t.Fatalf("UpsertProductRedirectRules: %v", err)
+	}
+	list, err = rs.FetchRedirectRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchRedirectRules: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchRedirectRules: want no rule, got %d", len(list))
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+var _ imodule_conf.RewriteRuleStorager = &RewriteRuleStorager{}
+
+func NewRewriteRuleStorager(dbCtxFactory lib.DBContextFactory) *RewriteRuleStorager {
+	return &RewriteRuleStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+type RewriteRuleStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+func (rs *RewriteRuleStorager) FetchRewriteRules(ctx context.Context, filter *imodule_conf.RewriteRuleFilter) ([]*imodule_conf.RewriteRule, error) {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := &dao.TModRewriteRuleParam{
+		OrderBy: lib.PString("product_id, rule_index"),
+	}
+	if filter != nil && filter.Product != nil {
+		where.ProductID = &filter.Product.ID
+	}
+
+	list, err := dao.TModRewriteRuleList(dbCtx, where)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*imodule_conf.RewriteRule, len(list))
+	for i, one := range list {
+		if rst[i], err = rewriteRuled2i(one); err != nil {
+			return nil, err
+		}
+	}
+
+	return rst, nil
+}
+
+func (rs *RewriteRuleStorager) UpsertProductRewriteRules(ctx context.Context, product *ibasic.Product,
+	rules []*imodule_conf.RewriteRule) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err = dao.TModRewriteRuleDelete(dbCtx, &dao.TModRewriteRuleParam{
+		ProductID: &product.ID,
+	}); err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	list := make([]*dao.TModRewriteRuleParam, len(rules))
+	for i, rule := range rules {
+		bs, err := json.Marshal(rule.Actions)
+		if err != nil {
+			return xerror.WrapDirtyDataError(err)
+		}
+
+		list[i] = &dao.TModRewriteRuleParam{
+			ProductID:   &product.ID,
+			RuleIndex:   lib.PInt(i),
+			Name:        lib.PString(rule.Name),
+			Description: lib.PString(rule.Description),
+			Cond:        lib.PString(rule.Cond),
+			Actions:     lib.PString(string(bs)),
+			IsLast:      lib.PBool(rule.Last),
+		}
+	}
+
+	_, err = dao.TModRewriteRuleCreate(dbCtx, list...)
+	return err
+}
+
+func rewriteRuled2i(p *dao.TModRewriteRule) (*imodule_conf.RewriteRule, error) {
+	rule := &imodule_conf.RewriteRule{
+		ProductID:   p.ProductID,
+		Index:       p.RuleIndex,
+		Name:        p.Name,
+		Description: p.Description,
+		Cond:        p.Cond,
+		Last:        p.IsLast,
+	}
+
+	if err := json.Unmarshal([]byte(p.Actions), &rule.Actions); err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Actions: %s, err: %v", p.Actions, err)
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package module_conf
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bfenetworks/api-server/model/imodule_conf"
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+func TestRewriteRuleStorager(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+	rs := NewRewriteRuleStorager(stateful.NewBFEDBContext)
+
+	rules := []*imodule_conf.RewriteRule{
+		{
+			Name: "trim_api",
+			Cond: "req_path_prefix_in(\"/api\", false)",
+			Actions: []*imodule_conf.RewriteAction{
+				{Cmd: "PATH_PREFIX_TRIM", Params: []string{"/api"}},
+			},
+			Last: true,
+		},
+		{
+			Name: "set_host",
+			Cond: "default_t()",
+			Actions: []*imodule_conf.RewriteAction{
+				{Cmd: "HOST_SET", Params: []string{"example.org"}},
+			},
+		},
+	}
+	if err := rs.UpsertProductRewriteRules(ctx, product, rules); err != nil {
+		t.Fatalf("UpsertProductRewriteRules: %v", err)
+	}
+
+	filter := &imodule_conf.RewriteRuleFilter{Product: product}
+	list, err := rs.FetchRewriteRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchRewriteRules: %v", err)
+	}
+	for i, rule := range rules {
+		rule.ProductID, rule.Index = product.ID, i
+	}
+	if !reflect.DeepEqual(list, rules) {
+		t.Fatalf("FetchRewriteRules: want %+v, got %+v", rules, list)
+	}
+
+	if err = rs.UpsertProductRewriteRules(ctx, product, nil); err != nil {
+		t.Fatalf("UpsertProductRewriteRules: %v", err)
+	}
+	list, err = rs.FetchRewriteRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchRewriteRules: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchRewriteRules: want no rule, got %d", len(list))
+	}
+}
@@ -87,15 +87,27 @@ func (ps *RDBCertificateStorager) FetchCertificates(ctx context.Context, filter
 	return rst, nil
 }
 
+func (ps *RDBCertificateStorager) CountCertificates(ctx context.Context, filter *iprotocol.CertificateFilter) (int64, error) {
+	dbCtx, err := ps.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dao.TCertificateCount(dbCtx, filte2param(filter))
+}
+
 func filte2param(filter *iprotocol.CertificateFilter) *dao.TCertificateParam {
 	if filter == nil {
 		return nil
 	}
 
-	return &dao.TCertificateParam{
+	tmp := &dao.TCertificateParam{
 		CertName:  filter.CertName,
 		IsDefault: filter.IsDefault,
 	}
+	tmp.OrderBy, tmp.Limit, tmp.NameLike = dao.PageParam(filter.Pagination, "cert_name")
+
+	return tmp
 }
 
 func certificateParamI2D(pp *iprotocol.CertificateParam) *dao.TCertificateParam {
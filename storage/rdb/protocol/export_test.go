@@ -0,0 +1,163 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bfenetworks/bfe/bfe_config/bfe_tls_conf/tls_rule_conf"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/api-server/stateful/container/rdb"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+// createProduct init container with a new sqlite db, and create product in it
+func createProduct(t *testing.T, name string) *ibasic.Product {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	stateful.DefaultConfig.RunTime.ExportCache.Disable = true
+	rdb.Init()
+
+	if err := container.ProductManager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString(name),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	list, err := container.ProductManager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString(name),
+	})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchProducts: %v %v", list, err)
+	}
+
+	return list[0]
+}
+
+// newCertPair return PEM of a self-signed certificate for hosts and its private key
+func newCertPair(t *testing.T, hosts ...string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: hosts[0]},
+		DNSNames:     hosts,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestExportTLSRuleLoadedByBFE(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	if err := container.DomainManager.CreateDomain(ctx, product, &iroute_conf.DomainParam{
+		Name: lib.PString("example.org"),
+	}); err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+
+	cert, key := newCertPair(t, "example.org")
+	if err := container.CertificateManager.CreateCertificate(ctx, &iprotocol.CertificateParam{
+		CertName:        lib.PString("demo_cert"),
+		Description:     lib.PString(""),
+		IsDefault:       lib.PBool(true),
+		CertFileName:    lib.PString("demo.crt"),
+		CertFileContent: &cert,
+		KeyFileName:     lib.PString("demo.key"),
+		KeyFileContent:  &key,
+		ExpiredDate:     lib.PString(time.Now().Add(24 * time.Hour).Format("2006-01-02")),
+	}); err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	if _, err := container.CertificateManager.UpsertProductTLSRule(ctx, product, &iprotocol.TLSRule{
+		CertName:   "demo_cert",
+		Hosts:      []string{"example.org"},
+		NextProtos: []string{"h2", "http/1.1"},
+		Grade:      "a",
+	}); err != nil {
+		t.Fatalf("UpsertProductTLSRule: %v", err)
+	}
+
+	conf, err := container.CertificateManager.ExportServerCert(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportServerCert: %v", err)
+	}
+	bs, err := json.Marshal(conf.TlsRuleConf)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "tls_rule_conf.data")
+	if err = ioutil.WriteFile(filename, bs, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	loaded, err := tls_rule_conf.TlsRuleConfLoad(filename)
+	if err != nil {
+		t.Fatalf("TlsRuleConfLoad: %v", err)
+	}
+	if loaded.Version != conf.Version {
+		t.Fatalf("TlsRuleConfLoad: want version %s, got %s", conf.Version, loaded.Version)
+	}
+	rule, ok := loaded.Config["demo"]
+	if !ok {
+		t.Fatalf("TlsRuleConfLoad: want rule of demo, got %+v", loaded.Config)
+	}
+	if rule.CertName != "demo_cert" || rule.Grade != "A" ||
+		len(rule.SniConf) != 1 || rule.SniConf[0] != "example.org" {
+		t.Fatalf("TlsRuleConfLoad: unexpected rule %+v", rule)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+type RDBTLSRuleStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+var _ iprotocol.TLSRuleStorager = &RDBTLSRuleStorager{}
+
+func NewTLSRuleStorager(dbCtxFactory lib.DBContextFactory) *RDBTLSRuleStorager {
+	return &RDBTLSRuleStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+func (rs *RDBTLSRuleStorager) FetchTLSRules(ctx context.Context, filter *iprotocol.TLSRuleFilter) ([]*iprotocol.TLSRule, error) {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where := &dao.TTLSRuleParam{
+		OrderBy: lib.PString("product_id"),
+	}
+	if filter != nil {
+		if filter.Product != nil {
+			where.ProductID = &filter.Product.ID
+		}
+		where.CertName = filter.CertName
+	}
+
+	list, err := dao.TTLSRuleList(dbCtx, where)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*iprotocol.TLSRule, len(list))
+	for i, one := range list {
+		if rst[i], err = tlsRuled2i(one); err != nil {
+			return nil, err
+		}
+	}
+
+	return rst, nil
+}
+
+func (rs *RDBTLSRuleStorager) UpsertProductTLSRule(ctx context.Context, product *ibasic.Product,
+	rule *iprotocol.TLSRule) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	hosts, err := json.Marshal(rule.Hosts)
+	if err != nil {
+		return xerror.WrapDirtyDataError(err)
+	}
+	nextProtos, err := json.Marshal(rule.NextProtos)
+	if err != nil {
+		return xerror.WrapDirtyDataError(err)
+	}
+
+	if _, err = dao.TTLSRuleDelete(dbCtx, &dao.TTLSRuleParam{
+		ProductID: &product.ID,
+	}); err != nil {
+		return err
+	}
+
+	_, err = dao.TTLSRuleCreate(dbCtx, &dao.TTLSRuleParam{
+		ProductID:     &product.ID,
+		CertName:      lib.PString(rule.CertName),
+		Hosts:         lib.PString(string(hosts)),
+		NextProtos:    lib.PString(string(nextProtos)),
+		Grade:         lib.PString(rule.Grade),
+		Chacha20:      lib.PBool(rule.Chacha20),
+		DynamicRecord: lib.PBool(rule.DynamicRecord),
+	})
+	return err
+}
+
+func (rs *RDBTLSRuleStorager) DeleteProductTLSRule(ctx context.Context, product *ibasic.Product) error {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TTLSRuleDelete(dbCtx, &dao.TTLSRuleParam{
+		ProductID: &product.ID,
+	})
+	return err
+}
+
+func tlsRuled2i(p *dao.TTLSRule) (*iprotocol.TLSRule, error) {
+	rule := &iprotocol.TLSRule{
+		ProductID:     p.ProductID,
+		CertName:      p.CertName,
+		Grade:         p.Grade,
+		Chacha20:      p.Chacha20,
+		DynamicRecord: p.DynamicRecord,
+	}
+
+	if err := json.Unmarshal([]byte(p.Hosts), &rule.Hosts); err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("Hosts: %s, err: %v", p.Hosts, err)
+	}
+	if err := json.Unmarshal([]byte(p.NextProtos), &rule.NextProtos); err != nil {
+		return nil, xerror.WrapDirtyDataErrorWithMsg("NextProtos: %s, err: %v", p.NextProtos, err)
+	}
+
+	return rule, nil
+}
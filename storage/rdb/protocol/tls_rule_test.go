@@ -0,0 +1,108 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iprotocol"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/basic"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+func TestTLSRuleStorager(t *testing.T) {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	productStorager := basic.NewProductManager(stateful.NewBFEDBContext)
+	rs := NewTLSRuleStorager(stateful.NewBFEDBContext)
+
+	if err := productStorager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString("demo"),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	products, err := productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString("demo"),
+	})
+	if err != nil || len(products) != 1 {
+		t.Fatalf("FetchProducts: %v %v", products, err)
+	}
+	product := products[0]
+
+	filter := &iprotocol.TLSRuleFilter{Product: product}
+	for _, rule := range []*iprotocol.TLSRule{
+		{
+			CertName:   "demo_cert",
+			Hosts:      []string{"example.org"},
+			NextProtos: []string{"h2", "http/1.1"},
+			Grade:      "A",
+			Chacha20:   true,
+		},
+		{
+			CertName:      "other_cert",
+			Hosts:         []string{"example.org", "www.example.org"},
+			NextProtos:    []string{"http/1.1"},
+			Grade:         "C",
+			DynamicRecord: true,
+		},
+	} {
+		// the existed rule is replaced
+		if err = rs.UpsertProductTLSRule(ctx, product, rule); err != nil {
+			t.Fatalf("UpsertProductTLSRule: %v", err)
+		}
+
+		list, err := rs.FetchTLSRules(ctx, filter)
+		if err != nil {
+			t.Fatalf("FetchTLSRules: %v", err)
+		}
+		rule.ProductID = product.ID
+		if len(list) != 1 || !reflect.DeepEqual(list[0], rule) {
+			t.Fatalf("FetchTLSRules: want %+v, got %+v", rule, list)
+		}
+	}
+
+	list, err := rs.FetchTLSRules(ctx, &iprotocol.TLSRuleFilter{
+		CertName: lib.PString("demo_cert"),
+	})
+	if err != nil {
+		t.Fatalf("FetchTLSRules: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchTLSRules: want no rule of demo_cert, got %d", len(list))
+	}
+
+	if err = rs.DeleteProductTLSRule(ctx, product); err != nil {
+		t.Fatalf("DeleteProductTLSRule: %v", err)
+	}
+	list, err = rs.FetchTLSRules(ctx, filter)
+	if err != nil {
+		t.Fatalf("FetchTLSRules: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchTLSRules: want no rule, got %d", len(list))
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rdbtest opens an in-memory sqlite bfe_db with all migrations executed,
+// for tests of rdb storagers.
+package rdbtest
+
+import (
+	"context"
+
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/schema"
+)
+
+// Open replace bfe_db with a new in-memory sqlite database,
+// data of the previous one is dropped, so tests using it must not run concurrently.
+func Open() error {
+	if db, ok := stateful.DBs["bfe_db"]; ok {
+		db.Close()
+	}
+
+	stateful.DefaultConfig = &stateful.Config{
+		Databases: map[string]*stateful.DbConfig{
+			"bfe_db": {
+				Driver:       stateful.DriverSQLite,
+				File:         ":memory:",
+				MaxOpenConns: 1,
+			},
+		},
+	}
+	if err := stateful.DefaultConfig.InitDB(); err != nil {
+		return err
+	}
+
+	db, err := stateful.BFEDB()
+	if err != nil {
+		return err
+	}
+
+	return schema.Migrate(context.Background(), db, stateful.BFEDBDriver())
+}
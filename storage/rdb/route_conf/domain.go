@@ -56,6 +56,15 @@ func (rs *DomainStorager) FetchDomains(ctx context.Context, filter *iroute_conf.
 	return rst, nil
 }
 
+func (rs *DomainStorager) CountDomains(ctx context.Context, filter *iroute_conf.DomainFilter) (int64, error) {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dao.TDomainCount(dbCtx, domainFilter2Param(filter))
+}
+
 func (rs *DomainStorager) CreateDomain(ctx context.Context, product *ibasic.Product, param *iroute_conf.DomainParam) error {
 	param.ProductID = &product.ID
 
@@ -70,6 +79,20 @@ func (rs *DomainStorager) CreateDomain(ctx context.Context, product *ibasic.Prod
 	return err
 }
 
+func (rs *DomainStorager) UpdateDomain(ctx context.Context, domain *iroute_conf.Domain, param *iroute_conf.DomainParam) error {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_p := domainParami2d(param)
+	_p.UpdatedAt = lib.PTimeNow()
+	_, err = dao.TDomainUpdate(dbCtx, _p, &dao.TDomainParam{
+		ID: &domain.ID,
+	})
+	return err
+}
+
 func (rs *DomainStorager) DeleteDomain(ctx context.Context, product *ibasic.Product, domain *iroute_conf.Domain) error {
 	dbCtx, err := rs.dbCtxFactory(ctx)
 	if err != nil {
@@ -91,10 +114,13 @@ func domainFilter2Param(filter *iroute_conf.DomainFilter) *dao.TDomainParam {
 	if filter.Product != nil {
 		pid = &filter.Product.ID
 	}
-	return &dao.TDomainParam{
+	tmp := &dao.TDomainParam{
 		ProductID: pid,
 		Name:      filter.Name,
 	}
+	tmp.OrderBy, tmp.Limit, tmp.NameLike = dao.PageParam(filter.Pagination, "name")
+
+	return tmp
 }
 
 func domainParami2d(p *iroute_conf.DomainParam) *dao.TDomainParam {
@@ -107,6 +133,9 @@ func domainParami2d(p *iroute_conf.DomainParam) *dao.TDomainParam {
 		Name:                  p.Name,
 		UsingAdvancedRedirect: p.UsingAdvancedRedirect,
 		UsingAdvancedHsts:     p.UsingAdvancedHsts,
+		HstsMaxAge:            p.HstsMaxAge,
+		HstsIncludeSubdomains: p.HstsIncludeSubDomains,
+		HstsPreload:           p.HstsPreload,
 	}
 }
 
@@ -120,6 +149,9 @@ func domaind2i(p *dao.TDomain) *iroute_conf.Domain {
 		Name:                  p.Name,
 		UsingAdvancedRedirect: p.UsingAdvancedRedirect,
 		UsingAdvancedHsts:     p.UsingAdvancedHsts,
+		HstsMaxAge:            p.HstsMaxAge,
+		HstsIncludeSubDomains: p.HstsIncludeSubdomains,
+		HstsPreload:           p.HstsPreload,
 		ID:                    p.ID,
 	}
 }
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route_conf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/basic"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+func TestDomainHTTPSOptions(t *testing.T) {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	productStorager := basic.NewProductManager(stateful.NewBFEDBContext)
+	ds := NewDomainStorager(stateful.NewBFEDBContext)
+
+	if err := productStorager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString("demo"),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	products, err := productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString("demo"),
+	})
+	if err != nil || len(products) != 1 {
+		t.Fatalf("FetchProducts: %v %v", products, err)
+	}
+	product := products[0]
+
+	if err = ds.CreateDomain(ctx, product, &iroute_conf.DomainParam{
+		Name: lib.PString("example.org"),
+	}); err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+	filter := &iroute_conf.DomainFilter{
+		Product: product,
+		Name:    lib.PString("example.org"),
+	}
+	list, err := ds.FetchDomains(ctx, filter)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchDomains: %v %v", list, err)
+	}
+	domain := list[0]
+	if domain.UsingAdvancedRedirect != 0 || domain.UsingAdvancedHsts != 0 {
+		t.Fatalf("FetchDomains: want HTTPS disabled by default, got %+v", domain)
+	}
+
+	if err = ds.UpdateDomain(ctx, domain, &iroute_conf.DomainParam{
+		UsingAdvancedRedirect: lib.PInt8(1),
+		UsingAdvancedHsts:     lib.PInt8(1),
+		HstsMaxAge:            lib.PInt64(31536000),
+		HstsIncludeSubDomains: lib.PBool(true),
+		HstsPreload:           lib.PBool(true),
+	}); err != nil {
+		t.Fatalf("UpdateDomain: %v", err)
+	}
+
+	list, err = ds.FetchDomains(ctx, filter)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchDomains: %v %v", list, err)
+	}
+	want := iroute_conf.Domain{
+		ID:                    domain.ID,
+		ProductID:             product.ID,
+		Name:                  "example.org",
+		UsingAdvancedRedirect: 1,
+		UsingAdvancedHsts:     1,
+		HstsMaxAge:            31536000,
+		HstsIncludeSubDomains: true,
+		HstsPreload:           true,
+	}
+	if *list[0] != want {
+		t.Fatalf("FetchDomains: want %+v, got %+v", want, *list[0])
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route_conf_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bfenetworks/bfe/bfe_modules/mod_header"
+	"github.com/bfenetworks/bfe/bfe_modules/mod_redirect"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/stateful/container"
+	"github.com/bfenetworks/api-server/stateful/container/rdb"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+// createProduct init container with a new sqlite db, and create product in it
+func createProduct(t *testing.T, name string) *ibasic.Product {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	stateful.DefaultConfig.RunTime.ExportCache.Disable = true
+	rdb.Init()
+
+	if err := container.ProductManager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString(name),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	list, err := container.ProductManager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString(name),
+	})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("FetchProducts: %v %v", list, err)
+	}
+
+	return list[0]
+}
+
+// writeConf write exported config to a file for BFE loaders
+func writeConf(t *testing.T, conf interface{}) string {
+	bs, err := json.Marshal(conf)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "conf.data")
+	if err = ioutil.WriteFile(filename, bs, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	return filename
+}
+
+func TestExportDomainHTTPSLoadedByBFE(t *testing.T) {
+	ctx := context.Background()
+	product := createProduct(t, "demo")
+
+	if err := container.DomainManager.CreateDomain(ctx, product, &iroute_conf.DomainParam{
+		Name: lib.PString("example.org"),
+	}); err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+	list, err := container.DomainManager.DomainList(ctx, &iroute_conf.DomainFilter{
+		Product: product,
+		Name:    lib.PString("example.org"),
+	})
+	if err != nil || len(list) != 1 {
+		t.Fatalf("DomainList: %v %v", list, err)
+	}
+
+	if err = container.DomainManager.UpdateDomainHTTPS(ctx, product, list[0], &iroute_conf.DomainParam{
+		UsingAdvancedRedirect: lib.PInt8(1),
+		UsingAdvancedHsts:     lib.PInt8(1),
+		HstsMaxAge:            lib.PInt64(31536000),
+		HstsIncludeSubDomains: lib.PBool(true),
+	}); err != nil {
+		t.Fatalf("UpdateDomainHTTPS: %v", err)
+	}
+
+	redirectConf, err := container.RedirectRuleManager.ExportRedirect(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportRedirect: %v", err)
+	}
+	bs, err := json.Marshal(redirectConf)
+	if err != nil {
+		t.Fatalf("marshal redirect conf: %v", err)
+	}
+	var redirectFile mod_redirect.RedirectConfFile
+	if err = json.Unmarshal(bs, &redirectFile); err != nil {
+		t.Fatalf("unmarshal redirect conf: %v", err)
+	}
+	if err = mod_redirect.RedirectConfCheck(redirectFile); err != nil {
+		t.Fatalf("RedirectConfCheck: %v", err)
+	}
+	if rules := (*redirectFile.Config)["demo"]; rules == nil || len(*rules) != 1 {
+		t.Fatalf("redirect conf: want 1 rule of demo, got %s", bs)
+	}
+
+	headerConf, err := container.HeaderRuleManager.ExportHeader(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("ExportHeader: %v", err)
+	}
+	loaded, err := mod_header.HeaderConfLoad(writeConf(t, headerConf))
+	if err != nil {
+		t.Fatalf("HeaderConfLoad: %v", err)
+	}
+	if _, ok := loaded.Config["demo"]; !ok {
+		t.Fatalf("HeaderConfLoad: want rules of demo, got %+v", loaded.Config)
+	}
+}
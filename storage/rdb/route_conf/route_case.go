@@ -0,0 +1,167 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route_conf
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/lib/xerror"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/storage/rdb/internal/dao"
+)
+
+var _ iroute_conf.RouteCaseStorager = &RouteCaseStorager{}
+
+func NewRouteCaseStorager(dbCtxFactory lib.DBContextFactory) *RouteCaseStorager {
+	return &RouteCaseStorager{
+		dbCtxFactory: dbCtxFactory,
+	}
+}
+
+type RouteCaseStorager struct {
+	dbCtxFactory lib.DBContextFactory
+}
+
+func (rs *RouteCaseStorager) FetchRouteCases(ctx context.Context, filter *iroute_conf.RouteCaseFilter) ([]*iroute_conf.RouteRuleCase, error) {
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := dao.TRouteCaseList(dbCtx, routeCaseFilter2Param(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*iroute_conf.RouteRuleCase, len(list))
+	for i, one := range list {
+		if rst[i], err = routeCased2i(one); err != nil {
+			return nil, err
+		}
+	}
+
+	return rst, nil
+}
+
+func (rs *RouteCaseStorager) CreateRouteCase(ctx context.Context, product *ibasic.Product,
+	param *iroute_conf.RouteCaseParam) (int64, error) {
+
+	_p, err := routeCaseParami2d(param)
+	if err != nil {
+		return 0, err
+	}
+	_p.ProductID = &product.ID
+	if _p.Header == nil {
+		_p.Header = lib.PString("{}")
+	}
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dao.TRouteCaseCreate(dbCtx, _p)
+}
+
+func (rs *RouteCaseStorager) UpdateRouteCase(ctx context.Context, product *ibasic.Product,
+	old *iroute_conf.RouteRuleCase, param *iroute_conf.RouteCaseParam) error {
+
+	_p, err := routeCaseParami2d(param)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TRouteCaseUpdate(dbCtx, _p, &dao.TRouteCaseParam{
+		ID:        &old.ID,
+		ProductID: &product.ID,
+	})
+	return err
+}
+
+func (rs *RouteCaseStorager) DeleteRouteCase(ctx context.Context, product *ibasic.Product,
+	rc *iroute_conf.RouteRuleCase) error {
+
+	dbCtx, err := rs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TRouteCaseDelete(dbCtx, &dao.TRouteCaseParam{
+		ID:        &rc.ID,
+		ProductID: &product.ID,
+	})
+	return err
+}
+
+func routeCaseFilter2Param(filter *iroute_conf.RouteCaseFilter) *dao.TRouteCaseParam {
+	if filter == nil {
+		return nil
+	}
+
+	var pid *int64
+	if filter.Product != nil {
+		pid = &filter.Product.ID
+	}
+	return &dao.TRouteCaseParam{
+		ID:        filter.ID,
+		ProductID: pid,
+		OrderBy:   lib.PString("id"),
+	}
+}
+
+func routeCaseParami2d(p *iroute_conf.RouteCaseParam) (*dao.TRouteCaseParam, error) {
+	_p := &dao.TRouteCaseParam{
+		Description:   p.Description,
+		URL:           p.URL,
+		Method:        p.Method,
+		ExpectCluster: p.ExpectCluster,
+	}
+
+	if p.Header != nil {
+		bs, err := json.Marshal(p.Header)
+		if err != nil {
+			return nil, xerror.WrapDirtyDataError(err)
+		}
+		_p.Header = lib.PString(string(bs))
+	}
+
+	return _p, nil
+}
+
+func routeCased2i(p *dao.TRouteCase) (*iroute_conf.RouteRuleCase, error) {
+	rc := &iroute_conf.RouteRuleCase{
+		ID:            p.ID,
+		Description:   p.Description,
+		URL:           p.URL,
+		Method:        p.Method,
+		ExpectCluster: p.ExpectCluster,
+	}
+
+	if p.Header != "" {
+		if err := json.Unmarshal([]byte(p.Header), &rc.Header); err != nil {
+			return nil, xerror.WrapDirtyDataErrorWithMsg("Header: %s, err: %v", p.Header, err)
+		}
+	}
+
+	return rc, nil
+}
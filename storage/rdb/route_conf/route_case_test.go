@@ -0,0 +1,75 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route_conf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bfenetworks/api-server/lib"
+	"github.com/bfenetworks/api-server/model/ibasic"
+	"github.com/bfenetworks/api-server/model/iroute_conf"
+	"github.com/bfenetworks/api-server/stateful"
+	"github.com/bfenetworks/api-server/storage/rdb/basic"
+	"github.com/bfenetworks/api-server/storage/rdb/rdbtest"
+)
+
+func TestRouteCasesDeletedWithProduct(t *testing.T) {
+	ctx := context.Background()
+	if err := rdbtest.Open(); err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	productStorager := basic.NewProductManager(stateful.NewBFEDBContext)
+	rs := NewRouteCaseStorager(stateful.NewBFEDBContext)
+
+	if err := productStorager.CreateProduct(ctx, &ibasic.ProductParam{
+		Name:              lib.PString("demo"),
+		Description:       lib.PString(""),
+		MailList:          []string{},
+		PhoneList:         []string{},
+		ContactPersonList: []string{},
+	}); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	products, err := productStorager.FetchProducts(ctx, &ibasic.ProductFilter{
+		Name: lib.PString("demo"),
+	})
+	if err != nil || len(products) != 1 {
+		t.Fatalf("FetchProducts: %v %v", products, err)
+	}
+	product := products[0]
+
+	if _, err = rs.CreateRouteCase(ctx, product, &iroute_conf.RouteCaseParam{
+		URL:           lib.PString("http://example.org/"),
+		ExpectCluster: lib.PString("demo_cluster"),
+	}); err != nil {
+		t.Fatalf("CreateRouteCase: %v", err)
+	}
+
+	if err = productStorager.DeleteProduct(ctx, product); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+
+	list, err := rs.FetchRouteCases(ctx, &iroute_conf.RouteCaseFilter{
+		Product: product,
+	})
+	if err != nil {
+		t.Fatalf("FetchRouteCases: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("FetchRouteCases: want no case, got %d", len(list))
+	}
+}
@@ -162,6 +162,20 @@ func newRouteBasicRule(param *dao.TRouteBasicRule, cluster *icluster_conf.Cluste
 	return rule, nil
 }
 
+// LockProductRule lock product row, so route rules of product can be changed by one transaction at the same time
+func (rs *RouteRuleStorager) LockProductRule(ctx context.Context, product *ibasic.Product) error {
+	dbCtx, err := rs.dbCtxFactory(ctx, lib.BlockWrite())
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TProductOne(dbCtx, &dao.TProductParam{
+		Id: &product.ID,
+	})
+
+	return err
+}
+
 func (rs *RouteRuleStorager) UpsertProductRule(ctx context.Context, product *ibasic.Product,
 	rule *iroute_conf.ProductRouteRule) error {
 
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 The BFE Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema create and upgrade tables of database automatically
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bfenetworks/api-server/stateful"
+)
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// migrations of each driver, tables of mysql are managed by db_ddl.sql
+var migrations = map[string]struct {
+	fs  embed.FS
+	dir string
+}{
+	stateful.DriverSQLite: {sqliteFS, "sqlite"},
+}
+
+type migration struct {
+	version int
+	name    string
+	content string
+}
+
+// Migrate execute migrations of driver which have not been executed, in order of version.
+// Migration file is named as {version}_{description}.sql, each one is executed in a transaction.
+func Migrate(ctx context.Context, db *sql.DB, driver string) error {
+	source, ok := migrations[driver]
+	if !ok {
+		return nil
+	}
+
+	list, err := loadMigrations(source.fs, source.dir)
+	if err != nil {
+		return err
+	}
+
+	if _, err = db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `schema_migrations` ("+
+		"`version` bigint NOT NULL PRIMARY KEY, "+
+		"`name` varchar(255) NOT NULL, "+
+		"`created_at` datetime NOT NULL)"); err != nil {
+		return fmt.Errorf("create schema_migrations: %v", err)
+	}
+
+	var current int
+	if err = db.QueryRowContext(ctx, "SELECT COALESCE(MAX(`version`), 0) FROM `schema_migrations`").
+		Scan(&current); err != nil {
+		return fmt.Errorf("query schema version: %v", err)
+	}
+
+	for _, one := range list {
+		if one.version <= current {
+			continue
+		}
+
+		if err = execMigration(ctx, db, one); err != nil {
+			return fmt.Errorf("migrate %s: %v", one.name, err)
+		}
+	}
+
+	return nil
+}
+
+func execMigration(ctx context.Context, db *sql.DB, one *migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, one.content); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "INSERT INTO `schema_migrations` (`version`, `name`, `created_at`) "+
+		"VALUES (?, ?, CURRENT_TIMESTAMP)", one.version, one.name); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func loadMigrations(fs embed.FS, dir string) ([]*migration, error) {
+	entries, err := fs.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*migration
+	for _, entry := range entries {
+		name := entry.Name()
+		ss := strings.SplitN(name, "_", 2)
+		version, err := strconv.Atoi(ss[0])
+		if err != nil || len(ss) != 2 || !strings.HasSuffix(name, ".sql") {
+			return nil, fmt.Errorf("illegal migration file name: %s", name)
+		}
+
+		content, err := fs.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, &migration{
+			version: version,
+			name:    name,
+			content: string(content),
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].version < list[j].version
+	})
+
+	for i := 1; i < len(list); i++ {
+		if list[i].version == list[i-1].version {
+			return nil, fmt.Errorf("duplicate migration version: %s, %s", list[i-1].name, list[i].name)
+		}
+	}
+
+	return list, nil
+}
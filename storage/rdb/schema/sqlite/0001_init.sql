@@ -0,0 +1,312 @@
+-- schema of open_bfe for sqlite, keep it the same as db_ddl.sql
+
+CREATE TABLE `bfe_clusters` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `pool_name` varchar(255) NOT NULL DEFAULT '',
+  `capacity` bigint NOT NULL,
+  `enabled` tinyint NOT NULL DEFAULT 1,
+  `gtc_enabled` tinyint NOT NULL DEFAULT 1,
+  `gtc_manual_enabled` tinyint NOT NULL DEFAULT 1,
+  `exempt_traffic_check` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`)
+);
+
+CREATE TABLE `products` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `mail_list` varchar(4096) NOT NULL,
+  `contact_person` varchar(4096) NOT NULL,
+  `sms_list` varchar(4096) NOT NULL DEFAULT 'no sms',
+  `description` varchar(1024) NOT NULL DEFAULT 'no desc',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`)
+);
+
+CREATE TABLE `domains` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL COLLATE NOCASE,
+  `product_id` bigint NOT NULL,
+  `type` int NOT NULL,
+  `using_advanced_redirect` tinyint NOT NULL DEFAULT 0,
+  `using_advanced_hsts` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`)
+);
+CREATE INDEX `domains_product_id` ON `domains` (`product_id`);
+CREATE INDEX `domains_type` ON `domains` (`type`);
+
+CREATE TABLE `clusters` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL COLLATE NOCASE,
+  `description` varchar(1024) NOT NULL DEFAULT 'no desc' COLLATE NOCASE,
+  `product_id` bigint NOT NULL,
+  `max_idle_conn_per_host` smallint NOT NULL DEFAULT 2,
+  `timeout_conn_serv` int NOT NULL DEFAULT 50000,
+  `timeout_response_header` int NOT NULL DEFAULT 50000,
+  `timeout_readbody_client` int NOT NULL DEFAULT 30000,
+  `timeout_read_client_again` int NOT NULL DEFAULT 30000,
+  `timeout_write_client` int NOT NULL DEFAULT 60000,
+  `healthcheck_schem` varchar(16) NOT NULL DEFAULT 'http',
+  `healthcheck_interval` int NOT NULL DEFAULT 1000,
+  `healthcheck_failnum` int NOT NULL DEFAULT 10,
+  `healthcheck_host` varchar(255) NOT NULL,
+  `healthcheck_uri` varchar(255) NOT NULL,
+  `healthcheck_statuscode` int NOT NULL DEFAULT 200,
+  `clientip_carry` tinyint NOT NULL DEFAULT 0,
+  `port_carry` tinyint NOT NULL DEFAULT 0,
+  `max_retry_in_cluster` tinyint NOT NULL DEFAULT 3,
+  `max_retry_cross_cluster` tinyint NOT NULL DEFAULT 0,
+  `ready` tinyint NOT NULL DEFAULT 1,
+  `hash_strategy` int NOT NULL DEFAULT 0,
+  `cookie_key` varchar(255) NOT NULL DEFAULT 'BAIDUID',
+  `hash_header` varchar(255) NOT NULL DEFAULT 'Cookie:BAIDUID',
+  `session_sticky` tinyint NOT NULL DEFAULT 0,
+  `req_write_buffer_size` int NOT NULL DEFAULT 512,
+  `req_flush_interval` int NOT NULL DEFAULT 0,
+  `res_flush_interval` int NOT NULL DEFAULT 20,
+  `cancel_on_client_close` tinyint NOT NULL DEFAULT 0,
+  `failure_status` tinyint NOT NULL DEFAULT 0,
+  `max_conns_per_host` int NOT NULL DEFAULT 0,
+  `protocol` varchar(16) NOT NULL DEFAULT 'http',
+  `balance_mode` varchar(16) NOT NULL DEFAULT 'WRR',
+  `outlier_detection_code` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`)
+);
+
+CREATE TABLE `lb_matrices` (
+  `cluster_id` integer PRIMARY KEY,
+  `lb_matrix` varchar(8192) NOT NULL,
+  `product_id` bigint NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE `sub_clusters` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `cluster_id` bigint NOT NULL,
+  `product_id` bigint NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT 'no desc' COLLATE NOCASE,
+  `bns_name_id` bigint NOT NULL,
+  `enabled` tinyint NOT NULL DEFAULT 1,
+  `port_name` varchar(255) NOT NULL DEFAULT '',
+  `instance_selector` varchar(1024) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`, `product_id`)
+);
+CREATE INDEX `sub_clusters_cluster_id` ON `sub_clusters` (`cluster_id`);
+
+CREATE TABLE `pools` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `product_id` bigint NOT NULL DEFAULT 0,
+  `ready` tinyint NOT NULL DEFAULT 1,
+  `instance_detail` mediumtext,
+  `type` tinyint NOT NULL DEFAULT 1,
+  `tag` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`)
+);
+
+CREATE TABLE `route_basic_rules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `product_id` bigint NOT NULL,
+  `host_names` text NOT NULL,
+  `paths` text NOT NULL,
+  `cluster_id` bigint NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX `route_basic_rules_product_id` ON `route_basic_rules` (`product_id`);
+
+CREATE TABLE `route_advance_rules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `product_id` bigint NOT NULL,
+  `expression` varchar(4096) NOT NULL,
+  `cluster_id` bigint NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX `route_advance_rules_product_id` ON `route_advance_rules` (`product_id`);
+
+CREATE TABLE `route_cases` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `product_id` bigint NOT NULL,
+  `url` varchar(4096) NOT NULL,
+  `method` varchar(255) NOT NULL DEFAULT '',
+  `protocol` varchar(255) NOT NULL DEFAULT '',
+  `header` varchar(4096) NOT NULL,
+  `body` varchar(4096) NOT NULL DEFAULT '',
+  `expect_cluster` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX `route_cases_product_id` ON `route_cases` (`product_id`);
+
+CREATE TABLE `active_health_checks` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `product_id` bigint NOT NULL,
+  `cluster_id` bigint NOT NULL,
+  `check_schema` varchar(16) NOT NULL,
+  `check_interval` int NOT NULL,
+  `check_timeout` int NOT NULL,
+  `succ_num` int NOT NULL,
+  `fail_num` int NOT NULL,
+  `host` varchar(255) NOT NULL DEFAULT '',
+  `uri` varchar(1024) NOT NULL DEFAULT '',
+  `status_code` int NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`cluster_id`)
+);
+CREATE INDEX `active_health_checks_product_id` ON `active_health_checks` (`product_id`);
+
+CREATE TABLE `certificates` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `cert_name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT 'no desc',
+  `is_default` tinyint NOT NULL DEFAULT 0,
+  `expired_date` varchar(255) NOT NULL,
+  `cert_file_name` varchar(255) NOT NULL,
+  `cert_file_path` varchar(255) NOT NULL,
+  `key_file_name` varchar(255) NOT NULL,
+  `key_file_path` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`cert_name`)
+);
+
+CREATE TABLE `extra_files` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `product_id` bigint NOT NULL DEFAULT 0,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `md5` blob NOT NULL,
+  `content` blob,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`, `product_id`)
+);
+CREATE INDEX `extra_files_product_id` ON `extra_files` (`product_id`);
+
+CREATE TABLE `config_versions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `data_sign` varchar(255) NOT NULL,
+  `version` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE `config_snapshots` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `version` varchar(255) NOT NULL,
+  `data` blob NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`, `version`)
+);
+
+CREATE TABLE `config_pins` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `version` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`)
+);
+
+CREATE TABLE `users` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `type` tinyint NOT NULL DEFAULT 0,
+  `password` varchar(255) NOT NULL DEFAULT '',
+  `ticket` varchar(20) NOT NULL DEFAULT '',
+  `ticket_created_at` datetime NOT NULL DEFAULT '0001-01-01 00:00:00',
+  `scopes` varchar(2048) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`name`, `type`)
+);
+
+CREATE TABLE `user_products` (
+  `user_id` bigint NOT NULL,
+  `product_id` bigint NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`, `product_id`)
+);
+
+CREATE TABLE `audits` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `visitor` varchar(255) NOT NULL DEFAULT '',
+  `product_id` bigint NOT NULL DEFAULT 0,
+  `product_name` varchar(255) NOT NULL DEFAULT '',
+  `resource_type` varchar(255) NOT NULL,
+  `resource_name` varchar(255) NOT NULL DEFAULT '',
+  `action` varchar(32) NOT NULL,
+  `before_data` mediumtext NOT NULL,
+  `after_data` mediumtext NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX `audits_product_id` ON `audits` (`product_id`);
+CREATE INDEX `audits_created_at` ON `audits` (`created_at`);
+
+-- sqlite has no ON UPDATE CURRENT_TIMESTAMP, times without zone are in UTC
+CREATE TRIGGER `bfe_clusters_updated_at` AFTER UPDATE ON `bfe_clusters` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `bfe_clusters` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `products_updated_at` AFTER UPDATE ON `products` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `products` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `domains_updated_at` AFTER UPDATE ON `domains` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `domains` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `clusters_updated_at` AFTER UPDATE ON `clusters` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `clusters` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `lb_matrices_updated_at` AFTER UPDATE ON `lb_matrices` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `lb_matrices` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `sub_clusters_updated_at` AFTER UPDATE ON `sub_clusters` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `sub_clusters` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `pools_updated_at` AFTER UPDATE ON `pools` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `pools` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `route_basic_rules_updated_at` AFTER UPDATE ON `route_basic_rules` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `route_basic_rules` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `route_advance_rules_updated_at` AFTER UPDATE ON `route_advance_rules` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `route_advance_rules` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `route_cases_updated_at` AFTER UPDATE ON `route_cases` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `route_cases` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `active_health_checks_updated_at` AFTER UPDATE ON `active_health_checks` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `active_health_checks` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `certificates_updated_at` AFTER UPDATE ON `certificates` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `certificates` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `extra_files_updated_at` AFTER UPDATE ON `extra_files` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `extra_files` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `config_versions_updated_at` AFTER UPDATE ON `config_versions` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `config_versions` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `config_snapshots_updated_at` AFTER UPDATE ON `config_snapshots` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `config_snapshots` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `config_pins_updated_at` AFTER UPDATE ON `config_pins` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `config_pins` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `users_updated_at` AFTER UPDATE ON `users` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `users` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `user_products_updated_at` AFTER UPDATE ON `user_products` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `user_products` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+CREATE TRIGGER `audits_updated_at` AFTER UPDATE ON `audits` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `audits` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+
+INSERT INTO users (id, name, password, scopes, created_at) VALUES (1, 'admin', 'admin', 'System', CURRENT_TIMESTAMP);
+INSERT INTO products (id, name, description, mail_list, contact_person, created_at) VALUES
+  (1, 'BFE', 'Build-in Product, User by System Manager', 'bfe@cncf.com', 'bfe', CURRENT_TIMESTAMP);
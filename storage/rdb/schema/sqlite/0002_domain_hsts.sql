@@ -0,0 +1,5 @@
+-- options of Strict-Transport-Security header of domains
+
+ALTER TABLE `domains` ADD COLUMN `hsts_max_age` bigint NOT NULL DEFAULT 0;
+ALTER TABLE `domains` ADD COLUMN `hsts_include_subdomains` tinyint NOT NULL DEFAULT 0;
+ALTER TABLE `domains` ADD COLUMN `hsts_preload` tinyint NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+-- header rules of BFE mod_header
+
+CREATE TABLE `mod_header_rules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `product_id` bigint NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `actions` text NOT NULL,
+  `is_last` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`product_id`, `name`)
+);
+CREATE TRIGGER `mod_header_rules_updated_at` AFTER UPDATE ON `mod_header_rules` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `mod_header_rules` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
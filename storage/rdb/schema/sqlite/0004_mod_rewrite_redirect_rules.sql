@@ -0,0 +1,36 @@
+-- rewrite rules of BFE mod_rewrite and redirect rules of BFE mod_redirect
+
+CREATE TABLE `mod_rewrite_rules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `product_id` bigint NOT NULL,
+  `rule_index` int NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `actions` text NOT NULL,
+  `is_last` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`product_id`, `rule_index`),
+  UNIQUE (`product_id`, `name`)
+);
+CREATE TRIGGER `mod_rewrite_rules_updated_at` AFTER UPDATE ON `mod_rewrite_rules` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `mod_rewrite_rules` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
+
+CREATE TABLE `mod_redirect_rules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `product_id` bigint NOT NULL,
+  `rule_index` int NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `cmd` varchar(32) NOT NULL,
+  `param` varchar(4096) NOT NULL,
+  `status` int NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`product_id`, `rule_index`),
+  UNIQUE (`product_id`, `name`)
+);
+CREATE TRIGGER `mod_redirect_rules_updated_at` AFTER UPDATE ON `mod_redirect_rules` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `mod_redirect_rules` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
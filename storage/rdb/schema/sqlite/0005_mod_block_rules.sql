@@ -0,0 +1,17 @@
+-- block rules of BFE mod_block, ip blocklists are saved as extra files
+
+CREATE TABLE `mod_block_rules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `product_id` bigint NOT NULL,
+  `rule_index` int NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1024) NOT NULL DEFAULT '',
+  `cond` varchar(4096) NOT NULL,
+  `action` varchar(32) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`product_id`, `rule_index`),
+  UNIQUE (`product_id`, `name`)
+);
+CREATE TRIGGER `mod_block_rules_updated_at` AFTER UPDATE ON `mod_block_rules` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `mod_block_rules` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
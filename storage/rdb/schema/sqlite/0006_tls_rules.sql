@@ -0,0 +1,17 @@
+-- tls rules of products, bind certificate to product and its domains
+
+CREATE TABLE `tls_rules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `product_id` bigint NOT NULL,
+  `cert_name` varchar(255) NOT NULL,
+  `hosts` text NOT NULL,
+  `next_protos` varchar(1024) NOT NULL DEFAULT '[]',
+  `grade` varchar(8) NOT NULL DEFAULT 'C',
+  `chacha20` tinyint NOT NULL DEFAULT 0,
+  `dynamic_record` tinyint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (`product_id`)
+);
+CREATE TRIGGER `tls_rules_updated_at` AFTER UPDATE ON `tls_rules` FOR EACH ROW WHEN NEW.updated_at = OLD.updated_at
+BEGIN UPDATE `tls_rules` SET updated_at = CURRENT_TIMESTAMP WHERE rowid = NEW.rowid; END;
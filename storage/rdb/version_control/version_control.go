@@ -57,10 +57,135 @@ func (vcs *VersionControlStorager) UpsertConfigLastExportedVersion(ctx context.C
 		return "", err
 	}
 
-	_, err = dao.TConfigVersionCreate(dbCtx, &dao.TConfigVersionParam{
+	if _, err = dao.TConfigVersionCreate(dbCtx, &dao.TConfigVersionParam{
 		Name:     &css.Topic,
 		DataSign: &css.DataSignWithoutVersion,
 		Version:  &version,
+	}); err != nil {
+		return "", err
+	}
+
+	_, err = dao.TConfigSnapshotCreate(dbCtx, &dao.TConfigSnapshotParam{
+		Name:    &css.Topic,
+		Version: &version,
+		Data:    css.Snapshot(),
 	})
 	return version, err
 }
+
+func (vcs *VersionControlStorager) FetchConfigVersions(ctx context.Context,
+	filter *iversion_control.ConfigVersionFilter) ([]*iversion_control.ConfigVersion, error) {
+
+	dbCtx, err := vcs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	param := &dao.TConfigVersionParam{
+		OrderBy: lib.PString("version DESC"),
+	}
+	if filter != nil {
+		param.Name = filter.Name
+		param.Version = filter.Version
+	}
+
+	list, err := dao.TConfigVersionList(dbCtx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*iversion_control.ConfigVersion, len(list))
+	for i, one := range list {
+		rst[i] = &iversion_control.ConfigVersion{
+			ID:        one.ID,
+			Name:      one.Name,
+			DataSign:  one.DataSign,
+			Version:   one.Version,
+			CreatedAt: one.CreatedAt,
+		}
+
+		if filter == nil || !filter.WithSnapshot {
+			continue
+		}
+
+		snapshot, err := dao.TConfigSnapshotOne(dbCtx, &dao.TConfigSnapshotParam{
+			Name:    &one.Name,
+			Version: &one.Version,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			rst[i].Snapshot = snapshot.Data
+		}
+	}
+
+	return rst, nil
+}
+
+func (vcs *VersionControlStorager) FetchConfigPins(ctx context.Context, name *string) ([]*iversion_control.ConfigPin, error) {
+	dbCtx, err := vcs.dbCtxFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := dao.TConfigPinList(dbCtx, &dao.TConfigPinParam{
+		Name:    name,
+		OrderBy: lib.PString("name"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rst := make([]*iversion_control.ConfigPin, len(list))
+	for i, one := range list {
+		rst[i] = &iversion_control.ConfigPin{
+			Name:      one.Name,
+			Version:   one.Version,
+			UpdatedAt: one.UpdatedAt,
+		}
+	}
+
+	return rst, nil
+}
+
+func (vcs *VersionControlStorager) UpsertConfigPin(ctx context.Context, pin *iversion_control.ConfigPin) error {
+	dbCtx, err := vcs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	old, err := dao.TConfigPinOne(dbCtx, &dao.TConfigPinParam{
+		Name: &pin.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	if old == nil {
+		_, err = dao.TConfigPinCreate(dbCtx, &dao.TConfigPinParam{
+			Name:    &pin.Name,
+			Version: &pin.Version,
+		})
+		return err
+	}
+
+	_, err = dao.TConfigPinUpdate(dbCtx, &dao.TConfigPinParam{
+		Version: &pin.Version,
+	}, &dao.TConfigPinParam{
+		ID: &old.ID,
+	})
+	return err
+}
+
+func (vcs *VersionControlStorager) DeleteConfigPin(ctx context.Context, name string) error {
+	dbCtx, err := vcs.dbCtxFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.TConfigPinDelete(dbCtx, &dao.TConfigPinParam{
+		Name: &name,
+	})
+	return err
+}